@@ -0,0 +1,79 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/eva01/backup-guardian/domain"
+)
+
+// jobAnomalyResponse is an anomalous run awaiting acknowledgement.
+type jobAnomalyResponse struct {
+	RunID      string    `json:"run_id"`
+	Action     string    `json:"action"`
+	Reasons    []string  `json:"reasons"`
+	DetectedAt time.Time `json:"detected_at"`
+}
+
+type anomalyResponse struct {
+	Reasons []string `json:"reasons"`
+	Score   float64  `json:"score"`
+}
+
+type changesResponse struct {
+	Copies      int64            `json:"copies"`
+	CopyBytes   int64            `json:"copy_bytes"`
+	Updates     int64            `json:"updates"`
+	UpdateBytes int64            `json:"update_bytes"`
+	Deletes     int64            `json:"deletes"`
+	DeleteBytes int64            `json:"delete_bytes"`
+	Extensions  map[string]int64 `json:"extensions,omitempty"`
+}
+
+// handleAcknowledge acknowledges the anomaly of a job, so that it runs in its own mode again.
+func (s *Server) handleAcknowledge(w http.ResponseWriter, r *http.Request) {
+	anomaly, err := s.service.Acknowledge(r.PathValue("job"))
+	if err != nil {
+		s.writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, mapJobAnomaly(anomaly))
+}
+
+func mapJobAnomaly(anomaly *domain.JobAnomaly) *jobAnomalyResponse {
+	if anomaly == nil {
+		return nil
+	}
+
+	return &jobAnomalyResponse{
+		RunID:      anomaly.RunID,
+		Action:     anomaly.Action,
+		Reasons:    anomaly.Reasons,
+		DetectedAt: anomaly.DetectedAt,
+	}
+}
+
+func mapAnomaly(anomaly *domain.Anomaly) *anomalyResponse {
+	if anomaly == nil {
+		return nil
+	}
+
+	return &anomalyResponse{Reasons: anomaly.Reasons, Score: anomaly.Score}
+}
+
+func mapChanges(changes *domain.ChangeStats) *changesResponse {
+	if changes == nil {
+		return nil
+	}
+
+	return &changesResponse{
+		Copies:      changes.Copies,
+		CopyBytes:   changes.CopyBytes,
+		Updates:     changes.Updates,
+		UpdateBytes: changes.UpdateBytes,
+		Deletes:     changes.Deletes,
+		DeleteBytes: changes.DeleteBytes,
+		Extensions:  changes.Extensions,
+	}
+}
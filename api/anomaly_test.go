@@ -0,0 +1,51 @@
+package api_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/eva01/backup-guardian/api"
+	"github.com/eva01/backup-guardian/domain"
+	domainmocks "github.com/eva01/backup-guardian/domain/mocks"
+	"github.com/eva01/backup-guardian/internal/errors"
+	"github.com/eva01/backup-guardian/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServer_Acknowledge(t *testing.T) {
+	selector := &domain.JobAnomalySelector{JobName: "test-job"}
+	anomaliesMock := domainmocks.NewJobAnomaliesReadWriter(t)
+	anomaliesMock.On("GetJobAnomaly", selector).
+		Return(&domain.JobAnomaly{JobName: "test-job", RunID: "run-1", Action: domain.AnomalyCopy, Reasons: []string{"900 files deleted, usually 2"}}, nil).Once()
+	anomaliesMock.On("DeleteJobAnomaly", selector).Return(nil).Once()
+	anomaliesMock.On("GetJobAnomaly", selector).Return(nil, &errors.Error{Code: errors.CodeNotFound}).Once()
+	pausesMock := domainmocks.NewJobPausesReadWriter(t)
+	pausesMock.On("GetJobPause", &domain.JobPauseSelector{Scope: "test-job"}).Return(nil, &errors.Error{Code: errors.CodeNotFound}).Once()
+
+	svc := service.New(
+		service.WithJobPauses(pausesMock),
+		service.WithJobAnomalies(anomaliesMock),
+		service.WithJobs(&domain.SyncJob{Name: "test-job", Source: "source", Destination: "dest"}),
+	)
+	server := httptest.NewServer(api.New(api.WithService(svc)).Handler())
+	t.Cleanup(server.Close)
+
+	resp, err := http.Post(server.URL+"/api/jobs/test-job/acknowledge", "", nil)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var body map[string]any
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Equal(t, "run-1", body["run_id"])
+	assert.Equal(t, "copy", body["action"])
+	assert.Equal(t, []any{"900 files deleted, usually 2"}, body["reasons"])
+
+	resp, err = http.Post(server.URL+"/api/jobs/test-job/acknowledge", "", nil)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
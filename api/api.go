@@ -0,0 +1,148 @@
+// Package api serves the backup-guardian HTTP API, web dashboard and Prometheus metrics.
+package api
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"github.com/eva01/backup-guardian/metrics"
+	"github.com/eva01/backup-guardian/service"
+	"github.com/eva01/backup-guardian/web"
+)
+
+const shutdownTimeout = 5 * time.Second
+
+// Server serves the HTTP API.
+type Server struct {
+	service  *service.Service
+	addr     string
+	username string
+	password string
+	token    string
+	logger   *slog.Logger
+}
+
+// Option configures the server.
+type Option func(*Server)
+
+// New creates a new server.
+func New(options ...Option) *Server {
+	s := &Server{
+		logger: slog.Default(),
+	}
+
+	for _, opt := range options {
+		opt(s)
+	}
+
+	return s
+}
+
+// WithService sets the service backing the API.
+func WithService(service *service.Service) Option {
+	return func(s *Server) { s.service = service }
+}
+
+// WithAddr sets the listen address.
+func WithAddr(addr string) Option {
+	return func(s *Server) { s.addr = addr }
+}
+
+// WithLogger sets the logger.
+func WithLogger(logger *slog.Logger) Option {
+	return func(s *Server) { s.logger = logger }
+}
+
+// Handler returns the HTTP handler serving the API, dashboard and metrics.
+func (s *Server) Handler() http.Handler {
+	if s.service == nil {
+		panic("api server requires service")
+	}
+
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(metrics.NewCollector(s.service))
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/jobs", s.handleListJobs)
+	mux.HandleFunc("POST /api/jobs/{job}/pause", s.handlePauseJob)
+	mux.HandleFunc("POST /api/jobs/{job}/resume", s.handleResumeJob)
+	mux.HandleFunc("POST /api/jobs/{job}/trigger", s.handleTriggerJob)
+	mux.HandleFunc("POST /api/jobs/{job}/dry-run", s.handleDryRun)
+	mux.HandleFunc("GET /api/jobs/{job}/runs", s.handleListRuns)
+	mux.HandleFunc("GET /api/jobs/{job}/capacity", s.handleGetCapacity)
+	mux.HandleFunc("POST /api/jobs/{job}/acknowledge", s.handleAcknowledge)
+	mux.HandleFunc("GET /api/jobs/{job}/manifests", s.handleListManifests)
+	mux.HandleFunc("POST /api/jobs/{job}/manifests/verify", s.handleVerifyManifests)
+	mux.HandleFunc("GET /api/jobs/{job}/scrub", s.handleGetScrub)
+	mux.HandleFunc("GET /api/runs/{id}", s.handleGetRun)
+	mux.HandleFunc("GET /api/runs/{id}/log", s.handleGetRunLog)
+	mux.HandleFunc("GET /api/runs/{id}/manifest", s.handleGetManifest)
+	mux.HandleFunc("GET /api/pipelines/{id}", s.handleGetPipeline)
+	mux.HandleFunc("GET /api/progress", s.handleListProgress)
+	mux.HandleFunc("GET /api/progress/stream", s.handleStreamProgress)
+	mux.HandleFunc("POST /api/pause", s.handlePauseRunner)
+	mux.HandleFunc("POST /api/resume", s.handleResumeRunner)
+	mux.HandleFunc("GET /api/config/reload", s.handleGetReload)
+	mux.HandleFunc("POST /api/config/reload", s.handleReload)
+	mux.Handle("GET /metrics", promhttp.HandlerFor(registry, promhttp.HandlerOpts{}))
+	mux.Handle("GET /", web.Handler())
+
+	return s.authenticate(mux)
+}
+
+// Run serves the API until ctx is cancelled.
+func (s *Server) Run(ctx context.Context) error {
+	server := &http.Server{
+		Addr:              s.addr,
+		Handler:           s.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	errCh := make(chan error, 1)
+	go func() { errCh <- server.ListenAndServe() }()
+
+	s.logger.Info("API listening", slog.String("addr", s.addr), slog.Bool("auth", s.authEnabled()))
+	if !s.authEnabled() && !loopback(s.addr) {
+		s.logger.Warn("API listening on a non-loopback address without authentication", slog.String("addr", s.addr))
+	}
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		return err
+	}
+	if err := <-errCh; !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+
+	return nil
+}
+
+// loopback reports whether addr only accepts local connections.
+func loopback(addr string) bool {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return false
+	}
+	if host == "localhost" {
+		return true
+	}
+
+	ip := net.ParseIP(host)
+
+	return ip != nil && ip.IsLoopback()
+}
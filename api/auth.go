@@ -0,0 +1,100 @@
+package api
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// authRealm is the realm of the basic auth challenge, prompting browsers for credentials.
+const authRealm = "backup-guardian"
+
+// WithBasicAuth requires requests to authenticate with the given basic auth credentials.
+func WithBasicAuth(username, password string) Option {
+	return func(s *Server) {
+		s.username = username
+		s.password = password
+	}
+}
+
+// WithBearerToken requires requests to authenticate with the given bearer token.
+// Combined with WithBasicAuth, either one is accepted.
+func WithBearerToken(token string) Option {
+	return func(s *Server) { s.token = token }
+}
+
+// authEnabled reports whether requests must authenticate.
+func (s *Server) authEnabled() bool {
+	return s.password != "" || s.token != ""
+}
+
+// authenticate wraps next to reject unauthenticated requests, and cross-origin requests
+// that change state, which browsers would otherwise send with the dashboard credentials.
+func (s *Server) authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !safeMethod(r.Method) && crossOrigin(r) {
+			writeJSON(w, http.StatusForbidden, &errorResponse{Code: "forbidden", Message: "Cross-origin request rejected"})
+			return
+		}
+
+		if s.authEnabled() && !s.authorized(r) {
+			if s.password != "" {
+				w.Header().Set("WWW-Authenticate", `Basic realm="`+authRealm+`", charset="UTF-8"`)
+			}
+			writeJSON(w, http.StatusUnauthorized, &errorResponse{Code: "unauthorized", Message: "Authentication required"})
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (s *Server) authorized(r *http.Request) bool {
+	if s.token != "" {
+		if token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok && equal(token, s.token) {
+			return true
+		}
+	}
+
+	if s.password != "" {
+		if username, password, ok := r.BasicAuth(); ok && equal(username, s.username) && equal(password, s.password) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// equal compares secrets in constant time.
+func equal(a, b string) bool {
+	return subtle.ConstantTimeCompare([]byte(a), []byte(b)) == 1
+}
+
+func safeMethod(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions
+}
+
+// crossOrigin reports whether r was sent by a browser from another origin than the server.
+// Requests without browser headers, e.g. from curl or bgctl, are not cross-origin.
+func crossOrigin(r *http.Request) bool {
+	switch r.Header.Get("Sec-Fetch-Site") {
+	case "same-origin", "none":
+		return false
+	case "":
+	default:
+		return true
+	}
+
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return false
+	}
+
+	u, err := url.Parse(origin)
+	if err != nil {
+		return true
+	}
+
+	return u.Host != r.Host
+}
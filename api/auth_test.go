@@ -0,0 +1,79 @@
+package api_test
+
+import (
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/eva01/backup-guardian/api"
+	"github.com/eva01/backup-guardian/domain"
+	domainmocks "github.com/eva01/backup-guardian/domain/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServer_Auth(t *testing.T) {
+	pausesMock := domainmocks.NewJobPausesReadWriter(t)
+	runsMock := domainmocks.NewSyncRunsReadWriter(t)
+	pausesMock.On("ListJobPauses").Return([]*domain.JobPause{}, nil)
+	runsMock.On("ListSyncRuns", mock.Anything).Return([]*domain.SyncRun{}, nil)
+	triggersMock := domainmocks.NewJobTriggersReadWriter(t)
+	triggersMock.On("ListJobTriggers").Return([]*domain.JobTrigger{}, nil)
+
+	server := newTriggerTestServer(t, pausesMock, runsMock, triggersMock,
+		api.WithBasicAuth("admin", "secret"), api.WithBearerToken("token"))
+
+	tests := map[string]struct {
+		setup func(r *http.Request)
+		want  int
+	}{
+		"anonymous":      {setup: func(r *http.Request) {}, want: http.StatusUnauthorized},
+		"basic":          {setup: func(r *http.Request) { r.SetBasicAuth("admin", "secret") }, want: http.StatusOK},
+		"wrong password": {setup: func(r *http.Request) { r.SetBasicAuth("admin", "guess") }, want: http.StatusUnauthorized},
+		"bearer":         {setup: func(r *http.Request) { r.Header.Set("Authorization", "Bearer token") }, want: http.StatusOK},
+		"wrong token":    {setup: func(r *http.Request) { r.Header.Set("Authorization", "Bearer guess") }, want: http.StatusUnauthorized},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodGet, server.URL+"/api/jobs", nil)
+			require.NoError(t, err)
+			tt.setup(req)
+
+			resp, err := http.DefaultClient.Do(req)
+			require.NoError(t, err)
+			defer resp.Body.Close()
+			assert.Equal(t, tt.want, resp.StatusCode)
+			if tt.want == http.StatusUnauthorized {
+				assert.Contains(t, resp.Header.Get("WWW-Authenticate"), "Basic")
+			}
+		})
+	}
+}
+
+func TestServer_CrossOrigin(t *testing.T) {
+	server := newTestServer(t, domainmocks.NewJobPausesReadWriter(t), domainmocks.NewSyncRunsReadWriter(t))
+
+	req, err := http.NewRequest(http.MethodPost, server.URL+"/api/pause", nil)
+	require.NoError(t, err)
+	req.Header.Set("Origin", "https://evil.example.com")
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+}
+
+func TestServer_Dashboard(t *testing.T) {
+	server := newTestServer(t, domainmocks.NewJobPausesReadWriter(t), domainmocks.NewSyncRunsReadWriter(t))
+
+	resp, err := http.Get(server.URL + "/")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Contains(t, string(body), `<script src="app.js">`)
+}
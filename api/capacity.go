@@ -0,0 +1,73 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/eva01/backup-guardian/domain"
+	"github.com/eva01/backup-guardian/internal/redact"
+)
+
+type capacityResponse struct {
+	Destination string `json:"destination"`
+	// Usage is the latest usage recorded for the destination, omitted when none was recorded.
+	Usage        *usageResponse `json:"usage,omitempty"`
+	GrowthPerDay float64        `json:"growth_per_day"`
+	Samples      int            `json:"samples"`
+	FullAt       *time.Time     `json:"full_at,omitempty"`
+}
+
+// usageResponse is the storage usage of a destination. Sizes are -1 when unknown.
+type usageResponse struct {
+	Destination string    `json:"destination"`
+	Total       int64     `json:"total"`
+	Used        int64     `json:"used"`
+	Free        int64     `json:"free"`
+	Required    int64     `json:"required,omitempty"`
+	CheckedAt   time.Time `json:"checked_at"`
+}
+
+// handleGetCapacity returns the forecast of when each destination of a job fills up.
+func (s *Server) handleGetCapacity(w http.ResponseWriter, r *http.Request) {
+	forecasts, err := s.service.Capacity(r.PathValue("job"))
+	if err != nil {
+		s.writeError(w, err)
+		return
+	}
+
+	result := make([]capacityResponse, len(forecasts))
+	for i, forecast := range forecasts {
+		result[i] = capacityResponse{
+			Destination:  redact.String(forecast.Destination),
+			GrowthPerDay: forecast.Growth,
+			Samples:      forecast.Samples,
+			FullAt:       timePtr(forecast.FullAt),
+		}
+		if forecast.Usage != nil {
+			usage := mapUsage(*forecast.Usage)
+			result[i].Usage = &usage
+		}
+	}
+
+	writeJSON(w, http.StatusOK, result)
+}
+
+func mapUsages(usages []domain.DestinationUsage) []usageResponse {
+	var response []usageResponse
+	for _, usage := range usages {
+		response = append(response, mapUsage(usage))
+	}
+
+	return response
+}
+
+func mapUsage(usage domain.DestinationUsage) usageResponse {
+	return usageResponse{
+		Destination: redact.String(usage.Destination),
+		Total:       usage.Total,
+		Used:        usage.Used,
+		Free:        usage.Free,
+		Required:    usage.Required,
+		CheckedAt:   usage.CheckedAt,
+	}
+}
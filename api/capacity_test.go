@@ -0,0 +1,43 @@
+package api_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/eva01/backup-guardian/domain"
+	domainmocks "github.com/eva01/backup-guardian/domain/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServer_GetCapacity(t *testing.T) {
+	checkedAt := time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC)
+	runsMock := domainmocks.NewSyncRunsReadWriter(t)
+	runsMock.On("ListSyncRuns", &domain.SyncRunsSelector{JobName: "test-job", Limit: 500}).
+		Return([]*domain.SyncRun{
+			{Usage: []domain.DestinationUsage{{Destination: "dest", Total: 1000, Used: 200, Free: 800, CheckedAt: checkedAt}}},
+			{Usage: []domain.DestinationUsage{{Destination: "dest", Total: 1000, Used: 100, Free: 900, CheckedAt: checkedAt.Add(-24 * time.Hour)}}},
+		}, nil).Once()
+
+	server := newTriggerTestServer(t, domainmocks.NewJobPausesReadWriter(t), runsMock, domainmocks.NewJobTriggersReadWriter(t))
+
+	resp, err := http.Get(server.URL + "/api/jobs/test-job/capacity")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var body []map[string]any
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	require.Len(t, body, 1)
+	assert.Equal(t, "dest", body[0]["destination"])
+	assert.InDelta(t, 100, body[0]["growth_per_day"], 0.001)
+	assert.Equal(t, "2026-01-10T00:00:00Z", body[0]["full_at"])
+	assert.Equal(t, float64(800), body[0]["usage"].(map[string]any)["free"])
+
+	resp, err = http.Get(server.URL + "/api/jobs/other-job/capacity")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
@@ -0,0 +1,381 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/eva01/backup-guardian/domain"
+	"github.com/eva01/backup-guardian/internal/errors"
+	"github.com/eva01/backup-guardian/internal/redact"
+	"github.com/eva01/backup-guardian/service"
+)
+
+type jobStatusResponse struct {
+	Name        string `json:"name"`
+	Source      string `json:"source"`
+	Destination string `json:"destination,omitempty"`
+	// Destinations and Require are set for jobs fanning out to several destinations.
+	Destinations []string          `json:"destinations,omitempty"`
+	Require      string            `json:"require,omitempty"`
+	Mode         string            `json:"mode"`
+	Interval     string            `json:"interval,omitempty"`
+	CatchUp      string            `json:"catch_up"`
+	Paused       bool              `json:"paused"`
+	Pause        *jobPauseResponse `json:"pause,omitempty"`
+	LastRun      *syncRunResponse  `json:"last_run,omitempty"`
+	LastRunAt    *time.Time        `json:"last_run_at,omitempty"`
+	NextDueAt    *time.Time        `json:"next_due_at,omitempty"`
+	RPO          *rpoResponse      `json:"rpo,omitempty"`
+	TriggeredAt  *time.Time        `json:"triggered_at,omitempty"`
+	Progress     *progressResponse `json:"progress,omitempty"`
+	// Anomaly is the anomalous run of the job awaiting acknowledgement.
+	Anomaly *jobAnomalyResponse `json:"anomaly,omitempty"`
+
+	// After lists the dependencies of jobs run after other jobs rather than on an interval.
+	After []dependencyResponse `json:"after,omitempty"`
+	// Encrypted is set for jobs encrypting their destinations with rclone crypt.
+	Encrypted bool `json:"encrypted,omitempty"`
+	// Scrubs is the job a scrub job scrubs.
+	Scrubs string `json:"scrubs,omitempty"`
+}
+
+type rpoResponse struct {
+	RPO           string     `json:"rpo"`
+	Violated      bool       `json:"violated"`
+	LastSuccessAt *time.Time `json:"last_success_at,omitempty"`
+	DeadlineAt    time.Time  `json:"deadline_at"`
+}
+
+type jobPauseResponse struct {
+	Scope    string     `json:"scope"`
+	Reason   string     `json:"reason,omitempty"`
+	PausedAt time.Time  `json:"paused_at"`
+	ResumeAt *time.Time `json:"resume_at,omitempty"`
+}
+
+type syncRunResponse struct {
+	ID               string                `json:"id"`
+	JobName          string                `json:"job_name"`
+	Mode             string                `json:"mode"`
+	Status           string                `json:"status"`
+	StartedAt        *time.Time            `json:"started_at,omitempty"`
+	FinishedAt       *time.Time            `json:"finished_at,omitempty"`
+	ErrorMessage     string                `json:"error_message,omitempty"`
+	FilesTransferred int64                 `json:"files_transferred"`
+	BytesTransferred int64                 `json:"bytes_transferred"`
+	UploadLimit      int64                 `json:"upload_limit,omitempty"`
+	DownloadLimit    int64                 `json:"download_limit,omitempty"`
+	FilesTotal       int64                 `json:"files_total,omitempty"`
+	BytesTotal       int64                 `json:"bytes_total,omitempty"`
+	Filters          *filtersResponse      `json:"filters,omitempty"`
+	Hooks            []hookResultResponse  `json:"hooks,omitempty"`
+	Destinations     []destinationResponse `json:"destinations,omitempty"`
+	PipelineID       string                `json:"pipeline_id,omitempty"`
+	Kind             string                `json:"kind"`
+	Plan             *planResponse         `json:"plan,omitempty"`
+
+	// Usage is the storage usage of the destinations at the end of the run.
+	Usage []usageResponse `json:"usage,omitempty"`
+	// Changes counts the changes made by the run, for jobs with anomaly detection.
+	Changes   *changesResponse `json:"changes,omitempty"`
+	Anomalous bool             `json:"anomalous"`
+	Anomaly   *anomalyResponse `json:"anomaly,omitempty"`
+	// Scrub is the outcome of the runs of scrub jobs.
+	Scrub *scrubResponse `json:"scrub,omitempty"`
+}
+
+type planResponse struct {
+	Copies      int64                   `json:"copies"`
+	CopyBytes   int64                   `json:"copy_bytes"`
+	Updates     int64                   `json:"updates"`
+	UpdateBytes int64                   `json:"update_bytes"`
+	Deletes     int64                   `json:"deletes"`
+	DeleteBytes int64                   `json:"delete_bytes"`
+	Changes     []plannedChangeResponse `json:"changes"`
+	Truncated   bool                    `json:"truncated"`
+}
+
+type plannedChangeResponse struct {
+	Action      string `json:"action"`
+	Path        string `json:"path"`
+	Size        int64  `json:"size"`
+	Destination string `json:"destination,omitempty"`
+}
+
+type destinationResponse struct {
+	Destination      string  `json:"destination"`
+	Status           string  `json:"status"`
+	Error            string  `json:"error,omitempty"`
+	FilesTransferred int64   `json:"files_transferred"`
+	BytesTransferred int64   `json:"bytes_transferred"`
+	DurationSeconds  float64 `json:"duration_seconds"`
+}
+
+type filtersResponse struct {
+	Include          []string `json:"include,omitempty"`
+	Exclude          []string `json:"exclude,omitempty"`
+	Rules            []string `json:"rules,omitempty"`
+	FilterFrom       []string `json:"filter_from,omitempty"`
+	ExcludeIfPresent []string `json:"exclude_if_present,omitempty"`
+	MinSize          int64    `json:"min_size,omitempty"`
+	MaxSize          int64    `json:"max_size,omitempty"`
+	MaxAge           string   `json:"max_age,omitempty"`
+}
+
+type hookResultResponse struct {
+	Name            string  `json:"name"`
+	Event           string  `json:"event"`
+	Output          string  `json:"output,omitempty"`
+	Error           string  `json:"error,omitempty"`
+	DurationSeconds float64 `json:"duration_seconds"`
+}
+
+// pauseRequest is the body of pause requests. Until is either a duration (e.g. "48h")
+// or an RFC 3339 timestamp; empty means paused until resumed.
+type pauseRequest struct {
+	Reason string `json:"reason"`
+	Until  string `json:"until"`
+}
+
+func (s *Server) handleListJobs(w http.ResponseWriter, r *http.Request) {
+	statuses, err := s.service.JobStatuses()
+	if err != nil {
+		s.writeError(w, err)
+		return
+	}
+
+	result := make([]*jobStatusResponse, len(statuses))
+	for i, status := range statuses {
+		result[i] = mapJobStatus(status)
+	}
+
+	writeJSON(w, http.StatusOK, result)
+}
+
+func (s *Server) handlePauseJob(w http.ResponseWriter, r *http.Request) {
+	s.pause(w, r, r.PathValue("job"))
+}
+
+func (s *Server) handleResumeJob(w http.ResponseWriter, r *http.Request) {
+	s.resume(w, r.PathValue("job"))
+}
+
+func (s *Server) handlePauseRunner(w http.ResponseWriter, r *http.Request) {
+	s.pause(w, r, domain.PauseScopeAll)
+}
+
+func (s *Server) handleResumeRunner(w http.ResponseWriter, r *http.Request) {
+	s.resume(w, domain.PauseScopeAll)
+}
+
+func (s *Server) pause(w http.ResponseWriter, r *http.Request, scope string) {
+	var body pauseRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			s.writeError(w, &errors.Error{Code: errors.CodeInvalid, Message: "Invalid JSON body", UnderlyingError: err})
+			return
+		}
+	}
+
+	resumeAt, err := service.ParseResumeAt(body.Until, time.Now())
+	if err != nil {
+		s.writeError(w, err)
+		return
+	}
+
+	pause, err := s.service.Pause(scope, body.Reason, resumeAt)
+	if err != nil {
+		s.writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, mapJobPause(pause))
+}
+
+func (s *Server) resume(w http.ResponseWriter, scope string) {
+	if err := s.service.Resume(scope); err != nil {
+		s.writeError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func mapJobStatus(status *service.JobStatus) *jobStatusResponse {
+	result := &jobStatusResponse{
+		Name:        status.Job.Name,
+		Source:      redact.String(status.Job.Source),
+		Destination: redact.String(status.Job.Destination),
+		Mode:        status.Job.SyncMode(),
+		CatchUp:     status.Job.CatchUpPolicy(),
+		Paused:      status.Paused(),
+	}
+
+	if status.Job.FanOut() {
+		result.Destinations = redact.Strings(status.Job.AllDestinations())
+		result.Require = status.Job.RequirePolicy()
+	}
+	result.After = mapDependencies(status.Job.After)
+	result.Encrypted = status.Job.Encryption != nil
+	if status.Job.Scrub != nil {
+		result.Scrubs = status.Job.Scrub.Job
+	}
+	if status.Job.Interval > 0 {
+		result.Interval = status.Job.Interval.String()
+	}
+
+	if status.Pause != nil {
+		result.Pause = mapJobPause(status.Pause)
+	}
+	if status.LastRun != nil {
+		result.LastRun = mapSyncRun(status.LastRun)
+	}
+	if status.Schedule != nil {
+		result.LastRunAt = timePtr(status.Schedule.LastRunAt)
+		result.NextDueAt = timePtr(status.Schedule.NextDueAt)
+	}
+	if status.Progress != nil {
+		result.Progress = mapProgress(status.Progress)
+	}
+	if status.Trigger != nil {
+		result.TriggeredAt = timePtr(status.Trigger.RequestedAt)
+	}
+	result.Anomaly = mapJobAnomaly(status.Anomaly)
+	if status.RPO != nil {
+		result.RPO = &rpoResponse{
+			RPO:           status.RPO.RPO.String(),
+			Violated:      status.RPO.Violated,
+			LastSuccessAt: timePtr(status.RPO.LastSuccessAt),
+			DeadlineAt:    status.RPO.DeadlineAt,
+		}
+	}
+
+	return result
+}
+
+func mapJobPause(pause *domain.JobPause) *jobPauseResponse {
+	return &jobPauseResponse{
+		Scope:    pause.Scope,
+		Reason:   pause.Reason,
+		PausedAt: pause.PausedAt,
+		ResumeAt: timePtr(pause.ResumeAt),
+	}
+}
+
+func mapSyncRun(run *domain.SyncRun) *syncRunResponse {
+	return &syncRunResponse{
+		ID:               run.ID,
+		JobName:          run.JobName,
+		Mode:             run.Mode,
+		Status:           run.Status,
+		StartedAt:        timePtr(run.StartedAt),
+		FinishedAt:       timePtr(run.FinishedAt),
+		ErrorMessage:     redact.String(run.ErrorMessage),
+		FilesTransferred: run.FilesTransferred,
+		BytesTransferred: run.BytesTransferred,
+		UploadLimit:      run.UploadLimit,
+		DownloadLimit:    run.DownloadLimit,
+		FilesTotal:       run.FilesTotal,
+		BytesTotal:       run.BytesTotal,
+		Filters:          mapFilters(run.Filters),
+		Hooks:            mapHookResults(run.Hooks),
+		Destinations:     mapDestinationResults(run.Destinations),
+		PipelineID:       run.PipelineID,
+		Kind:             run.Kind,
+		Plan:             mapPlan(run.Plan),
+		Usage:            mapUsages(run.Usage),
+		Changes:          mapChanges(run.Changes),
+		Anomalous:        run.Anomalous(),
+		Anomaly:          mapAnomaly(run.Anomaly),
+		Scrub:            mapScrub(run.Scrub),
+	}
+}
+
+func mapDestinationResults(results []domain.DestinationResult) []destinationResponse {
+	var response []destinationResponse
+	for _, result := range results {
+		response = append(response, destinationResponse{
+			Destination:      redact.String(result.Destination),
+			Status:           result.Status,
+			Error:            redact.String(result.Error),
+			FilesTransferred: result.FilesTransferred,
+			BytesTransferred: result.BytesTransferred,
+			DurationSeconds:  result.Duration.Seconds(),
+		})
+	}
+
+	return response
+}
+
+func mapPlan(plan *domain.SyncPlan) *planResponse {
+	if plan == nil {
+		return nil
+	}
+
+	result := &planResponse{
+		Copies:      plan.Copies,
+		CopyBytes:   plan.CopyBytes,
+		Updates:     plan.Updates,
+		UpdateBytes: plan.UpdateBytes,
+		Deletes:     plan.Deletes,
+		DeleteBytes: plan.DeleteBytes,
+		Changes:     make([]plannedChangeResponse, len(plan.Changes)),
+		Truncated:   plan.Truncated,
+	}
+	for i, change := range plan.Changes {
+		result.Changes[i] = plannedChangeResponse{
+			Action:      change.Action,
+			Path:        change.Path,
+			Size:        change.Size,
+			Destination: redact.String(change.Destination),
+		}
+	}
+
+	return result
+}
+
+func mapFilters(filters *domain.Filters) *filtersResponse {
+	if filters == nil {
+		return nil
+	}
+
+	response := &filtersResponse{
+		Include:          filters.Include,
+		Exclude:          filters.Exclude,
+		Rules:            filters.Rules,
+		FilterFrom:       filters.FilterFrom,
+		ExcludeIfPresent: filters.ExcludeIfPresent,
+		MinSize:          filters.MinSize,
+		MaxSize:          filters.MaxSize,
+	}
+	if filters.MaxAge > 0 {
+		response.MaxAge = filters.MaxAge.String()
+	}
+
+	return response
+}
+
+func mapHookResults(results []domain.HookResult) []hookResultResponse {
+	var response []hookResultResponse
+	for _, result := range results {
+		response = append(response, hookResultResponse{
+			Name:            result.Name,
+			Event:           result.Event,
+			Output:          redact.String(result.Output),
+			Error:           redact.String(result.Error),
+			DurationSeconds: result.Duration.Seconds(),
+		})
+	}
+
+	return response
+}
+
+// timePtr returns nil for the zero time, so that unset times are omitted from responses.
+func timePtr(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+
+	return &t
+}
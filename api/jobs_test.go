@@ -0,0 +1,105 @@
+package api_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/eva01/backup-guardian/api"
+	"github.com/eva01/backup-guardian/domain"
+	domainmocks "github.com/eva01/backup-guardian/domain/mocks"
+	"github.com/eva01/backup-guardian/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestServer(t *testing.T, pausesMock *domainmocks.JobPausesReadWriter, runsMock *domainmocks.SyncRunsReadWriter) *httptest.Server {
+	svc := service.New(
+		service.WithJobPauses(pausesMock),
+		service.WithSyncRuns(runsMock),
+		service.WithJobs(&domain.SyncJob{Name: "test-job", Source: "source", Destination: "dest"}),
+	)
+
+	server := httptest.NewServer(api.New(api.WithService(svc)).Handler())
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func TestServer_ListJobs(t *testing.T) {
+	pausesMock := domainmocks.NewJobPausesReadWriter(t)
+	runsMock := domainmocks.NewSyncRunsReadWriter(t)
+	pausesMock.On("ListJobPauses").Return([]*domain.JobPause{{Scope: "test-job", Reason: "migration"}}, nil)
+	runsMock.On("ListSyncRuns", mock.Anything).Return([]*domain.SyncRun{}, nil)
+
+	server := newTestServer(t, pausesMock, runsMock)
+
+	resp, err := http.Get(server.URL + "/api/jobs")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var body []map[string]any
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	require.Len(t, body, 1)
+	assert.Equal(t, "test-job", body[0]["name"])
+	assert.Equal(t, true, body[0]["paused"])
+	assert.Equal(t, "migration", body[0]["pause"].(map[string]any)["reason"])
+}
+
+func TestServer_PauseJob(t *testing.T) {
+	t.Run("paused", func(t *testing.T) {
+		pausesMock := domainmocks.NewJobPausesReadWriter(t)
+		pausesMock.On("UpsertJobPause", mock.MatchedBy(func(p *domain.JobPause) bool {
+			return p.Scope == "test-job" && p.Reason == "migration" && !p.ResumeAt.IsZero()
+		})).Return(func(p *domain.JobPause) (*domain.JobPause, error) { return p, nil }).Once()
+
+		server := newTestServer(t, pausesMock, domainmocks.NewSyncRunsReadWriter(t))
+
+		resp, err := http.Post(server.URL+"/api/jobs/test-job/pause", "application/json",
+			strings.NewReader(`{"reason": "migration", "until": "2h"}`))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+	})
+
+	t.Run("unknown job", func(t *testing.T) {
+		server := newTestServer(t, domainmocks.NewJobPausesReadWriter(t), domainmocks.NewSyncRunsReadWriter(t))
+
+		resp, err := http.Post(server.URL+"/api/jobs/other-job/pause", "application/json", nil)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+
+	t.Run("invalid until", func(t *testing.T) {
+		server := newTestServer(t, domainmocks.NewJobPausesReadWriter(t), domainmocks.NewSyncRunsReadWriter(t))
+
+		resp, err := http.Post(server.URL+"/api/pause", "application/json", strings.NewReader(`{"until": "tomorrow"}`))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+}
+
+func TestServer_Metrics(t *testing.T) {
+	pausesMock := domainmocks.NewJobPausesReadWriter(t)
+	runsMock := domainmocks.NewSyncRunsReadWriter(t)
+	pausesMock.On("ListJobPauses").Return([]*domain.JobPause{{Scope: domain.PauseScopeAll, Reason: "maintenance"}}, nil)
+	runsMock.On("ListSyncRuns", mock.Anything).Return([]*domain.SyncRun{}, nil)
+
+	server := newTestServer(t, pausesMock, runsMock)
+
+	resp, err := http.Get(server.URL + "/metrics")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	assert.Contains(t, string(body), `backup_guardian_job_paused{job="test-job"} 1`)
+	assert.Contains(t, string(body), `backup_guardian_job_pause_info{job="test-job",reason="maintenance",scope="*"} 1`)
+}
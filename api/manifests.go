@@ -0,0 +1,121 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/eva01/backup-guardian/domain"
+	"github.com/eva01/backup-guardian/internal/redact"
+)
+
+type manifestResponse struct {
+	RunID        string                        `json:"run_id"`
+	JobName      string                        `json:"job_name"`
+	Sequence     int64                         `json:"sequence"`
+	CreatedAt    time.Time                     `json:"created_at"`
+	PreviousHash string                        `json:"previous_hash"`
+	Hash         string                        `json:"hash"`
+	Files        int64                         `json:"files"`
+	Bytes        int64                         `json:"bytes"`
+	Destinations []manifestDestinationResponse `json:"destinations"`
+}
+
+// manifestDestinationResponse is a destination of a manifest. Entries are omitted from the
+// summaries of manifests.
+type manifestDestinationResponse struct {
+	Destination string                 `json:"destination"`
+	Files       int64                  `json:"files"`
+	Bytes       int64                  `json:"bytes"`
+	Entries     []manifestFileResponse `json:"entries,omitempty"`
+}
+
+type manifestFileResponse struct {
+	Path    string    `json:"path"`
+	Size    int64     `json:"size"`
+	Hash    string    `json:"hash"`
+	ModTime time.Time `json:"mod_time,omitzero"`
+}
+
+type manifestVerificationResponse struct {
+	JobName   string   `json:"job_name"`
+	Manifests int      `json:"manifests"`
+	Copies    int      `json:"copies"`
+	OK        bool     `json:"ok"`
+	Problems  []string `json:"problems"`
+}
+
+// handleListManifests returns the summaries of the manifests of a job, sorted by sequence.
+func (s *Server) handleListManifests(w http.ResponseWriter, r *http.Request) {
+	manifests, err := s.service.Manifests(r.PathValue("job"))
+	if err != nil {
+		s.writeError(w, err)
+		return
+	}
+
+	result := make([]*manifestResponse, len(manifests))
+	for i, manifest := range manifests {
+		result[i] = mapManifest(manifest)
+	}
+
+	writeJSON(w, http.StatusOK, result)
+}
+
+// handleGetManifest returns the manifest of a run, with the files of its destinations.
+func (s *Server) handleGetManifest(w http.ResponseWriter, r *http.Request) {
+	manifest, err := s.service.Manifest(r.PathValue("id"))
+	if err != nil {
+		s.writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, mapManifest(manifest))
+}
+
+// handleVerifyManifests verifies the chain of the manifests of a job and their copies on its
+// destinations. Problems found are reported in the response, with a 200 status.
+func (s *Server) handleVerifyManifests(w http.ResponseWriter, r *http.Request) {
+	verification, err := s.service.VerifyManifests(r.Context(), r.PathValue("job"))
+	if err != nil {
+		s.writeError(w, err)
+		return
+	}
+
+	problems := make([]string, len(verification.Problems))
+	for i, problem := range verification.Problems {
+		problems[i] = redact.String(problem)
+	}
+
+	writeJSON(w, http.StatusOK, &manifestVerificationResponse{
+		JobName:   verification.JobName,
+		Manifests: verification.Manifests,
+		Copies:    verification.Copies,
+		OK:        verification.OK(),
+		Problems:  problems,
+	})
+}
+
+func mapManifest(manifest *domain.Manifest) *manifestResponse {
+	destinations := make([]manifestDestinationResponse, len(manifest.Destinations))
+	for i, dest := range manifest.Destinations {
+		destinations[i] = manifestDestinationResponse{
+			Destination: redact.String(dest.Destination),
+			Files:       dest.Files,
+			Bytes:       dest.Bytes,
+		}
+		for _, file := range dest.Entries {
+			destinations[i].Entries = append(destinations[i].Entries, manifestFileResponse(file))
+		}
+	}
+
+	return &manifestResponse{
+		RunID:        manifest.RunID,
+		JobName:      manifest.JobName,
+		Sequence:     manifest.Sequence,
+		CreatedAt:    manifest.CreatedAt,
+		PreviousHash: manifest.PreviousHash,
+		Hash:         manifest.Hash,
+		Files:        manifest.Files,
+		Bytes:        manifest.Bytes,
+		Destinations: destinations,
+	}
+}
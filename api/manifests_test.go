@@ -0,0 +1,79 @@
+package api_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/eva01/backup-guardian/api"
+	"github.com/eva01/backup-guardian/domain"
+	domainmocks "github.com/eva01/backup-guardian/domain/mocks"
+	"github.com/eva01/backup-guardian/internal/errors"
+	"github.com/eva01/backup-guardian/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type manifestVerifierFunc func(ctx context.Context, job *domain.SyncJob) (*domain.ManifestVerification, error)
+
+func (f manifestVerifierFunc) VerifyManifests(ctx context.Context, job *domain.SyncJob) (*domain.ManifestVerification, error) {
+	return f(ctx, job)
+}
+
+func TestServer_Manifests(t *testing.T) {
+	manifest := domain.NewManifest(&domain.SyncRun{ID: "run-1", JobName: "test-job"}, nil,
+		[]domain.ManifestDestination{{Destination: "dest", Entries: []domain.ManifestFile{{Path: "a.txt", Size: 3, Hash: "sha256:abc"}}}},
+		time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC))
+	summary := *manifest
+	summary.Destinations = []domain.ManifestDestination{{Destination: "dest", Files: 1, Bytes: 3}}
+
+	manifestsMock := domainmocks.NewManifestsReadWriter(t)
+	manifestsMock.On("ListManifests", &domain.ManifestsSelector{JobName: "test-job"}).Return([]*domain.Manifest{&summary}, nil).Once()
+	manifestsMock.On("GetManifest", &domain.ManifestSelector{RunID: "run-1"}).Return(manifest, nil).Once()
+	manifestsMock.On("GetManifest", &domain.ManifestSelector{RunID: "missing"}).Return(nil, &errors.Error{Code: errors.CodeNotFound}).Once()
+	verifier := manifestVerifierFunc(func(_ context.Context, job *domain.SyncJob) (*domain.ManifestVerification, error) {
+		return &domain.ManifestVerification{JobName: job.Name, Manifests: 1, Problems: []string{"manifest 1 (run run-1) is missing from dest"}}, nil
+	})
+
+	svc := service.New(service.WithManifests(manifestsMock), service.WithManifestVerifier(verifier),
+		service.WithJobs(&domain.SyncJob{Name: "test-job", Source: "source", Destination: "dest", Manifest: true}))
+	server := httptest.NewServer(api.New(api.WithService(svc)).Handler())
+	t.Cleanup(server.Close)
+
+	resp, err := http.Get(server.URL + "/api/jobs/test-job/manifests")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var list []map[string]any
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&list))
+	require.Len(t, list, 1)
+	assert.Equal(t, manifest.Hash, list[0]["hash"])
+	assert.Equal(t, []any{map[string]any{"destination": "dest", "files": float64(1), "bytes": float64(3)}}, list[0]["destinations"])
+
+	resp, err = http.Get(server.URL + "/api/runs/run-1/manifest")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var body map[string]any
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Equal(t, float64(1), body["sequence"])
+	assert.Equal(t, []any{map[string]any{"path": "a.txt", "size": float64(3), "hash": "sha256:abc"}},
+		body["destinations"].([]any)[0].(map[string]any)["entries"])
+
+	resp, err = http.Get(server.URL + "/api/runs/missing/manifest")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	resp, err = http.Post(server.URL+"/api/jobs/test-job/manifests/verify", "application/json", nil)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	body = nil
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Equal(t, false, body["ok"])
+	assert.Equal(t, []any{"manifest 1 (run run-1) is missing from dest"}, body["problems"])
+}
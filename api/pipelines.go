@@ -0,0 +1,61 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/eva01/backup-guardian/domain"
+)
+
+type pipelineResponse struct {
+	ID    string                  `json:"id"`
+	Steps []*pipelineStepResponse `json:"steps"`
+}
+
+type pipelineStepResponse struct {
+	Job          string               `json:"job"`
+	After        []dependencyResponse `json:"after,omitempty"`
+	State        string               `json:"state"`
+	Dependencies string               `json:"dependencies,omitempty"`
+	Run          *syncRunResponse     `json:"run,omitempty"`
+}
+
+type dependencyResponse struct {
+	Job string `json:"job"`
+	On  string `json:"on"`
+}
+
+func (s *Server) handleGetPipeline(w http.ResponseWriter, r *http.Request) {
+	pipeline, err := s.service.Pipeline(r.PathValue("id"))
+	if err != nil {
+		s.writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, mapPipeline(pipeline))
+}
+
+func mapPipeline(pipeline *domain.Pipeline) *pipelineResponse {
+	result := &pipelineResponse{ID: pipeline.ID, Steps: make([]*pipelineStepResponse, len(pipeline.Steps))}
+	for i, step := range pipeline.Steps {
+		result.Steps[i] = &pipelineStepResponse{
+			Job:          step.Job.Name,
+			After:        mapDependencies(step.Job.After),
+			State:        step.State,
+			Dependencies: step.Dependencies,
+		}
+		if step.Run != nil {
+			result.Steps[i].Run = mapSyncRun(step.Run)
+		}
+	}
+
+	return result
+}
+
+func mapDependencies(deps []*domain.Dependency) []dependencyResponse {
+	var response []dependencyResponse
+	for _, dep := range deps {
+		response = append(response, dependencyResponse{Job: dep.Job, On: dep.Condition()})
+	}
+
+	return response
+}
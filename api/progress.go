@@ -0,0 +1,154 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/eva01/backup-guardian/domain"
+)
+
+// keepAliveInterval is how often a comment is sent on idle progress streams, so that proxies
+// do not close them.
+const keepAliveInterval = 15 * time.Second
+
+type progressResponse struct {
+	RunID      string                 `json:"run_id"`
+	JobName    string                 `json:"job_name"`
+	StartedAt  time.Time              `json:"started_at"`
+	UpdatedAt  time.Time              `json:"updated_at"`
+	BytesDone  int64                  `json:"bytes_done"`
+	BytesTotal int64                  `json:"bytes_total"`
+	FilesDone  int64                  `json:"files_done"`
+	FilesTotal int64                  `json:"files_total"`
+	Percent    float64                `json:"percent"`
+	Speed      float64                `json:"speed"`
+	ETASeconds float64                `json:"eta_seconds,omitempty"`
+	Transfers  []fileTransferResponse `json:"transfers,omitempty"`
+	Done       bool                   `json:"done,omitempty"`
+}
+
+type fileTransferResponse struct {
+	Name  string  `json:"name"`
+	Size  int64   `json:"size"`
+	Bytes int64   `json:"bytes"`
+	Speed float64 `json:"speed"`
+}
+
+func (s *Server) handleListProgress(w http.ResponseWriter, r *http.Request) {
+	list := s.service.Progress()
+
+	result := make([]*progressResponse, len(list))
+	for i, progress := range list {
+		result[i] = mapProgress(progress)
+	}
+
+	writeJSON(w, http.StatusOK, result)
+}
+
+// handleStreamProgress streams the progress of running syncs as server-sent events: a
+// "progress" event per snapshot, starting with the latest snapshot of every running sync,
+// and a "done" event when a run finishes. The job query parameter filters a single job.
+func (s *Server) handleStreamProgress(w http.ResponseWriter, r *http.Request) {
+	job := r.URL.Query().Get("job")
+	if job != "" {
+		if _, err := s.service.Job(job); err != nil {
+			s.writeError(w, err)
+			return
+		}
+	}
+
+	ch, unsubscribe, err := s.service.SubscribeProgress()
+	if err != nil {
+		s.writeError(w, err)
+		return
+	}
+	defer unsubscribe()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+
+	rc := http.NewResponseController(w)
+	send := func(progress *domain.Progress) error {
+		if job != "" && progress.JobName != job {
+			return nil
+		}
+
+		event := "progress"
+		if progress.Done {
+			event = "done"
+		}
+		data, err := json.Marshal(mapProgress(progress))
+		if err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, data); err != nil {
+			return err
+		}
+
+		return rc.Flush()
+	}
+
+	for _, progress := range s.service.Progress() {
+		if err := send(progress); err != nil {
+			return
+		}
+	}
+	if err := rc.Flush(); err != nil {
+		return
+	}
+
+	keepAlive := time.NewTicker(keepAliveInterval)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case progress, ok := <-ch:
+			if !ok {
+				return
+			}
+			if err := send(progress); err != nil {
+				return
+			}
+		case <-keepAlive.C:
+			if _, err := fmt.Fprint(w, ": keep-alive\n\n"); err != nil {
+				return
+			}
+			if err := rc.Flush(); err != nil {
+				return
+			}
+		}
+	}
+}
+
+func mapProgress(progress *domain.Progress) *progressResponse {
+	result := &progressResponse{
+		RunID:      progress.RunID,
+		JobName:    progress.JobName,
+		StartedAt:  progress.StartedAt,
+		UpdatedAt:  progress.UpdatedAt,
+		BytesDone:  progress.BytesDone,
+		BytesTotal: progress.BytesTotal,
+		FilesDone:  progress.FilesDone,
+		FilesTotal: progress.FilesTotal,
+		Percent:    progress.Percent(),
+		Speed:      progress.Speed,
+		ETASeconds: progress.ETA.Seconds(),
+		Done:       progress.Done,
+	}
+
+	for _, transfer := range progress.Transfers {
+		result.Transfers = append(result.Transfers, fileTransferResponse{
+			Name:  transfer.Name,
+			Size:  transfer.Size,
+			Bytes: transfer.Bytes,
+			Speed: transfer.Speed,
+		})
+	}
+
+	return result
+}
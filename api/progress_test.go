@@ -0,0 +1,84 @@
+package api_test
+
+import (
+	"bufio"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/eva01/backup-guardian/api"
+	"github.com/eva01/backup-guardian/domain"
+	"github.com/eva01/backup-guardian/progress"
+	"github.com/eva01/backup-guardian/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServer_StreamProgress(t *testing.T) {
+	tracker := progress.NewTracker()
+	tracker.Update(&domain.Progress{RunID: "run-1", JobName: "test-job", BytesDone: 25, BytesTotal: 100})
+
+	svc := service.New(
+		service.WithProgress(tracker),
+		service.WithJobs(&domain.SyncJob{Name: "test-job"}, &domain.SyncJob{Name: "other-job"}),
+	)
+	server := httptest.NewServer(api.New(api.WithService(svc)).Handler())
+	t.Cleanup(server.Close)
+
+	resp, err := http.Get(server.URL + "/api/progress/stream?job=test-job")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+	events := make(chan string, 8)
+	go func() {
+		scanner := bufio.NewScanner(resp.Body)
+		var event string
+		for scanner.Scan() {
+			line := scanner.Text()
+			switch {
+			case strings.HasPrefix(line, "event: "):
+				event = strings.TrimPrefix(line, "event: ")
+			case strings.HasPrefix(line, "data: "):
+				events <- event + " " + strings.TrimPrefix(line, "data: ")
+			}
+		}
+		close(events)
+	}()
+
+	next := func() string {
+		select {
+		case event := <-events:
+			return event
+		case <-time.After(5 * time.Second):
+			t.Fatal("no event received")
+			return ""
+		}
+	}
+
+	// The latest snapshot is sent on connect.
+	assert.Contains(t, next(), `progress {"run_id":"run-1","job_name":"test-job"`)
+
+	tracker.Update(&domain.Progress{RunID: "run-2", JobName: "other-job"})
+	tracker.Update(&domain.Progress{RunID: "run-1", JobName: "test-job", BytesDone: 50, BytesTotal: 100})
+	event := next()
+	assert.Contains(t, event, `"bytes_done":50`)
+	assert.Contains(t, event, `"percent":50`)
+
+	tracker.Done(&domain.Progress{RunID: "run-1", JobName: "test-job", BytesDone: 100, BytesTotal: 100})
+	assert.Contains(t, next(), `done {"run_id":"run-1"`)
+}
+
+func TestServer_StreamProgress_Unavailable(t *testing.T) {
+	svc := service.New(service.WithJobs(&domain.SyncJob{Name: "test-job"}))
+	server := httptest.NewServer(api.New(api.WithService(svc)).Handler())
+	t.Cleanup(server.Close)
+
+	resp, err := http.Get(server.URL + "/api/progress/stream")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
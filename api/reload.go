@@ -0,0 +1,62 @@
+package api
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/eva01/backup-guardian/domain"
+	"github.com/eva01/backup-guardian/internal/errors"
+)
+
+type configReloadResponse struct {
+	Trigger    string             `json:"trigger"`
+	Status     string             `json:"status"`
+	ReloadedAt time.Time          `json:"reloaded_at"`
+	Error      string             `json:"error,omitempty"`
+	Jobs       configDiffResponse `json:"jobs"`
+	Remotes    configDiffResponse `json:"remotes"`
+}
+
+type configDiffResponse struct {
+	Added   []string `json:"added,omitempty"`
+	Removed []string `json:"removed,omitempty"`
+	Changed []string `json:"changed,omitempty"`
+}
+
+func (s *Server) handleGetReload(w http.ResponseWriter, _ *http.Request) {
+	reload := s.service.LastReload()
+	if reload == nil {
+		s.writeError(w, &errors.Error{Code: errors.CodeNotFound, Message: "The configuration was not reloaded"})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, mapConfigReload(reload))
+}
+
+// handleReload reloads the configuration, as SIGHUP does, and returns the outcome.
+func (s *Server) handleReload(w http.ResponseWriter, _ *http.Request) {
+	reload, err := s.service.Reload(domain.ReloadTriggerAPI)
+	if err != nil {
+		if reload != nil {
+			s.logger.Error("Configuration reload failed, keeping the running configuration",
+				slog.String("trigger", reload.Trigger), slog.String("error", reload.Error))
+		}
+		s.writeError(w, err)
+		return
+	}
+
+	s.logger.Info("Configuration reloaded", slog.String("trigger", reload.Trigger), slog.String("status", reload.Status))
+	writeJSON(w, http.StatusOK, mapConfigReload(reload))
+}
+
+func mapConfigReload(reload *domain.ConfigReload) *configReloadResponse {
+	return &configReloadResponse{
+		Trigger:    reload.Trigger,
+		Status:     reload.Status,
+		ReloadedAt: reload.ReloadedAt,
+		Error:      reload.Error,
+		Jobs:       configDiffResponse(reload.Jobs),
+		Remotes:    configDiffResponse(reload.Remotes),
+	}
+}
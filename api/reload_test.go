@@ -0,0 +1,81 @@
+package api_test
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/eva01/backup-guardian/api"
+	"github.com/eva01/backup-guardian/domain"
+	domainmocks "github.com/eva01/backup-guardian/domain/mocks"
+	"github.com/eva01/backup-guardian/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type reloaderFunc func(jobs []*domain.SyncJob) error
+
+func (f reloaderFunc) Reload(jobs []*domain.SyncJob) error { return f(jobs) }
+
+func TestServer_Reload(t *testing.T) {
+	pausesMock := domainmocks.NewJobPausesReadWriter(t)
+	runsMock := domainmocks.NewSyncRunsReadWriter(t)
+	pausesMock.On("ListJobPauses").Return([]*domain.JobPause{}, nil)
+	runsMock.On("ListSyncRuns", mock.Anything).Return([]*domain.SyncRun{}, nil)
+
+	job := &domain.SyncJob{Name: "test-job", Source: "a", Destination: "b"}
+	var loadErr error
+	svc := service.New(
+		service.WithJobPauses(pausesMock),
+		service.WithSyncRuns(runsMock),
+		service.WithJobs(job),
+		service.WithConfigLoader(func() ([]*domain.SyncJob, []*domain.Remote, error) {
+			return []*domain.SyncJob{job, {Name: "added", Source: "a", Destination: "c"}}, nil, loadErr
+		}),
+		service.WithRemoteInstaller(func([]*domain.Remote) error { return nil }),
+		service.WithReloader(reloaderFunc(func([]*domain.SyncJob) error { return nil })),
+	)
+	server := httptest.NewServer(api.New(api.WithService(svc)).Handler())
+	t.Cleanup(server.Close)
+
+	resp, err := http.Get(server.URL + "/api/config/reload")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	resp, err = http.Post(server.URL+"/api/config/reload", "application/json", nil)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var body map[string]any
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Equal(t, "api", body["trigger"])
+	assert.Equal(t, "applied", body["status"])
+	assert.Equal(t, map[string]any{"added": []any{"added"}}, body["jobs"])
+
+	loadErr = errors.New("invalid interval")
+	resp, err = http.Post(server.URL+"/api/config/reload", "application/json", nil)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	resp, err = http.Get(server.URL + "/api/config/reload")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Equal(t, "failed", body["status"])
+	assert.Equal(t, "invalid interval", body["error"])
+
+	resp, err = http.Get(server.URL + "/metrics")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	metrics, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Contains(t, string(metrics), "backup_guardian_config_last_reload_successful 0")
+}
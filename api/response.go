@@ -0,0 +1,40 @@
+package api
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+
+	"github.com/eva01/backup-guardian/internal/errors"
+)
+
+type errorResponse struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// writeError maps err to an HTTP status from its error code. Internal errors are logged
+// and reported with a generic message.
+func (s *Server) writeError(w http.ResponseWriter, err error) {
+	code := errors.ErrorCode(err)
+
+	status := http.StatusInternalServerError
+	switch code {
+	case errors.CodeInvalid:
+		status = http.StatusBadRequest
+	case errors.CodeNotFound:
+		status = http.StatusNotFound
+	case errors.CodeConflict:
+		status = http.StatusConflict
+	default:
+		s.logger.Error("API request failed", slog.Any("error", err))
+	}
+
+	writeJSON(w, status, &errorResponse{Code: code, Message: errors.ErrorMessage(err)})
+}
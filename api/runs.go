@@ -0,0 +1,119 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/eva01/backup-guardian/domain"
+	"github.com/eva01/backup-guardian/internal/errors"
+	"github.com/eva01/backup-guardian/internal/redact"
+	"github.com/eva01/backup-guardian/service"
+)
+
+type jobTriggerResponse struct {
+	JobName     string    `json:"job_name"`
+	RequestedAt time.Time `json:"requested_at"`
+}
+
+func (s *Server) handleTriggerJob(w http.ResponseWriter, r *http.Request) {
+	trigger, err := s.service.Trigger(r.PathValue("job"))
+	if err != nil {
+		s.writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusAccepted, mapJobTrigger(trigger))
+}
+
+// handleDryRun runs a job in dry-run mode and returns the recorded run once it finishes, with the
+// changes a sync would make.
+func (s *Server) handleDryRun(w http.ResponseWriter, r *http.Request) {
+	run, err := s.service.DryRun(r.Context(), r.PathValue("job"))
+	if err != nil {
+		s.writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, mapSyncRun(run))
+}
+
+func (s *Server) handleListRuns(w http.ResponseWriter, r *http.Request) {
+	limit, err := queryInt(r, "limit", 50)
+	if err != nil {
+		s.writeError(w, err)
+		return
+	}
+	offset, err := queryInt(r, "offset", 0)
+	if err != nil {
+		s.writeError(w, err)
+		return
+	}
+
+	runs, err := s.service.Runs(r.PathValue("job"), limit, offset)
+	if err != nil {
+		s.writeError(w, err)
+		return
+	}
+
+	result := make([]*syncRunResponse, len(runs))
+	for i, run := range runs {
+		result[i] = mapSyncRun(run)
+	}
+
+	writeJSON(w, http.StatusOK, result)
+}
+
+func (s *Server) handleGetRun(w http.ResponseWriter, r *http.Request) {
+	run, err := s.service.Run(r.PathValue("id"))
+	if err != nil {
+		s.writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, mapSyncRun(run))
+}
+
+// handleGetRunLog serves the log of a run as text. The level query parameter keeps the records
+// of that level and above.
+func (s *Server) handleGetRunLog(w http.ResponseWriter, r *http.Request) {
+	level, err := service.ParseLogLevel(r.URL.Query().Get("level"))
+	if err != nil {
+		s.writeError(w, err)
+		return
+	}
+
+	log, err := s.service.RunLog(r.PathValue("id"), level)
+	if err != nil {
+		s.writeError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Header().Set("X-Log-Truncated", strconv.FormatBool(log.Truncated))
+	w.WriteHeader(http.StatusOK)
+	// Logs are redacted when recorded; older logs may predate the secrets they mention.
+	_, _ = w.Write([]byte(redact.String(string(log.Content))))
+}
+
+func mapJobTrigger(trigger *domain.JobTrigger) *jobTriggerResponse {
+	return &jobTriggerResponse{
+		JobName:     trigger.JobName,
+		RequestedAt: trigger.RequestedAt,
+	}
+}
+
+// queryInt returns the non-negative integer query parameter name, or def when it is not set.
+func queryInt(r *http.Request, name string, def int) (int, error) {
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		return def, nil
+	}
+
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 {
+		return 0, &errors.Error{Code: errors.CodeInvalid, Message: "Invalid " + name + " parameter " + strconv.Quote(value)}
+	}
+
+	return n, nil
+}
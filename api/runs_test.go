@@ -0,0 +1,241 @@
+package api_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/eva01/backup-guardian/api"
+	"github.com/eva01/backup-guardian/domain"
+	domainmocks "github.com/eva01/backup-guardian/domain/mocks"
+	"github.com/eva01/backup-guardian/internal/errors"
+	"github.com/eva01/backup-guardian/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newTriggerTestServer(t *testing.T, pausesMock *domainmocks.JobPausesReadWriter, runsMock *domainmocks.SyncRunsReadWriter,
+	triggersMock *domainmocks.JobTriggersReadWriter, options ...api.Option) *httptest.Server {
+	svc := service.New(
+		service.WithJobPauses(pausesMock),
+		service.WithSyncRuns(runsMock),
+		service.WithJobTriggers(triggersMock),
+		service.WithJobs(&domain.SyncJob{Name: "test-job", Source: "source", Destination: "dest"}),
+	)
+
+	server := httptest.NewServer(api.New(append(options, api.WithService(svc))...).Handler())
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func TestServer_TriggerJob(t *testing.T) {
+	t.Run("triggered", func(t *testing.T) {
+		pausesMock := domainmocks.NewJobPausesReadWriter(t)
+		pausesMock.On("ListJobPauses").Return([]*domain.JobPause{}, nil).Once()
+		triggersMock := domainmocks.NewJobTriggersReadWriter(t)
+		triggersMock.On("UpsertJobTrigger", mock.MatchedBy(func(t *domain.JobTrigger) bool { return t.JobName == "test-job" })).
+			Return(func(t *domain.JobTrigger) (*domain.JobTrigger, error) { return t, nil }).Once()
+
+		server := newTriggerTestServer(t, pausesMock, domainmocks.NewSyncRunsReadWriter(t), triggersMock)
+
+		resp, err := http.Post(server.URL+"/api/jobs/test-job/trigger", "application/json", nil)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusAccepted, resp.StatusCode)
+
+		var body map[string]any
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+		assert.Equal(t, "test-job", body["job_name"])
+	})
+
+	t.Run("paused", func(t *testing.T) {
+		pausesMock := domainmocks.NewJobPausesReadWriter(t)
+		pausesMock.On("ListJobPauses").Return([]*domain.JobPause{{Scope: "test-job"}}, nil).Once()
+
+		server := newTriggerTestServer(t, pausesMock, domainmocks.NewSyncRunsReadWriter(t), domainmocks.NewJobTriggersReadWriter(t))
+
+		resp, err := http.Post(server.URL+"/api/jobs/test-job/trigger", "application/json", nil)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusConflict, resp.StatusCode)
+	})
+}
+
+func TestServer_ListRuns(t *testing.T) {
+	startedAt := time.Date(2026, 1, 2, 3, 0, 0, 0, time.UTC)
+	runsMock := domainmocks.NewSyncRunsReadWriter(t)
+	runsMock.On("ListSyncRuns", &domain.SyncRunsSelector{JobName: "test-job", Limit: 10, Offset: 20, DryRuns: true}).Return([]*domain.SyncRun{
+		{ID: "run-2", JobName: "test-job", Status: domain.StatusFailed, StartedAt: startedAt, ErrorMessage: "boom"},
+		{ID: "run-1", JobName: "test-job", Status: domain.StatusSuccess, StartedAt: startedAt.Add(-time.Hour)},
+	}, nil).Once()
+
+	server := newTriggerTestServer(t, domainmocks.NewJobPausesReadWriter(t), runsMock, domainmocks.NewJobTriggersReadWriter(t))
+
+	resp, err := http.Get(server.URL + "/api/jobs/test-job/runs?limit=10&offset=20")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var body []map[string]any
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	require.Len(t, body, 2)
+	assert.Equal(t, "run-2", body[0]["id"])
+	assert.Equal(t, "boom", body[0]["error_message"])
+
+	resp, err = http.Get(server.URL + "/api/jobs/test-job/runs?limit=many")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
+
+func TestServer_GetRun(t *testing.T) {
+	runsMock := domainmocks.NewSyncRunsReadWriter(t)
+	runsMock.On("GetSyncRun", &domain.SyncRunSelector{ID: "run-1"}).
+		Return(&domain.SyncRun{ID: "run-1", JobName: "test-job", Status: domain.StatusSuccess, FilesTransferred: 3,
+			ErrorMessage: "1 of 2 destinations failed: :b2,account=abc,key=K3Y:bucket: quota exceeded", Hooks: []domain.HookResult{
+				{Name: "dump", Event: domain.HookPreSync, Output: "dumped", Duration: 1500 * time.Millisecond},
+			}, Destinations: []domain.DestinationResult{
+				{Destination: "s3:", Status: domain.StatusSuccess, FilesTransferred: 3, Duration: 2 * time.Second},
+				{Destination: ":b2,account=abc,key=K3Y:bucket", Status: domain.StatusFailed, Error: "quota exceeded"},
+			}}, nil).Once()
+	runsMock.On("GetSyncRun", &domain.SyncRunSelector{ID: "missing"}).
+		Return(nil, &errors.Error{Code: errors.CodeNotFound, Message: "not found"}).Once()
+
+	server := newTriggerTestServer(t, domainmocks.NewJobPausesReadWriter(t), runsMock, domainmocks.NewJobTriggersReadWriter(t))
+
+	resp, err := http.Get(server.URL + "/api/runs/run-1")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var body map[string]any
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Equal(t, float64(3), body["files_transferred"])
+	// Secrets of connection strings are masked.
+	assert.Equal(t, "1 of 2 destinations failed: :b2,account=abc,key=***:bucket: quota exceeded", body["error_message"])
+	assert.Equal(t, []any{map[string]any{"name": "dump", "event": "pre_sync", "output": "dumped", "duration_seconds": 1.5}}, body["hooks"])
+	assert.Equal(t, []any{
+		map[string]any{"destination": "s3:", "status": "success", "files_transferred": float64(3), "bytes_transferred": float64(0), "duration_seconds": float64(2)},
+		map[string]any{"destination": ":b2,account=abc,key=***:bucket", "status": "failed", "error": "quota exceeded", "files_transferred": float64(0), "bytes_transferred": float64(0), "duration_seconds": float64(0)},
+	}, body["destinations"])
+
+	resp, err = http.Get(server.URL + "/api/runs/missing")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
+
+func TestServer_GetRunLog(t *testing.T) {
+	logsMock := domainmocks.NewSyncRunLogsReadWriter(t)
+	logsMock.On("GetSyncRunLog", &domain.SyncRunLogSelector{RunID: "run-1"}).Return(&domain.SyncRunLog{
+		RunID:     "run-1",
+		Content:   []byte("time=t level=DEBUG msg=one\ntime=t level=INFO msg=two\n"),
+		Truncated: true,
+	}, nil).Once()
+	logsMock.On("GetSyncRunLog", &domain.SyncRunLogSelector{RunID: "missing"}).
+		Return(nil, &errors.Error{Code: errors.CodeNotFound, Message: "not found"}).Once()
+
+	svc := service.New(service.WithSyncRunLogs(logsMock))
+	server := httptest.NewServer(api.New(api.WithService(svc)).Handler())
+	t.Cleanup(server.Close)
+
+	resp, err := http.Get(server.URL + "/api/runs/run-1/log?level=info")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "true", resp.Header.Get("X-Log-Truncated"))
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Equal(t, "time=t level=INFO msg=two\n", string(body))
+
+	resp, err = http.Get(server.URL + "/api/runs/run-1/log?level=loud")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	resp, err = http.Get(server.URL + "/api/runs/missing/log")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
+
+func TestServer_GetPipeline(t *testing.T) {
+	runsMock := domainmocks.NewSyncRunsReadWriter(t)
+	runsMock.On("ListSyncRuns", &domain.SyncRunsSelector{PipelineID: "run-1", Limit: 1000}).Return([]*domain.SyncRun{
+		{ID: "run-1", JobName: "backup", Status: domain.StatusSuccess, PipelineID: "run-1"},
+	}, nil).Once()
+	runsMock.On("ListSyncRuns", &domain.SyncRunsSelector{PipelineID: "missing", Limit: 1000}).Return([]*domain.SyncRun{}, nil).Once()
+
+	svc := service.New(service.WithSyncRuns(runsMock), service.WithJobs(
+		&domain.SyncJob{Name: "backup", Source: "a", Destination: "b"},
+		&domain.SyncJob{Name: "verify", Source: "b", Destination: "c", After: []*domain.Dependency{{Job: "backup"}}},
+	))
+	server := httptest.NewServer(api.New(api.WithService(svc)).Handler())
+	t.Cleanup(server.Close)
+
+	resp, err := http.Get(server.URL + "/api/pipelines/run-1")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var body map[string]any
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Equal(t, "run-1", body["id"])
+	steps := body["steps"].([]any)
+	require.Len(t, steps, 2)
+	assert.Equal(t, "success", steps[0].(map[string]any)["state"])
+	assert.Equal(t, "run-1", steps[0].(map[string]any)["run"].(map[string]any)["pipeline_id"])
+	assert.Equal(t, map[string]any{
+		"job": "verify", "state": "pending", "dependencies": "met",
+		"after": []any{map[string]any{"job": "backup", "on": "success"}},
+	}, steps[1])
+
+	resp, err = http.Get(server.URL + "/api/pipelines/missing")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
+
+type dryRunnerFunc func(ctx context.Context, job *domain.SyncJob) (*domain.SyncRun, error)
+
+func (f dryRunnerFunc) DryRun(ctx context.Context, job *domain.SyncJob) (*domain.SyncRun, error) {
+	return f(ctx, job)
+}
+
+func TestServer_DryRun(t *testing.T) {
+	dryRunner := dryRunnerFunc(func(_ context.Context, job *domain.SyncJob) (*domain.SyncRun, error) {
+		return &domain.SyncRun{ID: "run-1", JobName: job.Name, Kind: domain.KindDryRun, Status: domain.StatusSuccess, Plan: &domain.SyncPlan{
+			Changes: []domain.PlannedChange{{Action: domain.ChangeDelete, Path: "old.txt", Size: 12}},
+			Deletes: 1, DeleteBytes: 12,
+		}}, nil
+	})
+	svc := service.New(service.WithDryRunner(dryRunner), service.WithJobs(&domain.SyncJob{Name: "test-job", Source: "a", Destination: "b"}))
+	server := httptest.NewServer(api.New(api.WithService(svc)).Handler())
+	t.Cleanup(server.Close)
+
+	resp, err := http.Post(server.URL+"/api/jobs/test-job/dry-run", "application/json", nil)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var body map[string]any
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Equal(t, "dry-run", body["kind"])
+	assert.Equal(t, map[string]any{
+		"copies": float64(0), "copy_bytes": float64(0), "updates": float64(0), "update_bytes": float64(0),
+		"deletes": float64(1), "delete_bytes": float64(12), "truncated": false,
+		"changes": []any{map[string]any{"action": "delete", "path": "old.txt", "size": float64(12)}},
+	}, body["plan"])
+
+	resp, err = http.Post(server.URL+"/api/jobs/other-job/dry-run", "application/json", nil)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
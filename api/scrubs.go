@@ -0,0 +1,68 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/eva01/backup-guardian/domain"
+	"github.com/eva01/backup-guardian/internal/redact"
+)
+
+type scrubResponse struct {
+	Job          string                     `json:"job"`
+	Against      string                     `json:"against"`
+	Destinations []scrubDestinationResponse `json:"destinations"`
+}
+
+type scrubDestinationResponse struct {
+	Destination  string                  `json:"destination"`
+	Files        int64                   `json:"files"`
+	Checked      int64                   `json:"checked"`
+	CheckedBytes int64                   `json:"checked_bytes"`
+	Skipped      int64                   `json:"skipped"`
+	Mismatches   []scrubMismatchResponse `json:"mismatches"`
+	Rotation     int64                   `json:"rotation"`
+	Coverage     float64                 `json:"coverage"`
+	Error        string                  `json:"error,omitempty"`
+}
+
+type scrubMismatchResponse struct {
+	Path   string `json:"path"`
+	Reason string `json:"reason"`
+}
+
+// handleGetScrub returns the latest finished run of a scrub job.
+func (s *Server) handleGetScrub(w http.ResponseWriter, r *http.Request) {
+	run, err := s.service.Scrub(r.PathValue("job"))
+	if err != nil {
+		s.writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, mapSyncRun(run))
+}
+
+func mapScrub(scrub *domain.ScrubResult) *scrubResponse {
+	if scrub == nil {
+		return nil
+	}
+
+	result := &scrubResponse{Job: scrub.Job, Against: scrub.Against, Destinations: make([]scrubDestinationResponse, len(scrub.Destinations))}
+	for i, dest := range scrub.Destinations {
+		result.Destinations[i] = scrubDestinationResponse{
+			Destination:  redact.String(dest.Destination),
+			Files:        dest.Files,
+			Checked:      dest.Checked,
+			CheckedBytes: dest.CheckedBytes,
+			Skipped:      dest.Skipped,
+			Mismatches:   make([]scrubMismatchResponse, len(dest.Mismatches)),
+			Rotation:     dest.Rotation,
+			Coverage:     dest.Coverage,
+			Error:        redact.String(dest.Error),
+		}
+		for j, mismatch := range dest.Mismatches {
+			result.Destinations[i].Mismatches[j] = scrubMismatchResponse{Path: mismatch.Path, Reason: redact.String(mismatch.Reason)}
+		}
+	}
+
+	return result
+}
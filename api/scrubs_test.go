@@ -0,0 +1,68 @@
+package api_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/eva01/backup-guardian/api"
+	"github.com/eva01/backup-guardian/domain"
+	domainmocks "github.com/eva01/backup-guardian/domain/mocks"
+	"github.com/eva01/backup-guardian/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServer_GetScrub(t *testing.T) {
+	runsMock := domainmocks.NewSyncRunsReadWriter(t)
+	runsMock.On("ListSyncRuns", &domain.SyncRunsSelector{JobName: "scrub-job", Limit: 10}).Return([]*domain.SyncRun{{
+		ID:      "run-1",
+		JobName: "scrub-job",
+		Kind:    domain.KindScrub,
+		Status:  domain.StatusFailed,
+		Scrub: &domain.ScrubResult{Job: "test-job", Against: domain.ScrubAgainstSource, Destinations: []domain.ScrubDestination{{
+			Destination: "dest",
+			Files:       10,
+			Checked:     2,
+			Mismatches:  []domain.ScrubMismatch{{Path: "a.txt", Reason: "content hashes to sha256:x, the source to sha256:y"}},
+			Rotation:    1,
+			Coverage:    0.2,
+		}}},
+	}}, nil).Once()
+
+	svc := service.New(
+		service.WithSyncRuns(runsMock),
+		service.WithJobs(
+			&domain.SyncJob{Name: "test-job", Source: "source", Destination: "dest"},
+			&domain.SyncJob{Name: "scrub-job", Source: "source", Destination: "dest", Scrub: &domain.Scrub{Job: "test-job", Percent: 20}},
+		),
+	)
+	server := httptest.NewServer(api.New(api.WithService(svc)).Handler())
+	t.Cleanup(server.Close)
+
+	resp, err := http.Get(server.URL + "/api/jobs/scrub-job/scrub")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var body struct {
+		Kind  string `json:"kind"`
+		Scrub struct {
+			Job          string           `json:"job"`
+			Destinations []map[string]any `json:"destinations"`
+		} `json:"scrub"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Equal(t, domain.KindScrub, body.Kind)
+	assert.Equal(t, "test-job", body.Scrub.Job)
+	require.Len(t, body.Scrub.Destinations, 1)
+	assert.Equal(t, 0.2, body.Scrub.Destinations[0]["coverage"])
+	assert.Equal(t, []any{map[string]any{"path": "a.txt", "reason": "content hashes to sha256:x, the source to sha256:y"}},
+		body.Scrub.Destinations[0]["mismatches"])
+
+	resp, err = http.Get(server.URL + "/api/jobs/test-job/scrub")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
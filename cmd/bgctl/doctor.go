@@ -0,0 +1,111 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/eva01/backup-guardian/config"
+	"github.com/eva01/backup-guardian/doctor"
+	"github.com/eva01/backup-guardian/domain"
+	"github.com/eva01/backup-guardian/environment"
+	"github.com/eva01/backup-guardian/internal/database"
+	"github.com/eva01/backup-guardian/internal/redact"
+	"github.com/eva01/backup-guardian/runner"
+	"github.com/eva01/backup-guardian/service"
+	"github.com/eva01/backup-guardian/store"
+)
+
+// runDoctor checks the configuration and, unless -offline is set, probes the storage of the
+// jobs, then prints a report. It loads the configuration itself, so that every problem is
+// reported rather than the first one.
+func runDoctor(args []string) error {
+	fs := flag.NewFlagSet("doctor", flag.ExitOnError)
+	offline := fs.Bool("offline", false, "only check the configuration, without probing remotes and storage")
+	fs.Parse(args)
+
+	report := &doctor.Report{}
+	checkConfiguration(report, !*offline)
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "STATUS\tCHECK\tMESSAGE")
+	for _, check := range report.Checks {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", strings.ToUpper(check.Status), check.Name, check.Message)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	fmt.Printf("\n%d passed, %d warnings, %d failed\n", report.Count(doctor.StatusPass),
+		report.Count(doctor.StatusWarn), report.Count(doctor.StatusFail))
+	if report.Failed() {
+		return fmt.Errorf("configuration has problems")
+	}
+
+	return nil
+}
+
+// checkConfiguration loads the environment, the rclone config, the database, the jobs and the
+// remotes as the runner does, and adds the outcome to report. With probe, it then runs the
+// checks of the runner startup.
+func checkConfiguration(report *doctor.Report, probe bool) {
+	vars, err := environment.Load()
+	if err != nil {
+		report.Fail("environment", fmt.Sprintf("%v. Check the BG_* variables against .env.example", err))
+		return
+	}
+	if err := vars.Validate(); err != nil {
+		report.Fail("environment", strings.ReplaceAll(err.Error(), "\n", "; "))
+	} else {
+		report.Pass("environment", "variables parsed")
+	}
+	redact.Add(vars.Secrets()...)
+
+	if err := runner.LoadRcloneConfig(vars.RcloneConfig, vars.RcloneConfigPass); err != nil {
+		report.Fail("rclone config", fmt.Sprintf("%v. An encrypted file needs its password in BG_RCLONE_CONFIG_PASS_FILE", err))
+	} else {
+		report.Pass("rclone config", "loaded")
+	}
+
+	var jobs []*domain.SyncJob
+	source := "environment job"
+	if vars.JobsFile != "" {
+		source = "jobs file " + vars.JobsFile
+	}
+	if jobs, err = config.Jobs(vars); err != nil {
+		report.Fail(source, err.Error())
+	} else {
+		report.Pass(source, fmt.Sprintf("%d jobs, intervals, windows and filters parsed", len(jobs)))
+	}
+
+	db, err := database.Open(vars.DBPath())
+	if err != nil {
+		report.Fail("database "+vars.DBPath(), fmt.Sprintf("%v. Check that BG_DATA_DIR exists and is writable", err))
+		return
+	}
+	defer db.Close()
+	report.Pass("database "+vars.DBPath(), "opened, migrations applied")
+
+	remotes, err := config.Remotes(vars)
+	if err == nil {
+		s := store.New(store.WithDB(db))
+		svc := service.New(service.WithRemotes(s.Remotes), service.WithConfigRemotes(remotes...),
+			service.WithRemoteInstaller(runner.InstallRemotes))
+		remotes, err = svc.InstallRemotes()
+	}
+	if err != nil {
+		report.Fail("remotes", err.Error())
+		remotes = nil
+	} else {
+		report.Pass("remotes", fmt.Sprintf("%d remotes defined", len(remotes)))
+	}
+
+	options := []doctor.Option{doctor.WithDatabase(db), doctor.WithDataDir(vars.DataDir)}
+	if probe {
+		options = append(options, doctor.WithProber(runner.RcloneProber{}))
+	}
+	doctor.New(options...).Check(context.Background(), report, jobs, remotes)
+}
@@ -0,0 +1,579 @@
+// Command bgctl controls backup-guardian jobs. It works directly on the database,
+// so it can be used whether or not the runner is running.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/eva01/backup-guardian/config"
+	"github.com/eva01/backup-guardian/domain"
+	"github.com/eva01/backup-guardian/environment"
+	"github.com/eva01/backup-guardian/internal/database"
+	"github.com/eva01/backup-guardian/internal/redact"
+	"github.com/eva01/backup-guardian/runner"
+	"github.com/eva01/backup-guardian/service"
+	"github.com/eva01/backup-guardian/store"
+	"github.com/pressly/goose/v3"
+	rclonefs "github.com/rclone/rclone/fs"
+)
+
+const usage = `Usage: bgctl <command> [flags] [job]
+
+Commands:
+  status                         Show the status of every job
+  pause [-reason r] [-until t]   Pause a job, or the whole runner when no job is given
+  resume                         Resume a job, or the whole runner when no job is given
+  trigger <job>                  Run a job as soon as possible, outside of its schedule
+  acknowledge <job>              Acknowledge the anomalous run of a job, so that it runs in its own mode again
+  dry-run [-all] <job>           Show what a sync of a job would copy, update and delete, without changing anything
+  restore [-from d] <job> <dir>   Copy the files of a destination of a job to dir, decrypting them
+  verify [-dest d] <job>         Compare a destination of a job with its source, decrypting it
+  watch [job]                    Stream the live progress of running syncs from the runner API
+  runs [-n count] <job>          List the recent runs of a job
+  capacity <job>                 Show the usage of the destinations of a job and when they will fill up
+  logs [-level l] <run-id>       Print the log of a run, keeping records of level l and above
+  pipeline <run-id>              Show the jobs of the pipeline started by a run
+  manifest list <job>            List the manifests of the successful runs of a job
+  manifest show [-json] <run-id> Show the files listed by the manifest of a run
+  manifest verify <job>          Verify the chain of the manifests of a job and their copies on its destinations
+  scrub <job>                    Show the coverage and mismatches of the latest run of a scrub job
+  remote list                    List the remotes defined in the jobs file and the database
+  remote show <name>             Show the definition of a remote, secrets masked
+  remote test [name]             Check that remotes can be reached, by listing their root
+  remote set [-secret-file k=path] [-secret-env k=VAR] <name> <type> [k=v...]
+                                 Define a remote in the database, or replace it
+  remote delete <name>           Delete a remote defined in the database
+  doctor [-offline]              Check the configuration, remotes, sources, destinations and database
+
+-until accepts a duration (e.g. 48h) or an RFC 3339 timestamp.
+`
+
+func main() {
+	log.SetFlags(0)
+	flag.Usage = func() { fmt.Fprint(os.Stderr, usage) }
+	flag.Parse()
+
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	if flag.Arg(0) == "doctor" {
+		// The doctor loads the configuration itself, to report every problem.
+		if err := runDoctor(flag.Args()[1:]); err != nil {
+			log.Fatalf("doctor: %v", err)
+		}
+		return
+	}
+
+	vars, err := environment.Load()
+	if err != nil {
+		log.Fatalf("%v. Check the BG_* variables against .env.example", err)
+	}
+	redact.Add(vars.Secrets()...)
+	if err := runner.LoadRcloneConfig(vars.RcloneConfig, vars.RcloneConfigPass); err != nil {
+		log.Fatalf("could not load rclone config: %v", err)
+	}
+
+	goose.SetLogger(goose.NopLogger())
+	db, err := database.Open(vars.DBPath())
+	if err != nil {
+		log.Fatalf("could not open database: %v", err)
+	}
+	defer db.Close()
+
+	jobs, err := config.Jobs(vars)
+	if err != nil {
+		log.Fatalf("invalid job configuration: %v", err)
+	}
+	remotes, err := config.Remotes(vars)
+	if err != nil {
+		log.Fatalf("invalid remote configuration: %v", err)
+	}
+
+	s := store.New(store.WithDB(db))
+	// Dry runs, restores and verifications run here, with bgctl's rclone configuration.
+	r := runner.New(
+		runner.WithStore(s.SyncRuns),
+		runner.WithRcloneExecutor(&runner.LibraryRcloneExecutor{BisyncWorkdir: vars.BisyncDir()}),
+		runner.WithManifests(s.Manifests, runner.RcloneManifestStorage{}),
+	)
+	svc := service.New(
+		service.WithSyncRuns(s.SyncRuns),
+		service.WithSyncRunLogs(s.SyncRunLogs),
+		service.WithJobPauses(s.JobPauses),
+		service.WithJobSchedules(s.JobSchedules),
+		service.WithJobTriggers(s.JobTriggers),
+		service.WithJobAnomalies(s.JobAnomalies),
+		service.WithManifests(s.Manifests),
+		service.WithRemotes(s.Remotes),
+		service.WithDryRunner(r),
+		service.WithManifestVerifier(r),
+		service.WithJobs(jobs...),
+		service.WithConfigRemotes(remotes...),
+		service.WithRemoteInstaller(runner.InstallRemotes),
+	)
+
+	command, args := flag.Arg(0), flag.Args()[1:]
+	switch {
+	case command == "dry-run", command == "restore", command == "verify", command == "manifest" && len(args) > 0 && args[0] == "verify":
+		// These commands run rclone, on the remotes managed by backup-guardian.
+		if _, err := svc.InstallRemotes(); err != nil {
+			log.Fatalf("%s: could not install remotes: %v", command, err)
+		}
+	}
+
+	switch command {
+	case "status":
+		err = runStatus(svc)
+	case "pause":
+		err = runPause(svc, args)
+	case "resume":
+		err = runResume(svc, args)
+	case "trigger":
+		err = runTrigger(svc, args)
+	case "acknowledge":
+		err = runAcknowledge(svc, args)
+	case "dry-run":
+		err = runDryRun(svc, args)
+	case "restore":
+		err = runRestore(svc, r, args)
+	case "verify":
+		err = runVerify(svc, r, args)
+	case "watch":
+		err = runWatch(vars, args)
+	case "runs":
+		err = runRuns(svc, args)
+	case "capacity":
+		err = runCapacity(svc, args)
+	case "logs":
+		err = runLogs(svc, args)
+	case "pipeline":
+		err = runPipeline(svc, args)
+	case "manifest":
+		err = runManifest(svc, args)
+	case "scrub":
+		err = runScrub(svc, args)
+	case "remote":
+		err = runRemote(svc, args)
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+	if err != nil {
+		log.Fatalf("%s: %v", command, err)
+	}
+}
+
+func runStatus(svc *service.Service) error {
+	statuses, err := svc.JobStatuses()
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "JOB\tSTATE\tLAST RUN\tLAST STATUS\tNEXT DUE\tRPO\tREASON")
+	for _, status := range statuses {
+		state := "active"
+		reason := ""
+		if status.Paused() {
+			state = "paused"
+			if status.Pause.IsRunnerScope() {
+				state = "paused (runner)"
+			}
+			if !status.Pause.ResumeAt.IsZero() {
+				state += " until " + status.Pause.ResumeAt.Local().Format(time.DateTime)
+			}
+			reason = status.Pause.Reason
+		}
+		if status.Trigger != nil {
+			state += " (triggered)"
+		}
+		if status.Anomaly != nil {
+			state += " (anomaly)"
+			if reason == "" {
+				reason = strings.Join(status.Anomaly.Reasons, "; ")
+			}
+		}
+
+		lastRun, lastStatus := "-", "-"
+		if status.LastRun != nil {
+			lastRun = status.LastRun.StartedAt.Local().Format(time.DateTime)
+			lastStatus = status.LastRun.Status
+		}
+
+		nextDue := "-"
+		if status.Schedule != nil {
+			nextDue = status.Schedule.NextDueAt.Local().Format(time.DateTime)
+		}
+
+		rpo := "-"
+		if status.RPO != nil {
+			rpo = "ok"
+			if status.RPO.Violated {
+				rpo = "violated"
+			}
+		}
+
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n", status.Job.Name, state, lastRun, lastStatus, nextDue, rpo, reason)
+	}
+
+	return w.Flush()
+}
+
+func runPause(svc *service.Service, args []string) error {
+	fs := flag.NewFlagSet("pause", flag.ExitOnError)
+	reason := fs.String("reason", "", "why the job is paused")
+	until := fs.String("until", "", "auto-resume after a duration (e.g. 48h) or at an RFC 3339 time")
+	fs.Parse(args)
+
+	resumeAt, err := service.ParseResumeAt(*until, time.Now())
+	if err != nil {
+		return err
+	}
+
+	pause, err := svc.Pause(scopeArg(fs), *reason, resumeAt)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Paused %s", describeScope(pause.Scope))
+	if !pause.ResumeAt.IsZero() {
+		fmt.Printf(" until %s", pause.ResumeAt.Local().Format(time.DateTime))
+	}
+	fmt.Println()
+
+	return nil
+}
+
+func runResume(svc *service.Service, args []string) error {
+	fs := flag.NewFlagSet("resume", flag.ExitOnError)
+	fs.Parse(args)
+
+	scope := scopeArg(fs)
+	if err := svc.Resume(scope); err != nil {
+		return err
+	}
+
+	fmt.Printf("Resumed %s\n", describeScope(scope))
+
+	return nil
+}
+
+func runTrigger(svc *service.Service, args []string) error {
+	fs := flag.NewFlagSet("trigger", flag.ExitOnError)
+	fs.Parse(args)
+
+	if fs.NArg() != 1 {
+		return fmt.Errorf("expected a job name")
+	}
+
+	trigger, err := svc.Trigger(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Triggered job %s at %s, it runs once the runner picks it up\n", trigger.JobName,
+		trigger.RequestedAt.Local().Format(time.DateTime))
+
+	return nil
+}
+
+func runAcknowledge(svc *service.Service, args []string) error {
+	fs := flag.NewFlagSet("acknowledge", flag.ExitOnError)
+	fs.Parse(args)
+
+	if fs.NArg() != 1 {
+		return fmt.Errorf("expected a job name")
+	}
+
+	anomaly, err := svc.Acknowledge(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Acknowledged anomalous run %s of job %s, it runs in its own mode again\n", anomaly.RunID, anomaly.JobName)
+
+	return nil
+}
+
+// maxPrintedChanges is the number of planned changes printed by dry-run without -all.
+const maxPrintedChanges = 50
+
+func runDryRun(svc *service.Service, args []string) error {
+	fs := flag.NewFlagSet("dry-run", flag.ExitOnError)
+	all := fs.Bool("all", false, "print all the planned changes")
+	fs.Parse(args)
+
+	if fs.NArg() != 1 {
+		return fmt.Errorf("expected a job name")
+	}
+
+	run, err := svc.DryRun(context.Background(), fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	if run.Status == domain.StatusFailed {
+		return fmt.Errorf("dry run %s failed: %s", run.ID, run.ErrorMessage)
+	}
+
+	plan := run.Plan
+	if plan == nil || plan.Empty() {
+		fmt.Printf("Dry run %s: nothing to change\n", run.ID)
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ACTION\tSIZE\tPATH")
+	for i, change := range plan.Changes {
+		if i == maxPrintedChanges && !*all {
+			fmt.Fprintf(w, "...\t\t%d more, use -all to list them\n", len(plan.Changes)-i)
+			break
+		}
+		path := change.Path
+		if change.Destination != "" {
+			path = change.Destination + ": " + path
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\n", change.Action, sizeString(change.Size), path)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	fmt.Printf("\nDry run %s: %d to copy (%s), %d to update (%s), %d to delete (%s)\n", run.ID,
+		plan.Copies, sizeString(plan.CopyBytes), plan.Updates, sizeString(plan.UpdateBytes), plan.Deletes, sizeString(plan.DeleteBytes))
+	if plan.Truncated {
+		fmt.Printf("Only the first %d changes were recorded.\n", domain.MaxPlannedChanges)
+	}
+
+	return nil
+}
+
+func runRestore(svc *service.Service, r *runner.Runner, args []string) error {
+	fs := flag.NewFlagSet("restore", flag.ExitOnError)
+	from := fs.String("from", "", "destination to restore from, the first one of the job by default")
+	fs.Parse(args)
+
+	if fs.NArg() != 2 {
+		return fmt.Errorf("expected a job name and a target directory")
+	}
+
+	job, err := svc.Job(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	res, err := r.Restore(context.Background(), job, *from, fs.Arg(1))
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Restored %d files (%s) to %s in %s\n", res.FilesTransferred, sizeString(res.BytesTransferred),
+		fs.Arg(1), res.Duration.Round(time.Second))
+	return nil
+}
+
+func runVerify(svc *service.Service, r *runner.Runner, args []string) error {
+	fs := flag.NewFlagSet("verify", flag.ExitOnError)
+	dest := fs.String("dest", "", "destination to verify, the first one of the job by default")
+	all := fs.Bool("all", false, "print all the differences")
+	fs.Parse(args)
+
+	if fs.NArg() != 1 {
+		return fmt.Errorf("expected a job name")
+	}
+
+	job, err := svc.Job(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	check, err := r.Verify(context.Background(), job, *dest)
+	if err != nil {
+		return err
+	}
+	if check.OK() {
+		fmt.Printf("%d files match\n", check.Matches)
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "PROBLEM\tPATH")
+	var rows []string
+	for _, path := range check.Differ {
+		rows = append(rows, "differ\t"+path)
+	}
+	for _, path := range check.Missing {
+		rows = append(rows, "missing\t"+path)
+	}
+	for _, path := range check.Errors {
+		rows = append(rows, "error\t"+path)
+	}
+	for i, row := range rows {
+		if i == maxPrintedChanges && !*all {
+			fmt.Fprintf(w, "...\t%d more, use -all to list them\n", len(rows)-i)
+			break
+		}
+		fmt.Fprintln(w, row)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	return fmt.Errorf("%d files match, %d differ, %d missing, %d could not be checked",
+		check.Matches, len(check.Differ), len(check.Missing), len(check.Errors))
+}
+
+func runRuns(svc *service.Service, args []string) error {
+	fs := flag.NewFlagSet("runs", flag.ExitOnError)
+	count := fs.Int("n", 20, "number of runs to list")
+	fs.Parse(args)
+
+	if fs.NArg() != 1 {
+		return fmt.Errorf("expected a job name")
+	}
+
+	runs, err := svc.Runs(fs.Arg(0), *count, 0)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "RUN\tSTARTED\tDURATION\tKIND\tMODE\tSTATUS\tFILES\tBYTES\tERROR")
+	for _, run := range runs {
+		duration := "-"
+		if !run.FinishedAt.IsZero() {
+			duration = run.FinishedAt.Sub(run.StartedAt).Round(time.Second).String()
+		}
+		status := run.Status
+		if run.Anomalous() {
+			status += " (anomalous)"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%d\t%d\t%s\n", run.ID, run.StartedAt.Local().Format(time.DateTime), duration,
+			run.Kind, run.Mode, status, run.FilesTransferred, run.BytesTransferred, run.ErrorMessage)
+	}
+
+	return w.Flush()
+}
+
+func runCapacity(svc *service.Service, args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("expected a job name")
+	}
+
+	forecasts, err := svc.Capacity(args[0])
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "DESTINATION\tUSED\tFREE\tTOTAL\tCHECKED\tGROWTH/DAY\tFULL BY")
+	for _, forecast := range forecasts {
+		dest := redact.String(forecast.Destination)
+		if forecast.Usage == nil {
+			fmt.Fprintf(w, "%s\t-\t-\t-\tnever\t-\t-\n", dest)
+			continue
+		}
+
+		growth, fullBy := "-", "-"
+		if forecast.Samples > 1 {
+			growth = sizeString(int64(forecast.Growth))
+			if forecast.Growth < 0 {
+				growth = "-" + sizeString(int64(-forecast.Growth))
+			}
+			switch {
+			case !forecast.FullAt.IsZero():
+				fullBy = forecast.FullAt.Local().Format(time.DateOnly)
+			case forecast.Growth > 0:
+				fullBy = "unknown"
+			default:
+				fullBy = "not growing"
+			}
+		}
+		usage := forecast.Usage
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n", dest, sizeString(usage.Used), sizeString(usage.Free),
+			sizeString(usage.Total), usage.CheckedAt.Local().Format(time.DateTime), growth, fullBy)
+	}
+
+	return w.Flush()
+}
+
+func runLogs(svc *service.Service, args []string) error {
+	fs := flag.NewFlagSet("logs", flag.ExitOnError)
+	level := fs.String("level", "", "minimum level of the records to print: debug, info, warn or error")
+	fs.Parse(args)
+
+	if fs.NArg() != 1 {
+		return fmt.Errorf("expected a run ID")
+	}
+
+	minLevel, err := service.ParseLogLevel(*level)
+	if err != nil {
+		return err
+	}
+
+	log, err := svc.RunLog(fs.Arg(0), minLevel)
+	if err != nil {
+		return err
+	}
+
+	_, err = os.Stdout.Write(log.Content)
+
+	return err
+}
+
+func runPipeline(svc *service.Service, args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("expected a pipeline ID")
+	}
+
+	pipeline, err := svc.Pipeline(args[0])
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "JOB	STATE	RUN	STARTED	ERROR")
+	for _, step := range pipeline.Steps {
+		run, started, message := "-", "-", ""
+		if step.Run != nil {
+			run, started, message = step.Run.ID, step.Run.StartedAt.Local().Format(time.DateTime), step.Run.ErrorMessage
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", step.Job.Name, step.State, run, started, message)
+	}
+
+	return w.Flush()
+}
+
+// scopeArg returns the job named on the command line, or the runner-wide scope when none is given.
+func scopeArg(fs *flag.FlagSet) string {
+	if fs.NArg() == 0 {
+		return domain.PauseScopeAll
+	}
+
+	return fs.Arg(0)
+}
+
+// sizeString returns size in rclone's human-readable notation, or "-" when it is unknown.
+func sizeString(size int64) string {
+	if size < 0 {
+		return "-"
+	}
+
+	return rclonefs.SizeSuffix(size).ByteUnit()
+}
+
+func describeScope(scope string) string {
+	if scope == domain.PauseScopeAll {
+		return "runner"
+	}
+
+	return "job " + scope
+}
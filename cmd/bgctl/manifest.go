@@ -0,0 +1,115 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/eva01/backup-guardian/internal/redact"
+	"github.com/eva01/backup-guardian/service"
+)
+
+func runManifest(svc *service.Service, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("expected a subcommand: list, show or verify")
+	}
+
+	command, args := args[0], args[1:]
+	switch command {
+	case "list":
+		return runManifestList(svc, args)
+	case "show":
+		return runManifestShow(svc, args)
+	case "verify":
+		return runManifestVerify(svc, args)
+	default:
+		return fmt.Errorf("unknown subcommand %s", command)
+	}
+}
+
+func runManifestList(svc *service.Service, args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("expected a job name")
+	}
+
+	manifests, err := svc.Manifests(args[0])
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "SEQUENCE\tRUN\tCREATED\tFILES\tSIZE\tHASH")
+	for _, manifest := range manifests {
+		fmt.Fprintf(w, "%d\t%s\t%s\t%d\t%s\t%s\n", manifest.Sequence, manifest.RunID, manifest.CreatedAt.Local().Format(time.DateTime),
+			manifest.Files, sizeString(manifest.Bytes), manifest.Hash)
+	}
+
+	return w.Flush()
+}
+
+func runManifestShow(svc *service.Service, args []string) error {
+	fs := flag.NewFlagSet("manifest show", flag.ExitOnError)
+	asJSON := fs.Bool("json", false, "print the manifest as stored, in JSON")
+	fs.Parse(args)
+
+	if fs.NArg() != 1 {
+		return fmt.Errorf("expected a run ID")
+	}
+
+	manifest, err := svc.Manifest(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(manifest)
+	}
+
+	fmt.Printf("Run:      %s\n", manifest.RunID)
+	fmt.Printf("Job:      %s\n", manifest.JobName)
+	fmt.Printf("Sequence: %d\n", manifest.Sequence)
+	fmt.Printf("Created:  %s\n", manifest.CreatedAt.Local().Format(time.DateTime))
+	fmt.Printf("Hash:     %s\n", manifest.Hash)
+	fmt.Printf("Previous: %s\n", manifest.PreviousHash)
+	for _, dest := range manifest.Destinations {
+		fmt.Printf("\n%s: %d files, %s\n", redact.String(dest.Destination), dest.Files, sizeString(dest.Bytes))
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "PATH\tSIZE\tHASH")
+		for _, file := range dest.Entries {
+			fmt.Fprintf(w, "%s\t%d\t%s\n", file.Path, file.Size, file.Hash)
+		}
+		if err := w.Flush(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func runManifestVerify(svc *service.Service, args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("expected a job name")
+	}
+
+	verification, err := svc.VerifyManifests(context.Background(), args[0])
+	if err != nil {
+		return err
+	}
+	if verification.OK() {
+		fmt.Printf("%d manifests chained, %d copies match\n", verification.Manifests, verification.Copies)
+		return nil
+	}
+
+	for _, problem := range verification.Problems {
+		fmt.Println(redact.String(problem))
+	}
+
+	return fmt.Errorf("%d problems found in %d manifests and %d copies", len(verification.Problems),
+		verification.Manifests, verification.Copies)
+}
@@ -0,0 +1,206 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/eva01/backup-guardian/domain"
+	"github.com/eva01/backup-guardian/runner"
+	"github.com/eva01/backup-guardian/service"
+)
+
+// remoteTestTimeout bounds the check of each remote by remote test.
+const remoteTestTimeout = 30 * time.Second
+
+func runRemote(svc *service.Service, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("expected a subcommand: list, show, test, set or delete")
+	}
+
+	command, args := args[0], args[1:]
+	switch command {
+	case "list":
+		return runRemoteList(svc)
+	case "show":
+		return runRemoteShow(svc, args)
+	case "test":
+		return runRemoteTest(svc, args)
+	case "set":
+		return runRemoteSet(svc, args)
+	case "delete":
+		return runRemoteDelete(svc, args)
+	default:
+		return fmt.Errorf("unknown subcommand %s", command)
+	}
+}
+
+func runRemoteList(svc *service.Service) error {
+	remotes, err := svc.Remotes()
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "REMOTE\tTYPE\tORIGIN\tUPDATED")
+	for _, remote := range remotes {
+		updated := "-"
+		if !remote.UpdatedAt.IsZero() {
+			updated = remote.UpdatedAt.Local().Format(time.DateTime)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", remote.Name, remote.Type, remote.Origin, updated)
+	}
+
+	return w.Flush()
+}
+
+func runRemoteShow(svc *service.Service, args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("expected a remote name")
+	}
+
+	remote, err := svc.Remote(args[0])
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("[%s]\n", remote.Name)
+	fmt.Printf("type = %s\n", remote.Type)
+	params := runner.RemoteParameters(remote)
+	for _, key := range slices.Sorted(maps.Keys(params)) {
+		fmt.Printf("%s = %s\n", key, params[key])
+	}
+	fmt.Printf("\nDefined in the %s.\n", describeOrigin(remote.Origin))
+
+	return nil
+}
+
+func runRemoteTest(svc *service.Service, args []string) error {
+	remotes, err := svc.InstallRemotes()
+	if err != nil {
+		return err
+	}
+	if len(args) > 0 {
+		remote, err := svc.Remote(args[0])
+		if err != nil {
+			return err
+		}
+		remotes = []*domain.Remote{remote}
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "REMOTE\tSTATUS\tERROR")
+	failed := 0
+	for _, remote := range remotes {
+		ctx, cancel := context.WithTimeout(context.Background(), remoteTestTimeout)
+		err := runner.CheckRemote(ctx, remote.Name)
+		cancel()
+		if err != nil {
+			failed++
+			fmt.Fprintf(w, "%s\tfailed\t%v\n", remote.Name, err)
+			continue
+		}
+		fmt.Fprintf(w, "%s\tok\t\n", remote.Name)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d remotes could not be reached", failed, len(remotes))
+	}
+
+	return nil
+}
+
+func runRemoteSet(svc *service.Service, args []string) error {
+	fs := flag.NewFlagSet("remote set", flag.ExitOnError)
+	secretFiles := keyValues{}
+	secretEnv := keyValues{}
+	fs.Var(secretFiles, "secret-file", "read the value of a parameter from a file, as key=path (repeatable)")
+	fs.Var(secretEnv, "secret-env", "read the value of a parameter from an environment variable, as key=VAR (repeatable)")
+	fs.Parse(args)
+
+	if fs.NArg() < 2 {
+		return fmt.Errorf("expected a remote name, a type and key=value parameters")
+	}
+
+	params := keyValues{}
+	for _, arg := range fs.Args()[2:] {
+		if err := params.Set(arg); err != nil {
+			return err
+		}
+	}
+
+	remote := &domain.Remote{
+		Name:        fs.Arg(0),
+		Type:        fs.Arg(1),
+		Parameters:  params,
+		SecretFiles: secretFiles,
+		SecretEnv:   secretEnv,
+	}
+	// Secrets are read by the runner, from its own environment: only check the definition.
+	if err := runner.ValidateRemote(remote); err != nil {
+		return err
+	}
+
+	if _, err := svc.SaveRemote(remote); err != nil {
+		return err
+	}
+
+	fmt.Printf("Saved remote %s, reload the runner (SIGHUP) to use it\n", remote.Name)
+
+	return nil
+}
+
+func runRemoteDelete(svc *service.Service, args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("expected a remote name")
+	}
+
+	if err := svc.DeleteRemote(args[0]); err != nil {
+		return err
+	}
+
+	fmt.Printf("Deleted remote %s\n", args[0])
+
+	return nil
+}
+
+func describeOrigin(origin string) string {
+	if origin == domain.RemoteOriginConfig {
+		return "jobs file"
+	}
+
+	return "database"
+}
+
+// keyValues is a flag collecting key=value pairs.
+type keyValues map[string]string
+
+// String implements flag.Value.
+func (kv keyValues) String() string {
+	pairs := make([]string, 0, len(kv))
+	for _, key := range slices.Sorted(maps.Keys(kv)) {
+		pairs = append(pairs, key+"="+kv[key])
+	}
+
+	return strings.Join(pairs, ",")
+}
+
+// Set implements flag.Value.
+func (kv keyValues) Set(value string) error {
+	key, val, ok := strings.Cut(value, "=")
+	if !ok || key == "" {
+		return fmt.Errorf("expected key=value, got %q", value)
+	}
+	kv[key] = val
+
+	return nil
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/eva01/backup-guardian/internal/redact"
+	"github.com/eva01/backup-guardian/service"
+)
+
+func runScrub(svc *service.Service, args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("expected a job name")
+	}
+
+	run, err := svc.Scrub(args[0])
+	if err != nil {
+		return err
+	}
+
+	scrub := run.Scrub
+	fmt.Printf("Run %s of %s: %s, scrubbing job %s against its %s\n", run.ID, run.FinishedAt.Local().Format(time.DateTime),
+		run.Status, scrub.Job, scrub.Against)
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "DESTINATION\tFILES\tCHECKED\tSIZE\tSKIPPED\tMISMATCHES\tROTATION\tCOVERAGE")
+	for _, dest := range scrub.Destinations {
+		if dest.Error != "" {
+			fmt.Fprintf(w, "%s\terror: %s\n", redact.String(dest.Destination), redact.String(dest.Error))
+			continue
+		}
+		fmt.Fprintf(w, "%s\t%d\t%d\t%s\t%d\t%d\t%d\t%.1f%%\n", redact.String(dest.Destination), dest.Files, dest.Checked,
+			sizeString(dest.CheckedBytes), dest.Skipped, len(dest.Mismatches), dest.Rotation, dest.Coverage*100)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	for _, dest := range scrub.Destinations {
+		for _, mismatch := range dest.Mismatches {
+			fmt.Printf("MISMATCH %s: %s: %s\n", redact.String(dest.Destination), mismatch.Path, redact.String(mismatch.Reason))
+		}
+	}
+	if count := scrub.MismatchCount(); count > 0 {
+		return fmt.Errorf("%d mismatched files", count)
+	}
+
+	return nil
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"github.com/rclone/rclone/fs"
+
+	"github.com/eva01/backup-guardian/environment"
+)
+
+// progressEvent is a progress snapshot sent by the runner progress stream.
+type progressEvent struct {
+	RunID      string  `json:"run_id"`
+	JobName    string  `json:"job_name"`
+	BytesDone  int64   `json:"bytes_done"`
+	BytesTotal int64   `json:"bytes_total"`
+	FilesDone  int64   `json:"files_done"`
+	FilesTotal int64   `json:"files_total"`
+	Percent    float64 `json:"percent"`
+	Speed      float64 `json:"speed"`
+	ETASeconds float64 `json:"eta_seconds"`
+	Transfers  []struct {
+		Name string `json:"name"`
+	} `json:"transfers"`
+	Done bool `json:"done"`
+}
+
+// runWatch prints the live progress of running syncs. Live progress is only known to the
+// runner, so unlike the other commands it connects to the runner API.
+func runWatch(vars *environment.Variables, args []string) error {
+	fset := flag.NewFlagSet("watch", flag.ExitOnError)
+	fset.Parse(args)
+
+	streamURL, err := progressStreamURL(vars.APIAddr, fset.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, streamURL, nil)
+	if err != nil {
+		return err
+	}
+	switch {
+	case vars.APIToken != "":
+		req.Header.Set("Authorization", "Bearer "+vars.APIToken)
+	case vars.APIPassword != "":
+		req.SetBasicAuth(vars.APIUsername, vars.APIPassword)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("could not connect to the runner: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		var body struct {
+			Message string `json:"message"`
+		}
+		_ = json.NewDecoder(resp.Body).Decode(&body)
+		return fmt.Errorf("runner API returned %s: %s", resp.Status, body.Message)
+	}
+
+	fmt.Println("Watching sync progress, press Ctrl-C to stop")
+
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		data, ok := strings.CutPrefix(scanner.Text(), "data: ")
+		if !ok {
+			continue
+		}
+
+		var event progressEvent
+		if err := json.Unmarshal([]byte(data), &event); err != nil {
+			return err
+		}
+		fmt.Println(describeProgress(&event))
+	}
+	if ctx.Err() != nil {
+		return nil
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+
+	return fmt.Errorf("runner closed the progress stream")
+}
+
+// progressStreamURL returns the URL of the progress stream of the runner API listening on
+// addr, filtered on job when set.
+func progressStreamURL(addr, job string) (string, error) {
+	if addr == "" {
+		return "", fmt.Errorf("BG_API_ADDR is not set, the runner API is disabled")
+	}
+
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return "", fmt.Errorf("invalid BG_API_ADDR %q: %w", addr, err)
+	}
+	// A wildcard listen address accepts local connections.
+	if ip := net.ParseIP(host); host == "" || (ip != nil && ip.IsUnspecified()) {
+		host = "127.0.0.1"
+	}
+
+	u := url.URL{Scheme: "http", Host: net.JoinHostPort(host, port), Path: "/api/progress/stream"}
+	if job != "" {
+		u.RawQuery = url.Values{"job": {job}}.Encode()
+	}
+
+	return u.String(), nil
+}
+
+func describeProgress(event *progressEvent) string {
+	if event.Done {
+		return fmt.Sprintf("%s  finished  %s  files %d", event.JobName, fs.SizeSuffix(event.BytesDone).ByteUnit(), event.FilesDone)
+	}
+
+	line := fmt.Sprintf("%s  %5.1f%%  %s/%s  files %d/%d  %s/s", event.JobName, event.Percent,
+		fs.SizeSuffix(event.BytesDone).ByteUnit(), fs.SizeSuffix(event.BytesTotal).ByteUnit(),
+		event.FilesDone, event.FilesTotal, fs.SizeSuffix(int64(event.Speed)).ByteUnit())
+	if event.ETASeconds > 0 {
+		line += "  ETA " + (time.Duration(event.ETASeconds) * time.Second).String()
+	}
+	if n := len(event.Transfers); n > 0 {
+		line += fmt.Sprintf("  transferring %d: %s", n, event.Transfers[0].Name)
+		if n > 1 {
+			line += ", ..."
+		}
+	}
+
+	return line
+}
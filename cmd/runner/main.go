@@ -3,48 +3,71 @@ package main
 import (
 	"context"
 	"database/sql"
+	"fmt"
 	"log"
 	"log/slog"
 	"os"
-	"path/filepath"
+	"os/signal"
 	"strings"
+	"syscall"
+	"time"
 
+	"github.com/rclone/rclone/fs"
+
+	"github.com/eva01/backup-guardian/api"
+	"github.com/eva01/backup-guardian/config"
+	"github.com/eva01/backup-guardian/doctor"
 	"github.com/eva01/backup-guardian/domain"
 	"github.com/eva01/backup-guardian/environment"
-	"github.com/eva01/backup-guardian/migrations"
+	"github.com/eva01/backup-guardian/heartbeat"
+	"github.com/eva01/backup-guardian/hook"
+	"github.com/eva01/backup-guardian/internal/database"
+	"github.com/eva01/backup-guardian/internal/redact"
+	"github.com/eva01/backup-guardian/notify"
+	"github.com/eva01/backup-guardian/progress"
+	"github.com/eva01/backup-guardian/runlog"
 	"github.com/eva01/backup-guardian/runner"
+	"github.com/eva01/backup-guardian/service"
 	"github.com/eva01/backup-guardian/store"
-	"github.com/pressly/goose/v3"
-	_ "modernc.org/sqlite"
+	"github.com/eva01/backup-guardian/watchdog"
 )
 
 func main() {
-	vars := environment.Parse()
+	vars, err := environment.Load()
+	if err != nil {
+		log.Fatalf("%v. Check the BG_* variables against .env.example", err)
+	}
+	if err := vars.Validate(); err != nil {
+		log.Fatalf("invalid environment: %v", err)
+	}
+	redact.Add(vars.Secrets()...)
 
-	logger := newLogger(vars.LogLevel)
+	handler := newHandler(vars.LogLevel)
 
-	if err := os.MkdirAll(filepath.Dir(vars.DBPath()), 0755); err != nil {
-		log.Fatalf("could not create data directory: %v", err)
+	var recorder *runlog.Recorder
+	if !strings.EqualFold(vars.RunLogLevel, "off") {
+		if recorder, err = newRunLogRecorder(vars); err != nil {
+			log.Fatalf("invalid run log configuration: %v", err)
+		}
+		handler = recorder.Handler(handler)
 	}
 
-	db, err := sql.Open("sqlite", vars.DBPath())
-	if err != nil {
-		log.Fatalf("could not open database: %v", err)
-	}
-	defer db.Close()
+	// Secrets are masked before records are written and captured in run logs.
+	logger := slog.New(redact.Handler(handler))
+	// rclone logs to the default logger: its records are formatted like ours and captured in
+	// run logs.
+	slog.SetDefault(logger)
 
-	if err := db.Ping(); err != nil {
-		log.Fatalf("could not ping database: %v", err)
+	if err := runner.LoadRcloneConfig(vars.RcloneConfig, vars.RcloneConfigPass); err != nil {
+		log.Fatalf("could not load rclone config: %v", err)
 	}
 
 	// Migrations (goose) — appliquées au démarrage
-	goose.SetBaseFS(migrations.FS)
-	if err := goose.SetDialect("sqlite3"); err != nil {
-		log.Fatalf("could not set dialect: %v", err)
-	}
-	if err := goose.Up(db, "."); err != nil {
-		log.Fatalf("migration failed: %v", err)
+	db, err := database.Open(vars.DBPath())
+	if err != nil {
+		log.Fatalf("could not open database: %v", err)
 	}
+	defer db.Close()
 
 	s := store.New(store.WithDB(db))
 
@@ -53,24 +76,163 @@ func main() {
 		log.Fatalf("invalid sync interval %q: %v", vars.SyncInterval, err)
 	}
 
-	r := runner.New(
+	loadConfig := func() ([]*domain.SyncJob, []*domain.Remote, error) {
+		jobs, err := config.Jobs(vars)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid job configuration: %w", err)
+		}
+		for _, job := range jobs {
+			redact.Add(job.Secrets()...)
+		}
+		remotes, err := config.Remotes(vars)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid remote configuration: %w", err)
+		}
+		return jobs, remotes, nil
+	}
+	jobs, remotes, err := loadConfig()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	tracker := progress.NewTracker()
+
+	pinger := heartbeat.New(heartbeat.WithLogger(logger))
+	defer func() {
+		// Flush the last pings, without hanging the shutdown on an unreachable monitor.
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
+		if err := pinger.Close(ctx); err != nil {
+			logger.Warn("Heartbeat pings not sent before shutdown", slog.Any("error", err))
+		}
+	}()
+
+	notifier := notify.Multi{notify.NewLogNotifier(logger)}
+	if vars.NotifyWebhookURL != "" {
+		notifier = append(notifier, notify.NewWebhookNotifier(vars.NotifyWebhookURL))
+	}
+
+	runnerOptions := []runner.Option{
 		runner.WithStore(s.SyncRuns),
-		runner.WithRcloneExecutor(&runner.LibraryRcloneExecutor{}),
-		runner.WithScheduler(runner.NewScheduler(interval)),
-		runner.WithSyncJob(&domain.SyncJob{
-			Name:        "gdrive-to-s3",
-			Source:      vars.SyncSource,
-			Destination: vars.SyncDest,
-		}),
+		runner.WithHeartbeat(pinger),
+		runner.WithHooks(hook.New()),
+		runner.WithProgress(tracker),
+		runner.WithJobPauses(s.JobPauses),
+		runner.WithRcloneExecutor(&runner.LibraryRcloneExecutor{BisyncWorkdir: vars.BisyncDir()}),
+		runner.WithUsage(runner.RcloneProber{}),
+		runner.WithCanaries(runner.RcloneProber{}),
+		runner.WithAnomalies(s.JobAnomalies, s.JobPauses),
+		runner.WithManifests(s.Manifests, runner.RcloneManifestStorage{}),
+		runner.WithScrubs(s.ScrubStates, runner.RcloneScrubber{}),
+		runner.WithNotifier(notifier),
+		runner.WithScheduler(runner.NewScheduler(interval,
+			runner.WithJobSchedules(s.JobSchedules),
+			runner.WithRunHistory(s.SyncRuns),
+			runner.WithJobTriggers(s.JobTriggers, 0),
+			runner.WithSchedulerLogger(logger),
+		)),
+		runner.WithSyncJobs(jobs...),
 		runner.WithLogger(logger),
+	}
+	if recorder != nil {
+		runnerOptions = append(runnerOptions, runner.WithRunLogs(recorder, s.SyncRunLogs))
+	}
+
+	r := runner.New(runnerOptions...)
+
+	svc := service.New(
+		service.WithSyncRuns(s.SyncRuns),
+		service.WithSyncRunLogs(s.SyncRunLogs),
+		service.WithJobPauses(s.JobPauses),
+		service.WithJobSchedules(s.JobSchedules),
+		service.WithJobTriggers(s.JobTriggers),
+		service.WithJobAnomalies(s.JobAnomalies),
+		service.WithManifests(s.Manifests),
+		service.WithRemotes(s.Remotes),
+		service.WithProgress(tracker),
+		service.WithDryRunner(r),
+		service.WithManifestVerifier(r),
+		service.WithJobs(jobs...),
+		service.WithConfigRemotes(remotes...),
+		service.WithRemoteInstaller(runner.InstallRemotes),
+		service.WithConfigLoader(loadConfig),
+		service.WithReloader(r),
+	)
+
+	if remotes, err = svc.InstallRemotes(); err != nil {
+		log.Fatalf("invalid remote configuration: %v", err)
+	}
+
+	if vars.StartupChecks != environment.StartupChecksOff {
+		runStartupChecks(ctx, logger, vars, db, jobs, remotes)
+	}
+
+	w := watchdog.New(
+		watchdog.WithStatuses(svc),
+		watchdog.WithJobAlerts(s.JobAlerts),
+		watchdog.WithNotifier(notifier),
+		watchdog.WithLogger(logger),
 	)
+	go w.Run(ctx)
+
+	if vars.APIAddr != "" {
+		server := api.New(
+			api.WithService(svc),
+			api.WithAddr(vars.APIAddr),
+			api.WithBasicAuth(vars.APIUsername, vars.APIPassword),
+			api.WithBearerToken(vars.APIToken),
+			api.WithLogger(logger),
+		)
+		go func() {
+			if err := server.Run(ctx); err != nil {
+				logger.Error("API server failed", slog.Any("error", err))
+			}
+		}()
+	}
+
+	// Registered before the runner starts, so that an early SIGHUP does not stop it.
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	watchedFile := ""
+	if vars.ReloadOnChange {
+		watchedFile = vars.JobsFile
+	}
+	go watchReloads(ctx, logger, svc, hup, watchedFile)
 
-	if err := r.Run(context.Background(), vars); err != nil {
+	if err := r.Run(ctx, vars); err != nil {
 		log.Fatalf("runner failed: %v", err)
 	}
 }
 
-func newLogger(level string) *slog.Logger {
+// runStartupChecks runs the checks of bgctl doctor on the storage of the jobs and logs their
+// failures. In strict mode, a failure stops the runner; otherwise the runs of the jobs affected
+// fail, and are retried, until the problem is fixed.
+func runStartupChecks(ctx context.Context, logger *slog.Logger, vars *environment.Variables, db *sql.DB, jobs []*domain.SyncJob, remotes []*domain.Remote) {
+	report := &doctor.Report{}
+	doctor.New(
+		doctor.WithProber(runner.RcloneProber{}),
+		doctor.WithDatabase(db),
+		doctor.WithDataDir(vars.DataDir),
+	).Check(ctx, report, jobs, remotes)
+	report.Log(logger)
+
+	attrs := []any{slog.Int("passed", report.Count(doctor.StatusPass)), slog.Int("warnings", report.Count(doctor.StatusWarn)),
+		slog.Int("failed", report.Count(doctor.StatusFail))}
+	switch {
+	case report.Failed() && vars.StartupChecks == environment.StartupChecksStrict:
+		log.Fatalf("startup checks failed: %d of %d checks failed, run bgctl doctor for details",
+			report.Count(doctor.StatusFail), len(report.Checks))
+	case report.Failed():
+		logger.Error("Startup checks failed, starting anyway", attrs...)
+	default:
+		logger.Info("Startup checks passed", attrs...)
+	}
+}
+
+func newHandler(level string) slog.Handler {
 	var lvl slog.Level
 	switch strings.ToLower(level) {
 	case "debug":
@@ -86,7 +248,20 @@ func newLogger(level string) *slog.Logger {
 	}
 
 	opts := &slog.HandlerOptions{Level: lvl}
-	handler := slog.NewJSONHandler(os.Stdout, opts)
 
-	return slog.New(handler)
+	return slog.NewJSONHandler(os.Stdout, opts)
+}
+
+func newRunLogRecorder(vars *environment.Variables) (*runlog.Recorder, error) {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(vars.RunLogLevel)); err != nil {
+		return nil, fmt.Errorf("invalid BG_RUN_LOG_LEVEL %q: %w", vars.RunLogLevel, err)
+	}
+
+	var maxSize fs.SizeSuffix
+	if err := maxSize.Set(vars.RunLogMaxSize); err != nil || maxSize <= 0 {
+		return nil, fmt.Errorf("invalid BG_RUN_LOG_MAX_SIZE %q", vars.RunLogMaxSize)
+	}
+
+	return runlog.New(runlog.WithLevel(level), runlog.WithMaxSize(int(maxSize))), nil
 }
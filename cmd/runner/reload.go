@@ -0,0 +1,80 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/eva01/backup-guardian/domain"
+	"github.com/eva01/backup-guardian/service"
+)
+
+// reloadPollInterval is how often the jobs file is read to detect changes.
+const reloadPollInterval = 10 * time.Second
+
+// watchReloads reloads the configuration on each signal of hup and, when path is set, when the
+// content of the file at path changes, until ctx is cancelled. The file is polled rather than
+// watched, so that the updates of mounted config maps, which replace a symlink, are seen.
+func watchReloads(ctx context.Context, logger *slog.Logger, svc *service.Service, hup <-chan os.Signal, path string) {
+	var tick <-chan time.Time
+	var digest [sha256.Size]byte
+	if path != "" {
+		ticker := time.NewTicker(reloadPollInterval)
+		defer ticker.Stop()
+		tick = ticker.C
+		digest, _ = fileDigest(path)
+	}
+
+	for {
+		trigger := domain.ReloadTriggerSignal
+		select {
+		case <-ctx.Done():
+			return
+		case <-hup:
+		case <-tick:
+			// A file missing, e.g. while it is replaced, is read again on the next tick.
+			current, err := fileDigest(path)
+			if err != nil || current == digest {
+				continue
+			}
+			trigger = domain.ReloadTriggerFile
+		}
+
+		if path != "" {
+			digest, _ = fileDigest(path)
+		}
+		reload, err := svc.Reload(trigger)
+		logReload(logger, trigger, reload, err)
+	}
+}
+
+// logReload logs the outcome of a reload requested by trigger. reload is nil when the reload
+// could not be attempted.
+func logReload(logger *slog.Logger, trigger string, reload *domain.ConfigReload, err error) {
+	if err != nil {
+		logger.Error("Configuration reload failed, keeping the running configuration",
+			slog.String("trigger", trigger), slog.Any("error", err))
+		return
+	}
+
+	logger.Info("Configuration reloaded", slog.String("trigger", trigger), slog.String("status", reload.Status),
+		slog.String("jobs_added", strings.Join(reload.Jobs.Added, ", ")),
+		slog.String("jobs_removed", strings.Join(reload.Jobs.Removed, ", ")),
+		slog.String("jobs_changed", strings.Join(reload.Jobs.Changed, ", ")),
+		slog.String("remotes_added", strings.Join(reload.Remotes.Added, ", ")),
+		slog.String("remotes_removed", strings.Join(reload.Remotes.Removed, ", ")),
+		slog.String("remotes_changed", strings.Join(reload.Remotes.Changed, ", ")))
+}
+
+// fileDigest returns the SHA-256 of the content of the file at path.
+func fileDigest(path string) ([sha256.Size]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return [sha256.Size]byte{}, err
+	}
+
+	return sha256.Sum256(data), nil
+}
@@ -0,0 +1,807 @@
+// Package config loads the job definitions, either from the jobs file (BG_JOBS_FILE)
+// or from the single job described by the environment.
+package config
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rclone/rclone/fs"
+	"gopkg.in/yaml.v3"
+
+	"github.com/eva01/backup-guardian/domain"
+	"github.com/eva01/backup-guardian/environment"
+	"github.com/eva01/backup-guardian/runner/options"
+)
+
+// File is the jobs file format.
+type File struct {
+	// Remotes are rclone remotes usable by the jobs, without an rclone.conf section.
+	Remotes []Remote `yaml:"remotes"`
+	Jobs    []Job    `yaml:"jobs"`
+}
+
+// Remote is an rclone remote definition in the jobs file.
+type Remote struct {
+	Name string `yaml:"name"`
+	// Type is the rclone backend (e.g. s3, sftp, b2).
+	Type string `yaml:"type"`
+	// Parameters are the backend options, as in rclone.conf but with passwords in clear.
+	Parameters map[string]string `yaml:"parameters"`
+	// SecretFiles and SecretEnv read backend options from files or environment variables,
+	// so that secrets never appear in the jobs file.
+	SecretFiles map[string]string `yaml:"secret_files"`
+	SecretEnv   map[string]string `yaml:"secret_env"`
+}
+
+// Job is a job definition in the jobs file.
+type Job struct {
+	Name        string `yaml:"name"`
+	Source      string `yaml:"source"`
+	Destination string `yaml:"destination"`
+	// Destinations fans the job out to several destinations, transferred in parallel.
+	// Exclusive with Destination.
+	Destinations []string `yaml:"destinations"`
+	// Require is how many destinations must succeed for a run to succeed: all (default)
+	// or any.
+	Require string `yaml:"require"`
+	// Mode is the operation of the runs: sync (default), copy, move or bisync.
+	Mode string `yaml:"mode"`
+	// Interval between scheduled runs (e.g. 6h). Empty uses BG_SYNC_INTERVAL.
+	Interval string `yaml:"interval"`
+	// CatchUp is the catch-up policy for runs missed while the runner was down:
+	// once (default), all or skip.
+	CatchUp string `yaml:"catch_up"`
+	// RPO is the maximum age of the last successful run (e.g. 26h) before the job is
+	// reported stale. Omitted disables staleness alerting.
+	RPO string `yaml:"rpo"`
+	// Windows restricts when the job may run. Omitted means at any time.
+	Windows *Windows `yaml:"windows"`
+	// Bandwidth limits transfer rates. Omitted means unlimited.
+	Bandwidth *Bandwidth `yaml:"bandwidth"`
+	// Filters selects the synced files with rclone filter rules. Omitted syncs everything.
+	Filters *Filters `yaml:"filters"`
+	// Encryption encrypts the destinations client-side with rclone crypt. Omitted stores
+	// files as they are.
+	Encryption *Encryption `yaml:"encryption"`
+	// Heartbeat pings an external monitor on each run. Omitted sends no ping.
+	Heartbeat *Heartbeat `yaml:"heartbeat"`
+	// Hooks are commands or HTTP calls run before and after each sync.
+	Hooks []Hook `yaml:"hooks"`
+	// After are the jobs this job runs after, instead of on a schedule.
+	After []Dependency `yaml:"after"`
+	// Capacity checks that the changes of each run fit on the destinations. Omitted runs
+	// without checking.
+	Capacity *Capacity `yaml:"capacity"`
+	// Anomaly flags the runs that deviate from the previous runs of the job. Omitted checks
+	// no run.
+	Anomaly *Anomaly `yaml:"anomaly"`
+	// Canaries are files of the source verified before each run, which aborts without syncing
+	// when any is missing or altered.
+	Canaries []Canary `yaml:"canaries"`
+	// Manifest records, after each successful run, a manifest of the files of the destinations,
+	// chained to the previous one, in the database and on the destinations. Local and sftp
+	// destinations, and those without hashes, are read back to hash the files that changed.
+	Manifest bool `yaml:"manifest"`
+	// Scrub makes the job a scrub job: instead of syncing, its runs download a sample of the
+	// files of the destinations of another job and compare them with its source or its latest
+	// manifest. Scrub jobs set no source nor destination.
+	Scrub *Scrub `yaml:"scrub"`
+}
+
+// Scrub defines the job a scrub job scrubs, and how.
+type Scrub struct {
+	// Job is the name of the scrubbed job.
+	Job string `yaml:"job"`
+	// Sample is the amount checked on each destination by each run: a percentage of the files
+	// (e.g. 5%) or an rclone size to download (e.g. 20G).
+	Sample string `yaml:"sample"`
+	// Against is source (default), comparing the files with the source of the job, or manifest,
+	// comparing them with its latest manifest.
+	Against string `yaml:"against"`
+}
+
+// Canary defines a file with known content placed in the source of a job.
+type Canary struct {
+	// Path is the path of the file, relative to the source.
+	Path string `yaml:"path"`
+	// SHA256 is the hex SHA-256 hash of the content of the file. Content can be given instead,
+	// for text files: the file must hold exactly that text.
+	SHA256  string `yaml:"sha256"`
+	Content string `yaml:"content"`
+}
+
+// Anomaly defines the detection of anomalous runs of a job.
+type Anomaly struct {
+	// Sensitivity is low, medium (default) or high: the higher, the smaller the deviations
+	// flagged.
+	Sensitivity string `yaml:"sensitivity"`
+	// Action is notify (default), pause, or copy: pause fails the anomalous run without syncing
+	// and pauses the job, and copy runs it in copy mode, until the anomaly is acknowledged.
+	Action string `yaml:"action"`
+}
+
+// Capacity defines the free space check of a job.
+type Capacity struct {
+	// Check pre-scans the changes of each run and refuses the run when the destinations do not
+	// have enough free space for them.
+	Check bool `yaml:"check"`
+	// Reserve is the space to keep free on the destinations, as an rclone size (e.g. 10G).
+	Reserve string `yaml:"reserve"`
+}
+
+// Dependency makes a job run after a run of another job.
+type Dependency struct {
+	Job string `yaml:"job"`
+	// On is the outcome of the run of Job that triggers the job: success (default), failure
+	// or completion.
+	On string `yaml:"on"`
+}
+
+// Heartbeat defines the URLs pinged when a run starts, succeeds, fails or is deferred.
+type Heartbeat struct {
+	// URL is a healthchecks.io style check URL: pinged on success, with /start on start,
+	// /fail on failure and /log when a run is deferred. The URLs below override it.
+	URL         string `yaml:"url"`
+	StartURL    string `yaml:"start_url"`
+	SuccessURL  string `yaml:"success_url"`
+	FailURL     string `yaml:"fail_url"`
+	DeferredURL string `yaml:"deferred_url"`
+	// Timeout bounds each ping (e.g. 10s).
+	Timeout string `yaml:"timeout"`
+}
+
+// Hook defines a command or HTTP call run at some point of each run.
+type Hook struct {
+	Name string `yaml:"name"`
+	// Event is when the hook runs: pre_sync, post_success, post_failure or always.
+	Event string `yaml:"event"`
+	// Command is the program and its arguments, run without a shell.
+	Command []string `yaml:"command"`
+	// Env names the variables of the runner passed to Command, besides PATH, HOME and BG_*.
+	Env []string `yaml:"env"`
+	// URL receives the run as JSON, with Method (POST by default) and Headers.
+	URL     string            `yaml:"url"`
+	Method  string            `yaml:"method"`
+	Headers map[string]string `yaml:"headers"`
+	// Timeout bounds the hook (e.g. 10m). Empty uses 5m.
+	Timeout string `yaml:"timeout"`
+	// FailRun fails the run, without syncing, when a pre_sync hook fails.
+	FailRun bool `yaml:"fail_run"`
+}
+
+// Filters defines the rclone filters of a job (see https://rclone.org/filtering/).
+type Filters struct {
+	Include []string `yaml:"include"`
+	Exclude []string `yaml:"exclude"`
+	// Rules are filter rules ("+ pattern" or "- pattern"), the first match wins.
+	Rules []string `yaml:"rules"`
+	// FilterFrom are paths of filter rules files.
+	FilterFrom []string `yaml:"filter_from"`
+	// ExcludeIfPresent excludes the directories containing a file with one of these names.
+	ExcludeIfPresent []string `yaml:"exclude_if_present"`
+	// MinSize and MaxSize are rclone sizes (e.g. 100K, 4G).
+	MinSize string `yaml:"min_size"`
+	MaxSize string `yaml:"max_size"`
+	// MaxAge is an rclone duration (e.g. 12h, 30d, 1y).
+	MaxAge string `yaml:"max_age"`
+}
+
+// Encryption defines the rclone crypt encryption of the destinations of a job
+// (see https://rclone.org/crypt/). Secrets are read from files or environment variables, so
+// that they never appear in the jobs file.
+type Encryption struct {
+	// PasswordFile or PasswordEnv provides the password; set exactly one.
+	PasswordFile string `yaml:"password_file"`
+	PasswordEnv  string `yaml:"password_env"`
+	// SaltFile or SaltEnv provides the salt (rclone's password2). Omitted uses rclone's
+	// default salt.
+	SaltFile string `yaml:"salt_file"`
+	SaltEnv  string `yaml:"salt_env"`
+	// FilenameEncryption is standard (default), obfuscate or off.
+	FilenameEncryption string `yaml:"filename_encryption"`
+	// DirectoryNameEncryption encrypts directory names too. Defaults to true.
+	DirectoryNameEncryption *bool `yaml:"directory_name_encryption"`
+	// FilenameEncoding is base32 (default), base64 or base32768.
+	FilenameEncoding string `yaml:"filename_encoding"`
+}
+
+// Bandwidth defines the bandwidth limits of a job, either constant or by time of day.
+// Rates are rclone sizes per second (e.g. 512K, 1M, 10M, off).
+type Bandwidth struct {
+	// TimeZone is an IANA time zone name for the timetable. Empty means UTC.
+	TimeZone string `yaml:"time_zone"`
+	// Upload and Download are constant limits, used when Timetable is empty.
+	Upload   string `yaml:"upload"`
+	Download string `yaml:"download"`
+	// Timetable sets limits by time of day; each entry applies until the next one starts.
+	Timetable []BandwidthSlot `yaml:"timetable"`
+}
+
+// BandwidthSlot sets bandwidth limits from Start ("15:04") on some days of the week.
+type BandwidthSlot struct {
+	// Days are mon, tue, wed, thu, fri, sat, sun. Empty means every day.
+	Days     []string `yaml:"days"`
+	Start    string   `yaml:"start"`
+	Upload   string   `yaml:"upload"`
+	Download string   `yaml:"download"`
+}
+
+// Windows defines when a job may run.
+type Windows struct {
+	// TimeZone is an IANA time zone name (e.g. Europe/Paris). Empty means UTC.
+	TimeZone string `yaml:"time_zone"`
+	// Allowed are the periods of the week during which runs may start. Empty means all day, every day.
+	Allowed []Window `yaml:"allowed"`
+	// Blackouts are dates (2006-01-02) or inclusive date ranges (2006-01-02/2006-01-05)
+	// during which the job never runs.
+	Blackouts []string `yaml:"blackouts"`
+	// StopOnClose stops a running sync when its window closes and resumes it in the next one.
+	StopOnClose bool `yaml:"stop_on_close"`
+}
+
+// Window is a daily period on some days of the week. Start and End are "15:04" clock times;
+// an End before Start spans midnight, and omitting both covers the whole day.
+type Window struct {
+	// Days are mon, tue, wed, thu, fri, sat, sun. Empty means every day.
+	Days  []string `yaml:"days"`
+	Start string   `yaml:"start"`
+	End   string   `yaml:"end"`
+}
+
+// Jobs returns the jobs configured by vars: those of the jobs file when BG_JOBS_FILE
+// is set, otherwise the single job described by BG_SYNC_SOURCE and BG_SYNC_DEST.
+func Jobs(vars *environment.Variables) ([]*domain.SyncJob, error) {
+	if vars.JobsFile == "" {
+		job := vars.SyncJob()
+		if err := job.Validate(); err != nil {
+			return nil, fmt.Errorf("job %q: %w", job.Name, err)
+		}
+		return []*domain.SyncJob{job}, nil
+	}
+
+	file, err := Load(vars.JobsFile)
+	if err != nil {
+		return nil, err
+	}
+
+	return file.SyncJobs()
+}
+
+// Remotes returns the remotes defined in the jobs file, none when BG_JOBS_FILE is not set.
+func Remotes(vars *environment.Variables) ([]*domain.Remote, error) {
+	if vars.JobsFile == "" {
+		return nil, nil
+	}
+
+	file, err := Load(vars.JobsFile)
+	if err != nil {
+		return nil, err
+	}
+
+	return file.SyncRemotes()
+}
+
+// Load reads and parses the jobs file at path. Unknown keys are rejected.
+func Load(path string) (*File, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not open jobs file: %w", err)
+	}
+	defer f.Close()
+
+	decoder := yaml.NewDecoder(f)
+	decoder.KnownFields(true)
+
+	result := &File{}
+	if err := decoder.Decode(result); err != nil {
+		return nil, fmt.Errorf("could not parse jobs file %s: %w", path, err)
+	}
+
+	return result, nil
+}
+
+// SyncJobs converts and validates the jobs of the file.
+func (f *File) SyncJobs() ([]*domain.SyncJob, error) {
+	if len(f.Jobs) == 0 {
+		return nil, fmt.Errorf("jobs file defines no job")
+	}
+
+	result := make([]*domain.SyncJob, len(f.Jobs))
+	names := map[string]bool{}
+	for i := range f.Jobs {
+		job, err := f.Jobs[i].syncJob()
+		if err != nil {
+			return nil, fmt.Errorf("job %q: %w", f.Jobs[i].Name, err)
+		}
+		if names[job.Name] {
+			return nil, fmt.Errorf("job %q: defined more than once", job.Name)
+		}
+		names[job.Name] = true
+
+		result[i] = job
+	}
+
+	if err := domain.ValidateDependencies(result); err != nil {
+		return nil, err
+	}
+	if err := domain.ResolveScrubs(result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// SyncRemotes converts and validates the remotes of the file.
+func (f *File) SyncRemotes() ([]*domain.Remote, error) {
+	result := make([]*domain.Remote, len(f.Remotes))
+	names := map[string]bool{}
+	for i, r := range f.Remotes {
+		remote := &domain.Remote{
+			Name:        r.Name,
+			Type:        r.Type,
+			Parameters:  r.Parameters,
+			SecretFiles: r.SecretFiles,
+			SecretEnv:   r.SecretEnv,
+			Origin:      domain.RemoteOriginConfig,
+		}
+		if err := remote.Validate(); err != nil {
+			return nil, fmt.Errorf("remote %q: %w", r.Name, err)
+		}
+		if names[remote.Name] {
+			return nil, fmt.Errorf("remote %q: defined more than once", remote.Name)
+		}
+		names[remote.Name] = true
+
+		result[i] = remote
+	}
+
+	return result, nil
+}
+
+func (j *Job) syncJob() (*domain.SyncJob, error) {
+	job := &domain.SyncJob{
+		Name:         j.Name,
+		Source:       j.Source,
+		Destination:  j.Destination,
+		Destinations: j.Destinations,
+		Require:      j.Require,
+		Mode:         j.Mode,
+		CatchUp:      j.CatchUp,
+		Manifest:     j.Manifest,
+	}
+
+	if j.Destination != "" && len(j.Destinations) > 0 {
+		return nil, fmt.Errorf("destination and destinations are mutually exclusive")
+	}
+
+	if j.Interval != "" {
+		interval, err := time.ParseDuration(j.Interval)
+		if err != nil {
+			return nil, fmt.Errorf("invalid interval %q: %w", j.Interval, err)
+		}
+		job.Interval = interval
+	}
+
+	if j.RPO != "" {
+		rpo, err := time.ParseDuration(j.RPO)
+		if err != nil {
+			return nil, fmt.Errorf("invalid rpo %q: %w", j.RPO, err)
+		}
+		job.RPO = rpo
+	}
+
+	if j.Windows != nil {
+		windows, err := j.Windows.runWindows()
+		if err != nil {
+			return nil, err
+		}
+		job.Windows = windows
+	}
+
+	if j.Bandwidth != nil {
+		bandwidth, err := j.Bandwidth.bandwidthSchedule()
+		if err != nil {
+			return nil, err
+		}
+		job.Bandwidth = bandwidth
+	}
+
+	if j.Filters != nil {
+		filters, err := j.Filters.filters()
+		if err != nil {
+			return nil, err
+		}
+		job.Filters = filters
+	}
+
+	if j.Encryption != nil {
+		job.Encryption = j.Encryption.encryption()
+	}
+
+	if j.Heartbeat != nil {
+		heartbeat, err := j.Heartbeat.heartbeat()
+		if err != nil {
+			return nil, err
+		}
+		job.Heartbeat = heartbeat
+	}
+
+	for i := range j.Hooks {
+		hook, err := j.Hooks[i].hook()
+		if err != nil {
+			return nil, err
+		}
+		job.Hooks = append(job.Hooks, hook)
+	}
+
+	for _, dep := range j.After {
+		job.After = append(job.After, &domain.Dependency{Job: dep.Job, On: dep.On})
+	}
+
+	if j.Capacity != nil {
+		reserve, err := parseSize(j.Capacity.Reserve)
+		if err != nil {
+			return nil, fmt.Errorf("invalid capacity reserve: %w", err)
+		}
+		job.Capacity = &domain.Capacity{Check: j.Capacity.Check, Reserve: reserve}
+	}
+
+	if j.Anomaly != nil {
+		job.Anomaly = &domain.AnomalyDetection{Sensitivity: j.Anomaly.Sensitivity, Action: j.Anomaly.Action}
+	}
+
+	for _, c := range j.Canaries {
+		canary, err := c.canary()
+		if err != nil {
+			return nil, err
+		}
+		job.Canaries = append(job.Canaries, canary)
+	}
+
+	if j.Scrub != nil {
+		if j.Source != "" || j.Destination != "" || len(j.Destinations) > 0 || j.Encryption != nil {
+			return nil, fmt.Errorf("scrub jobs use the source, destinations and encryption of the job they scrub")
+		}
+		scrub, err := j.Scrub.scrub()
+		if err != nil {
+			return nil, err
+		}
+		job.Scrub = scrub
+	}
+
+	if err := job.Validate(); err != nil {
+		return nil, err
+	}
+
+	if job.Filters != nil {
+		// Compiling the filters checks the rules and the filter files.
+		if _, err := options.NewFilter(job.Filters); err != nil {
+			return nil, fmt.Errorf("invalid filters: %w", err)
+		}
+	}
+
+	return job, nil
+}
+
+func (c *Canary) canary() (*domain.Canary, error) {
+	if c.SHA256 != "" && c.Content != "" {
+		return nil, fmt.Errorf("canary %s: sha256 and content are mutually exclusive", c.Path)
+	}
+	if c.Content != "" {
+		hash := sha256.Sum256([]byte(c.Content))
+		return &domain.Canary{Path: c.Path, SHA256: hex.EncodeToString(hash[:])}, nil
+	}
+
+	return &domain.Canary{Path: c.Path, SHA256: strings.ToLower(c.SHA256)}, nil
+}
+
+func (s *Scrub) scrub() (*domain.Scrub, error) {
+	result := &domain.Scrub{Job: s.Job, Against: s.Against}
+
+	if percent, ok := strings.CutSuffix(strings.TrimSpace(s.Sample), "%"); ok {
+		value, err := strconv.ParseFloat(strings.TrimSpace(percent), 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid scrub sample %q: %w", s.Sample, err)
+		}
+		result.Percent = value
+		return result, nil
+	}
+
+	bytes, err := parseSize(s.Sample)
+	if err != nil {
+		return nil, fmt.Errorf("invalid scrub sample: %w", err)
+	}
+	result.Bytes = bytes
+
+	return result, nil
+}
+
+func (f *Filters) filters() (*domain.Filters, error) {
+	result := &domain.Filters{
+		Include:          f.Include,
+		Exclude:          f.Exclude,
+		Rules:            f.Rules,
+		FilterFrom:       f.FilterFrom,
+		ExcludeIfPresent: f.ExcludeIfPresent,
+	}
+
+	var err error
+	if result.MinSize, err = parseSize(f.MinSize); err != nil {
+		return nil, err
+	}
+	if result.MaxSize, err = parseSize(f.MaxSize); err != nil {
+		return nil, err
+	}
+
+	if f.MaxAge != "" {
+		var age fs.Duration
+		if err := age.Set(f.MaxAge); err != nil {
+			return nil, fmt.Errorf("invalid max_age %q: %w", f.MaxAge, err)
+		}
+		result.MaxAge = time.Duration(age)
+	}
+
+	return result, nil
+}
+
+func (e *Encryption) encryption() *domain.Encryption {
+	return &domain.Encryption{
+		PasswordFile:        e.PasswordFile,
+		PasswordEnv:         e.PasswordEnv,
+		SaltFile:            e.SaltFile,
+		SaltEnv:             e.SaltEnv,
+		FilenameEncryption:  e.FilenameEncryption,
+		PlainDirectoryNames: e.DirectoryNameEncryption != nil && !*e.DirectoryNameEncryption,
+		FilenameEncoding:    e.FilenameEncoding,
+	}
+}
+
+// parseSize parses an rclone size (e.g. 10M). Empty and "off" mean no bound (0).
+func parseSize(value string) (int64, error) {
+	if value == "" {
+		return 0, nil
+	}
+
+	var size fs.SizeSuffix
+	if err := size.Set(value); err != nil {
+		return 0, fmt.Errorf("invalid size %q: %w", value, err)
+	}
+
+	return max(int64(size), 0), nil
+}
+
+func (h *Heartbeat) heartbeat() (*domain.Heartbeat, error) {
+	result := &domain.Heartbeat{}
+	if h.URL != "" {
+		result = domain.HeartbeatFromURL(h.URL)
+	}
+
+	if h.StartURL != "" {
+		result.StartURL = h.StartURL
+	}
+	if h.SuccessURL != "" {
+		result.SuccessURL = h.SuccessURL
+	}
+	if h.FailURL != "" {
+		result.FailURL = h.FailURL
+	}
+	if h.DeferredURL != "" {
+		result.DeferredURL = h.DeferredURL
+	}
+
+	if h.Timeout != "" {
+		timeout, err := time.ParseDuration(h.Timeout)
+		if err != nil {
+			return nil, fmt.Errorf("invalid heartbeat timeout %q: %w", h.Timeout, err)
+		}
+		result.Timeout = timeout
+	}
+
+	return result, nil
+}
+
+func (h *Hook) hook() (*domain.Hook, error) {
+	result := &domain.Hook{
+		Name:    h.Name,
+		Event:   h.Event,
+		Command: h.Command,
+		Env:     h.Env,
+		URL:     h.URL,
+		Method:  strings.ToUpper(h.Method),
+		Headers: h.Headers,
+		FailRun: h.FailRun,
+	}
+
+	if h.Timeout != "" {
+		timeout, err := time.ParseDuration(h.Timeout)
+		if err != nil {
+			return nil, fmt.Errorf("invalid hook timeout %q: %w", h.Timeout, err)
+		}
+		result.Timeout = timeout
+	}
+
+	return result, nil
+}
+
+func (b *Bandwidth) bandwidthSchedule() (*domain.BandwidthSchedule, error) {
+	result := &domain.BandwidthSchedule{}
+
+	if b.TimeZone != "" {
+		loc, err := time.LoadLocation(b.TimeZone)
+		if err != nil {
+			return nil, fmt.Errorf("invalid time zone %q: %w", b.TimeZone, err)
+		}
+		result.Location = loc
+	}
+
+	var err error
+	if result.Limit, err = parseBandwidthLimit(b.Upload, b.Download); err != nil {
+		return nil, err
+	}
+
+	for _, s := range b.Timetable {
+		slot := domain.BandwidthSlot{}
+		if slot.Days, err = parseWeekdays(s.Days); err != nil {
+			return nil, err
+		}
+		if s.Start == "" {
+			return nil, fmt.Errorf("bandwidth timetable entry must set start")
+		}
+		if slot.Start, err = parseClock(s.Start); err != nil {
+			return nil, err
+		}
+		if slot.Limit, err = parseBandwidthLimit(s.Upload, s.Download); err != nil {
+			return nil, err
+		}
+		result.Slots = append(result.Slots, slot)
+	}
+
+	return result, nil
+}
+
+func parseBandwidthLimit(upload, download string) (domain.BandwidthLimit, error) {
+	var limit domain.BandwidthLimit
+	var err error
+	if limit.Upload, err = parseRate(upload); err != nil {
+		return limit, err
+	}
+	if limit.Download, err = parseRate(download); err != nil {
+		return limit, err
+	}
+
+	return limit, nil
+}
+
+// parseRate parses an rclone size per second (e.g. 1M). Empty and "off" mean unlimited (0).
+func parseRate(value string) (int64, error) {
+	if value == "" {
+		return 0, nil
+	}
+
+	var size fs.SizeSuffix
+	if err := size.Set(value); err != nil {
+		return 0, fmt.Errorf("invalid bandwidth %q: %w", value, err)
+	}
+	if size < 0 {
+		return 0, nil
+	}
+
+	return int64(size), nil
+}
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday,
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+}
+
+func (w *Windows) runWindows() (*domain.RunWindows, error) {
+	result := &domain.RunWindows{StopOnClose: w.StopOnClose}
+
+	if w.TimeZone != "" {
+		loc, err := time.LoadLocation(w.TimeZone)
+		if err != nil {
+			return nil, fmt.Errorf("invalid time zone %q: %w", w.TimeZone, err)
+		}
+		result.Location = loc
+	}
+
+	for _, allowed := range w.Allowed {
+		window, err := allowed.timeWindow()
+		if err != nil {
+			return nil, err
+		}
+		result.Windows = append(result.Windows, window)
+	}
+
+	for _, blackout := range w.Blackouts {
+		dates, err := parseDateRange(blackout)
+		if err != nil {
+			return nil, err
+		}
+		result.Blackouts = append(result.Blackouts, dates)
+	}
+
+	return result, nil
+}
+
+func (w *Window) timeWindow() (domain.TimeWindow, error) {
+	var result domain.TimeWindow
+
+	var err error
+	if result.Days, err = parseWeekdays(w.Days); err != nil {
+		return result, err
+	}
+
+	if (w.Start == "") != (w.End == "") {
+		return result, fmt.Errorf("window must set both start and end, or neither")
+	}
+	if w.Start == "" {
+		return result, nil
+	}
+
+	if result.Start, err = parseClock(w.Start); err != nil {
+		return result, err
+	}
+	if result.End, err = parseClock(w.End); err != nil {
+		return result, err
+	}
+
+	return result, nil
+}
+
+func parseWeekdays(days []string) ([]time.Weekday, error) {
+	var result []time.Weekday
+	for _, day := range days {
+		weekday, ok := weekdays[strings.ToLower(day)]
+		if !ok {
+			return nil, fmt.Errorf("invalid day %q, expected one of mon, tue, wed, thu, fri, sat, sun", day)
+		}
+		result = append(result, weekday)
+	}
+
+	return result, nil
+}
+
+// parseClock parses a "15:04" clock time, or "24:00", into an offset from midnight.
+func parseClock(value string) (time.Duration, error) {
+	if value == "24:00" {
+		return 24 * time.Hour, nil
+	}
+
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q, expected HH:MM", value)
+	}
+
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+// parseDateRange parses a date (2006-01-02) or an inclusive date range (2006-01-02/2006-01-05).
+func parseDateRange(value string) (domain.DateRange, error) {
+	from, to, isRange := strings.Cut(value, "/")
+	if !isRange {
+		to = from
+	}
+
+	var result domain.DateRange
+	var err error
+	if result.From, err = time.Parse(time.DateOnly, strings.TrimSpace(from)); err != nil {
+		return result, fmt.Errorf("invalid blackout %q, expected 2006-01-02 or 2006-01-02/2006-01-05", value)
+	}
+	if result.To, err = time.Parse(time.DateOnly, strings.TrimSpace(to)); err != nil {
+		return result, fmt.Errorf("invalid blackout %q, expected 2006-01-02 or 2006-01-02/2006-01-05", value)
+	}
+
+	return result, nil
+}
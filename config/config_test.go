@@ -0,0 +1,539 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/eva01/backup-guardian/domain"
+	"github.com/eva01/backup-guardian/environment"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeJobsFile(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "jobs.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	return path
+}
+
+func TestJobs_Environment(t *testing.T) {
+	vars := &environment.Variables{SyncSource: "gdrive:", SyncDest: "s3:bucket", SyncCatchUp: domain.CatchUpSkip}
+
+	jobs, err := Jobs(vars)
+	require.NoError(t, err)
+	require.Len(t, jobs, 1)
+	assert.Equal(t, environment.SyncJobName, jobs[0].Name)
+	assert.Equal(t, domain.CatchUpSkip, jobs[0].CatchUp)
+}
+
+func TestJobs_File(t *testing.T) {
+	path := writeJobsFile(t, `
+jobs:
+  - name: gdrive-to-s3
+    source: "gdrive:"
+    destination: "s3:bucket/backups"
+    interval: 6h
+    catch_up: all
+    rpo: 26h
+  - name: photos-to-b2
+    source: "gdrive:Photos"
+    destination: "b2:photos"
+    mode: copy
+  - name: drive-3-2-1
+    source: "gdrive:"
+    destinations: ["s3:bucket/drive", "b2:drive"]
+    require: any
+`)
+
+	jobs, err := Jobs(&environment.Variables{JobsFile: path})
+	require.NoError(t, err)
+	require.Len(t, jobs, 3)
+
+	assert.Equal(t, &domain.SyncJob{
+		Name:        "gdrive-to-s3",
+		Source:      "gdrive:",
+		Destination: "s3:bucket/backups",
+		Interval:    6 * time.Hour,
+		CatchUp:     domain.CatchUpAll,
+		RPO:         26 * time.Hour,
+	}, jobs[0])
+	assert.Equal(t, "photos-to-b2", jobs[1].Name)
+	assert.Equal(t, domain.ModeCopy, jobs[1].Mode)
+	assert.Zero(t, jobs[1].Interval)
+	assert.Equal(t, []string{"s3:bucket/drive", "b2:drive"}, jobs[2].Destinations)
+	assert.Equal(t, domain.RequireAny, jobs[2].Require)
+}
+
+func TestJobs_FileWindows(t *testing.T) {
+	path := writeJobsFile(t, `
+jobs:
+  - name: gdrive-to-s3
+    source: "gdrive:"
+    destination: "s3:bucket/backups"
+    windows:
+      time_zone: Europe/Paris
+      allowed:
+        - days: [mon, tue, wed, thu, fri]
+          start: "22:00"
+          end: "06:00"
+        - days: [sat, sun]
+      blackouts: ["2026-12-24", "2026-12-31/2027-01-01"]
+      stop_on_close: true
+`)
+
+	jobs, err := Jobs(&environment.Variables{JobsFile: path})
+	require.NoError(t, err)
+	require.Len(t, jobs, 1)
+
+	windows := jobs[0].Windows
+	require.NotNil(t, windows)
+	assert.Equal(t, "Europe/Paris", windows.Location.String())
+	assert.True(t, windows.StopOnClose)
+	assert.Equal(t, []domain.TimeWindow{
+		{
+			Days:  []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday},
+			Start: 22 * time.Hour,
+			End:   6 * time.Hour,
+		},
+		{Days: []time.Weekday{time.Saturday, time.Sunday}},
+	}, windows.Windows)
+	assert.Equal(t, []domain.DateRange{
+		{From: time.Date(2026, 12, 24, 0, 0, 0, 0, time.UTC), To: time.Date(2026, 12, 24, 0, 0, 0, 0, time.UTC)},
+		{From: time.Date(2026, 12, 31, 0, 0, 0, 0, time.UTC), To: time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC)},
+	}, windows.Blackouts)
+}
+
+func TestJobs_FileBandwidth(t *testing.T) {
+	path := writeJobsFile(t, `
+jobs:
+  - name: constant
+    source: "gdrive:"
+    destination: "s3:bucket/constant"
+    bandwidth:
+      upload: 512K
+      download: 2M
+  - name: timetable
+    source: "gdrive:"
+    destination: "s3:bucket/timetable"
+    bandwidth:
+      time_zone: Europe/Paris
+      timetable:
+        - days: [mon, tue, wed, thu, fri]
+          start: "08:00"
+          upload: 1M
+          download: 1M
+        - start: "19:00"
+          upload: "off"
+`)
+
+	jobs, err := Jobs(&environment.Variables{JobsFile: path})
+	require.NoError(t, err)
+	require.Len(t, jobs, 2)
+
+	assert.Equal(t, domain.BandwidthLimit{Upload: 512 << 10, Download: 2 << 20}, jobs[0].Bandwidth.Limit)
+	assert.Empty(t, jobs[0].Bandwidth.Slots)
+
+	timetable := jobs[1].Bandwidth
+	assert.Equal(t, "Europe/Paris", timetable.Location.String())
+	assert.Equal(t, []domain.BandwidthSlot{
+		{
+			Days:  []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday},
+			Start: 8 * time.Hour,
+			Limit: domain.BandwidthLimit{Upload: 1 << 20, Download: 1 << 20},
+		},
+		{Start: 19 * time.Hour},
+	}, timetable.Slots)
+}
+
+func TestJobs_FileHeartbeat(t *testing.T) {
+	path := writeJobsFile(t, `
+jobs:
+  - name: healthchecks
+    source: "gdrive:"
+    destination: "s3:bucket/a"
+    heartbeat:
+      url: https://hc-ping.com/abc/
+      timeout: 5s
+  - name: kuma
+    source: "gdrive:"
+    destination: "s3:bucket/b"
+    heartbeat:
+      success_url: https://kuma.example.com/api/push/abc?status=up
+      fail_url: https://kuma.example.com/api/push/abc?status=down
+`)
+
+	jobs, err := Jobs(&environment.Variables{JobsFile: path})
+	require.NoError(t, err)
+	require.Len(t, jobs, 2)
+
+	assert.Equal(t, &domain.Heartbeat{
+		StartURL:    "https://hc-ping.com/abc/start",
+		SuccessURL:  "https://hc-ping.com/abc",
+		FailURL:     "https://hc-ping.com/abc/fail",
+		DeferredURL: "https://hc-ping.com/abc/log",
+		Timeout:     5 * time.Second,
+	}, jobs[0].Heartbeat)
+	assert.Equal(t, &domain.Heartbeat{
+		SuccessURL: "https://kuma.example.com/api/push/abc?status=up",
+		FailURL:    "https://kuma.example.com/api/push/abc?status=down",
+	}, jobs[1].Heartbeat)
+}
+
+func TestJobs_FileFilters(t *testing.T) {
+	filterFile := filepath.Join(t.TempDir(), "filters.txt")
+	require.NoError(t, os.WriteFile(filterFile, []byte("- cache/**\n"), 0o644))
+
+	path := writeJobsFile(t, `
+jobs:
+  - name: filtered
+    source: "gdrive:"
+    destination: "s3:bucket/a"
+    filters:
+      rules: ["- node_modules/**", "- *.tmp"]
+      filter_from: [`+filterFile+`]
+      exclude_if_present: [.nobackup]
+      min_size: 1K
+      max_size: "off"
+      max_age: 30d
+  - name: subtree
+    source: "gdrive:"
+    destination: "s3:bucket/b"
+    filters:
+      include: ["/photos/**"]
+`)
+
+	jobs, err := Jobs(&environment.Variables{JobsFile: path})
+	require.NoError(t, err)
+	require.Len(t, jobs, 2)
+
+	assert.Equal(t, &domain.Filters{
+		Rules:            []string{"- node_modules/**", "- *.tmp"},
+		FilterFrom:       []string{filterFile},
+		ExcludeIfPresent: []string{".nobackup"},
+		MinSize:          1024,
+		MaxAge:           30 * 24 * time.Hour,
+	}, jobs[0].Filters)
+	assert.Equal(t, &domain.Filters{Include: []string{"/photos/**"}}, jobs[1].Filters)
+}
+
+func TestJobs_FileEncryption(t *testing.T) {
+	path := writeJobsFile(t, `
+jobs:
+  - name: encrypted
+    source: "gdrive:"
+    destination: "s3:bucket/a"
+    encryption:
+      password_file: /run/secrets/backup-password
+      salt_env: BACKUP_SALT
+  - name: plain-dirs
+    source: "gdrive:"
+    destination: "s3:bucket/b"
+    encryption:
+      password_env: BACKUP_PASSWORD
+      filename_encryption: obfuscate
+      directory_name_encryption: false
+      filename_encoding: base64
+`)
+
+	jobs, err := Jobs(&environment.Variables{JobsFile: path})
+	require.NoError(t, err)
+	require.Len(t, jobs, 2)
+
+	assert.Equal(t, &domain.Encryption{PasswordFile: "/run/secrets/backup-password", SaltEnv: "BACKUP_SALT"}, jobs[0].Encryption)
+	assert.Equal(t, &domain.Encryption{
+		PasswordEnv:         "BACKUP_PASSWORD",
+		FilenameEncryption:  domain.FilenameEncryptionObfuscate,
+		PlainDirectoryNames: true,
+		FilenameEncoding:    "base64",
+	}, jobs[1].Encryption)
+}
+
+func TestJobs_FileHooks(t *testing.T) {
+	path := writeJobsFile(t, `
+jobs:
+  - name: hooked
+    source: "gdrive:"
+    destination: "s3:bucket/a"
+    hooks:
+      - name: dump
+        event: pre_sync
+        command: [pg_dump, -f, /exports/db.sql]
+        env: [PGPASSWORD]
+        timeout: 10m
+        fail_run: true
+      - event: always
+        url: https://tools.example.com/results
+        method: put
+        headers: {Authorization: Bearer x}
+`)
+
+	jobs, err := Jobs(&environment.Variables{JobsFile: path})
+	require.NoError(t, err)
+	require.Len(t, jobs, 1)
+
+	assert.Equal(t, []*domain.Hook{
+		{Name: "dump", Event: domain.HookPreSync, Command: []string{"pg_dump", "-f", "/exports/db.sql"}, Env: []string{"PGPASSWORD"}, Timeout: 10 * time.Minute, FailRun: true},
+		{Event: domain.HookAlways, URL: "https://tools.example.com/results", Method: "PUT", Headers: map[string]string{"Authorization": "Bearer x"}},
+	}, jobs[0].Hooks)
+}
+
+func TestJobs_FileDependencies(t *testing.T) {
+	path := writeJobsFile(t, `
+jobs:
+  - name: drive-to-nas
+    source: "gdrive:"
+    destination: "nas:drive"
+  - name: nas-to-s3
+    source: "nas:drive"
+    destination: "s3:bucket/drive"
+    after:
+      - job: drive-to-nas
+  - name: report
+    source: "s3:bucket/drive"
+    destination: "/tmp/report"
+    after:
+      - {job: nas-to-s3, on: completion}
+`)
+
+	jobs, err := Jobs(&environment.Variables{JobsFile: path})
+	require.NoError(t, err)
+	require.Len(t, jobs, 3)
+
+	assert.Empty(t, jobs[0].After)
+	assert.Equal(t, []*domain.Dependency{{Job: "drive-to-nas"}}, jobs[1].After)
+	assert.Equal(t, []*domain.Dependency{{Job: "nas-to-s3", On: domain.DependOnCompletion}}, jobs[2].After)
+}
+
+func TestRemotes_File(t *testing.T) {
+	path := writeJobsFile(t, `
+remotes:
+  - name: nas
+    type: sftp
+    parameters:
+      host: nas.local
+      user: backup
+    secret_files:
+      pass: /run/secrets/nas
+  - name: offsite
+    type: s3
+    parameters:
+      provider: AWS
+    secret_env:
+      secret_access_key: AWS_SECRET
+jobs:
+  - name: nas-to-s3
+    source: "nas:data"
+    destination: "offsite:bucket"
+`)
+
+	remotes, err := Remotes(&environment.Variables{JobsFile: path})
+	require.NoError(t, err)
+	require.Len(t, remotes, 2)
+	assert.Equal(t, &domain.Remote{
+		Name:        "nas",
+		Type:        "sftp",
+		Parameters:  map[string]string{"host": "nas.local", "user": "backup"},
+		SecretFiles: map[string]string{"pass": "/run/secrets/nas"},
+		Origin:      domain.RemoteOriginConfig,
+	}, remotes[0])
+	assert.Equal(t, map[string]string{"secret_access_key": "AWS_SECRET"}, remotes[1].SecretEnv)
+
+	remotes, err = Remotes(&environment.Variables{SyncSource: "gdrive:", SyncDest: "s3:bucket"})
+	require.NoError(t, err)
+	assert.Empty(t, remotes)
+
+	path = writeJobsFile(t, `
+remotes:
+  - name: nas
+    type: sftp
+  - name: nas
+    type: local
+jobs:
+  - name: job
+    source: "nas:"
+    destination: /backup
+`)
+	_, err = Remotes(&environment.Variables{JobsFile: path})
+	assert.ErrorContains(t, err, `remote "nas": defined more than once`)
+}
+
+func TestJobs_FileCapacity(t *testing.T) {
+	path := writeJobsFile(t, `
+jobs:
+  - name: checked
+    source: "gdrive:"
+    destination: "nas:drive"
+    capacity:
+      check: true
+      reserve: 10G
+`)
+
+	jobs, err := Jobs(&environment.Variables{JobsFile: path})
+	require.NoError(t, err)
+	require.Len(t, jobs, 1)
+
+	assert.Equal(t, &domain.Capacity{Check: true, Reserve: 10 << 30}, jobs[0].Capacity)
+}
+
+func TestJobs_FileAnomaly(t *testing.T) {
+	path := writeJobsFile(t, `
+jobs:
+  - name: watched
+    source: "gdrive:"
+    destination: "nas:drive"
+    anomaly:
+      sensitivity: high
+      action: pause
+`)
+
+	jobs, err := Jobs(&environment.Variables{JobsFile: path})
+	require.NoError(t, err)
+	require.Len(t, jobs, 1)
+
+	assert.Equal(t, &domain.AnomalyDetection{Sensitivity: domain.SensitivityHigh, Action: domain.AnomalyPause}, jobs[0].Anomaly)
+}
+
+func TestJobs_FileCanaries(t *testing.T) {
+	path := writeJobsFile(t, `
+jobs:
+  - name: watched
+    source: "/srv/share"
+    destination: "nas:share"
+    canaries:
+      - path: finance/.canary.txt
+        content: "do not edit\n"
+      - path: .canary.docx
+        sha256: 9F86D081884C7D659A2FEAA0C55AD015A3BF4F1B2B0B822CD15D6C15B0F00A08
+`)
+
+	jobs, err := Jobs(&environment.Variables{JobsFile: path})
+	require.NoError(t, err)
+	require.Len(t, jobs, 1)
+
+	assert.Equal(t, []*domain.Canary{
+		{Path: "finance/.canary.txt", SHA256: "73ee63c63b6506412079e256ec71e29e0d671887b767fd47c00dcdeb46f31074"},
+		{Path: ".canary.docx", SHA256: "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"},
+	}, jobs[0].Canaries)
+}
+
+func TestJobs_FileManifest(t *testing.T) {
+	path := writeJobsFile(t, `
+jobs:
+  - name: audited
+    source: "/srv/share"
+    destination: "nas:share"
+    manifest: true
+  - name: plain
+    source: "/srv/share"
+    destination: "nas:plain"
+`)
+
+	jobs, err := Jobs(&environment.Variables{JobsFile: path})
+	require.NoError(t, err)
+	require.Len(t, jobs, 2)
+
+	assert.True(t, jobs[0].Manifest)
+	assert.False(t, jobs[1].Manifest)
+}
+
+func TestJobs_FileScrub(t *testing.T) {
+	path := writeJobsFile(t, `
+jobs:
+  - name: share
+    source: "/srv/share"
+    destinations: ["nas:share", "s3:share"]
+    manifest: true
+  - name: share-scrub
+    interval: 168h
+    scrub:
+      job: share
+      sample: 5%
+  - name: share-deep-scrub
+    scrub: {job: share, sample: 20G, against: manifest}
+`)
+
+	jobs, err := Jobs(&environment.Variables{JobsFile: path})
+	require.NoError(t, err)
+	require.Len(t, jobs, 3)
+
+	assert.Nil(t, jobs[0].Scrub)
+
+	scrub := jobs[1]
+	assert.Equal(t, &domain.Scrub{Job: "share", Percent: 5}, scrub.Scrub)
+	assert.Equal(t, domain.ModeScrub, scrub.SyncMode())
+	assert.Equal(t, 168*time.Hour, scrub.Interval)
+	// Scrub jobs get the source and destinations of the job they scrub.
+	assert.Equal(t, "/srv/share", scrub.Source)
+	assert.Equal(t, []string{"nas:share", "s3:share"}, scrub.AllDestinations())
+
+	assert.Equal(t, &domain.Scrub{Job: "share", Bytes: 20 << 30, Against: domain.ScrubAgainstManifest}, jobs[2].Scrub)
+}
+
+func TestJobs_FileErrors(t *testing.T) {
+	tests := map[string]struct {
+		content string
+		want    string
+	}{
+		"no job":         {content: "jobs: []", want: "defines no job"},
+		"unknown key":    {content: "jobs:\n  - name: a\n    sauce: x", want: "field sauce not found"},
+		"missing source": {content: "jobs:\n  - name: a\n    destination: 'b:'", want: "Source must be set"},
+		"bad interval":   {content: "jobs:\n  - {name: a, source: 'a:', destination: 'b:', interval: soon}", want: "invalid interval"},
+		"bad mode":       {content: "jobs:\n  - {name: a, source: 'a:', destination: 'b:', mode: mirror}", want: "Mode must be one of"},
+		"both dests":     {content: "jobs:\n  - {name: a, source: 'a:', destination: 'b:', destinations: ['c:', 'd:']}", want: "mutually exclusive"},
+		"fan out move":   {content: "jobs:\n  - {name: a, source: 'a:', destinations: ['c:', 'd:'], mode: move}", want: "cannot fan out"},
+		"bad require":    {content: "jobs:\n  - {name: a, source: 'a:', destinations: ['c:', 'd:'], require: some}", want: "Require must be one of"},
+		"bad after":      {content: "jobs:\n  - {name: a, source: 'a:', destination: 'b:', after: [{job: c}]}", want: "depends on unknown job c"},
+		"cycle": {
+			content: "jobs:\n  - {name: a, source: 'a:', destination: 'b:', after: [{job: b}]}\n  - {name: b, source: 'a:', destination: 'c:', after: [{job: a}]}",
+			want:    "Dependency cycle: a -> b -> a",
+		},
+		"bad catch_up":       {content: "jobs:\n  - {name: a, source: 'a:', destination: 'b:', catch_up: maybe}", want: "CatchUp must be one of"},
+		"bad heartbeat":      {content: "jobs:\n  - {name: a, source: 'a:', destination: 'b:', heartbeat: {url: 'hc-ping.com/abc'}}", want: "absolute http(s) URLs"},
+		"bad hook event":     {content: "jobs:\n  - {name: a, source: 'a:', destination: 'b:', hooks: [{event: before, command: [true]}]}", want: "Hook event must be one of"},
+		"hook fail_run":      {content: "jobs:\n  - {name: a, source: 'a:', destination: 'b:', hooks: [{event: always, command: [true], fail_run: true}]}", want: "Only pre_sync hooks"},
+		"bad filter rule":    {content: "jobs:\n  - {name: a, source: 'a:', destination: 'b:', filters: {rules: ['cache/**']}}", want: "invalid filters"},
+		"missing filters":    {content: "jobs:\n  - {name: a, source: 'a:', destination: 'b:', filters: {filter_from: [/nonexistent/filters.txt]}}", want: "invalid filters"},
+		"include, exclude":   {content: "jobs:\n  - {name: a, source: 'a:', destination: 'b:', filters: {include: [a], exclude: [b]}}", want: "use rules instead"},
+		"bad filter size":    {content: "jobs:\n  - {name: a, source: 'a:', destination: 'b:', filters: {max_size: huge}}", want: "invalid size"},
+		"no password":        {content: "jobs:\n  - {name: a, source: 'a:', destination: 'b:', encryption: {salt_env: SALT}}", want: "one of password file and password env"},
+		"bad rpo":            {content: "jobs:\n  - {name: a, source: 'a:', destination: 'b:', rpo: daily}", want: "invalid rpo"},
+		"bad day":            {content: "jobs:\n  - {name: a, source: 'a:', destination: 'b:', windows: {allowed: [{days: [someday]}]}}", want: "invalid day"},
+		"bad time":           {content: "jobs:\n  - {name: a, source: 'a:', destination: 'b:', windows: {allowed: [{start: '25:00', end: '06:00'}]}}", want: "invalid time"},
+		"half window":        {content: "jobs:\n  - {name: a, source: 'a:', destination: 'b:', windows: {allowed: [{start: '22:00'}]}}", want: "both start and end"},
+		"bad time zone":      {content: "jobs:\n  - {name: a, source: 'a:', destination: 'b:', windows: {time_zone: Mars/Olympus}}", want: "invalid time zone"},
+		"bad blackout":       {content: "jobs:\n  - {name: a, source: 'a:', destination: 'b:', windows: {blackouts: [christmas]}}", want: "invalid blackout"},
+		"bad bandwidth":      {content: "jobs:\n  - {name: a, source: 'a:', destination: 'b:', bandwidth: {upload: fast}}", want: "invalid bandwidth"},
+		"slot no start":      {content: "jobs:\n  - {name: a, source: 'a:', destination: 'b:', bandwidth: {timetable: [{upload: 1M}]}}", want: "must set start"},
+		"limit and slots":    {content: "jobs:\n  - {name: a, source: 'a:', destination: 'b:', bandwidth: {upload: 1M, timetable: [{start: '08:00'}]}}", want: "mutually exclusive"},
+		"bad reserve":        {content: "jobs:\n  - {name: a, source: 'a:', destination: 'b:', capacity: {reserve: lots}}", want: "invalid capacity reserve"},
+		"bisync capacity":    {content: "jobs:\n  - {name: a, source: 'a:', destination: 'b:', mode: bisync, capacity: {check: true}}", want: "cannot check capacity"},
+		"bad sensitivity":    {content: "jobs:\n  - {name: a, source: 'a:', destination: 'b:', anomaly: {sensitivity: paranoid}}", want: "Anomaly sensitivity must be one of"},
+		"bad anomaly action": {content: "jobs:\n  - {name: a, source: 'a:', destination: 'b:', anomaly: {action: delete}}", want: "Anomaly action must be one of"},
+		"bisync anomaly":     {content: "jobs:\n  - {name: a, source: 'a:', destination: 'b:', mode: bisync, anomaly: {}}", want: "cannot detect anomalies"},
+		"canary hashes":      {content: "jobs:\n  - {name: a, source: 'a:', destination: 'b:', canaries: [{path: c, sha256: ab, content: x}]}", want: "canary c: sha256 and content are mutually exclusive"},
+		"bad canary hash":    {content: "jobs:\n  - {name: a, source: 'a:', destination: 'b:', canaries: [{path: c, sha256: ab}]}", want: "must have a hex encoded SHA-256 hash"},
+		"canary outside":     {content: "jobs:\n  - {name: a, source: 'a:', destination: 'b:', canaries: [{path: ../c, content: x}]}", want: "relative to the source"},
+		"move canary":        {content: "jobs:\n  - {name: a, source: 'a:', destination: 'b:', mode: move, canaries: [{path: c, content: x}]}", want: "cannot verify canaries"},
+		"scrub with source":  {content: "jobs:\n  - {name: a, source: 'a:', destination: 'b:'}\n  - {name: s, source: 'a:', scrub: {job: a, sample: 5%}}", want: "use the source, destinations and encryption"},
+		"bad scrub sample":   {content: "jobs:\n  - {name: a, source: 'a:', destination: 'b:'}\n  - {name: s, scrub: {job: a, sample: half}}", want: "invalid scrub sample"},
+		"scrub percentage":   {content: "jobs:\n  - {name: a, source: 'a:', destination: 'b:'}\n  - {name: s, scrub: {job: a, sample: 150%}}", want: "between 0 and 100"},
+		"scrub no sample":    {content: "jobs:\n  - {name: a, source: 'a:', destination: 'b:'}\n  - {name: s, scrub: {job: a}}", want: "either a percentage or a size"},
+		"scrub sets mode":    {content: "jobs:\n  - {name: a, source: 'a:', destination: 'b:'}\n  - {name: s, mode: copy, scrub: {job: a, sample: 5%}}", want: "Scrub jobs cannot set Mode"},
+		"scrub unknown job":  {content: "jobs:\n  - {name: a, source: 'a:', destination: 'b:'}\n  - {name: s, scrub: {job: b, sample: 5%}}", want: "Job s scrubs unknown job b"},
+		"scrub no manifest":  {content: "jobs:\n  - {name: a, source: 'a:', destination: 'b:'}\n  - {name: s, scrub: {job: a, sample: 5%, against: manifest}}", want: "which it does not record"},
+		"duplicate": {
+			content: "jobs:\n  - {name: a, source: 'a:', destination: 'b:'}\n  - {name: a, source: 'a:', destination: 'c:'}",
+			want:    "defined more than once",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := Jobs(&environment.Variables{JobsFile: writeJobsFile(t, tt.content)})
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.want)
+		})
+	}
+}
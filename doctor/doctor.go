@@ -0,0 +1,364 @@
+// Package doctor checks that the runner can do its job: that remotes can be reached, sources
+// read, destinations written, and that there is room for the data and the database. It backs
+// bgctl doctor and the startup checks of the runner, so that a misconfiguration is reported
+// when it is made rather than as a failed run hours later.
+package doctor
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/rclone/rclone/fs"
+	"github.com/rclone/rclone/lib/diskusage"
+
+	"github.com/eva01/backup-guardian/domain"
+	"github.com/eva01/backup-guardian/internal/database"
+)
+
+// Statuses of checks.
+const (
+	StatusPass = "pass"
+	StatusWarn = "warn"
+	StatusFail = "fail"
+)
+
+const (
+	defaultTimeout = 30 * time.Second
+	// minDataDirSpace is the free space of the data directory under which the database and the
+	// run logs may fail to be written.
+	minDataDirSpace = 64 * 1024 * 1024
+	// lowSpace is the free space under which a warning is reported.
+	lowSpace = 1024 * 1024 * 1024
+)
+
+// Check is the outcome of a check.
+type Check struct {
+	// Name is what was checked, e.g. `job "photos": destination b2:photos`.
+	Name   string
+	Status string
+	// Message is what was found and, for failures and warnings, how to fix it.
+	Message string
+}
+
+// Report collects the outcome of checks.
+type Report struct {
+	Checks []*Check
+}
+
+// Pass records a successful check.
+func (r *Report) Pass(name, message string) {
+	r.Checks = append(r.Checks, &Check{Name: name, Status: StatusPass, Message: message})
+}
+
+// Warn records a check that found a problem that does not prevent runs.
+func (r *Report) Warn(name, message string) {
+	r.Checks = append(r.Checks, &Check{Name: name, Status: StatusWarn, Message: message})
+}
+
+// Fail records a failed check.
+func (r *Report) Fail(name, message string) {
+	r.Checks = append(r.Checks, &Check{Name: name, Status: StatusFail, Message: message})
+}
+
+// Count returns the number of checks with status.
+func (r *Report) Count(status string) int {
+	count := 0
+	for _, check := range r.Checks {
+		if check.Status == status {
+			count++
+		}
+	}
+
+	return count
+}
+
+// Failed reports whether a check failed.
+func (r *Report) Failed() bool {
+	return r.Count(StatusFail) > 0
+}
+
+// Log logs the checks: passed checks at debug level, warnings and failures at their level.
+func (r *Report) Log(logger *slog.Logger) {
+	for _, check := range r.Checks {
+		attrs := []any{slog.String("check", check.Name), slog.String("message", check.Message)}
+		switch check.Status {
+		case StatusPass:
+			logger.Debug("Check passed", attrs...)
+		case StatusWarn:
+			logger.Warn("Check warning", attrs...)
+		case StatusFail:
+			logger.Error("Check failed", attrs...)
+		}
+	}
+}
+
+// Prober probes the storage of jobs. Implemented by runner.RcloneProber.
+type Prober interface {
+	// CheckRemote lists the root of the remote named name.
+	CheckRemote(ctx context.Context, name string) error
+	// CheckRead lists the directory at path.
+	CheckRead(ctx context.Context, path string) error
+	// CheckWrite writes a small object in the directory at path, and deletes it.
+	CheckWrite(ctx context.Context, path string) error
+	// CheckEncryption checks the password and salt of the encrypted destination at path.
+	CheckEncryption(ctx context.Context, path string, encryption *domain.Encryption) error
+	// FreeSpace returns the free space of the storage of path, or -1 when it is unknown.
+	FreeSpace(ctx context.Context, path string) (int64, error)
+	// VerifyCanaries returns the state of each canary of source.
+	VerifyCanaries(ctx context.Context, source string, canaries []*domain.Canary) ([]domain.CanaryResult, error)
+}
+
+// Doctor runs checks.
+type Doctor struct {
+	prober  Prober
+	db      *sql.DB
+	dataDir string
+	timeout time.Duration
+}
+
+// Option configures the doctor.
+type Option func(*Doctor)
+
+// New creates a new doctor.
+func New(options ...Option) *Doctor {
+	d := &Doctor{
+		timeout: defaultTimeout,
+	}
+
+	for _, opt := range options {
+		opt(d)
+	}
+
+	return d
+}
+
+// WithProber sets the prober of the storage of jobs.
+func WithProber(prober Prober) Option {
+	return func(d *Doctor) { d.prober = prober }
+}
+
+// WithDatabase sets the database whose writability is checked.
+func WithDatabase(db *sql.DB) Option {
+	return func(d *Doctor) { d.db = db }
+}
+
+// WithDataDir sets the data directory whose free space is checked.
+func WithDataDir(dir string) Option {
+	return func(d *Doctor) { d.dataDir = dir }
+}
+
+// WithTimeout bounds each probe of the storage.
+func WithTimeout(timeout time.Duration) Option {
+	return func(d *Doctor) { d.timeout = timeout }
+}
+
+// Check checks the data directory, the database, remotes and the storage of jobs, and adds the
+// outcome to report. Sources and destinations shared by several jobs are probed once.
+func (d *Doctor) Check(ctx context.Context, report *Report, jobs []*domain.SyncJob, remotes []*domain.Remote) {
+	if d.dataDir != "" {
+		d.checkDataDir(report)
+	}
+	if d.db != nil {
+		if err := database.CheckWritable(d.db); err != nil {
+			report.Fail("database", fmt.Sprintf("cannot be written: %v. Check the permissions and free space of %s", err, d.dataDir))
+		} else {
+			report.Pass("database", "writable")
+		}
+	}
+
+	if d.prober == nil {
+		return
+	}
+
+	for _, remote := range remotes {
+		name := "remote " + remote.Name
+		if err := d.probe(ctx, func(ctx context.Context) error { return d.prober.CheckRemote(ctx, remote.Name) }); err != nil {
+			report.Fail(name, fmt.Sprintf("cannot list its root: %v. Check its parameters with bgctl remote show %s", err, remote.Name))
+			continue
+		}
+		report.Pass(name, "reachable")
+	}
+
+	byName := map[string]*domain.SyncJob{}
+	for _, job := range jobs {
+		byName[job.Name] = job
+	}
+	probes := &probes{doctor: d, errs: map[string]error{}, free: map[string]int64{}}
+	for _, job := range jobs {
+		d.checkJob(ctx, report, job, byName, probes)
+	}
+}
+
+// checkJob checks the storage and hooks of job. jobs are all the jobs, by name.
+func (d *Doctor) checkJob(ctx context.Context, report *Report, job *domain.SyncJob, jobs map[string]*domain.SyncJob, probes *probes) {
+	prefix := fmt.Sprintf("job %q: ", job.Name)
+
+	if job.Scrub != nil {
+		// Scrub jobs have no source or destination of their own: they read those of the
+		// scrubbed job.
+		if scrubbed := jobs[job.Scrub.Job]; scrubbed != nil {
+			d.checkScrub(ctx, report, prefix, scrubbed, probes)
+		}
+	} else {
+		d.checkStorage(ctx, report, prefix, job, probes)
+	}
+
+	for _, hook := range job.Hooks {
+		if len(hook.Command) == 0 {
+			continue
+		}
+		name := prefix + "hook " + hook.Name
+		if _, err := exec.LookPath(hook.Command[0]); err != nil {
+			report.Fail(name, fmt.Sprintf("command %s not found: install it or use an absolute path", hook.Command[0]))
+			continue
+		}
+		report.Pass(name, "command found")
+	}
+}
+
+// checkScrub checks that the destinations of scrubbed, downloaded by the scrub job, can be
+// read.
+func (d *Doctor) checkScrub(ctx context.Context, report *Report, prefix string, scrubbed *domain.SyncJob, probes *probes) {
+	for _, dest := range scrubbed.AllDestinations() {
+		name := prefix + "scrubbed destination " + dest
+		if err := probes.run(ctx, "read", dest, func(ctx context.Context) error { return d.prober.CheckRead(ctx, dest) }); err != nil {
+			report.Fail(name, fmt.Sprintf("cannot be read: %v. Check that the credentials of its remote grant read access", err))
+			continue
+		}
+		report.Pass(name, "readable")
+	}
+}
+
+// checkStorage checks the source, canaries and destinations of job.
+func (d *Doctor) checkStorage(ctx context.Context, report *Report, prefix string, job *domain.SyncJob, probes *probes) {
+	name := prefix + "source " + job.Source
+	readErr := probes.run(ctx, "read", job.Source, func(ctx context.Context) error { return d.prober.CheckRead(ctx, job.Source) })
+	if readErr != nil {
+		report.Fail(name, fmt.Sprintf("cannot be read: %v. Check that it exists and that the credentials of its remote grant read access", readErr))
+	} else if mode := job.SyncMode(); mode == domain.ModeMove || mode == domain.ModeBisync {
+		// Moves delete from the source, and two-way syncs write to it.
+		if err := probes.run(ctx, "write", job.Source, func(ctx context.Context) error { return d.prober.CheckWrite(ctx, job.Source) }); err != nil {
+			report.Fail(name, fmt.Sprintf("cannot be written: %v. Mode %s writes to the source: grant write access, or use mode copy", err, mode))
+		} else {
+			report.Pass(name, "readable and writable")
+		}
+	} else {
+		report.Pass(name, "readable")
+	}
+
+	if len(job.Canaries) > 0 && readErr == nil {
+		d.checkCanaries(ctx, report, prefix, job)
+	}
+
+	for _, dest := range job.AllDestinations() {
+		name := prefix + "destination " + dest
+		if err := probes.run(ctx, "write", dest, func(ctx context.Context) error { return d.prober.CheckWrite(ctx, dest) }); err != nil {
+			report.Fail(name, fmt.Sprintf("cannot be written: %v. Check that the credentials of its remote grant write and delete access", err))
+			continue
+		}
+		if job.Encryption != nil {
+			err := probes.run(ctx, "encryption", dest, func(ctx context.Context) error { return d.prober.CheckEncryption(ctx, dest, job.Encryption) })
+			if err != nil {
+				report.Fail(name, fmt.Sprintf("encryption: %v. Check the password and salt files or variables of the job", err))
+				continue
+			}
+		}
+
+		free, err := probes.freeSpace(ctx, dest)
+		switch {
+		case err != nil:
+			report.Warn(name, fmt.Sprintf("writable, but its free space cannot be read: %v", err))
+		case free >= 0 && free < lowSpace:
+			report.Warn(name, fmt.Sprintf("writable, but only %s free: make room or raise the quota before it fills up", fs.SizeSuffix(free).ByteUnit()))
+		case free >= 0:
+			report.Pass(name, fmt.Sprintf("writable, %s free", fs.SizeSuffix(free).ByteUnit()))
+		default:
+			report.Pass(name, "writable")
+		}
+	}
+}
+
+// checkCanaries checks that the canaries of job are in its source with their expected content,
+// as runs refuse to sync otherwise.
+func (d *Doctor) checkCanaries(ctx context.Context, report *Report, prefix string, job *domain.SyncJob) {
+	name := prefix + "canaries"
+	var results []domain.CanaryResult
+	err := d.probe(ctx, func(ctx context.Context) (err error) {
+		results, err = d.prober.VerifyCanaries(ctx, job.Source, job.Canaries)
+		return err
+	})
+	if err != nil {
+		report.Fail(name, fmt.Sprintf("cannot be verified: %v", err))
+		return
+	}
+
+	if failures := domain.CanaryFailures(results); len(failures) > 0 {
+		report.Fail(name, fmt.Sprintf("%s: runs will not sync. Place the canaries in the source with their configured content, "+
+			"unless the source was tampered with", strings.Join(failures, "; ")))
+		return
+	}
+	report.Pass(name, fmt.Sprintf("%d intact", len(results)))
+}
+
+// checkDataDir checks the free space of the data directory.
+func (d *Doctor) checkDataDir(report *Report) {
+	name := "data directory " + d.dataDir
+	usage, err := diskusage.New(d.dataDir)
+	if err != nil {
+		report.Warn(name, fmt.Sprintf("free space cannot be read: %v", err))
+		return
+	}
+
+	free := fs.SizeSuffix(usage.Available).ByteUnit()
+	switch {
+	case usage.Available < minDataDirSpace:
+		report.Fail(name, fmt.Sprintf("only %s free: the database and run logs cannot be written, make room", free))
+	case usage.Available < lowSpace:
+		report.Warn(name, fmt.Sprintf("only %s free: make room before the database fills it", free))
+	default:
+		report.Pass(name, free+" free")
+	}
+}
+
+// probes runs the probes of the storage of jobs, once for each path.
+type probes struct {
+	doctor *Doctor
+	errs   map[string]error
+	free   map[string]int64
+}
+
+// run runs the probe fn of kind on path, unless it already ran.
+func (p *probes) run(ctx context.Context, kind, path string, fn func(ctx context.Context) error) error {
+	key := kind + " " + path
+	if err, ok := p.errs[key]; ok {
+		return err
+	}
+
+	err := p.doctor.probe(ctx, fn)
+	p.errs[key] = err
+
+	return err
+}
+
+// freeSpace returns the free space of the storage of path, -1 when it is unknown.
+func (p *probes) freeSpace(ctx context.Context, path string) (int64, error) {
+	err := p.run(ctx, "space", path, func(ctx context.Context) (err error) {
+		p.free[path], err = p.doctor.prober.FreeSpace(ctx, path)
+		return err
+	})
+
+	return p.free[path], err
+}
+
+// probe calls fn with a context bounded by the timeout of probes.
+func (d *Doctor) probe(ctx context.Context, fn func(ctx context.Context) error) error {
+	ctx, cancel := context.WithTimeout(ctx, d.timeout)
+	defer cancel()
+
+	return fn(ctx)
+}
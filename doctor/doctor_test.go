@@ -0,0 +1,192 @@
+package doctor_test
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/eva01/backup-guardian/doctor"
+	"github.com/eva01/backup-guardian/domain"
+	"github.com/eva01/backup-guardian/internal/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// prober fails the probes of the paths in its maps, and counts the probes.
+type prober struct {
+	unreachable map[string]bool
+	unreadable  map[string]bool
+	readOnly    map[string]bool
+	free        map[string]int64
+	// canaries are the states of the canaries, intact when missing from the map.
+	canaries map[string]string
+	calls    int
+}
+
+func (p *prober) CheckRemote(ctx context.Context, name string) error {
+	p.calls++
+	if p.unreachable[name] {
+		return errors.New("connection refused")
+	}
+	return nil
+}
+
+func (p *prober) CheckRead(ctx context.Context, path string) error {
+	p.calls++
+	if p.unreadable[path] {
+		return errors.New("directory not found")
+	}
+	return nil
+}
+
+func (p *prober) CheckWrite(ctx context.Context, path string) error {
+	p.calls++
+	if p.readOnly[path] {
+		return errors.New("permission denied")
+	}
+	return nil
+}
+
+func (p *prober) CheckEncryption(ctx context.Context, path string, encryption *domain.Encryption) error {
+	p.calls++
+	return nil
+}
+
+func (p *prober) FreeSpace(ctx context.Context, path string) (int64, error) {
+	p.calls++
+	if free, ok := p.free[path]; ok {
+		return free, nil
+	}
+	return -1, nil
+}
+
+func (p *prober) VerifyCanaries(ctx context.Context, source string, canaries []*domain.Canary) ([]domain.CanaryResult, error) {
+	p.calls++
+	var results []domain.CanaryResult
+	for _, canary := range canaries {
+		state, ok := p.canaries[canary.Path]
+		if !ok {
+			state = domain.CanaryIntact
+		}
+		results = append(results, domain.CanaryResult{Path: canary.Path, State: state})
+	}
+	return results, nil
+}
+
+func TestDoctor_Check(t *testing.T) {
+	p := &prober{
+		unreachable: map[string]bool{"nas": true},
+		unreadable:  map[string]bool{"nas:data": true},
+		readOnly:    map[string]bool{"gdrive:": true},
+		free:        map[string]int64{"b2:backups": 10 << 30, "s3:bucket": 100 << 20},
+	}
+	jobs := []*domain.SyncJob{
+		{Name: "nas", Source: "nas:data", Destination: "b2:backups"},
+		{Name: "drive", Source: "gdrive:", Destinations: []string{"b2:backups", "s3:bucket"}, Mode: domain.ModeMove,
+			Hooks: []*domain.Hook{{Name: "dump", Event: domain.HookPreSync, Command: []string{"no-such-command-bg"}}}},
+	}
+
+	report := &doctor.Report{}
+	doctor.New(doctor.WithProber(p)).Check(context.Background(), report, jobs, []*domain.Remote{{Name: "nas"}, {Name: "offsite"}})
+
+	statuses := map[string]string{}
+	for _, check := range report.Checks {
+		statuses[check.Name] = check.Status
+	}
+	assert.Equal(t, map[string]string{
+		"remote nas":                          doctor.StatusFail,
+		"remote offsite":                      doctor.StatusPass,
+		`job "nas": source nas:data`:          doctor.StatusFail,
+		`job "nas": destination b2:backups`:   doctor.StatusPass,
+		`job "drive": source gdrive:`:         doctor.StatusFail,
+		`job "drive": destination b2:backups`: doctor.StatusPass,
+		`job "drive": destination s3:bucket`:  doctor.StatusWarn,
+		`job "drive": hook dump`:              doctor.StatusFail,
+	}, statuses)
+	assert.True(t, report.Failed())
+	assert.Equal(t, 4, report.Count(doctor.StatusFail))
+	// Shared destinations are probed once: 2 remotes, 2 sources read, 1 source written,
+	// 2 destinations written and their free space.
+	assert.Equal(t, 9, p.calls)
+
+	for _, check := range report.Checks {
+		if check.Name == `job "drive": source gdrive:` {
+			assert.Contains(t, check.Message, "Mode move writes to the source")
+		}
+		if check.Name == `job "drive": destination s3:bucket` {
+			assert.Contains(t, check.Message, "only 100 MiB free")
+		}
+	}
+}
+
+func TestDoctor_Check_Canaries(t *testing.T) {
+	p := &prober{
+		unreadable: map[string]bool{"nas:data": true},
+		canaries:   map[string]string{"hr/.canary": domain.CanaryMissing},
+	}
+	canaries := []*domain.Canary{{Path: ".canary"}, {Path: "hr/.canary"}}
+	jobs := []*domain.SyncJob{
+		{Name: "share", Source: "/srv/share", Destination: "b2:share", Canaries: canaries},
+		{Name: "docs", Source: "/srv/docs", Destination: "b2:docs", Canaries: canaries[:1]},
+		// The canaries of unreadable sources are not verified.
+		{Name: "nas", Source: "nas:data", Destination: "b2:nas", Canaries: canaries},
+	}
+
+	report := &doctor.Report{}
+	doctor.New(doctor.WithProber(p)).Check(context.Background(), report, jobs, nil)
+
+	checks := map[string]*doctor.Check{}
+	for _, check := range report.Checks {
+		checks[check.Name] = check
+	}
+	require.Contains(t, checks, `job "share": canaries`)
+	assert.Equal(t, doctor.StatusFail, checks[`job "share": canaries`].Status)
+	assert.Contains(t, checks[`job "share": canaries`].Message, "hr/.canary missing: runs will not sync")
+	assert.Equal(t, &doctor.Check{Name: `job "docs": canaries`, Status: doctor.StatusPass, Message: "1 intact"}, checks[`job "docs": canaries`])
+	assert.NotContains(t, checks, `job "nas": canaries`)
+}
+
+func TestDoctor_Check_Scrub(t *testing.T) {
+	p := &prober{unreadable: map[string]bool{"s3:bucket": true}}
+	jobs := []*domain.SyncJob{
+		{Name: "drive", Source: "gdrive:", Destinations: []string{"b2:backups", "s3:bucket"}},
+		{Name: "scrub", Scrub: &domain.Scrub{Job: "drive", Percent: 5}},
+	}
+
+	report := &doctor.Report{}
+	doctor.New(doctor.WithProber(p)).Check(context.Background(), report, jobs, nil)
+
+	statuses := map[string]string{}
+	for _, check := range report.Checks {
+		statuses[check.Name] = check.Status
+	}
+	// The scrub job reads the destinations of the scrubbed job, and has no storage of its own.
+	assert.Equal(t, map[string]string{
+		`job "drive": source gdrive:`:                  doctor.StatusPass,
+		`job "drive": destination b2:backups`:          doctor.StatusPass,
+		`job "drive": destination s3:bucket`:           doctor.StatusPass,
+		`job "scrub": scrubbed destination b2:backups`: doctor.StatusPass,
+		`job "scrub": scrubbed destination s3:bucket`:  doctor.StatusFail,
+	}, statuses)
+}
+
+func TestDoctor_Check_Database(t *testing.T) {
+	dir := t.TempDir()
+	db, err := database.Open(filepath.Join(dir, "test.db"))
+	require.NoError(t, err)
+	defer db.Close()
+
+	report := &doctor.Report{}
+	doctor.New(doctor.WithDatabase(db), doctor.WithDataDir(dir)).Check(context.Background(), report, nil, nil)
+
+	require.Len(t, report.Checks, 2)
+	assert.Equal(t, "data directory "+dir, report.Checks[0].Name)
+	assert.Equal(t, &doctor.Check{Name: "database", Status: doctor.StatusPass, Message: "writable"}, report.Checks[1])
+	assert.False(t, report.Failed())
+
+	// The check leaves nothing behind.
+	var count int
+	require.NoError(t, db.QueryRow("SELECT COUNT(*) FROM sqlite_master WHERE name = 'writable_check'").Scan(&count))
+	assert.Zero(t, count)
+}
@@ -0,0 +1,402 @@
+package domain
+
+import (
+	"fmt"
+	"math"
+	"path"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/eva01/backup-guardian/internal/errors"
+)
+
+// Sensitivities of anomaly detection: how far a run must deviate from the baseline of its job
+// to be flagged.
+const (
+	SensitivityLow    = "low"
+	SensitivityMedium = "medium"
+	SensitivityHigh   = "high"
+)
+
+// Actions taken when a run is anomalous, on top of the notification.
+const (
+	// AnomalyNotify only marks the run and notifies.
+	AnomalyNotify = "notify"
+	// AnomalyPause fails the anomalous run without syncing, and pauses the job until the anomaly
+	// is acknowledged.
+	AnomalyPause = "pause"
+	// AnomalyCopy runs the anomalous run and the next ones in copy mode, which never deletes
+	// from the destinations, until the anomaly is acknowledged.
+	AnomalyCopy = "copy"
+)
+
+const (
+	// AnomalyBaselineRuns is the number of recent successful runs the baseline of a job is
+	// computed from.
+	AnomalyBaselineRuns = 30
+	// MinAnomalyBaselineRuns is the number of runs needed before runs are checked.
+	MinAnomalyBaselineRuns = 5
+	// MaxExtensions caps the number of extensions counted by ChangeStats, ExtensionOther
+	// included.
+	MaxExtensions = 100
+	// ExtensionOther counts the files whose extension is past MaxExtensions.
+	ExtensionOther = "*"
+)
+
+// AnomalyDetection configures the detection of anomalous runs of a job.
+type AnomalyDetection struct {
+	// Sensitivity is SensitivityLow, SensitivityMedium or SensitivityHigh. Empty means
+	// SensitivityMedium.
+	Sensitivity string
+	// Action is AnomalyNotify, AnomalyPause or AnomalyCopy. Empty means AnomalyNotify.
+	Action string
+}
+
+// Validate validates the anomaly detection.
+func (d *AnomalyDetection) Validate() error {
+	switch d.Sensitivity {
+	case "", SensitivityLow, SensitivityMedium, SensitivityHigh:
+	default:
+		return &errors.Error{Code: errors.CodeInvalid, Message: "Anomaly sensitivity must be one of low, medium, high"}
+	}
+
+	switch d.Action {
+	case "", AnomalyNotify, AnomalyPause, AnomalyCopy:
+	default:
+		return &errors.Error{Code: errors.CodeInvalid, Message: "Anomaly action must be one of notify, pause, copy"}
+	}
+
+	return nil
+}
+
+// ActionOrDefault returns the action, defaulting to AnomalyNotify.
+func (d *AnomalyDetection) ActionOrDefault() string {
+	if d.Action == "" {
+		return AnomalyNotify
+	}
+
+	return d.Action
+}
+
+// threshold returns the deviation, in robust standard deviations, from which a run is flagged.
+func (d *AnomalyDetection) threshold() float64 {
+	switch d.Sensitivity {
+	case SensitivityLow:
+		return 6
+	case SensitivityHigh:
+		return 3
+	default:
+		return 4
+	}
+}
+
+// ChangeStats counts the changes made by a run, to compare it with the previous runs of its job.
+type ChangeStats struct {
+	Copies      int64 `json:"copies"`
+	CopyBytes   int64 `json:"copy_bytes"`
+	Updates     int64 `json:"updates"`
+	UpdateBytes int64 `json:"update_bytes"`
+	Deletes     int64 `json:"deletes"`
+	DeleteBytes int64 `json:"delete_bytes"`
+	// Extensions counts the copied and updated files by extension, lower-cased and without
+	// the dot ("" for none). Extensions past MaxExtensions are counted as ExtensionOther.
+	Extensions map[string]int64 `json:"extensions,omitempty"`
+}
+
+// NewChangeStats counts the changes of plan, the changes a run would make. The counters of
+// plan are exact; the extensions are counted from the changes it lists.
+func NewChangeStats(plan *SyncPlan) *ChangeStats {
+	stats := &ChangeStats{
+		Copies:      plan.Copies,
+		CopyBytes:   plan.CopyBytes,
+		Updates:     plan.Updates,
+		UpdateBytes: plan.UpdateBytes,
+		Deletes:     plan.Deletes,
+		DeleteBytes: plan.DeleteBytes,
+	}
+	for _, change := range plan.Changes {
+		if change.Action == ChangeCopy || change.Action == ChangeUpdate {
+			stats.addExtension(strings.ToLower(strings.TrimPrefix(path.Ext(change.Path), ".")), 1)
+		}
+	}
+
+	return stats
+}
+
+// Add counts change in the statistics.
+func (s *ChangeStats) Add(change PlannedChange) {
+	size := max(change.Size, 0)
+	switch change.Action {
+	case ChangeCopy:
+		s.Copies++
+		s.CopyBytes += size
+	case ChangeUpdate:
+		s.Updates++
+		s.UpdateBytes += size
+	case ChangeDelete:
+		s.Deletes++
+		s.DeleteBytes += size
+		return
+	default:
+		return
+	}
+
+	s.addExtension(strings.ToLower(strings.TrimPrefix(path.Ext(change.Path), ".")), 1)
+}
+
+// Merge adds the counts of other to the statistics.
+func (s *ChangeStats) Merge(other *ChangeStats) {
+	if other == nil {
+		return
+	}
+
+	s.Copies += other.Copies
+	s.CopyBytes += other.CopyBytes
+	s.Updates += other.Updates
+	s.UpdateBytes += other.UpdateBytes
+	s.Deletes += other.Deletes
+	s.DeleteBytes += other.DeleteBytes
+	for ext, count := range other.Extensions {
+		s.addExtension(ext, count)
+	}
+}
+
+func (s *ChangeStats) addExtension(ext string, count int64) {
+	if s.Extensions == nil {
+		s.Extensions = map[string]int64{}
+	}
+	// The last slot is kept for ExtensionOther.
+	if _, ok := s.Extensions[ext]; !ok && len(s.Extensions) >= MaxExtensions-1 {
+		ext = ExtensionOther
+	}
+	s.Extensions[ext] += count
+}
+
+// Changed returns the number of files copied or updated.
+func (s *ChangeStats) Changed() int64 {
+	return s.Copies + s.Updates
+}
+
+// Anomaly describes how a run deviates from the baseline of its job.
+type Anomaly struct {
+	// Reasons describe the deviations, e.g. "840 files deleted, usually 2".
+	Reasons []string `json:"reasons"`
+	// Score is the largest deviation relative to the threshold of the sensitivity: runs are
+	// flagged from 1.
+	Score float64 `json:"score"`
+}
+
+// Message describes the anomaly for notifications.
+func (a *Anomaly) Message() string {
+	return strings.Join(a.Reasons, "; ")
+}
+
+// metric is the typical value of a statistic: its median, and the scale of its deviations.
+type metric struct {
+	median float64
+	scale  float64
+}
+
+// newMetric returns the metric of values. The scale is the median absolute deviation, scaled
+// to estimate a standard deviation, and never less than a quarter of the median or floor, so
+// that steady jobs are not flagged for small changes.
+func newMetric(values []float64, floor float64) metric {
+	median := medianOf(values)
+	deviations := make([]float64, len(values))
+	for i, v := range values {
+		deviations[i] = math.Abs(v - median)
+	}
+
+	return metric{median: median, scale: max(1.4826*medianOf(deviations), median/4, floor)}
+}
+
+// deviation returns how far value is above the metric, in scales.
+func (m metric) deviation(value float64) float64 {
+	return (value - m.median) / m.scale
+}
+
+func medianOf(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+
+	sorted := slices.Clone(values)
+	slices.Sort(sorted)
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2
+	}
+
+	return sorted[mid]
+}
+
+// Floors of the scales of the metrics of a baseline.
+const (
+	changedFloor = 10
+	deletesFloor = 10
+	bytesFloor   = 256 * 1024 * 1024
+	// newExtensionsFloor is the number of files with new extensions flagged at sensitivity
+	// medium.
+	newExtensionsFloor = 20
+)
+
+// Baseline is the usual activity of a job, computed from its previous runs.
+type Baseline struct {
+	// Runs is the number of runs the baseline is computed from.
+	Runs       int
+	changed    metric
+	deletes    metric
+	bytes      metric
+	extensions map[string]bool
+}
+
+// NewBaseline computes the baseline of runs performed in mode from their change statistics.
+// Only successful runs that recorded statistics and were not anomalous count.
+func NewBaseline(runs []*SyncRun, mode string) *Baseline {
+	b := &Baseline{extensions: map[string]bool{}}
+
+	var changed, deletes, bytes []float64
+	for _, run := range runs {
+		if run.DryRun() || run.Status != StatusSuccess || run.Changes == nil || run.Anomalous() || run.Mode != mode {
+			continue
+		}
+		b.Runs++
+		changed = append(changed, float64(run.Changes.Changed()))
+		deletes = append(deletes, float64(run.Changes.Deletes))
+		bytes = append(bytes, float64(run.Changes.CopyBytes+run.Changes.UpdateBytes))
+		for ext := range run.Changes.Extensions {
+			b.extensions[ext] = true
+		}
+	}
+
+	b.changed = newMetric(changed, changedFloor)
+	b.deletes = newMetric(deletes, deletesFloor)
+	b.bytes = newMetric(bytes, bytesFloor)
+
+	return b
+}
+
+// Check compares stats with the baseline, and returns the anomaly they show under detection,
+// or nil when they are usual or the baseline has fewer than MinAnomalyBaselineRuns runs.
+func (b *Baseline) Check(stats *ChangeStats, detection *AnomalyDetection) *Anomaly {
+	if stats == nil || b.Runs < MinAnomalyBaselineRuns {
+		return nil
+	}
+
+	threshold := detection.threshold()
+	anomaly := &Anomaly{}
+	flag := func(score float64, reason string) {
+		anomaly.Score = max(anomaly.Score, score/threshold)
+		if score >= threshold {
+			anomaly.Reasons = append(anomaly.Reasons, reason)
+		}
+	}
+
+	changed := float64(stats.Changed())
+	flag(b.changed.deviation(changed), fmt.Sprintf("%d files copied or updated, usually %.0f", stats.Changed(), b.changed.median))
+	flag(b.deletes.deviation(float64(stats.Deletes)), fmt.Sprintf("%d files deleted, usually %.0f", stats.Deletes, b.deletes.median))
+	transferred := stats.CopyBytes + stats.UpdateBytes
+	flag(b.bytes.deviation(float64(transferred)), fmt.Sprintf("%s written, usually %s", formatBytes(transferred), formatBytes(int64(b.bytes.median))))
+
+	// Files encrypted by ransomware are often renamed with an extension of its own.
+	var novel int64
+	var names []string
+	for ext, count := range stats.Extensions {
+		if !b.extensions[ext] {
+			novel += count
+			names = append(names, ext)
+		}
+	}
+	if changed > 0 && novel > 0 {
+		// Flagged when newExtensionsFloor files or more, scaled by the sensitivity, have new
+		// extensions and make up threshold/8 of the changed files or more: half of them at
+		// sensitivity medium.
+		minimum := newExtensionsFloor * threshold / 4
+		share := float64(novel) / changed
+		score := min(float64(novel)/minimum, share/(threshold/8)) * threshold
+		sort.Strings(names)
+		if len(names) > 5 {
+			names = append(names[:5], "...")
+		}
+		flag(score, fmt.Sprintf("%d of %d changed files have new extensions (%s)", novel, stats.Changed(), strings.Join(names, ", ")))
+	}
+
+	if len(anomaly.Reasons) == 0 {
+		return nil
+	}
+
+	return anomaly
+}
+
+// formatBytes formats size with binary units, e.g. 1.5 GiB.
+func formatBytes(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+
+	div, exp := int64(unit), 0
+	for n := size / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+
+	return fmt.Sprintf("%.1f %ciB", float64(size)/float64(div), "KMGTPE"[exp])
+}
+
+// JobAnomaly is an anomalous run of a job awaiting acknowledgement. While it is not
+// acknowledged, the job is paused or runs in copy mode, as set by Action.
+type JobAnomaly struct {
+	JobName    string
+	RunID      string
+	Action     string
+	Reasons    []string
+	DetectedAt time.Time
+}
+
+// JobAnomalySelector identifies the anomaly of a job for reads and deletes.
+type JobAnomalySelector struct {
+	JobName string
+}
+
+// Validate validates the job anomaly.
+func (a *JobAnomaly) Validate() error {
+	if a.JobName == "" {
+		return &errors.Error{Code: errors.CodeInvalid, Message: "JobName must be set"}
+	}
+	if a.RunID == "" {
+		return &errors.Error{Code: errors.CodeInvalid, Message: "RunID must be set"}
+	}
+	if a.Action != AnomalyPause && a.Action != AnomalyCopy {
+		return &errors.Error{Code: errors.CodeInvalid, Message: "Action must be one of pause, copy"}
+	}
+
+	return nil
+}
+
+// PauseReason is the reason of the pause of the job, when Action is AnomalyPause.
+func (a *JobAnomaly) PauseReason() string {
+	return fmt.Sprintf("anomalous run %s, acknowledge to resume", a.RunID)
+}
+
+// JobAnomaliesReadWriter combines read and write operations for job anomalies.
+type JobAnomaliesReadWriter interface {
+	JobAnomaliesReader
+	JobAnomaliesWriter
+}
+
+// JobAnomaliesReader defines read operations.
+type JobAnomaliesReader interface {
+	GetJobAnomaly(selector *JobAnomalySelector) (*JobAnomaly, error)
+	ListJobAnomalies() ([]*JobAnomaly, error)
+}
+
+// JobAnomaliesWriter defines write operations.
+type JobAnomaliesWriter interface {
+	// CreateJobAnomaly records the anomaly of a job. An anomaly of the job awaiting
+	// acknowledgement is kept as is.
+	CreateJobAnomaly(anomaly *JobAnomaly) (*JobAnomaly, error)
+	DeleteJobAnomaly(selector *JobAnomalySelector) error
+}
@@ -0,0 +1,118 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChangeStats(t *testing.T) {
+	stats := &ChangeStats{}
+	stats.Add(PlannedChange{Action: ChangeCopy, Path: "docs/Report.DOCX", Size: 10})
+	stats.Add(PlannedChange{Action: ChangeUpdate, Path: "Makefile", Size: 5})
+	stats.Add(PlannedChange{Action: ChangeDelete, Path: "old.txt", Size: 3})
+
+	assert.Equal(t, &ChangeStats{
+		Copies: 1, CopyBytes: 10, Updates: 1, UpdateBytes: 5, Deletes: 1, DeleteBytes: 3,
+		Extensions: map[string]int64{"docx": 1, "": 1},
+	}, stats)
+	assert.Equal(t, int64(2), stats.Changed())
+
+	stats.Merge(&ChangeStats{Copies: 2, CopyBytes: 4, Extensions: map[string]int64{"docx": 2}})
+	assert.Equal(t, int64(3), stats.Copies)
+	assert.Equal(t, int64(3), stats.Extensions["docx"])
+
+	t.Run("extensions capped", func(t *testing.T) {
+		stats := &ChangeStats{}
+		for i := range MaxExtensions + 5 {
+			stats.Add(PlannedChange{Action: ChangeCopy, Path: "f." + string(rune('a'+i%26)) + string(rune('a'+i/26))})
+		}
+		assert.Len(t, stats.Extensions, MaxExtensions)
+		assert.Equal(t, int64(6), stats.Extensions[ExtensionOther])
+	})
+
+	t.Run("from a plan", func(t *testing.T) {
+		plan := &SyncPlan{}
+		plan.Add(PlannedChange{Action: ChangeCopy, Path: "a.locked", Size: 10})
+		plan.Add(PlannedChange{Action: ChangeDelete, Path: "a.txt", Size: 8})
+		plan.Copies += 5
+
+		assert.Equal(t, &ChangeStats{
+			Copies: 6, CopyBytes: 10, Deletes: 1, DeleteBytes: 8,
+			Extensions: map[string]int64{"locked": 1},
+		}, NewChangeStats(plan))
+	})
+}
+
+func TestBaseline_Check(t *testing.T) {
+	history := func(n int) []*SyncRun {
+		var runs []*SyncRun
+		for i := range n {
+			runs = append(runs, &SyncRun{Status: StatusSuccess, Mode: ModeSync, Changes: &ChangeStats{
+				Copies: int64(8 + i%5), CopyBytes: 50 << 20, Deletes: int64(i % 3),
+				Extensions: map[string]int64{"docx": 5, "txt": 3},
+			}})
+		}
+		return runs
+	}
+	medium := &AnomalyDetection{}
+
+	t.Run("usual run", func(t *testing.T) {
+		baseline := NewBaseline(history(10), ModeSync)
+		assert.Equal(t, 10, baseline.Runs)
+		assert.Nil(t, baseline.Check(&ChangeStats{Copies: 14, CopyBytes: 80 << 20, Deletes: 3,
+			Extensions: map[string]int64{"docx": 10, "pdf": 4}}, medium))
+	})
+
+	t.Run("mass deletion", func(t *testing.T) {
+		anomaly := NewBaseline(history(10), ModeSync).Check(&ChangeStats{Copies: 10, Deletes: 900}, medium)
+		require.NotNil(t, anomaly)
+		assert.Equal(t, []string{"900 files deleted, usually 1"}, anomaly.Reasons)
+		assert.Greater(t, anomaly.Score, 1.0)
+	})
+
+	t.Run("files encrypted in place", func(t *testing.T) {
+		anomaly := NewBaseline(history(10), ModeSync).Check(&ChangeStats{
+			Updates: 600, UpdateBytes: 3 << 30, Deletes: 600, Extensions: map[string]int64{"locked": 600},
+		}, medium)
+		require.NotNil(t, anomaly)
+		assert.Equal(t, []string{
+			"600 files copied or updated, usually 10",
+			"600 files deleted, usually 1",
+			"3.0 GiB written, usually 50.0 MiB",
+			"600 of 600 changed files have new extensions (locked)",
+		}, anomaly.Reasons)
+	})
+
+	t.Run("sensitivity", func(t *testing.T) {
+		baseline := NewBaseline(history(10), ModeSync)
+		stats := &ChangeStats{Copies: 45}
+		assert.Nil(t, baseline.Check(stats, &AnomalyDetection{Sensitivity: SensitivityLow}))
+		assert.Nil(t, baseline.Check(stats, medium))
+		assert.NotNil(t, baseline.Check(stats, &AnomalyDetection{Sensitivity: SensitivityHigh}))
+	})
+
+	t.Run("not enough history", func(t *testing.T) {
+		baseline := NewBaseline(history(MinAnomalyBaselineRuns-1), ModeSync)
+		assert.Nil(t, baseline.Check(&ChangeStats{Deletes: 900}, medium))
+	})
+
+	t.Run("runs left out", func(t *testing.T) {
+		runs := append(history(4),
+			&SyncRun{Status: StatusFailed, Mode: ModeSync, Changes: &ChangeStats{}},
+			&SyncRun{Status: StatusSuccess, Mode: ModeSync, Kind: KindDryRun, Changes: &ChangeStats{}},
+			&SyncRun{Status: StatusSuccess, Mode: ModeCopy, Changes: &ChangeStats{}},
+			&SyncRun{Status: StatusSuccess, Mode: ModeSync},
+			&SyncRun{Status: StatusSuccess, Mode: ModeSync, Changes: &ChangeStats{}, Anomaly: &Anomaly{}},
+		)
+		assert.Equal(t, 4, NewBaseline(runs, ModeSync).Runs)
+	})
+}
+
+func TestAnomalyDetection_Validate(t *testing.T) {
+	assert.NoError(t, (&AnomalyDetection{}).Validate())
+	assert.NoError(t, (&AnomalyDetection{Sensitivity: SensitivityHigh, Action: AnomalyCopy}).Validate())
+	assert.Error(t, (&AnomalyDetection{Sensitivity: "paranoid"}).Validate())
+	assert.Error(t, (&AnomalyDetection{Action: "delete"}).Validate())
+}
@@ -0,0 +1,96 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/eva01/backup-guardian/internal/errors"
+)
+
+const week = 7 * 24 * time.Hour
+
+// BandwidthLimit caps transfer rates, in bytes per second. Zero means unlimited.
+type BandwidthLimit struct {
+	Upload   int64
+	Download int64
+}
+
+// Unlimited reports whether neither direction is capped.
+func (l BandwidthLimit) Unlimited() bool {
+	return l.Upload <= 0 && l.Download <= 0
+}
+
+// BandwidthSchedule sets the bandwidth limit of a job by time of day. Each slot applies from
+// its start until the next slot starts, wrapping around the week; without slots, Limit applies.
+// Times are evaluated in Location.
+type BandwidthSchedule struct {
+	// Limit applies at all times when Slots is empty.
+	Limit BandwidthLimit
+	// Slots is the timetable.
+	Slots []BandwidthSlot
+	// Location is the time zone of the timetable. Nil means UTC.
+	Location *time.Location
+}
+
+// BandwidthSlot sets Limit from Start on the given days of the week.
+type BandwidthSlot struct {
+	// Days are the days the slot starts on. Empty means every day.
+	Days []time.Weekday
+	// Start is an offset from midnight.
+	Start time.Duration
+	Limit BandwidthLimit
+}
+
+// Validate validates the bandwidth schedule.
+func (s *BandwidthSchedule) Validate() error {
+	if len(s.Slots) > 0 && !s.Limit.Unlimited() {
+		return &errors.Error{Code: errors.CodeInvalid, Message: "Bandwidth limit and timetable are mutually exclusive"}
+	}
+
+	limits := []BandwidthLimit{s.Limit}
+	for _, slot := range s.Slots {
+		if slot.Start < 0 || slot.Start >= 24*time.Hour {
+			return &errors.Error{Code: errors.CodeInvalid, Message: "Bandwidth slot start must be between 00:00 and 23:59"}
+		}
+		limits = append(limits, slot.Limit)
+	}
+	for _, limit := range limits {
+		if limit.Upload < 0 || limit.Download < 0 {
+			return &errors.Error{Code: errors.CodeInvalid, Message: "Bandwidth limits must not be negative"}
+		}
+	}
+
+	return nil
+}
+
+// LimitAt returns the limit in effect at t.
+func (s *BandwidthSchedule) LimitAt(t time.Time) BandwidthLimit {
+	if len(s.Slots) == 0 {
+		return s.Limit
+	}
+
+	loc := s.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+	local := t.In(loc)
+	now := time.Duration(local.Weekday())*24*time.Hour +
+		time.Duration(local.Hour())*time.Hour + time.Duration(local.Minute())*time.Minute
+
+	// The slot that started most recently, looking back at most a week.
+	var limit BandwidthLimit
+	elapsed := week
+	for _, slot := range s.Slots {
+		days := slot.Days
+		if len(days) == 0 {
+			days = []time.Weekday{time.Sunday, time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday, time.Saturday}
+		}
+		for _, day := range days {
+			since := (now - (time.Duration(day)*24*time.Hour + slot.Start) + week) % week
+			if since < elapsed {
+				elapsed, limit = since, slot.Limit
+			}
+		}
+	}
+
+	return limit
+}
@@ -0,0 +1,74 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBandwidthSchedule_LimitAt(t *testing.T) {
+	day := BandwidthLimit{Upload: 1 << 20, Download: 2 << 20}
+	weekend := BandwidthLimit{Upload: 4 << 20}
+	schedule := &BandwidthSchedule{
+		Slots: []BandwidthSlot{
+			{Days: []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday}, Start: 8 * time.Hour, Limit: day},
+			{Start: 19 * time.Hour},
+			{Days: []time.Weekday{time.Saturday}, Start: 10 * time.Hour, Limit: weekend},
+		},
+	}
+
+	tests := map[string]struct {
+		at   time.Time
+		want BandwidthLimit
+	}{
+		// 2026-01-05 is a Monday.
+		"weekday morning":     {at: time.Date(2026, 1, 5, 8, 0, 0, 0, time.UTC), want: day},
+		"weekday afternoon":   {at: time.Date(2026, 1, 5, 18, 59, 0, 0, time.UTC), want: day},
+		"weekday night":       {at: time.Date(2026, 1, 5, 23, 0, 0, 0, time.UTC), want: BandwidthLimit{}},
+		"before dawn":         {at: time.Date(2026, 1, 6, 7, 59, 0, 0, time.UTC), want: BandwidthLimit{}},
+		"saturday":            {at: time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC), want: weekend},
+		"sunday wraps around": {at: time.Date(2026, 1, 11, 12, 0, 0, 0, time.UTC), want: BandwidthLimit{}},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tt.want, schedule.LimitAt(tt.at))
+		})
+	}
+}
+
+func TestBandwidthSchedule_LimitAt_Location(t *testing.T) {
+	paris, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Skip("time zone database not available")
+	}
+	limit := BandwidthLimit{Upload: 1 << 20}
+	schedule := &BandwidthSchedule{
+		Slots:    []BandwidthSlot{{Start: 8 * time.Hour, Limit: limit}, {Start: 19 * time.Hour}},
+		Location: paris,
+	}
+
+	// 07:30 UTC is 08:30 in Paris in winter.
+	assert.Equal(t, limit, schedule.LimitAt(time.Date(2026, 1, 5, 7, 30, 0, 0, time.UTC)))
+}
+
+func TestBandwidthSchedule_Constant(t *testing.T) {
+	limit := BandwidthLimit{Download: 1 << 20}
+	schedule := &BandwidthSchedule{Limit: limit}
+
+	assert.NoError(t, schedule.Validate())
+	assert.Equal(t, limit, schedule.LimitAt(time.Now()))
+}
+
+func TestBandwidthSchedule_Validate(t *testing.T) {
+	tests := map[string]*BandwidthSchedule{
+		"limit and slots": {Limit: BandwidthLimit{Upload: 1}, Slots: []BandwidthSlot{{}}},
+		"negative":        {Limit: BandwidthLimit{Upload: -1}},
+		"start too late":  {Slots: []BandwidthSlot{{Start: 24 * time.Hour}}},
+	}
+	for name, schedule := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Error(t, schedule.Validate())
+		})
+	}
+}
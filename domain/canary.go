@@ -0,0 +1,65 @@
+package domain
+
+import (
+	"encoding/hex"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/eva01/backup-guardian/internal/errors"
+)
+
+// Canary states, the outcome of the verification of a canary file.
+const (
+	// CanaryIntact marks a canary file found with its expected content.
+	CanaryIntact = "intact"
+	// CanaryMissing marks a canary file that is not in the source anymore.
+	CanaryMissing = "missing"
+	// CanaryAltered marks a canary file whose content changed.
+	CanaryAltered = "altered"
+)
+
+// Canary is a file with known content placed in the source of a job. Nothing should ever
+// change it: ransomware encrypting the source alters or renames it, and runs verify it before
+// syncing to stop before the damage reaches the destinations.
+type Canary struct {
+	// Path is the path of the file, relative to the source.
+	Path string
+	// SHA256 is the hex encoded SHA-256 hash of the content of the file.
+	SHA256 string
+}
+
+// Validate validates the canary.
+func (c *Canary) Validate() error {
+	if c.Path == "" {
+		return &errors.Error{Code: errors.CodeInvalid, Message: "Canary path must be set"}
+	}
+	if path.IsAbs(c.Path) || path.Clean(c.Path) != c.Path || c.Path == "." || c.Path == ".." || strings.HasPrefix(c.Path, "../") {
+		return &errors.Error{Code: errors.CodeInvalid, Message: fmt.Sprintf("Canary path %s must be a clean path relative to the source", c.Path)}
+	}
+	if hash, err := hex.DecodeString(c.SHA256); err != nil || len(hash) != 32 {
+		return &errors.Error{Code: errors.CodeInvalid, Message: fmt.Sprintf("Canary %s must have a hex encoded SHA-256 hash", c.Path)}
+	}
+
+	return nil
+}
+
+// CanaryResult is the outcome of the verification of a canary file.
+type CanaryResult struct {
+	Path string
+	// State is CanaryIntact, CanaryMissing or CanaryAltered.
+	State string
+}
+
+// CanaryFailures returns the canaries of results that are not intact, described as
+// "path missing" or "path altered", in order.
+func CanaryFailures(results []CanaryResult) []string {
+	var failures []string
+	for _, res := range results {
+		if res.State != CanaryIntact {
+			failures = append(failures, res.Path+" "+res.State)
+		}
+	}
+
+	return failures
+}
@@ -0,0 +1,55 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCanary_Validate(t *testing.T) {
+	hash := "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"
+	require.NoError(t, (&Canary{Path: "finance/.canary.xlsx", SHA256: hash}).Validate())
+
+	for name, tc := range map[string]struct {
+		canary *Canary
+		err    string
+	}{
+		"no path":    {&Canary{SHA256: hash}, "Canary path must be set"},
+		"absolute":   {&Canary{Path: "/etc/canary", SHA256: hash}, "must be a clean path relative to the source"},
+		"parent":     {&Canary{Path: "../canary", SHA256: hash}, "must be a clean path relative to the source"},
+		"unclean":    {&Canary{Path: "a/./canary", SHA256: hash}, "must be a clean path relative to the source"},
+		"source":     {&Canary{Path: ".", SHA256: hash}, "must be a clean path relative to the source"},
+		"no hash":    {&Canary{Path: "canary"}, "Canary canary must have a hex encoded SHA-256 hash"},
+		"short hash": {&Canary{Path: "canary", SHA256: hash[:62]}, "must have a hex encoded SHA-256 hash"},
+		"not hex":    {&Canary{Path: "canary", SHA256: "z" + hash[1:]}, "must have a hex encoded SHA-256 hash"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			assert.ErrorContains(t, tc.canary.Validate(), tc.err)
+		})
+	}
+}
+
+func TestSyncJob_Validate_Canaries(t *testing.T) {
+	canary := &Canary{Path: "canary", SHA256: "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"}
+
+	job := &SyncJob{Name: "job", Source: "src:", Destination: "dst:", Canaries: []*Canary{canary}}
+	require.NoError(t, job.Validate())
+
+	job.Canaries = []*Canary{canary, canary}
+	assert.ErrorContains(t, job.Validate(), "Canary canary is listed more than once")
+
+	job.Canaries = []*Canary{canary}
+	job.Mode = ModeMove
+	assert.ErrorContains(t, job.Validate(), "Mode move cannot verify canaries")
+}
+
+func TestCanaryFailures(t *testing.T) {
+	failures := CanaryFailures([]CanaryResult{
+		{Path: "a", State: CanaryIntact},
+		{Path: "b", State: CanaryMissing},
+		{Path: "c", State: CanaryAltered},
+	})
+	assert.Equal(t, []string{"b missing", "c altered"}, failures)
+	assert.Empty(t, CanaryFailures([]CanaryResult{{Path: "a", State: CanaryIntact}}))
+}
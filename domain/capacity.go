@@ -0,0 +1,138 @@
+package domain
+
+import (
+	"slices"
+	"time"
+)
+
+// Capacity configures the free space check of a job.
+type Capacity struct {
+	// Check pre-scans the changes of each run before transferring anything, and refuses the run
+	// when they need more space than the destinations have free.
+	Check bool
+	// Reserve is the space, in bytes, to keep free on the destinations on top of the changes.
+	Reserve int64
+}
+
+// DestinationUsage is the storage usage of a destination, as reported by its backend.
+type DestinationUsage struct {
+	Destination string `json:"destination"`
+	// Total, Used and Free are in bytes, -1 when the backend does not report them.
+	Total int64 `json:"total"`
+	Used  int64 `json:"used"`
+	Free  int64 `json:"free"`
+	// Required is the space the changes of the run were estimated to need by the capacity
+	// check. Zero when the run was not checked.
+	Required  int64     `json:"required,omitempty"`
+	CheckedAt time.Time `json:"checked_at"`
+}
+
+// Fits reports whether required bytes fit on the destination, keeping reserve bytes free. An
+// unknown free space fits everything.
+func (u *DestinationUsage) Fits(required, reserve int64) bool {
+	return u.Free < 0 || required+reserve <= u.Free
+}
+
+// maxForecastDays bounds forecasts: a destination filling up later is reported as not filling up.
+const maxForecastDays = 100 * 365
+
+// CapacityForecast is the forecast of when a destination fills up.
+type CapacityForecast struct {
+	Destination string
+	// Usage is the latest usage recorded for the destination. Nil when none was recorded.
+	Usage *DestinationUsage
+	// Growth is the trend of the data stored on the destination, in bytes per day.
+	Growth float64
+	// Samples is the number of runs the trend is computed from.
+	Samples int
+	// FullAt is when the destination is expected to fill up if the trend holds. Zero when it
+	// does not grow or its free space is unknown.
+	FullAt time.Time
+}
+
+// ForecastCapacity forecasts when dest fills up from the usage recorded by runs. The trend is
+// the least squares fit of the used space of dest across the runs when its backend reports it,
+// and of the bytes transferred to dest otherwise, which overestimates the growth of destinations
+// where runs replace or delete files.
+func ForecastCapacity(runs []*SyncRun, dest string) *CapacityForecast {
+	forecast := &CapacityForecast{Destination: dest}
+
+	type sample struct {
+		usage       *DestinationUsage
+		transferred int64
+	}
+	var samples []sample
+	for _, run := range runs {
+		if run.DryRun() {
+			continue
+		}
+		for i := range run.Usage {
+			if run.Usage[i].Destination == dest {
+				samples = append(samples, sample{usage: &run.Usage[i], transferred: run.BytesTransferredTo(dest)})
+			}
+		}
+	}
+	if len(samples) == 0 {
+		return forecast
+	}
+
+	slices.SortFunc(samples, func(a, b sample) int { return a.usage.CheckedAt.Compare(b.usage.CheckedAt) })
+	forecast.Usage = samples[len(samples)-1].usage
+
+	usedKnown := true
+	for _, s := range samples {
+		usedKnown = usedKnown && s.usage.Used >= 0
+	}
+
+	start := samples[0].usage.CheckedAt
+	xs := make([]float64, len(samples))
+	ys := make([]float64, len(samples))
+	var transferred int64
+	for i, s := range samples {
+		xs[i] = s.usage.CheckedAt.Sub(start).Hours() / 24
+		if usedKnown {
+			ys[i] = float64(s.usage.Used)
+		} else {
+			transferred += s.transferred
+			ys[i] = float64(transferred)
+		}
+	}
+
+	forecast.Samples = len(samples)
+	forecast.Growth = slope(xs, ys)
+	if forecast.Growth > 0 && forecast.Usage.Free >= 0 {
+		days := float64(forecast.Usage.Free) / forecast.Growth
+		if days > maxForecastDays {
+			return forecast
+		}
+		forecast.FullAt = forecast.Usage.CheckedAt.Add(time.Duration(days * float64(24*time.Hour)))
+	}
+
+	return forecast
+}
+
+// slope returns the slope of the least squares line through the points (xs[i], ys[i]), or 0
+// when it is undefined.
+func slope(xs, ys []float64) float64 {
+	n := float64(len(xs))
+	if n < 2 {
+		return 0
+	}
+
+	var meanX, meanY float64
+	for i := range xs {
+		meanX += xs[i] / n
+		meanY += ys[i] / n
+	}
+
+	var cov, variance float64
+	for i := range xs {
+		cov += (xs[i] - meanX) * (ys[i] - meanY)
+		variance += (xs[i] - meanX) * (xs[i] - meanX)
+	}
+	if variance == 0 {
+		return 0
+	}
+
+	return cov / variance
+}
@@ -0,0 +1,83 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDestinationUsage_Fits(t *testing.T) {
+	usage := &DestinationUsage{Total: 1000, Used: 600, Free: 400}
+	assert.True(t, usage.Fits(300, 100))
+	assert.False(t, usage.Fits(300, 101))
+	assert.True(t, (&DestinationUsage{Total: -1, Used: -1, Free: -1}).Fits(1<<40, 0))
+}
+
+func TestForecastCapacity(t *testing.T) {
+	day := 24 * time.Hour
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	usageRun := func(days int, dest string, used, free, transferred int64) *SyncRun {
+		return &SyncRun{
+			Kind:             KindSync,
+			BytesTransferred: transferred,
+			Usage: []DestinationUsage{{
+				Destination: dest, Total: -1, Used: used, Free: free, CheckedAt: start.Add(time.Duration(days) * day),
+			}},
+		}
+	}
+
+	t.Run("used space trend", func(t *testing.T) {
+		// Newest first, as runs are listed.
+		runs := []*SyncRun{
+			usageRun(2, "nas", 1200, 800, 10),
+			usageRun(1, "nas", 1100, 900, 10),
+			usageRun(0, "nas", 1000, 1000, 10),
+			usageRun(0, "s3", 5, 5, 10),
+			{Kind: KindDryRun, Usage: []DestinationUsage{{Destination: "nas", Used: 0, Free: 0, CheckedAt: start.Add(3 * day)}}},
+		}
+
+		forecast := ForecastCapacity(runs, "nas")
+		assert.Equal(t, 3, forecast.Samples)
+		assert.InDelta(t, 100, forecast.Growth, 0.001)
+		require.NotNil(t, forecast.Usage)
+		assert.Equal(t, int64(800), forecast.Usage.Free)
+		assert.Equal(t, start.Add(10*day), forecast.FullAt)
+	})
+
+	t.Run("bytes transferred trend", func(t *testing.T) {
+		runs := []*SyncRun{
+			usageRun(0, "drive", -1, 1000, 50),
+			usageRun(1, "drive", -1, 950, 50),
+			usageRun(2, "drive", -1, 900, 50),
+		}
+
+		forecast := ForecastCapacity(runs, "drive")
+		assert.InDelta(t, 50, forecast.Growth, 0.001)
+		assert.Equal(t, start.Add(20*day), forecast.FullAt)
+	})
+
+	t.Run("shrinking", func(t *testing.T) {
+		runs := []*SyncRun{usageRun(1, "nas", 900, 1100, 0), usageRun(0, "nas", 1000, 1000, 0)}
+
+		forecast := ForecastCapacity(runs, "nas")
+		assert.Negative(t, forecast.Growth)
+		assert.True(t, forecast.FullAt.IsZero())
+	})
+
+	t.Run("no usage", func(t *testing.T) {
+		forecast := ForecastCapacity([]*SyncRun{{Kind: KindSync}}, "nas")
+		assert.Equal(t, &CapacityForecast{Destination: "nas"}, forecast)
+	})
+}
+
+func TestSyncRun_BytesTransferredTo(t *testing.T) {
+	assert.Equal(t, int64(7), (&SyncRun{BytesTransferred: 7}).BytesTransferredTo("nas"))
+
+	fanOut := &SyncRun{BytesTransferred: 7, Destinations: []DestinationResult{
+		{Destination: "s3", BytesTransferred: 3}, {Destination: "nas", BytesTransferred: 4},
+	}}
+	assert.Equal(t, int64(4), fanOut.BytesTransferredTo("nas"))
+	assert.Zero(t, fanOut.BytesTransferredTo("b2"))
+}
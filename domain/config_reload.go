@@ -0,0 +1,74 @@
+package domain
+
+import (
+	"slices"
+	"time"
+)
+
+// Triggers of configuration reloads.
+const (
+	ReloadTriggerSignal = "signal"
+	ReloadTriggerFile   = "file"
+	ReloadTriggerAPI    = "api"
+)
+
+// Outcomes of configuration reloads.
+const (
+	ReloadApplied   = "applied"
+	ReloadUnchanged = "unchanged"
+	ReloadFailed    = "failed"
+)
+
+// ConfigReload is the outcome of a reload of the configuration of the runner: the jobs and
+// remotes it added, removed and changed, or why it failed. A failed reload changes nothing.
+type ConfigReload struct {
+	Trigger    string
+	Status     string
+	ReloadedAt time.Time
+	Error      string
+	Jobs       ConfigDiff
+	Remotes    ConfigDiff
+}
+
+// ConfigDiff lists the names of the items added, removed and changed by a reload.
+type ConfigDiff struct {
+	Added   []string
+	Removed []string
+	Changed []string
+}
+
+// Empty reports whether nothing was added, removed or changed.
+func (d ConfigDiff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// DiffJobs compares the jobs of two configurations by name.
+func DiffJobs(previous, next []*SyncJob) ConfigDiff {
+	return diffConfig(previous, next, func(j *SyncJob) string { return j.Name }, (*SyncJob).Equal)
+}
+
+// DiffRemotes compares the remotes of two configurations by name.
+func DiffRemotes(previous, next []*Remote) ConfigDiff {
+	return diffConfig(previous, next, func(r *Remote) string { return r.Name }, (*Remote).Equal)
+}
+
+func diffConfig[T any](previous, next []T, name func(T) string, equal func(T, T) bool) ConfigDiff {
+	var diff ConfigDiff
+
+	for _, item := range next {
+		i := slices.IndexFunc(previous, func(p T) bool { return name(p) == name(item) })
+		switch {
+		case i < 0:
+			diff.Added = append(diff.Added, name(item))
+		case !equal(previous[i], item):
+			diff.Changed = append(diff.Changed, name(item))
+		}
+	}
+	for _, item := range previous {
+		if !slices.ContainsFunc(next, func(n T) bool { return name(n) == name(item) }) {
+			diff.Removed = append(diff.Removed, name(item))
+		}
+	}
+
+	return diff
+}
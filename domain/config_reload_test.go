@@ -0,0 +1,64 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffJobs(t *testing.T) {
+	paris, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Skip("time zone database not available")
+	}
+	parisAgain, _ := time.LoadLocation("Europe/Paris")
+
+	previous := []*SyncJob{
+		{Name: "kept", Source: "a:", Destination: "b:", Interval: time.Hour},
+		{Name: "changed", Source: "a:", Destination: "b:", Interval: time.Hour},
+		{Name: "removed", Source: "a:", Destination: "b:"},
+		{Name: "windows", Source: "a:", Destination: "b:", Windows: &RunWindows{
+			Location:  paris,
+			Blackouts: []DateRange{{From: time.Date(2026, 12, 24, 0, 0, 0, 0, paris), To: time.Date(2026, 12, 26, 0, 0, 0, 0, paris)}},
+		}},
+	}
+	next := []*SyncJob{
+		{Name: "added", Source: "a:", Destination: "b:"},
+		{Name: "kept", Source: "a:", Destination: "b:", Interval: time.Hour},
+		{Name: "changed", Source: "a:", Destination: "b:", Interval: 2 * time.Hour},
+		{Name: "windows", Source: "a:", Destination: "b:", Windows: &RunWindows{
+			Location:  parisAgain,
+			Blackouts: []DateRange{{From: time.Date(2026, 12, 24, 0, 0, 0, 0, parisAgain), To: time.Date(2026, 12, 26, 0, 0, 0, 0, parisAgain)}},
+		}},
+	}
+
+	diff := DiffJobs(previous, next)
+	assert.Equal(t, []string{"added"}, diff.Added)
+	assert.Equal(t, []string{"removed"}, diff.Removed)
+	assert.Equal(t, []string{"changed"}, diff.Changed)
+	assert.False(t, diff.Empty())
+
+	assert.True(t, DiffJobs(next, next).Empty())
+
+	utc := *next[3].Windows
+	utc.Location = nil
+	assert.Equal(t, []string{"windows"}, DiffJobs(next, []*SyncJob{next[0], next[1], next[2], {Name: "windows", Source: "a:", Destination: "b:", Windows: &utc}}).Changed)
+}
+
+func TestDiffRemotes(t *testing.T) {
+	previous := []*Remote{
+		{Name: "nas", Type: "sftp", Parameters: map[string]string{"host": "nas"}, Origin: RemoteOriginConfig},
+		{Name: "s3", Type: "s3", SecretEnv: map[string]string{"secret_access_key": "KEY"}},
+	}
+	next := []*Remote{
+		// Moving a remote to the database, without changing it, is not a change.
+		{Name: "nas", Type: "sftp", Parameters: map[string]string{"host": "nas"}, Origin: RemoteOriginDatabase, UpdatedAt: time.Now()},
+		{Name: "s3", Type: "s3", SecretEnv: map[string]string{"secret_access_key": "OTHER_KEY"}},
+	}
+
+	diff := DiffRemotes(previous, next)
+	assert.Empty(t, diff.Added)
+	assert.Empty(t, diff.Removed)
+	assert.Equal(t, []string{"s3"}, diff.Changed)
+}
@@ -0,0 +1,141 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/eva01/backup-guardian/internal/errors"
+)
+
+// Dependency conditions, the outcomes of a run of the upstream job that trigger the dependent
+// job.
+const (
+	// DependOnSuccess triggers the dependent job when the upstream run succeeds.
+	DependOnSuccess = "success"
+	// DependOnFailure triggers the dependent job when the upstream run fails.
+	DependOnFailure = "failure"
+	// DependOnCompletion triggers the dependent job when the upstream run succeeds or fails.
+	DependOnCompletion = "completion"
+)
+
+// Dependency makes a job run after a run of another job, its upstream job. Jobs with
+// dependencies form pipelines: they run when triggered by their upstream jobs, not on a
+// schedule.
+type Dependency struct {
+	Job string
+	// On is the outcome of the upstream run that triggers the job. Empty means DependOnSuccess.
+	On string
+}
+
+// Validate validates the dependency.
+func (d *Dependency) Validate() error {
+	if d.Job == "" {
+		return &errors.Error{Code: errors.CodeInvalid, Message: "Dependency job must be set"}
+	}
+
+	switch d.On {
+	case "", DependOnSuccess, DependOnFailure, DependOnCompletion:
+	default:
+		return &errors.Error{Code: errors.CodeInvalid, Message: "Dependency condition must be one of success, failure, completion"}
+	}
+
+	return nil
+}
+
+// Condition returns the condition of the dependency, defaulting to DependOnSuccess.
+func (d *Dependency) Condition() string {
+	if d.On == "" {
+		return DependOnSuccess
+	}
+
+	return d.On
+}
+
+// Matches reports whether an upstream run finished with status satisfies the dependency.
+// Unfinished and deferred runs satisfy no dependency.
+func (d *Dependency) Matches(status string) bool {
+	switch d.Condition() {
+	case DependOnFailure:
+		return Failed(status)
+	case DependOnCompletion:
+		return status == StatusSuccess || Failed(status)
+	default:
+		return status == StatusSuccess
+	}
+}
+
+// Dependents returns the jobs of jobs that depend on the job named name.
+func Dependents(jobs []*SyncJob, name string) []*SyncJob {
+	var dependents []*SyncJob
+	for _, job := range jobs {
+		for _, dep := range job.After {
+			if dep.Job == name {
+				dependents = append(dependents, job)
+				break
+			}
+		}
+	}
+
+	return dependents
+}
+
+// ValidateDependencies checks that the dependencies of jobs refer to jobs of jobs and form no
+// cycle.
+func ValidateDependencies(jobs []*SyncJob) error {
+	byName := map[string]*SyncJob{}
+	for _, job := range jobs {
+		byName[job.Name] = job
+	}
+
+	for _, job := range jobs {
+		for _, dep := range job.After {
+			if byName[dep.Job] == nil {
+				return &errors.Error{Code: errors.CodeInvalid, Message: fmt.Sprintf("Job %s depends on unknown job %s", job.Name, dep.Job)}
+			}
+		}
+	}
+
+	// Depth-first search, keeping the path of jobs being visited to report the cycle.
+	const (
+		visiting = 1
+		visited  = 2
+	)
+	state := map[string]int{}
+	var path []string
+	var visit func(job *SyncJob) error
+	visit = func(job *SyncJob) error {
+		switch state[job.Name] {
+		case visited:
+			return nil
+		case visiting:
+			start := 0
+			for i, name := range path {
+				if name == job.Name {
+					start = i
+				}
+			}
+			cycle := append(path[start:], job.Name)
+			return &errors.Error{Code: errors.CodeInvalid, Message: "Dependency cycle: " + strings.Join(cycle, " -> ")}
+		}
+
+		state[job.Name] = visiting
+		path = append(path, job.Name)
+		for _, dep := range job.After {
+			if err := visit(byName[dep.Job]); err != nil {
+				return err
+			}
+		}
+		path = path[:len(path)-1]
+		state[job.Name] = visited
+
+		return nil
+	}
+
+	for _, job := range jobs {
+		if err := visit(job); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,102 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDependency_Matches(t *testing.T) {
+	success := &Dependency{Job: "a"}
+	assert.True(t, success.Matches(StatusSuccess))
+	assert.False(t, success.Matches(StatusFailed))
+	assert.False(t, success.Matches(StatusDeferred))
+
+	failure := &Dependency{Job: "a", On: DependOnFailure}
+	assert.True(t, failure.Matches(StatusFailed))
+	assert.True(t, failure.Matches(StatusCanaryFailed))
+	assert.False(t, failure.Matches(StatusSuccess))
+
+	completion := &Dependency{Job: "a", On: DependOnCompletion}
+	assert.True(t, completion.Matches(StatusSuccess))
+	assert.True(t, completion.Matches(StatusFailed))
+	assert.False(t, completion.Matches(StatusRunning))
+
+	assert.ErrorContains(t, (&Dependency{Job: "a", On: "later"}).Validate(), "must be one of")
+}
+
+func TestValidateDependencies(t *testing.T) {
+	job := func(name string, after ...string) *SyncJob {
+		j := &SyncJob{Name: name}
+		for _, dep := range after {
+			j.After = append(j.After, &Dependency{Job: dep})
+		}
+		return j
+	}
+
+	require.NoError(t, ValidateDependencies([]*SyncJob{job("drive"), job("nas", "drive"), job("s3", "nas"), job("verify", "s3", "nas")}))
+
+	err := ValidateDependencies([]*SyncJob{job("drive"), job("nas", "drive", "verify"), job("s3", "nas"), job("verify", "s3")})
+	assert.ErrorContains(t, err, "Dependency cycle: nas -> verify -> s3 -> nas")
+
+	err = ValidateDependencies([]*SyncJob{job("nas", "drive")})
+	assert.ErrorContains(t, err, "depends on unknown job drive")
+}
+
+func TestNewPipeline(t *testing.T) {
+	drive := &SyncJob{Name: "drive"}
+	nas := &SyncJob{Name: "nas", After: []*Dependency{{Job: "drive"}}}
+	s3 := &SyncJob{Name: "s3", After: []*Dependency{{Job: "nas"}}}
+	alert := &SyncJob{Name: "alert", After: []*Dependency{{Job: "nas", On: DependOnFailure}}}
+	other := &SyncJob{Name: "other"}
+	jobs := []*SyncJob{s3, alert, nas, drive, other}
+	never := func(string) *SyncRun { return nil }
+
+	runs := []*SyncRun{
+		{ID: "p1", JobName: "drive", Status: StatusSuccess, PipelineID: "p1"},
+		{ID: "r2", JobName: "nas", Status: StatusSuccess, PipelineID: "p1"},
+	}
+	pipeline := NewPipeline("p1", jobs, runs, never)
+	require.NotNil(t, pipeline)
+
+	var names, states []string
+	for _, step := range pipeline.Steps {
+		names = append(names, step.Job.Name)
+		states = append(states, step.State)
+	}
+	assert.Equal(t, []string{"drive", "nas", "s3", "alert"}, names)
+	assert.Equal(t, []string{StatusSuccess, StatusSuccess, StepPending, StepSkipped}, states)
+	require.Len(t, pipeline.Ready(), 1)
+	assert.Equal(t, "s3", pipeline.Ready()[0].Job.Name)
+
+	// A failed step skips the jobs depending on its success, transitively.
+	runs[1].Status = StatusFailed
+	pipeline = NewPipeline("p1", jobs, runs, never)
+	assert.Equal(t, StepPending, pipeline.Steps[3].State)
+	assert.Equal(t, StepSkipped, pipeline.Steps[2].State)
+
+	runs[1].Status = StatusRunning
+	pipeline = NewPipeline("p1", jobs, runs, never)
+	assert.Empty(t, pipeline.Ready())
+	assert.Equal(t, DependenciesPending, pipeline.Steps[2].Dependencies)
+
+	assert.Nil(t, NewPipeline("unknown", jobs, runs, never))
+}
+
+func TestNewPipeline_OutsideDependency(t *testing.T) {
+	drive := &SyncJob{Name: "drive"}
+	photos := &SyncJob{Name: "photos"}
+	archive := &SyncJob{Name: "archive", After: []*Dependency{{Job: "drive"}, {Job: "photos"}}}
+	jobs := []*SyncJob{drive, photos, archive}
+	runs := []*SyncRun{{ID: "p1", JobName: "drive", Status: StatusSuccess, PipelineID: "p1"}}
+
+	pipeline := NewPipeline("p1", jobs, runs, func(string) *SyncRun { return nil })
+	assert.Equal(t, DependenciesPending, pipeline.Steps[1].Dependencies)
+
+	pipeline = NewPipeline("p1", jobs, runs, func(job string) *SyncRun {
+		return &SyncRun{JobName: job, Status: StatusSuccess}
+	})
+	require.Len(t, pipeline.Ready(), 1)
+	assert.Equal(t, "archive", pipeline.Ready()[0].Job.Name)
+}
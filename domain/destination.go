@@ -0,0 +1,13 @@
+package domain
+
+import "time"
+
+// DestinationResult is the outcome of the transfer to one destination of a fan-out run.
+type DestinationResult struct {
+	Destination      string        `json:"destination"`
+	Status           string        `json:"status"`
+	Error            string        `json:"error,omitempty"`
+	FilesTransferred int64         `json:"files_transferred"`
+	BytesTransferred int64         `json:"bytes_transferred"`
+	Duration         time.Duration `json:"duration"`
+}
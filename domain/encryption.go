@@ -0,0 +1,76 @@
+package domain
+
+import (
+	"github.com/eva01/backup-guardian/internal/errors"
+)
+
+// Filename encryption modes of encrypted destinations, as rclone crypt's filename_encryption.
+const (
+	// FilenameEncryptionStandard encrypts file names.
+	FilenameEncryptionStandard = "standard"
+	// FilenameEncryptionObfuscate only obfuscates file names, keeping their length.
+	FilenameEncryptionObfuscate = "obfuscate"
+	// FilenameEncryptionOff keeps file names in clear, with a .bin suffix.
+	FilenameEncryptionOff = "off"
+)
+
+// KeyCheckFile is the name of the key-check file kept at the root of encrypted destinations.
+// It holds a known text encrypted with the key of the destination, so that a wrong password
+// or salt is detected before a run writes files that could not be decrypted with the right
+// one. Its name is not encrypted, so that it is found whatever the key.
+const KeyCheckFile = ".backup-guardian-keycheck"
+
+// Encryption encrypts the destinations of a job client-side with rclone crypt: files are
+// encrypted before they leave the runner, and decrypted when restored. The password and the
+// salt are read from a file or an environment variable at each run, and never stored.
+type Encryption struct {
+	// PasswordFile or PasswordEnv provides the password, one of them must be set.
+	PasswordFile string
+	PasswordEnv  string
+	// SaltFile or SaltEnv provides the salt (rclone's password2). None uses rclone's default
+	// salt.
+	SaltFile string
+	SaltEnv  string
+	// FilenameEncryption is the filename encryption mode. Empty means
+	// FilenameEncryptionStandard.
+	FilenameEncryption string
+	// PlainDirectoryNames leaves directory names in clear when file names are encrypted.
+	PlainDirectoryNames bool
+	// FilenameEncoding is how encrypted names are encoded: base32 (default), base64 or
+	// base32768.
+	FilenameEncoding string
+}
+
+// Validate validates the encryption.
+func (e *Encryption) Validate() error {
+	if (e.PasswordFile == "") == (e.PasswordEnv == "") {
+		return &errors.Error{Code: errors.CodeInvalid, Message: "Encryption must set one of password file and password env"}
+	}
+	if e.SaltFile != "" && e.SaltEnv != "" {
+		return &errors.Error{Code: errors.CodeInvalid, Message: "Encryption cannot set both salt file and salt env"}
+	}
+
+	switch e.FilenameEncryption {
+	case "", FilenameEncryptionStandard, FilenameEncryptionObfuscate, FilenameEncryptionOff:
+	default:
+		return &errors.Error{Code: errors.CodeInvalid, Message: "Filename encryption must be one of standard, obfuscate, off"}
+	}
+
+	switch e.FilenameEncoding {
+	case "", "base32", "base64", "base32768":
+	default:
+		return &errors.Error{Code: errors.CodeInvalid, Message: "Filename encoding must be one of base32, base64, base32768"}
+	}
+
+	return nil
+}
+
+// FilenameEncryptionMode returns the filename encryption mode, defaulting to
+// FilenameEncryptionStandard.
+func (e *Encryption) FilenameEncryptionMode() string {
+	if e.FilenameEncryption == "" {
+		return FilenameEncryptionStandard
+	}
+
+	return e.FilenameEncryption
+}
@@ -0,0 +1,35 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncryption_Validate(t *testing.T) {
+	tests := map[string]struct {
+		encryption *Encryption
+		want       string
+	}{
+		"password file":        {encryption: &Encryption{PasswordFile: "/run/secrets/pw", SaltEnv: "SALT"}},
+		"password env":         {encryption: &Encryption{PasswordEnv: "PW", FilenameEncryption: FilenameEncryptionOff}},
+		"no password":          {encryption: &Encryption{SaltFile: "/run/secrets/salt"}, want: "one of password file and password env"},
+		"both passwords":       {encryption: &Encryption{PasswordFile: "/pw", PasswordEnv: "PW"}, want: "one of password file and password env"},
+		"both salts":           {encryption: &Encryption{PasswordEnv: "PW", SaltFile: "/salt", SaltEnv: "SALT"}, want: "both salt file and salt env"},
+		"unknown mode":         {encryption: &Encryption{PasswordEnv: "PW", FilenameEncryption: "secret"}, want: "Filename encryption must be one of"},
+		"unknown encoding":     {encryption: &Encryption{PasswordEnv: "PW", FilenameEncoding: "hex"}, want: "Filename encoding must be one of"},
+		"base32768 encoding":   {encryption: &Encryption{PasswordEnv: "PW", FilenameEncoding: "base32768"}},
+		"obfuscated file name": {encryption: &Encryption{PasswordEnv: "PW", FilenameEncryption: FilenameEncryptionObfuscate}},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := tt.encryption.Validate()
+			if tt.want == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tt.want)
+		})
+	}
+}
@@ -0,0 +1,46 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/eva01/backup-guardian/internal/errors"
+)
+
+// Filters selects the files of a job's source that are synced, with rclone's filter rules.
+// Excluded files are neither copied nor deleted from the destination.
+type Filters struct {
+	// Include keeps only the files matching these patterns; Exclude drops the files matching
+	// them. They cannot be combined, use Rules to mix both.
+	Include []string `json:"include,omitempty"`
+	Exclude []string `json:"exclude,omitempty"`
+	// Rules are rclone filter rules ("+ pattern" or "- pattern"), the first match wins.
+	Rules []string `json:"rules,omitempty"`
+	// FilterFrom are paths of files of rclone filter rules, read at each run after Rules.
+	FilterFrom []string `json:"filter_from,omitempty"`
+	// ExcludeIfPresent excludes the directories containing a file with one of these names.
+	ExcludeIfPresent []string `json:"exclude_if_present,omitempty"`
+	// MinSize and MaxSize bound the size of synced files, in bytes. Zero means no bound.
+	MinSize int64 `json:"min_size,omitempty"`
+	MaxSize int64 `json:"max_size,omitempty"`
+	// MaxAge skips the files modified longer ago than this. Zero means no limit.
+	MaxAge time.Duration `json:"max_age,omitempty"`
+}
+
+// Validate validates the filters. The rule syntax is checked by rclone when the filters
+// are compiled.
+func (f *Filters) Validate() error {
+	if len(f.Include) > 0 && len(f.Exclude) > 0 {
+		return &errors.Error{Code: errors.CodeInvalid, Message: "Filters cannot set both include and exclude, use rules instead"}
+	}
+	if f.MinSize < 0 || f.MaxSize < 0 {
+		return &errors.Error{Code: errors.CodeInvalid, Message: "Filter sizes must not be negative"}
+	}
+	if f.MaxSize > 0 && f.MinSize > f.MaxSize {
+		return &errors.Error{Code: errors.CodeInvalid, Message: "Filter min size must not be larger than max size"}
+	}
+	if f.MaxAge < 0 {
+		return &errors.Error{Code: errors.CodeInvalid, Message: "Filter max age must not be negative"}
+	}
+
+	return nil
+}
@@ -0,0 +1,33 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFilters_Validate(t *testing.T) {
+	tests := map[string]struct {
+		filters *Filters
+		want    string
+	}{
+		"valid":            {filters: &Filters{Exclude: []string{"*.tmp"}, MinSize: 10, MaxSize: 100, MaxAge: time.Hour}},
+		"min size only":    {filters: &Filters{MinSize: 10}},
+		"include, exclude": {filters: &Filters{Include: []string{"a"}, Exclude: []string{"b"}}, want: "use rules instead"},
+		"negative size":    {filters: &Filters{MaxSize: -1}, want: "must not be negative"},
+		"min above max":    {filters: &Filters{MinSize: 100, MaxSize: 10}, want: "must not be larger"},
+		"negative age":     {filters: &Filters{MaxAge: -time.Hour}, want: "must not be negative"},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := tt.filters.Validate()
+			if tt.want == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tt.want)
+		})
+	}
+}
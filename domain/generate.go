@@ -1,3 +1,12 @@
 package domain
 
 //go:generate mockery --name=SyncRunsReadWriter --outpkg=mocks --output=./mocks --filename=sync_runs_read_writer_mock.go
+//go:generate mockery --name=JobPausesReadWriter --outpkg=mocks --output=./mocks --filename=job_pauses_read_writer_mock.go
+//go:generate mockery --name=JobAlertsReadWriter --outpkg=mocks --output=./mocks --filename=job_alerts_read_writer_mock.go
+//go:generate mockery --name=SyncRunLogsReadWriter --outpkg=mocks --output=./mocks --filename=sync_run_logs_read_writer_mock.go
+//go:generate mockery --name=JobTriggersReadWriter --outpkg=mocks --output=./mocks --filename=job_triggers_read_writer_mock.go
+//go:generate mockery --name=JobSchedulesReadWriter --outpkg=mocks --output=./mocks --filename=job_schedules_read_writer_mock.go
+//go:generate mockery --name=RemotesReadWriter --outpkg=mocks --output=./mocks --filename=remotes_read_writer_mock.go
+//go:generate mockery --name=JobAnomaliesReadWriter --outpkg=mocks --output=./mocks --filename=job_anomalies_read_writer_mock.go
+//go:generate mockery --name=ManifestsReadWriter --outpkg=mocks --output=./mocks --filename=manifests_read_writer_mock.go
+//go:generate mockery --name=ScrubStatesReadWriter --outpkg=mocks --output=./mocks --filename=scrub_states_read_writer_mock.go
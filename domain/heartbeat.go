@@ -0,0 +1,54 @@
+package domain
+
+import (
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/eva01/backup-guardian/internal/errors"
+)
+
+// Heartbeat configures the pings sent to an external monitor (healthchecks.io, Uptime Kuma...)
+// when a run of the job starts, succeeds, fails or is deferred, so that the monitor alerts when
+// pings stop. An empty URL sends no ping for that event.
+type Heartbeat struct {
+	StartURL   string
+	SuccessURL string
+	FailURL    string
+	// DeferredURL is pinged when a run stops at the end of its window. It must not count as a
+	// success: the job has not caught up yet.
+	DeferredURL string
+	// Timeout bounds each ping. Zero uses a default.
+	Timeout time.Duration
+}
+
+// Validate validates the heartbeat.
+func (h *Heartbeat) Validate() error {
+	for _, raw := range []string{h.StartURL, h.SuccessURL, h.FailURL, h.DeferredURL} {
+		if raw == "" {
+			continue
+		}
+		u, err := url.Parse(raw)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return &errors.Error{Code: errors.CodeInvalid, Message: "Heartbeat URLs must be absolute http(s) URLs"}
+		}
+	}
+	if h.Timeout < 0 {
+		return &errors.Error{Code: errors.CodeInvalid, Message: "Heartbeat timeout must not be negative"}
+	}
+
+	return nil
+}
+
+// HeartbeatFromURL returns a heartbeat following the healthchecks.io convention: url is pinged
+// on success, url/start on start, url/fail on failure and url/log, which only logs the ping,
+// when a run is deferred.
+func HeartbeatFromURL(url string) *Heartbeat {
+	url = strings.TrimSuffix(url, "/")
+	return &Heartbeat{
+		StartURL:    url + "/start",
+		SuccessURL:  url,
+		FailURL:     url + "/fail",
+		DeferredURL: url + "/log",
+	}
+}
@@ -0,0 +1,136 @@
+package domain
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/eva01/backup-guardian/internal/errors"
+)
+
+// Hook events, the points of a run at which hooks are run.
+const (
+	// HookPreSync runs before the sync starts.
+	HookPreSync = "pre_sync"
+	// HookPostSuccess runs after a successful sync.
+	HookPostSuccess = "post_success"
+	// HookPostFailure runs after a failed sync.
+	HookPostFailure = "post_failure"
+	// HookAlways runs after every sync, after the success or failure hooks.
+	HookAlways = "always"
+)
+
+// Hook is an external command or HTTP call run at some point of each run of a job, for example
+// to dump a database before the sync or to report results to other tools. Exactly one of
+// Command and URL is set.
+type Hook struct {
+	// Name identifies the hook in run results. Empty uses the program or the host of the URL.
+	Name  string
+	Event string
+	// Command is the program and its arguments, run without a shell. The run is described
+	// by BG_* environment variables.
+	Command []string
+	// Env names the variables of the runner's environment passed to Command. Commands only
+	// get PATH, HOME and the BG_* variables of the run otherwise, so that they do not see the
+	// credentials of the runner.
+	Env []string
+	// URL receives the run as a JSON request, with Method (POST by default) and Headers.
+	URL     string
+	Method  string
+	Headers map[string]string
+	// Timeout bounds the hook. Zero uses a default.
+	Timeout time.Duration
+	// FailRun fails the run, without syncing, when the hook fails. Only for HookPreSync hooks;
+	// other failures are only logged and recorded.
+	FailRun bool
+}
+
+// HookResult is the outcome of a hook during a run.
+type HookResult struct {
+	Name  string `json:"name"`
+	Event string `json:"event"`
+	// Output is the combined output of a command, or the status and body of an HTTP response.
+	Output string `json:"output,omitempty"`
+	// Error is empty when the hook succeeded.
+	Error    string        `json:"error,omitempty"`
+	Duration time.Duration `json:"duration"`
+}
+
+// Failed reports whether the hook failed.
+func (r *HookResult) Failed() bool {
+	return r.Error != ""
+}
+
+// Validate validates the hook.
+func (h *Hook) Validate() error {
+	switch h.Event {
+	case HookPreSync, HookPostSuccess, HookPostFailure, HookAlways:
+	default:
+		return &errors.Error{Code: errors.CodeInvalid, Message: "Hook event must be one of pre_sync, post_success, post_failure, always"}
+	}
+
+	if (len(h.Command) == 0) == (h.URL == "") {
+		return &errors.Error{Code: errors.CodeInvalid, Message: "Hook must set exactly one of command and url"}
+	}
+	if len(h.Command) > 0 && h.Command[0] == "" {
+		return &errors.Error{Code: errors.CodeInvalid, Message: "Hook command must name a program"}
+	}
+	if len(h.Env) > 0 && len(h.Command) == 0 {
+		return &errors.Error{Code: errors.CodeInvalid, Message: "Only command hooks can set env"}
+	}
+	for _, name := range h.Env {
+		if name == "" || strings.Contains(name, "=") {
+			return &errors.Error{Code: errors.CodeInvalid, Message: fmt.Sprintf("Hook env %q is not a variable name", name)}
+		}
+	}
+	if h.URL != "" {
+		u, err := url.Parse(h.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return &errors.Error{Code: errors.CodeInvalid, Message: "Hook URL must be an absolute http(s) URL"}
+		}
+	}
+	switch h.Method {
+	case "", http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch:
+	default:
+		return &errors.Error{Code: errors.CodeInvalid, Message: "Hook method must be one of GET, POST, PUT, PATCH"}
+	}
+
+	if h.Timeout < 0 {
+		return &errors.Error{Code: errors.CodeInvalid, Message: "Hook timeout must not be negative"}
+	}
+	if h.FailRun && h.Event != HookPreSync {
+		return &errors.Error{Code: errors.CodeInvalid, Message: "Only pre_sync hooks can fail the run"}
+	}
+
+	return nil
+}
+
+// DisplayName returns the name of the hook, defaulting to its program or the host of its URL:
+// the rest of the URL may hold a token.
+func (h *Hook) DisplayName() string {
+	switch {
+	case h.Name != "":
+		return h.Name
+	case len(h.Command) > 0:
+		return h.Command[0]
+	}
+	if u, err := url.Parse(h.URL); err == nil && u.Host != "" {
+		return u.Host
+	}
+
+	return "url"
+}
+
+// HooksFor returns the hooks of the job run at event, in configuration order.
+func (j *SyncJob) HooksFor(event string) []*Hook {
+	var result []*Hook
+	for _, hook := range j.Hooks {
+		if hook.Event == event {
+			result = append(result, hook)
+		}
+	}
+
+	return result
+}
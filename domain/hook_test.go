@@ -0,0 +1,51 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHook_Validate(t *testing.T) {
+	tests := map[string]struct {
+		hook *Hook
+		want string
+	}{
+		"command":          {hook: &Hook{Event: HookPreSync, Command: []string{"true"}, FailRun: true}},
+		"url":              {hook: &Hook{Event: HookAlways, URL: "https://example.com/hook", Method: "PUT"}},
+		"unknown event":    {hook: &Hook{Event: "later", Command: []string{"true"}}, want: "Hook event must be one of"},
+		"command and url":  {hook: &Hook{Event: HookAlways, Command: []string{"true"}, URL: "https://example.com"}, want: "exactly one of"},
+		"neither":          {hook: &Hook{Event: HookAlways}, want: "exactly one of"},
+		"relative url":     {hook: &Hook{Event: HookAlways, URL: "example.com/hook"}, want: "absolute http(s) URL"},
+		"bad method":       {hook: &Hook{Event: HookAlways, URL: "https://example.com", Method: "DELETE"}, want: "Hook method must be one of"},
+		"env":              {hook: &Hook{Event: HookPreSync, Command: []string{"true"}, Env: []string{"PGPASSWORD"}}},
+		"env on url":       {hook: &Hook{Event: HookAlways, URL: "https://example.com", Env: []string{"TOKEN"}}, want: "Only command hooks"},
+		"bad env":          {hook: &Hook{Event: HookAlways, Command: []string{"true"}, Env: []string{"A=b"}}, want: "not a variable name"},
+		"fail run on post": {hook: &Hook{Event: HookPostFailure, Command: []string{"true"}, FailRun: true}, want: "Only pre_sync hooks"},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := tt.hook.Validate()
+			if tt.want == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tt.want)
+		})
+	}
+}
+
+func TestSyncJob_HooksFor(t *testing.T) {
+	pre := &Hook{Event: HookPreSync, Command: []string{"a"}}
+	always1 := &Hook{Event: HookAlways, Command: []string{"b"}}
+	always2 := &Hook{Event: HookAlways, Command: []string{"c"}}
+	job := &SyncJob{Hooks: []*Hook{always1, pre, always2}}
+
+	assert.Equal(t, []*Hook{always1, always2}, job.HooksFor(HookAlways))
+	assert.Equal(t, []*Hook{pre}, job.HooksFor(HookPreSync))
+	assert.Empty(t, job.HooksFor(HookPostSuccess))
+	assert.Equal(t, "b", always1.DisplayName())
+	assert.Equal(t, "hooks.slack.com", (&Hook{URL: "https://hooks.slack.com/services/T0/B0/token"}).DisplayName())
+	assert.Equal(t, "results", (&Hook{Name: "results", URL: "https://ntfy.sh/token"}).DisplayName())
+}
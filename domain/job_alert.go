@@ -0,0 +1,58 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/eva01/backup-guardian/internal/errors"
+)
+
+// Alert kinds.
+const (
+	// AlertKindRPO is raised while a job has no successful run within its RPO.
+	AlertKindRPO = "rpo"
+)
+
+// JobAlert is an alert raised on a job and not cleared yet. Alerts are persisted so that
+// a restart neither forgets an ongoing alert nor notifies it again.
+type JobAlert struct {
+	JobName  string
+	Kind     string
+	Message  string
+	RaisedAt time.Time
+}
+
+// JobAlertSelector identifies an alert for reads and deletes.
+type JobAlertSelector struct {
+	JobName string
+	Kind    string
+}
+
+// Validate validates the job alert.
+func (a *JobAlert) Validate() error {
+	if a.JobName == "" {
+		return &errors.Error{Code: errors.CodeInvalid, Message: "JobName must be set"}
+	}
+	if a.Kind == "" {
+		return &errors.Error{Code: errors.CodeInvalid, Message: "Kind must be set"}
+	}
+
+	return nil
+}
+
+// JobAlertsReadWriter combines read and write operations for job alerts.
+type JobAlertsReadWriter interface {
+	JobAlertsReader
+	JobAlertsWriter
+}
+
+// JobAlertsReader defines read operations.
+type JobAlertsReader interface {
+	GetJobAlert(selector *JobAlertSelector) (*JobAlert, error)
+	ListJobAlerts() ([]*JobAlert, error)
+}
+
+// JobAlertsWriter defines write operations.
+type JobAlertsWriter interface {
+	UpsertJobAlert(alert *JobAlert) (*JobAlert, error)
+	DeleteJobAlert(selector *JobAlertSelector) error
+}
@@ -0,0 +1,84 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/eva01/backup-guardian/internal/errors"
+)
+
+// PauseScopeAll is the pause scope that covers every job of the runner.
+const PauseScopeAll = "*"
+
+// JobPause represents a pause of a single job, or of the whole runner when Scope is PauseScopeAll.
+// Pauses are persisted so that a restart does not silently resume backups.
+type JobPause struct {
+	Scope    string
+	Reason   string
+	PausedAt time.Time
+	// ResumeAt is the optional auto-resume time. Zero means paused until resumed manually.
+	ResumeAt time.Time
+}
+
+// JobPauseSelector identifies a pause for reads and deletes.
+type JobPauseSelector struct {
+	Scope string
+}
+
+// Validate validates the job pause.
+func (p *JobPause) Validate() error {
+	if p.Scope == "" {
+		return &errors.Error{Code: errors.CodeInvalid, Message: "Scope must be set"}
+	}
+	if !p.ResumeAt.IsZero() && !p.ResumeAt.After(p.PausedAt) {
+		return &errors.Error{Code: errors.CodeInvalid, Message: "ResumeAt must be after PausedAt"}
+	}
+
+	return nil
+}
+
+// IsRunnerScope reports whether the pause covers the whole runner.
+func (p *JobPause) IsRunnerScope() bool {
+	return p.Scope == PauseScopeAll
+}
+
+// Active reports whether the pause is still in effect at t.
+func (p *JobPause) Active(t time.Time) bool {
+	return p.ResumeAt.IsZero() || t.Before(p.ResumeAt)
+}
+
+// ActiveJobPause returns the pause in effect for jobName at t, or nil when the job may run.
+// A runner-wide pause takes precedence over a pause of the job itself.
+func ActiveJobPause(pauses []*JobPause, jobName string, t time.Time) *JobPause {
+	var jobPause *JobPause
+	for _, p := range pauses {
+		if !p.Active(t) {
+			continue
+		}
+		if p.IsRunnerScope() {
+			return p
+		}
+		if p.Scope == jobName {
+			jobPause = p
+		}
+	}
+
+	return jobPause
+}
+
+// JobPausesReadWriter combines read and write operations for job pauses.
+type JobPausesReadWriter interface {
+	JobPausesReader
+	JobPausesWriter
+}
+
+// JobPausesReader defines read operations.
+type JobPausesReader interface {
+	GetJobPause(selector *JobPauseSelector) (*JobPause, error)
+	ListJobPauses() ([]*JobPause, error)
+}
+
+// JobPausesWriter defines write operations.
+type JobPausesWriter interface {
+	UpsertJobPause(pause *JobPause) (*JobPause, error)
+	DeleteJobPause(selector *JobPauseSelector) error
+}
@@ -0,0 +1,54 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJobPause_Validate(t *testing.T) {
+	now := time.Now()
+
+	t.Run("valid", func(t *testing.T) {
+		p := &JobPause{Scope: "job", PausedAt: now}
+		require.NoError(t, p.Validate())
+	})
+
+	t.Run("empty Scope", func(t *testing.T) {
+		p := &JobPause{PausedAt: now}
+		err := p.Validate()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "Scope must be set")
+	})
+
+	t.Run("ResumeAt before PausedAt", func(t *testing.T) {
+		p := &JobPause{Scope: "job", PausedAt: now, ResumeAt: now.Add(-time.Hour)}
+		err := p.Validate()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "ResumeAt must be after PausedAt")
+	})
+}
+
+func TestJobPause_Active(t *testing.T) {
+	now := time.Now()
+
+	assert.True(t, (&JobPause{Scope: "job"}).Active(now))
+	assert.True(t, (&JobPause{Scope: "job", ResumeAt: now.Add(time.Hour)}).Active(now))
+	assert.False(t, (&JobPause{Scope: "job", ResumeAt: now}).Active(now))
+}
+
+func TestActiveJobPause(t *testing.T) {
+	now := time.Now()
+	jobPause := &JobPause{Scope: "job-1", Reason: "migration"}
+	runnerPause := &JobPause{Scope: PauseScopeAll, Reason: "maintenance"}
+	expired := &JobPause{Scope: "job-2", ResumeAt: now.Add(-time.Minute)}
+
+	assert.Nil(t, ActiveJobPause(nil, "job-1", now))
+	assert.Equal(t, jobPause, ActiveJobPause([]*JobPause{jobPause}, "job-1", now))
+	assert.Nil(t, ActiveJobPause([]*JobPause{jobPause}, "job-2", now))
+	assert.Nil(t, ActiveJobPause([]*JobPause{expired}, "job-2", now))
+	assert.Equal(t, runnerPause, ActiveJobPause([]*JobPause{jobPause, runnerPause}, "job-1", now))
+	assert.Equal(t, runnerPause, ActiveJobPause([]*JobPause{runnerPause}, "job-2", now))
+}
@@ -0,0 +1,59 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/eva01/backup-guardian/internal/errors"
+)
+
+// JobSchedule is the persisted scheduler state of a job.
+type JobSchedule struct {
+	JobName   string
+	LastRunAt time.Time
+	// NextDueAt is the next schedule slot of the job. Slots in the past were missed.
+	NextDueAt time.Time
+}
+
+// JobScheduleSelector identifies a job schedule for reads.
+type JobScheduleSelector struct {
+	JobName string
+}
+
+// Validate validates the job schedule.
+func (s *JobSchedule) Validate() error {
+	if s.JobName == "" {
+		return &errors.Error{Code: errors.CodeInvalid, Message: "JobName must be set"}
+	}
+	if s.NextDueAt.IsZero() {
+		return &errors.Error{Code: errors.CodeInvalid, Message: "NextDueAt must be set"}
+	}
+
+	return nil
+}
+
+// MissedRuns returns the number of schedule slots, every interval from NextDueAt,
+// that are due at now.
+func (s *JobSchedule) MissedRuns(interval time.Duration, now time.Time) int {
+	if now.Before(s.NextDueAt) || interval <= 0 {
+		return 0
+	}
+
+	return int(now.Sub(s.NextDueAt)/interval) + 1
+}
+
+// JobSchedulesReadWriter combines read and write operations for job schedules.
+type JobSchedulesReadWriter interface {
+	JobSchedulesReader
+	JobSchedulesWriter
+}
+
+// JobSchedulesReader defines read operations.
+type JobSchedulesReader interface {
+	GetJobSchedule(selector *JobScheduleSelector) (*JobSchedule, error)
+	ListJobSchedules() ([]*JobSchedule, error)
+}
+
+// JobSchedulesWriter defines write operations.
+type JobSchedulesWriter interface {
+	UpsertJobSchedule(schedule *JobSchedule) (*JobSchedule, error)
+}
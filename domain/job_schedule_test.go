@@ -0,0 +1,40 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJobSchedule_Validate(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		s := &JobSchedule{JobName: "job", NextDueAt: time.Now()}
+		require.NoError(t, s.Validate())
+	})
+
+	t.Run("empty JobName", func(t *testing.T) {
+		s := &JobSchedule{NextDueAt: time.Now()}
+		err := s.Validate()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "JobName must be set")
+	})
+
+	t.Run("empty NextDueAt", func(t *testing.T) {
+		s := &JobSchedule{JobName: "job"}
+		err := s.Validate()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "NextDueAt must be set")
+	})
+}
+
+func TestJobSchedule_MissedRuns(t *testing.T) {
+	due := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	s := &JobSchedule{JobName: "job", NextDueAt: due}
+
+	assert.Equal(t, 0, s.MissedRuns(6*time.Hour, due.Add(-time.Minute)))
+	assert.Equal(t, 1, s.MissedRuns(6*time.Hour, due))
+	assert.Equal(t, 1, s.MissedRuns(6*time.Hour, due.Add(5*time.Hour)))
+	assert.Equal(t, 9, s.MissedRuns(6*time.Hour, due.Add(48*time.Hour)))
+}
@@ -0,0 +1,50 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/eva01/backup-guardian/internal/errors"
+)
+
+// JobTrigger is a request to run a job now, outside of its schedule. Triggers are persisted so
+// that they can be requested from another process (bgctl) and picked up by the runner.
+type JobTrigger struct {
+	JobName     string
+	RequestedAt time.Time
+	// PipelineID is set when the job is triggered by the run of a job it depends on, to link
+	// their runs.
+	PipelineID string
+}
+
+// JobTriggerSelector identifies a trigger for reads and deletes.
+type JobTriggerSelector struct {
+	JobName string
+}
+
+// Validate validates the job trigger.
+func (t *JobTrigger) Validate() error {
+	if t.JobName == "" {
+		return &errors.Error{Code: errors.CodeInvalid, Message: "JobName must be set"}
+	}
+
+	return nil
+}
+
+// JobTriggersReadWriter combines read and write operations for job triggers.
+type JobTriggersReadWriter interface {
+	JobTriggersReader
+	JobTriggersWriter
+}
+
+// JobTriggersReader defines read operations.
+type JobTriggersReader interface {
+	GetJobTrigger(selector *JobTriggerSelector) (*JobTrigger, error)
+	ListJobTriggers() ([]*JobTrigger, error)
+}
+
+// JobTriggersWriter defines write operations.
+type JobTriggersWriter interface {
+	// UpsertJobTrigger requests a run of the job. A pending trigger of the job is kept as is.
+	UpsertJobTrigger(trigger *JobTrigger) (*JobTrigger, error)
+	DeleteJobTrigger(selector *JobTriggerSelector) error
+}
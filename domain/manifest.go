@@ -0,0 +1,252 @@
+package domain
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/eva01/backup-guardian/internal/errors"
+)
+
+// ReservedDir is the directory backup-guardian keeps at the root of destinations. Transfers
+// exclude it: syncs never delete it, and restores and verifications skip it.
+const ReservedDir = ".backup-guardian"
+
+// ManifestDir is the directory of the manifests on destinations, one subdirectory per job.
+const ManifestDir = ReservedDir + "/manifests"
+
+// Reserved reports whether remote, a path relative to the root of a destination, is in
+// ReservedDir.
+func Reserved(remote string) bool {
+	return remote == ReservedDir || strings.HasPrefix(remote, ReservedDir+"/")
+}
+
+// Manifest lists the files of the destinations of a job after a successful run. The manifests
+// of a job form a chain: each one holds the hash of the previous one, so that rewriting any of
+// them breaks the hashes of all the following ones.
+type Manifest struct {
+	RunID   string `json:"run_id"`
+	JobName string `json:"job"`
+	// Sequence numbers the manifests of the job, from 1.
+	Sequence  int64     `json:"sequence"`
+	CreatedAt time.Time `json:"created_at"`
+	// PreviousHash is the hash of the previous manifest of the job, empty for the first one.
+	PreviousHash string `json:"previous_hash"`
+	// Files and Bytes are the totals of the destinations.
+	Files        int64                 `json:"files"`
+	Bytes        int64                 `json:"bytes"`
+	Destinations []ManifestDestination `json:"destinations"`
+	// Hash is the hex SHA-256 hash of the JSON encoding of the manifest without its hash.
+	Hash string `json:"hash,omitempty"`
+}
+
+// ManifestDestination lists the files of a destination.
+type ManifestDestination struct {
+	Destination string `json:"destination"`
+	Files       int64  `json:"files"`
+	Bytes       int64  `json:"bytes"`
+	// Entries are the files, sorted by path. Nil in manifest summaries.
+	Entries []ManifestFile `json:"entries"`
+}
+
+// ManifestFile is a file of a destination, as stored: the files of encrypted destinations are
+// listed with their encrypted names and sizes.
+type ManifestFile struct {
+	Path string `json:"path"`
+	Size int64  `json:"size"`
+	// Hash is the hash of the content of the file, prefixed with its type, e.g. "sha256:..." or
+	// "md5:...". Backends hash with the type they support.
+	Hash string `json:"hash"`
+	// ModTime is the modification time of the file, recorded by backends that hash by reading
+	// the files. A file whose size and ModTime did not change keeps the hash of the previous
+	// manifest instead of being read again.
+	ModTime time.Time `json:"mod_time,omitzero"`
+}
+
+// NewManifest returns the manifest of run, chained to previous, the latest manifest of the job
+// or nil, and hashed.
+func NewManifest(run *SyncRun, previous *Manifest, destinations []ManifestDestination, now time.Time) *Manifest {
+	m := &Manifest{RunID: run.ID, JobName: run.JobName, Sequence: 1, CreatedAt: now.UTC(), Destinations: destinations}
+	if previous != nil {
+		m.Sequence = previous.Sequence + 1
+		m.PreviousHash = previous.Hash
+	}
+	for i := range m.Destinations {
+		dest := &m.Destinations[i]
+		dest.Files, dest.Bytes = 0, 0
+		for _, file := range dest.Entries {
+			dest.Files++
+			dest.Bytes += file.Size
+		}
+		m.Files += dest.Files
+		m.Bytes += dest.Bytes
+	}
+	m.Hash = m.ComputeHash()
+
+	return m
+}
+
+// ComputeHash returns the hash of the manifest, ignoring its Hash field.
+func (m *Manifest) ComputeHash() string {
+	unhashed := *m
+	unhashed.Hash = ""
+	data, err := json.Marshal(&unhashed)
+	if err != nil {
+		// Manifests only hold strings, numbers and times, which always encode.
+		panic(err)
+	}
+	sum := sha256.Sum256(data)
+
+	return hex.EncodeToString(sum[:])
+}
+
+// ObjectName returns the path of the copy of the manifest on its destinations.
+func (m *Manifest) ObjectName() string {
+	return path.Join(ManifestDir, m.JobName, fmt.Sprintf("%08d-%s.json", m.Sequence, m.RunID))
+}
+
+// Destination returns the listing of dest in the manifest, or nil.
+func (m *Manifest) Destination(dest string) *ManifestDestination {
+	for i := range m.Destinations {
+		if m.Destinations[i].Destination == dest {
+			return &m.Destinations[i]
+		}
+	}
+
+	return nil
+}
+
+// Validate validates the manifest.
+func (m *Manifest) Validate() error {
+	if m.RunID == "" {
+		return &errors.Error{Code: errors.CodeInvalid, Message: "RunID must be set"}
+	}
+	if m.JobName == "" {
+		return &errors.Error{Code: errors.CodeInvalid, Message: "JobName must be set"}
+	}
+	if m.Sequence < 1 {
+		return &errors.Error{Code: errors.CodeInvalid, Message: "Sequence must be positive"}
+	}
+	if m.Hash == "" {
+		return &errors.Error{Code: errors.CodeInvalid, Message: "Hash must be set"}
+	}
+
+	return nil
+}
+
+// VerifyManifestChain returns the breaks of the chain of manifests, the manifests of a job
+// sorted by sequence: manifests whose hash does not match their content, missing manifests,
+// and manifests not chained to the previous one.
+func VerifyManifestChain(manifests []*Manifest) []string {
+	var problems []string
+	var previous *Manifest
+	for _, m := range manifests {
+		if hash := m.ComputeHash(); hash != m.Hash {
+			problems = append(problems, fmt.Sprintf("manifest %d (run %s) was altered: its content hashes to %s, not %s",
+				m.Sequence, m.RunID, hash, m.Hash))
+		}
+
+		expected, previousHash := int64(1), ""
+		if previous != nil {
+			expected, previousHash = previous.Sequence+1, previous.Hash
+		}
+		switch {
+		case m.Sequence > expected:
+			problems = append(problems, fmt.Sprintf("manifests %d to %d are missing", expected, m.Sequence-1))
+		case m.Sequence < expected:
+			problems = append(problems, fmt.Sprintf("manifest %d (run %s) is duplicated", m.Sequence, m.RunID))
+		case m.PreviousHash != previousHash:
+			problems = append(problems, fmt.Sprintf("manifest %d (run %s) is not chained to the previous manifest", m.Sequence, m.RunID))
+		}
+		previous = m
+	}
+
+	return problems
+}
+
+// CompareManifestCopies returns the differences between manifests, the manifests of a job in
+// the database that list dest, and copies, the copies of the manifests of the job found on
+// dest: copies missing, differing from the database or altered, and copies of manifests
+// missing from the database.
+func CompareManifestCopies(dest string, manifests, copies []*Manifest) []string {
+	byRun := make(map[string]*Manifest, len(copies))
+	for _, c := range copies {
+		byRun[c.RunID] = c
+	}
+
+	var problems []string
+	for _, m := range manifests {
+		c, ok := byRun[m.RunID]
+		delete(byRun, m.RunID)
+		switch {
+		case !ok:
+			problems = append(problems, fmt.Sprintf("manifest %d (run %s) is missing from %s", m.Sequence, m.RunID, dest))
+		case c.Hash != m.Hash:
+			problems = append(problems, fmt.Sprintf("manifest %d (run %s) differs on %s: the copy has hash %s, the database %s",
+				m.Sequence, m.RunID, dest, c.Hash, m.Hash))
+		case c.ComputeHash() != c.Hash:
+			problems = append(problems, fmt.Sprintf("copy of manifest %d (run %s) on %s was altered", m.Sequence, m.RunID, dest))
+		}
+	}
+	for _, c := range copies {
+		if _, ok := byRun[c.RunID]; ok {
+			problems = append(problems, fmt.Sprintf("manifest %d (run %s) on %s is missing from the database", c.Sequence, c.RunID, dest))
+		}
+	}
+
+	return problems
+}
+
+// ManifestVerification is the outcome of the verification of the manifests of a job.
+type ManifestVerification struct {
+	JobName string
+	// Manifests is the number of manifests of the job in the database.
+	Manifests int
+	// Copies is the number of copies of the manifests found on the destinations.
+	Copies int
+	// Problems are the breaks of the chain, and the differences between the database and the
+	// copies. Empty when the manifests are intact.
+	Problems []string
+}
+
+// OK reports whether the manifests are intact.
+func (v *ManifestVerification) OK() bool {
+	return len(v.Problems) == 0
+}
+
+// ManifestSelector identifies a manifest: the manifest of RunID when set, or else the latest
+// manifest of JobName.
+type ManifestSelector struct {
+	RunID   string
+	JobName string
+}
+
+// ManifestsSelector filters manifests for listing.
+type ManifestsSelector struct {
+	JobName string
+	// Entries reads the files of the manifests. Without it, only their summaries are read:
+	// their destinations have no entries.
+	Entries bool
+}
+
+// ManifestsReadWriter combines read and write operations for manifests.
+type ManifestsReadWriter interface {
+	ManifestsReader
+	ManifestsWriter
+}
+
+// ManifestsReader defines read operations.
+type ManifestsReader interface {
+	GetManifest(selector *ManifestSelector) (*Manifest, error)
+	// ListManifests lists the manifests of a job, sorted by sequence.
+	ListManifests(selector *ManifestsSelector) ([]*Manifest, error)
+}
+
+// ManifestsWriter defines write operations. Manifests are never updated nor deleted.
+type ManifestsWriter interface {
+	CreateManifest(manifest *Manifest) (*Manifest, error)
+}
@@ -0,0 +1,124 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// manifestChain returns a chain of n manifests of the job "job".
+func manifestChain(n int) []*Manifest {
+	now := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	var chain []*Manifest
+	var previous *Manifest
+	for i := range n {
+		run := &SyncRun{ID: string(rune('a' + i)), JobName: "job"}
+		previous = NewManifest(run, previous, []ManifestDestination{{Destination: "dst:", Entries: []ManifestFile{
+			{Path: "a.txt", Size: 3, Hash: "sha256:aaa"},
+			{Path: "dir/b.txt", Size: int64(i), Hash: "sha256:bbb"},
+		}}}, now.Add(time.Duration(i)*time.Hour))
+		chain = append(chain, previous)
+	}
+
+	return chain
+}
+
+func TestNewManifest(t *testing.T) {
+	chain := manifestChain(2)
+
+	first := chain[0]
+	assert.Equal(t, int64(1), first.Sequence)
+	assert.Empty(t, first.PreviousHash)
+	assert.Equal(t, int64(2), first.Files)
+	assert.Equal(t, int64(3), first.Bytes)
+	assert.Equal(t, int64(2), first.Destination("dst:").Files)
+	assert.Nil(t, first.Destination("other:"))
+	assert.Equal(t, first.ComputeHash(), first.Hash)
+	assert.Len(t, first.Hash, 64)
+	require.NoError(t, first.Validate())
+
+	second := chain[1]
+	assert.Equal(t, int64(2), second.Sequence)
+	assert.Equal(t, first.Hash, second.PreviousHash)
+	assert.Equal(t, int64(4), second.Bytes)
+	assert.NotEqual(t, first.Hash, second.Hash)
+	assert.Equal(t, ".backup-guardian/manifests/job/00000002-b.json", second.ObjectName())
+}
+
+func TestReserved(t *testing.T) {
+	assert.True(t, Reserved(".backup-guardian"))
+	assert.True(t, Reserved(".backup-guardian/manifests/job/00000001-a.json"))
+	assert.False(t, Reserved(".backup-guardian-old/file"))
+	assert.False(t, Reserved("dir/.backup-guardian/file"))
+}
+
+func TestVerifyManifestChain(t *testing.T) {
+	assert.Empty(t, VerifyManifestChain(manifestChain(3)))
+	assert.Empty(t, VerifyManifestChain(nil))
+
+	t.Run("altered", func(t *testing.T) {
+		chain := manifestChain(3)
+		chain[1].Destinations[0].Entries[0].Size = 4
+
+		problems := VerifyManifestChain(chain)
+		require.Len(t, problems, 1)
+		assert.Contains(t, problems[0], "manifest 2 (run b) was altered")
+	})
+
+	t.Run("rewritten and rehashed", func(t *testing.T) {
+		chain := manifestChain(3)
+		chain[1].Destinations[0].Entries[0].Size = 4
+		chain[1].Hash = chain[1].ComputeHash()
+
+		assert.Equal(t, []string{"manifest 3 (run c) is not chained to the previous manifest"}, VerifyManifestChain(chain))
+	})
+
+	t.Run("missing", func(t *testing.T) {
+		chain := manifestChain(4)
+
+		assert.Equal(t, []string{"manifests 2 to 3 are missing"}, VerifyManifestChain([]*Manifest{chain[0], chain[3]}))
+		assert.Equal(t, []string{"manifests 1 to 1 are missing"}, VerifyManifestChain(chain[1:]))
+	})
+
+	t.Run("duplicated", func(t *testing.T) {
+		chain := manifestChain(2)
+
+		assert.Equal(t, []string{"manifest 2 (run b) is duplicated"}, VerifyManifestChain(append(chain, chain[1])))
+	})
+}
+
+func TestCompareManifestCopies(t *testing.T) {
+	chain := manifestChain(3)
+	// The copies are equal to the manifests, in distinct values.
+	copies := func() []*Manifest { return manifestChain(3) }
+
+	assert.Empty(t, CompareManifestCopies("dst:", chain, copies()))
+
+	t.Run("missing copy", func(t *testing.T) {
+		assert.Equal(t, []string{"manifest 3 (run c) is missing from dst:"}, CompareManifestCopies("dst:", chain, copies()[:2]))
+	})
+
+	t.Run("rewritten database", func(t *testing.T) {
+		rewritten := manifestChain(3)
+		rewritten[2].Destinations[0].Entries[0].Hash = "sha256:ccc"
+		rewritten[2].Hash = rewritten[2].ComputeHash()
+
+		problems := CompareManifestCopies("dst:", rewritten, copies())
+		require.Len(t, problems, 1)
+		assert.Contains(t, problems[0], "manifest 3 (run c) differs on dst:")
+	})
+
+	t.Run("altered copy", func(t *testing.T) {
+		altered := copies()
+		altered[0].Files = 10
+
+		assert.Equal(t, []string{"copy of manifest 1 (run a) on dst: was altered"}, CompareManifestCopies("dst:", chain, altered))
+	})
+
+	t.Run("missing from the database", func(t *testing.T) {
+		assert.Equal(t, []string{"manifest 3 (run c) on dst: is missing from the database"},
+			CompareManifestCopies("dst:", chain[:2], copies()))
+	})
+}
@@ -0,0 +1,173 @@
+package domain
+
+// Dependency states of a job in a pipeline.
+const (
+	// DependenciesMet means the upstream runs satisfy all the dependencies of the job.
+	DependenciesMet = "met"
+	// DependenciesPending means an upstream job has not finished running in the pipeline.
+	DependenciesPending = "pending"
+	// DependenciesUnmet means an upstream run does not satisfy a dependency of the job, or an
+	// upstream job was skipped.
+	DependenciesUnmet = "unmet"
+)
+
+// Step states of a pipeline, for jobs that have not run in it. Jobs that ran have the status
+// of their run.
+const (
+	// StepPending marks a job waiting for its upstream jobs, or triggered and about to run.
+	StepPending = "pending"
+	// StepSkipped marks a job that will not run in the pipeline, as its dependencies are unmet.
+	StepSkipped = "skipped"
+)
+
+// Pipeline is a run of a job followed by the runs of the jobs depending on it, directly or
+// not. Its runs are linked by their PipelineID, the ID of the run of its first job.
+type Pipeline struct {
+	ID string
+	// Steps are the jobs of the pipeline, each one after the jobs it depends on.
+	Steps []*PipelineStep
+}
+
+// PipelineStep is a job of a pipeline.
+type PipelineStep struct {
+	Job *SyncJob
+	// Run is the latest run of the job in the pipeline. Nil when it has not run in it.
+	Run *SyncRun
+	// Dependencies is the state of the dependencies of the job. Empty for the first job.
+	Dependencies string
+	// State is the status of Run, or StepPending or StepSkipped when the job has not run.
+	State string
+}
+
+// NewPipeline returns the state of pipeline id of jobs from its runs, oldest first. The
+// dependencies on jobs outside the pipeline are checked against their latest run, returned by
+// latest (nil when the job never ran). It returns nil when runs do not include the run of the
+// first job.
+func NewPipeline(id string, jobs []*SyncJob, runs []*SyncRun, latest func(job string) *SyncRun) *Pipeline {
+	var first *SyncJob
+	for _, run := range runs {
+		if run.ID == id {
+			first = jobByName(jobs, run.JobName)
+		}
+	}
+	if first == nil {
+		return nil
+	}
+
+	pipeline := &Pipeline{ID: id}
+	steps := map[string]*PipelineStep{}
+	for _, job := range pipelineJobs(jobs, first) {
+		step := &PipelineStep{Job: job, State: StepPending}
+		for _, run := range runs {
+			if run.JobName == job.Name {
+				step.Run = run
+			}
+		}
+
+		if job != first {
+			step.Dependencies = dependencies(job, steps, latest)
+		}
+		if step.Run != nil {
+			step.State = step.Run.Status
+		} else if step.Dependencies == DependenciesUnmet {
+			step.State = StepSkipped
+		}
+
+		steps[job.Name] = step
+		pipeline.Steps = append(pipeline.Steps, step)
+	}
+
+	return pipeline
+}
+
+// Ready returns the steps of the pipeline whose dependencies are met and that have not run.
+func (p *Pipeline) Ready() []*PipelineStep {
+	var ready []*PipelineStep
+	for _, step := range p.Steps {
+		if step.Run == nil && step.Dependencies == DependenciesMet {
+			ready = append(ready, step)
+		}
+	}
+
+	return ready
+}
+
+// dependencies returns the state of the dependencies of job, from the steps of the pipeline
+// for jobs in it and from latest for the others.
+func dependencies(job *SyncJob, steps map[string]*PipelineStep, latest func(job string) *SyncRun) string {
+	state := DependenciesMet
+	for _, dep := range job.After {
+		var run *SyncRun
+		if step, ok := steps[dep.Job]; ok {
+			if step.State == StepSkipped {
+				return DependenciesUnmet
+			}
+			run = step.Run
+		} else {
+			run = latest(dep.Job)
+		}
+
+		switch {
+		case run == nil || (run.Status != StatusSuccess && !Failed(run.Status)):
+			state = DependenciesPending
+		case !dep.Matches(run.Status):
+			return DependenciesUnmet
+		}
+	}
+
+	return state
+}
+
+// pipelineJobs returns first and the jobs depending on it, directly or not, each one after
+// the jobs it depends on. jobs must form no dependency cycle.
+func pipelineJobs(jobs []*SyncJob, first *SyncJob) []*SyncJob {
+	in := map[string]bool{first.Name: true}
+	queue := []*SyncJob{first}
+	for len(queue) > 0 {
+		job := queue[0]
+		queue = queue[1:]
+		for _, dependent := range Dependents(jobs, job.Name) {
+			if !in[dependent.Name] {
+				in[dependent.Name] = true
+				queue = append(queue, dependent)
+			}
+		}
+	}
+
+	// Order the jobs of the pipeline after their upstream jobs in it.
+	var ordered []*SyncJob
+	added := map[string]bool{}
+	var add func(job *SyncJob)
+	add = func(job *SyncJob) {
+		if added[job.Name] {
+			return
+		}
+		added[job.Name] = true
+		if job != first {
+			for _, dep := range job.After {
+				if in[dep.Job] {
+					add(jobByName(jobs, dep.Job))
+				}
+			}
+		}
+		ordered = append(ordered, job)
+	}
+	add(first)
+	for _, job := range jobs {
+		if in[job.Name] {
+			add(job)
+		}
+	}
+
+	return ordered
+}
+
+func jobByName(jobs []*SyncJob, name string) *SyncJob {
+	for _, job := range jobs {
+		if job.Name == name {
+			return job
+		}
+	}
+
+	return nil
+}
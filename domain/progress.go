@@ -0,0 +1,44 @@
+package domain
+
+import "time"
+
+// Progress is a snapshot of the progress of a running sync, from rclone's accounting.
+type Progress struct {
+	RunID   string
+	JobName string
+	// StartedAt is when the run started, UpdatedAt when the snapshot was taken.
+	StartedAt time.Time
+	UpdatedAt time.Time
+	// BytesTotal and FilesTotal are the amounts known to transfer so far. They grow while
+	// the source is listed.
+	BytesDone  int64
+	BytesTotal int64
+	FilesDone  int64
+	FilesTotal int64
+	// Speed is the average transfer speed, in bytes per second.
+	Speed float64
+	// ETA is the estimated time left. Zero when unknown.
+	ETA time.Duration
+	// Transfers are the files being transferred.
+	Transfers []FileTransfer
+	// Done reports that the run finished. It is set on the last snapshot of a run only.
+	Done bool
+}
+
+// FileTransfer is the progress of a file being transferred.
+type FileTransfer struct {
+	Name  string
+	Size  int64
+	Bytes int64
+	// Speed is the current transfer speed, in bytes per second.
+	Speed float64
+}
+
+// Percent returns the percentage of bytes done, or 0 when the total is unknown.
+func (p *Progress) Percent() float64 {
+	if p.BytesTotal <= 0 {
+		return 0
+	}
+
+	return 100 * float64(p.BytesDone) / float64(p.BytesTotal)
+}
@@ -0,0 +1,96 @@
+package domain
+
+import (
+	"fmt"
+	"maps"
+	"regexp"
+	"time"
+
+	"github.com/eva01/backup-guardian/internal/errors"
+)
+
+// Origins of remote definitions.
+const (
+	// RemoteOriginConfig marks remotes defined in the jobs file.
+	RemoteOriginConfig = "config"
+	// RemoteOriginDatabase marks remotes defined in the database, with bgctl.
+	RemoteOriginDatabase = "database"
+)
+
+// remoteName matches the remote names accepted by rclone, without spaces.
+var remoteName = regexp.MustCompile(`^[\w.+@-]+$`)
+
+// Remote is an rclone remote managed by backup-guardian, so that jobs can use "name:path"
+// without a hand-written rclone.conf section. Remotes are injected into rclone's configuration
+// at runtime and never written to the rclone config file.
+type Remote struct {
+	Name string
+	// Type is the rclone backend (e.g. s3, sftp, b2).
+	Type string
+	// Parameters are the backend options (e.g. host, user, provider), with their value in
+	// clear: passwords are obscured when the remote is injected.
+	Parameters map[string]string
+	// SecretFiles and SecretEnv map backend options to the file or the environment variable
+	// their value is read from when the remote is injected, so that secrets are never stored.
+	SecretFiles map[string]string
+	SecretEnv   map[string]string
+	// Origin is where the remote is defined: RemoteOriginConfig or RemoteOriginDatabase.
+	Origin string
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// RemoteSelector identifies a remote for reads and deletes.
+type RemoteSelector struct {
+	Name string
+}
+
+// Validate validates the remote.
+func (r *Remote) Validate() error {
+	if !remoteName.MatchString(r.Name) || r.Name[0] == '-' {
+		return &errors.Error{Code: errors.CodeInvalid, Message: "Remote name must only contain letters, digits, _ . + @ and -, and not start with -"}
+	}
+	if r.Type == "" {
+		return &errors.Error{Code: errors.CodeInvalid, Message: "Remote type must be set"}
+	}
+
+	seen := map[string]bool{}
+	for _, options := range []map[string]string{r.Parameters, r.SecretFiles, r.SecretEnv} {
+		for key := range options {
+			if key == "" || key == "type" {
+				return &errors.Error{Code: errors.CodeInvalid, Message: fmt.Sprintf("Remote %s has an invalid parameter name %q", r.Name, key)}
+			}
+			if seen[key] {
+				return &errors.Error{Code: errors.CodeInvalid, Message: fmt.Sprintf("Remote %s sets parameter %s more than once", r.Name, key)}
+			}
+			seen[key] = true
+		}
+	}
+
+	return nil
+}
+
+// Equal reports whether r and other have the same definition, wherever they are defined.
+func (r *Remote) Equal(other *Remote) bool {
+	return r.Name == other.Name && r.Type == other.Type && maps.Equal(r.Parameters, other.Parameters) &&
+		maps.Equal(r.SecretFiles, other.SecretFiles) && maps.Equal(r.SecretEnv, other.SecretEnv)
+}
+
+// RemotesReadWriter combines read and write operations for remotes.
+type RemotesReadWriter interface {
+	RemotesReader
+	RemotesWriter
+}
+
+// RemotesReader defines read operations.
+type RemotesReader interface {
+	GetRemote(selector *RemoteSelector) (*Remote, error)
+	ListRemotes() ([]*Remote, error)
+}
+
+// RemotesWriter defines write operations.
+type RemotesWriter interface {
+	UpsertRemote(remote *Remote) (*Remote, error)
+	DeleteRemote(selector *RemoteSelector) error
+}
@@ -0,0 +1,41 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRemote_Validate(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		r := &Remote{Name: "nas-1", Type: "sftp", Parameters: map[string]string{"host": "nas"}, SecretEnv: map[string]string{"pass": "NAS_PASS"}}
+		require.NoError(t, r.Validate())
+	})
+
+	tests := map[string]struct {
+		remote *Remote
+		want   string
+	}{
+		"empty name":   {remote: &Remote{Type: "s3"}, want: "Remote name must only contain"},
+		"invalid name": {remote: &Remote{Name: "my remote", Type: "s3"}, want: "Remote name must only contain"},
+		"leading dash": {remote: &Remote{Name: "-s3", Type: "s3"}, want: "Remote name must only contain"},
+		"no type":      {remote: &Remote{Name: "s3"}, want: "Remote type must be set"},
+		"type parameter": {
+			remote: &Remote{Name: "s3", Type: "s3", Parameters: map[string]string{"type": "sftp"}},
+			want:   `Remote s3 has an invalid parameter name "type"`,
+		},
+		"secret set twice": {
+			remote: &Remote{Name: "s3", Type: "s3", Parameters: map[string]string{"secret_access_key": "x"}, SecretFiles: map[string]string{"secret_access_key": "/run/secrets/s3"}},
+			want:   "Remote s3 sets parameter secret_access_key more than once",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := tt.remote.Validate()
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.want)
+		})
+	}
+}
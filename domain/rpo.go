@@ -0,0 +1,49 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// RPOStatus is the evaluation of a job's recovery point objective: the maximum age of
+// its most recent successful run.
+type RPOStatus struct {
+	RPO time.Duration
+	// LastSuccessAt is the start of the last successful run, i.e. the newest recovery point.
+	// Zero when the job never succeeded.
+	LastSuccessAt time.Time
+	// DeadlineAt is when the RPO is violated unless a new run succeeds.
+	DeadlineAt time.Time
+	Violated   bool
+}
+
+// CheckRPO evaluates rpo at now. lastSuccessAt is the start of the last successful run, or zero;
+// a job that never succeeded is measured from since instead.
+func CheckRPO(rpo time.Duration, lastSuccessAt, since, now time.Time) *RPOStatus {
+	from := lastSuccessAt
+	if from.IsZero() {
+		from = since
+	}
+
+	deadline := from.Add(rpo)
+	return &RPOStatus{
+		RPO:           rpo,
+		LastSuccessAt: lastSuccessAt,
+		DeadlineAt:    deadline,
+		Violated:      now.After(deadline),
+	}
+}
+
+// Message describes the status for notifications.
+func (s *RPOStatus) Message() string {
+	if s.LastSuccessAt.IsZero() {
+		return fmt.Sprintf("No successful run recorded, RPO is %s", s.RPO)
+	}
+	if s.Violated {
+		return fmt.Sprintf("Last successful run started at %s, more than the RPO of %s ago",
+			s.LastSuccessAt.UTC().Format(time.RFC3339), s.RPO)
+	}
+
+	return fmt.Sprintf("Last successful run started at %s, within the RPO of %s",
+		s.LastSuccessAt.UTC().Format(time.RFC3339), s.RPO)
+}
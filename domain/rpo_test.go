@@ -0,0 +1,40 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckRPO(t *testing.T) {
+	now := time.Date(2026, 1, 2, 12, 0, 0, 0, time.UTC)
+	since := now.Add(-time.Hour)
+
+	tests := map[string]struct {
+		lastSuccessAt time.Time
+		wantDeadline  time.Time
+		wantViolated  bool
+	}{
+		"recent success":      {lastSuccessAt: now.Add(-25 * time.Hour), wantDeadline: now.Add(time.Hour)},
+		"old success":         {lastSuccessAt: now.Add(-27 * time.Hour), wantDeadline: now.Add(-time.Hour), wantViolated: true},
+		"never succeeded":     {wantDeadline: since.Add(26 * time.Hour)},
+		"exactly at deadline": {lastSuccessAt: now.Add(-26 * time.Hour), wantDeadline: now},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			status := CheckRPO(26*time.Hour, tt.lastSuccessAt, since, now)
+			assert.Equal(t, tt.wantDeadline, status.DeadlineAt)
+			assert.Equal(t, tt.wantViolated, status.Violated)
+			assert.Equal(t, tt.lastSuccessAt, status.LastSuccessAt)
+		})
+	}
+}
+
+func TestCheckRPO_NeverSucceeded(t *testing.T) {
+	now := time.Date(2026, 1, 2, 12, 0, 0, 0, time.UTC)
+
+	status := CheckRPO(time.Hour, time.Time{}, now.Add(-2*time.Hour), now)
+	assert.True(t, status.Violated)
+	assert.Contains(t, status.Message(), "No successful run")
+}
@@ -0,0 +1,224 @@
+package domain
+
+import (
+	"sort"
+	"time"
+
+	"github.com/eva01/backup-guardian/internal/errors"
+)
+
+// windowHorizon bounds the search for the next allowed period, so that a job whose
+// windows are entirely blacked out does not loop forever.
+const windowHorizon = 366 * 24 * time.Hour
+
+// RunWindows restricts when a job may run: only during one of Windows, and never on a
+// Blackout date. Times are evaluated in Location.
+type RunWindows struct {
+	// Windows are the allowed periods of the week. Empty means all day, every day.
+	Windows []TimeWindow
+	// Blackouts are date ranges during which the job never runs.
+	Blackouts []DateRange
+	// Location is the time zone of windows and blackouts. Nil means UTC.
+	Location *time.Location
+	// StopOnClose stops a running sync gracefully when its window closes. The run is
+	// recorded as deferred and resumed when the next window opens.
+	StopOnClose bool
+}
+
+// TimeWindow is a daily period, from Start to End, on the given days of the week.
+// A window whose End is not after its Start spans midnight and belongs to the day it starts on;
+// Start == End covers the whole day.
+type TimeWindow struct {
+	// Days are the days the window starts on. Empty means every day.
+	Days []time.Weekday
+	// Start and End are offsets from midnight.
+	Start time.Duration
+	End   time.Duration
+}
+
+// DateRange is an inclusive range of calendar dates. From and To hold the dates at midnight UTC.
+type DateRange struct {
+	From time.Time
+	To   time.Time
+}
+
+type period struct {
+	start time.Time
+	end   time.Time
+}
+
+// Validate validates the run windows.
+func (w *RunWindows) Validate() error {
+	for _, window := range w.Windows {
+		if window.Start < 0 || window.Start >= 24*time.Hour || window.End < 0 || window.End > 24*time.Hour {
+			return &errors.Error{Code: errors.CodeInvalid, Message: "Window times must be between 00:00 and 24:00"}
+		}
+	}
+	for _, blackout := range w.Blackouts {
+		if blackout.To.Before(blackout.From) {
+			return &errors.Error{Code: errors.CodeInvalid, Message: "Blackout end must not be before its start"}
+		}
+	}
+
+	return nil
+}
+
+// Allowed reports whether a run may be in progress at t.
+func (w *RunWindows) Allowed(t time.Time) bool {
+	for _, p := range w.periods(t) {
+		if !t.Before(p.start) && t.Before(p.end) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// NextOpen returns the earliest time not before t at which a run may start.
+// It returns the zero time when no window opens within a year.
+func (w *RunWindows) NextOpen(t time.Time) time.Time {
+	for _, p := range w.periods(t) {
+		if t.Before(p.end) {
+			if t.Before(p.start) {
+				return p.start
+			}
+			return t
+		}
+	}
+
+	return time.Time{}
+}
+
+// CloseAt returns when the allowed period containing t ends, or the zero time when t is
+// outside of any window or no window end is found within a year.
+func (w *RunWindows) CloseAt(t time.Time) time.Time {
+	for _, p := range w.periods(t) {
+		if !t.Before(p.start) && t.Before(p.end) {
+			if p.end.Sub(t) >= windowHorizon {
+				return time.Time{}
+			}
+			return p.end
+		}
+	}
+
+	return time.Time{}
+}
+
+// periods returns the allowed periods overlapping [t, t+windowHorizon), sorted, merged
+// when contiguous, and with blackout dates removed.
+func (w *RunWindows) periods(t time.Time) []period {
+	loc := w.location()
+	local := t.In(loc)
+	// Start the day before t, for windows that span midnight.
+	first := time.Date(local.Year(), local.Month(), local.Day()-1, 0, 0, 0, 0, loc)
+	days := int(windowHorizon/(24*time.Hour)) + 2
+
+	windows := w.Windows
+	if len(windows) == 0 {
+		windows = []TimeWindow{{}}
+	}
+
+	var result []period
+	for i := 0; i < days; i++ {
+		day := first.AddDate(0, 0, i)
+		for _, window := range windows {
+			if !window.onDay(day.Weekday()) {
+				continue
+			}
+			result = append(result, w.withoutBlackouts(window.period(day))...)
+		}
+	}
+
+	return mergePeriods(result)
+}
+
+func (w *RunWindows) location() *time.Location {
+	if w.Location == nil {
+		return time.UTC
+	}
+
+	return w.Location
+}
+
+// withoutBlackouts splits p at midnight and drops the days that are blacked out.
+func (w *RunWindows) withoutBlackouts(p period) []period {
+	if len(w.Blackouts) == 0 {
+		return []period{p}
+	}
+
+	loc := w.location()
+	var result []period
+	for start := p.start; start.Before(p.end); {
+		local := start.In(loc)
+		midnight := time.Date(local.Year(), local.Month(), local.Day()+1, 0, 0, 0, 0, loc)
+		end := p.end
+		if midnight.Before(end) {
+			end = midnight
+		}
+		if !w.blackedOut(local) {
+			result = append(result, period{start: start, end: end})
+		}
+		start = end
+	}
+
+	return result
+}
+
+func (w *RunWindows) blackedOut(local time.Time) bool {
+	date := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, time.UTC)
+	for _, blackout := range w.Blackouts {
+		if !date.Before(blackout.From) && !date.After(blackout.To) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (window *TimeWindow) onDay(day time.Weekday) bool {
+	if len(window.Days) == 0 {
+		return true
+	}
+	for _, d := range window.Days {
+		if d == day {
+			return true
+		}
+	}
+
+	return false
+}
+
+// period returns the window's period on the day starting at midnight day.
+func (window *TimeWindow) period(day time.Time) period {
+	start := clockTime(day, window.Start)
+	if window.End == window.Start {
+		return period{start: start, end: clockTime(day.AddDate(0, 0, 1), window.Start)}
+	}
+	if window.End > window.Start {
+		return period{start: start, end: clockTime(day, window.End)}
+	}
+
+	return period{start: start, end: clockTime(day.AddDate(0, 0, 1), window.End)}
+}
+
+// clockTime returns the time of day offset on the day starting at midnight day, in day's location.
+func clockTime(day time.Time, offset time.Duration) time.Time {
+	return time.Date(day.Year(), day.Month(), day.Day(), 0, int(offset/time.Minute), 0, 0, day.Location())
+}
+
+func mergePeriods(periods []period) []period {
+	sort.Slice(periods, func(i, j int) bool { return periods[i].start.Before(periods[j].start) })
+
+	var result []period
+	for _, p := range periods {
+		if n := len(result); n > 0 && !p.start.After(result[n-1].end) {
+			if p.end.After(result[n-1].end) {
+				result[n-1].end = p.end
+			}
+			continue
+		}
+		result = append(result, p)
+	}
+
+	return result
+}
@@ -0,0 +1,84 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunWindows(t *testing.T) {
+	paris, err := time.LoadLocation("Europe/Paris")
+	require.NoError(t, err)
+
+	weekdays := []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday}
+	w := &RunWindows{
+		Windows: []TimeWindow{
+			{Days: weekdays, Start: 22 * time.Hour, End: 6 * time.Hour},
+			{Days: []time.Weekday{time.Saturday, time.Sunday}},
+		},
+		Location: paris,
+	}
+	at := func(day, hour, minute int) time.Time {
+		// January 2026: the 5th is a Monday.
+		return time.Date(2026, 1, day, hour, minute, 0, 0, paris)
+	}
+
+	t.Run("Allowed", func(t *testing.T) {
+		assert.False(t, w.Allowed(at(5, 12, 0)), "Monday noon")
+		assert.True(t, w.Allowed(at(5, 22, 0)), "Monday 22:00")
+		assert.True(t, w.Allowed(at(6, 5, 59)), "Tuesday 05:59, Monday's window")
+		assert.False(t, w.Allowed(at(6, 6, 0)), "Tuesday 06:00")
+		assert.True(t, w.Allowed(at(10, 12, 0)), "Saturday noon")
+		assert.False(t, w.Allowed(at(12, 3, 0)), "Monday 03:00, after Sunday's window")
+		assert.True(t, w.Allowed(at(13, 3, 0)), "Tuesday 03:00, Monday's window")
+	})
+
+	t.Run("NextOpen", func(t *testing.T) {
+		assert.Equal(t, at(5, 22, 0), w.NextOpen(at(5, 12, 0)))
+		assert.Equal(t, at(5, 23, 0), w.NextOpen(at(5, 23, 0)))
+		assert.Equal(t, at(9, 22, 0), w.NextOpen(at(9, 7, 0)), "Friday morning opens Friday night")
+	})
+
+	t.Run("CloseAt", func(t *testing.T) {
+		assert.Equal(t, at(6, 6, 0), w.CloseAt(at(5, 23, 0)))
+		assert.True(t, w.CloseAt(at(5, 12, 0)).IsZero())
+		// Friday night, the weekend and Sunday merge up to Monday 00:00.
+		assert.Equal(t, at(12, 0, 0), w.CloseAt(at(9, 23, 0)))
+	})
+
+	t.Run("Blackouts", func(t *testing.T) {
+		b := *w
+		b.Blackouts = []DateRange{{
+			From: time.Date(2026, 1, 10, 0, 0, 0, 0, time.UTC),
+			To:   time.Date(2026, 1, 11, 0, 0, 0, 0, time.UTC),
+		}}
+
+		assert.False(t, b.Allowed(at(10, 12, 0)), "blacked out Saturday")
+		assert.Equal(t, at(10, 0, 0), b.CloseAt(at(9, 23, 0)), "Friday window cut at midnight")
+		assert.Equal(t, at(12, 22, 0), b.NextOpen(at(10, 12, 0)))
+	})
+
+	t.Run("no windows", func(t *testing.T) {
+		always := &RunWindows{}
+		now := time.Now()
+		assert.True(t, always.Allowed(now))
+		assert.Equal(t, now, always.NextOpen(now))
+		assert.True(t, always.CloseAt(now).IsZero())
+	})
+}
+
+func TestRunWindows_Validate(t *testing.T) {
+	require.NoError(t, (&RunWindows{Windows: []TimeWindow{{Start: 22 * time.Hour, End: 6 * time.Hour}}}).Validate())
+
+	err := (&RunWindows{Windows: []TimeWindow{{Start: 25 * time.Hour}}}).Validate()
+	require.Error(t, err)
+
+	err = (&RunWindows{Blackouts: []DateRange{{
+		From: time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC),
+		To:   time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+	}}}).Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "Blackout end")
+}
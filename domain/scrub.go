@@ -0,0 +1,306 @@
+package domain
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/eva01/backup-guardian/internal/errors"
+)
+
+// ModeScrub is the mode of scrub jobs, which check destinations rather than transfer files.
+const ModeScrub = "scrub"
+
+// References the files of scrubbed destinations are compared with.
+const (
+	// ScrubAgainstSource compares the files with the files of the source of the scrubbed job.
+	ScrubAgainstSource = "source"
+	// ScrubAgainstManifest compares the files with the latest manifest of the scrubbed job.
+	ScrubAgainstManifest = "manifest"
+)
+
+// Outcomes of the checks of scrubbed files.
+const (
+	// ScrubMatched marks a file whose content matches the reference.
+	ScrubMatched = "match"
+	// ScrubMismatched marks a file whose content differs from the reference, or that could not
+	// be downloaded.
+	ScrubMismatched = "mismatch"
+	// ScrubSkipped marks a file that could not be compared: missing from the reference, or
+	// changed in the source since it was synced.
+	ScrubSkipped = "skipped"
+)
+
+// Scrub makes a job a scrub job. Its runs do not sync: they download a sample of the files of
+// the destinations of another job, hash them locally and compare them with the source of that
+// job or its latest manifest, so that corruption is found without trusting the hashes the
+// providers report. The sample rotates: each run checks files not checked yet in the current
+// rotation, and a new rotation starts once all were.
+type Scrub struct {
+	// Job is the name of the scrubbed job.
+	Job string
+	// Percent is the share of the files of each destination checked by each run, from 0 to 100.
+	// Exclusive with Bytes.
+	Percent float64
+	// Bytes is the number of bytes downloaded from each destination by each run. Exclusive with
+	// Percent.
+	Bytes int64
+	// Against is ScrubAgainstSource or ScrubAgainstManifest. Empty means ScrubAgainstSource.
+	Against string
+}
+
+// Validate validates the scrub.
+func (s *Scrub) Validate() error {
+	if s.Job == "" {
+		return &errors.Error{Code: errors.CodeInvalid, Message: "Scrub job must be set"}
+	}
+	if (s.Percent == 0) == (s.Bytes == 0) {
+		return &errors.Error{Code: errors.CodeInvalid, Message: "Scrub sample must be set as either a percentage or a size"}
+	}
+	if s.Percent < 0 || s.Percent > 100 {
+		return &errors.Error{Code: errors.CodeInvalid, Message: "Scrub percentage must be between 0 and 100"}
+	}
+	if s.Bytes < 0 {
+		return &errors.Error{Code: errors.CodeInvalid, Message: "Scrub size must not be negative"}
+	}
+
+	switch s.Against {
+	case "", ScrubAgainstSource, ScrubAgainstManifest:
+	default:
+		return &errors.Error{Code: errors.CodeInvalid, Message: "Scrub against must be one of source, manifest"}
+	}
+
+	return nil
+}
+
+// AgainstOrDefault returns the reference of the scrub, defaulting to ScrubAgainstSource.
+func (s *Scrub) AgainstOrDefault() string {
+	if s.Against == "" {
+		return ScrubAgainstSource
+	}
+
+	return s.Against
+}
+
+// ResolveScrubs checks that the scrub jobs of jobs scrub sync jobs of jobs, and gives them the
+// source, destinations and encryption of the jobs they scrub.
+func ResolveScrubs(jobs []*SyncJob) error {
+	byName := map[string]*SyncJob{}
+	for _, job := range jobs {
+		byName[job.Name] = job
+	}
+
+	for _, job := range jobs {
+		if job.Scrub == nil {
+			continue
+		}
+
+		target := byName[job.Scrub.Job]
+		switch {
+		case target == nil:
+			return &errors.Error{Code: errors.CodeInvalid, Message: fmt.Sprintf("Job %s scrubs unknown job %s", job.Name, job.Scrub.Job)}
+		case target.Scrub != nil:
+			return &errors.Error{Code: errors.CodeInvalid, Message: fmt.Sprintf("Job %s cannot scrub scrub job %s", job.Name, target.Name)}
+		case job.Scrub.AgainstOrDefault() == ScrubAgainstManifest && !target.Manifest:
+			return &errors.Error{Code: errors.CodeInvalid, Message: fmt.Sprintf("Job %s scrubs job %s against its manifests, which it does not record",
+				job.Name, target.Name)}
+		}
+
+		job.Source = target.Source
+		job.Destination = target.Destination
+		job.Destinations = target.Destinations
+		job.Encryption = target.Encryption
+	}
+
+	return nil
+}
+
+// ScrubFile is a file of a destination, a candidate for scrubbing.
+type ScrubFile struct {
+	Path string
+	Size int64
+	// Hash is the hash of the file in the manifest, prefixed with its type, when scrubbing
+	// against a manifest. Empty otherwise.
+	Hash string
+}
+
+// ScrubCheck is the outcome of the check of a scrubbed file.
+type ScrubCheck struct {
+	Path string
+	Size int64
+	// Result is ScrubMatched, ScrubMismatched or ScrubSkipped.
+	Result string
+	// Reason explains mismatches and skips.
+	Reason string
+}
+
+// ScrubState is the rotation of the scrubs of a destination by a scrub job.
+type ScrubState struct {
+	JobName     string
+	Destination string
+	// Rotation numbers the rotations, from 1.
+	Rotation          int64
+	RotationStartedAt time.Time
+	// Checked are the files checked in the rotation, with when they were checked.
+	Checked   map[string]time.Time
+	UpdatedAt time.Time
+}
+
+// NewScrubState returns the state of a destination never scrubbed by the job jobName.
+func NewScrubState(jobName, dest string, now time.Time) *ScrubState {
+	return &ScrubState{JobName: jobName, Destination: dest, Rotation: 1, RotationStartedAt: now.UTC(), Checked: map[string]time.Time{}}
+}
+
+// Sample returns the files to check in the next run, among files, the files of the destination:
+// files not checked yet in the rotation, in the random order set by shuffle, up to the sample
+// size of scrub. The first file is taken even when larger than the byte budget, so that every
+// file is eventually checked. When all files were checked, a new rotation starts.
+func (s *ScrubState) Sample(files []ScrubFile, scrub *Scrub, now time.Time, shuffle func(n int, swap func(i, j int))) []ScrubFile {
+	var due []ScrubFile
+	for _, file := range files {
+		if _, ok := s.Checked[file.Path]; !ok {
+			due = append(due, file)
+		}
+	}
+	if len(due) == 0 && len(files) > 0 {
+		s.Rotation++
+		s.RotationStartedAt = now.UTC()
+		s.Checked = map[string]time.Time{}
+		due = append(due, files...)
+	}
+	shuffle(len(due), func(i, j int) { due[i], due[j] = due[j], due[i] })
+
+	if scrub.Percent > 0 {
+		count := max(int(math.Ceil(float64(len(files))*scrub.Percent/100)), 1)
+		return due[:min(count, len(due))]
+	}
+
+	var sample []ScrubFile
+	var size int64
+	for _, file := range due {
+		if len(sample) > 0 && size+file.Size > scrub.Bytes {
+			continue
+		}
+		sample = append(sample, file)
+		size += file.Size
+	}
+
+	return sample
+}
+
+// Record marks the files of checks as checked at now, and forgets the files that are not in
+// files, the files of the destination, anymore.
+func (s *ScrubState) Record(files []ScrubFile, checks []ScrubCheck, now time.Time) {
+	present := make(map[string]bool, len(files))
+	for _, file := range files {
+		present[file.Path] = true
+	}
+	for path := range s.Checked {
+		if !present[path] {
+			delete(s.Checked, path)
+		}
+	}
+	for _, check := range checks {
+		s.Checked[check.Path] = now.UTC()
+	}
+}
+
+// Coverage returns the share of files, the files of the destination, checked in the rotation,
+// from 0 to 1. A destination without files is fully covered.
+func (s *ScrubState) Coverage(files []ScrubFile) float64 {
+	if len(files) == 0 {
+		return 1
+	}
+
+	var checked int
+	for _, file := range files {
+		if _, ok := s.Checked[file.Path]; ok {
+			checked++
+		}
+	}
+
+	return float64(checked) / float64(len(files))
+}
+
+// ScrubResult is the outcome of a scrub run.
+type ScrubResult struct {
+	// Job is the scrubbed job.
+	Job          string             `json:"job"`
+	Against      string             `json:"against"`
+	Destinations []ScrubDestination `json:"destinations"`
+}
+
+// ScrubDestination is the outcome of the scrub of a destination.
+type ScrubDestination struct {
+	Destination string `json:"destination"`
+	// Files is the number of files of the destination.
+	Files int64 `json:"files"`
+	// Checked and CheckedBytes are the files downloaded and compared, and their size.
+	Checked      int64           `json:"checked"`
+	CheckedBytes int64           `json:"checked_bytes"`
+	Skipped      int64           `json:"skipped"`
+	Mismatches   []ScrubMismatch `json:"mismatches,omitempty"`
+	Rotation     int64           `json:"rotation"`
+	// Coverage is the share of the files of the destination checked in the rotation, from 0
+	// to 1.
+	Coverage float64 `json:"coverage"`
+	// Error is why the destination could not be scrubbed. Empty when it was.
+	Error string `json:"error,omitempty"`
+}
+
+// ScrubMismatch is a scrubbed file whose content differs from the reference.
+type ScrubMismatch struct {
+	Path   string `json:"path"`
+	Reason string `json:"reason"`
+}
+
+// NewScrubDestination returns the outcome of the scrub of dest, from checks, the checks of
+// its files, and state, its rotation updated by the checks.
+func NewScrubDestination(dest string, files []ScrubFile, checks []ScrubCheck, state *ScrubState) ScrubDestination {
+	result := ScrubDestination{Destination: dest, Files: int64(len(files)), Rotation: state.Rotation, Coverage: state.Coverage(files)}
+	for _, check := range checks {
+		switch check.Result {
+		case ScrubSkipped:
+			result.Skipped++
+			continue
+		case ScrubMismatched:
+			result.Mismatches = append(result.Mismatches, ScrubMismatch{Path: check.Path, Reason: check.Reason})
+		}
+		result.Checked++
+		result.CheckedBytes += check.Size
+	}
+
+	return result
+}
+
+// MismatchCount returns the number of mismatched files of all destinations.
+func (r *ScrubResult) MismatchCount() int {
+	var count int
+	for _, dest := range r.Destinations {
+		count += len(dest.Mismatches)
+	}
+
+	return count
+}
+
+// ScrubStateSelector identifies the scrub state of a destination of a scrub job.
+type ScrubStateSelector struct {
+	JobName     string
+	Destination string
+}
+
+// ScrubStatesReadWriter combines read and write operations for scrub states.
+type ScrubStatesReadWriter interface {
+	ScrubStatesReader
+	ScrubStatesWriter
+}
+
+// ScrubStatesReader defines read operations.
+type ScrubStatesReader interface {
+	GetScrubState(selector *ScrubStateSelector) (*ScrubState, error)
+}
+
+// ScrubStatesWriter defines write operations.
+type ScrubStatesWriter interface {
+	UpsertScrubState(state *ScrubState) (*ScrubState, error)
+}
@@ -0,0 +1,177 @@
+package domain
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// noShuffle keeps the files in order, so that samples are predictable.
+func noShuffle(int, func(i, j int)) {}
+
+// scrubFiles returns n files of 10 bytes each.
+func scrubFiles(n int) []ScrubFile {
+	files := make([]ScrubFile, n)
+	for i := range files {
+		files[i] = ScrubFile{Path: fmt.Sprintf("f%02d", i), Size: 10}
+	}
+
+	return files
+}
+
+// matches returns the checks of files, all matching.
+func matches(files []ScrubFile) []ScrubCheck {
+	checks := make([]ScrubCheck, len(files))
+	for i, file := range files {
+		checks[i] = ScrubCheck{Path: file.Path, Size: file.Size, Result: ScrubMatched}
+	}
+
+	return checks
+}
+
+func TestScrub_Validate(t *testing.T) {
+	require.NoError(t, (&Scrub{Job: "a", Percent: 5}).Validate())
+	require.NoError(t, (&Scrub{Job: "a", Bytes: 1 << 30, Against: ScrubAgainstManifest}).Validate())
+
+	tests := map[string]struct {
+		scrub *Scrub
+		want  string
+	}{
+		"no job":      {scrub: &Scrub{Percent: 5}, want: "Scrub job must be set"},
+		"no sample":   {scrub: &Scrub{Job: "a"}, want: "either a percentage or a size"},
+		"both":        {scrub: &Scrub{Job: "a", Percent: 5, Bytes: 10}, want: "either a percentage or a size"},
+		"over 100":    {scrub: &Scrub{Job: "a", Percent: 101}, want: "between 0 and 100"},
+		"bad against": {scrub: &Scrub{Job: "a", Percent: 5, Against: "provider"}, want: "Scrub against must be one of"},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := tt.scrub.Validate()
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.want)
+		})
+	}
+}
+
+func TestSyncJob_Validate_Scrub(t *testing.T) {
+	job := &SyncJob{Name: "s", Scrub: &Scrub{Job: "a", Percent: 5}}
+	require.NoError(t, job.Validate())
+	assert.Equal(t, ModeScrub, job.SyncMode())
+
+	job.Manifest = true
+	assert.ErrorContains(t, job.Validate(), "Scrub jobs cannot set Manifest")
+}
+
+func TestResolveScrubs(t *testing.T) {
+	enc := &Encryption{PasswordEnv: "PASSWORD"}
+	target := &SyncJob{Name: "a", Source: "src:", Destinations: []string{"b:", "c:"}, Encryption: enc}
+	scrub := &SyncJob{Name: "s", Scrub: &Scrub{Job: "a", Percent: 5}}
+
+	require.NoError(t, ResolveScrubs([]*SyncJob{target, scrub}))
+	assert.Equal(t, "src:", scrub.Source)
+	assert.Equal(t, []string{"b:", "c:"}, scrub.AllDestinations())
+	assert.Same(t, enc, scrub.Encryption)
+
+	t.Run("scrub of a scrub job", func(t *testing.T) {
+		other := &SyncJob{Name: "t", Scrub: &Scrub{Job: "s", Percent: 5}}
+		assert.ErrorContains(t, ResolveScrubs([]*SyncJob{target, scrub, other}), "Job t cannot scrub scrub job s")
+	})
+
+	t.Run("against missing manifests", func(t *testing.T) {
+		other := &SyncJob{Name: "t", Scrub: &Scrub{Job: "a", Percent: 5, Against: ScrubAgainstManifest}}
+		assert.ErrorContains(t, ResolveScrubs([]*SyncJob{target, other}), "which it does not record")
+
+		target.Manifest = true
+		assert.NoError(t, ResolveScrubs([]*SyncJob{target, other}))
+	})
+}
+
+func TestScrubState_Sample_Percent(t *testing.T) {
+	now := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	files := scrubFiles(10)
+	scrub := &Scrub{Job: "a", Percent: 25}
+	state := NewScrubState("s", "dst:", now)
+
+	// 25% of 10 files rounds up to 3: the rotation takes 4 runs, the last one checking 1 file.
+	var checked []string
+	for run, want := range []int{3, 3, 3, 1} {
+		sample := state.Sample(files, scrub, now, noShuffle)
+		require.Len(t, sample, want, "run %d", run)
+		state.Record(files, matches(sample), now)
+		for _, file := range sample {
+			checked = append(checked, file.Path)
+		}
+		assert.Equal(t, int64(1), state.Rotation)
+	}
+	// Every file was checked exactly once.
+	assert.ElementsMatch(t, []string{"f00", "f01", "f02", "f03", "f04", "f05", "f06", "f07", "f08", "f09"}, checked)
+	assert.Equal(t, 1.0, state.Coverage(files))
+
+	// Then a new rotation starts.
+	later := now.Add(time.Hour)
+	sample := state.Sample(files, scrub, later, noShuffle)
+	assert.Len(t, sample, 3)
+	assert.Equal(t, int64(2), state.Rotation)
+	assert.Equal(t, later, state.RotationStartedAt)
+	assert.Equal(t, 0.0, state.Coverage(files))
+}
+
+func TestScrubState_Sample_Bytes(t *testing.T) {
+	now := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	files := []ScrubFile{{Path: "big", Size: 100}, {Path: "a", Size: 10}, {Path: "b", Size: 15}, {Path: "c", Size: 10}}
+	state := NewScrubState("s", "dst:", now)
+
+	// The first file is taken even when larger than the budget, so that it is eventually checked.
+	sample := state.Sample(files, &Scrub{Job: "a", Bytes: 20}, now, noShuffle)
+	assert.Equal(t, []ScrubFile{{Path: "big", Size: 100}}, sample)
+	state.Record(files, matches(sample), now)
+
+	// Files that do not fit are skipped for smaller ones.
+	sample = state.Sample(files, &Scrub{Job: "a", Bytes: 20}, now, noShuffle)
+	assert.Equal(t, []ScrubFile{{Path: "a", Size: 10}, {Path: "c", Size: 10}}, sample)
+}
+
+func TestScrubState_Record(t *testing.T) {
+	now := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	files := scrubFiles(4)
+	state := NewScrubState("s", "dst:", now)
+
+	state.Record(files, matches(files[:2]), now)
+	assert.Equal(t, 0.5, state.Coverage(files))
+
+	// Files gone from the destination are forgotten, and new ones are due.
+	files = append(files[1:], ScrubFile{Path: "new", Size: 1})
+	state.Record(files, nil, now)
+	assert.Equal(t, map[string]time.Time{"f01": now}, state.Checked)
+	assert.Equal(t, 0.25, state.Coverage(files))
+	assert.Equal(t, 1.0, state.Coverage(nil))
+}
+
+func TestNewScrubDestination(t *testing.T) {
+	now := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	files := scrubFiles(4)
+	checks := []ScrubCheck{
+		{Path: "f00", Size: 10, Result: ScrubMatched},
+		{Path: "f01", Size: 10, Result: ScrubMismatched, Reason: "content hashes to sha256:x, the source to sha256:y"},
+		{Path: "f02", Result: ScrubSkipped, Reason: "changed in the source since the last sync"},
+	}
+	state := NewScrubState("s", "dst:", now)
+	state.Record(files, checks, now)
+
+	result := NewScrubDestination("dst:", files, checks, state)
+	assert.Equal(t, ScrubDestination{
+		Destination:  "dst:",
+		Files:        4,
+		Checked:      2,
+		CheckedBytes: 20,
+		Skipped:      1,
+		Mismatches:   []ScrubMismatch{{Path: "f01", Reason: "content hashes to sha256:x, the source to sha256:y"}},
+		Rotation:     1,
+		Coverage:     0.75,
+	}, result)
+
+	scrub := &ScrubResult{Destinations: []ScrubDestination{result, result}}
+	assert.Equal(t, 2, scrub.MismatchCount())
+}
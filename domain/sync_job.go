@@ -1,9 +1,401 @@
 package domain
 
-// SyncJob represents a sync job configuration (source, destination).
-// Initially configured via .env; can be extended to DB later.
+import (
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/eva01/backup-guardian/internal/errors"
+)
+
+// Catch-up policies, applied to schedule slots missed while the runner was down.
+const (
+	// CatchUpOnce runs the job once for all missed slots.
+	CatchUpOnce = "once"
+	// CatchUpAll runs the job once per missed slot.
+	CatchUpAll = "all"
+	// CatchUpSkip ignores missed slots and waits for the next one.
+	CatchUpSkip = "skip"
+)
+
+// Modes, the rclone operation performed by the runs of a job.
+const (
+	// ModeSync makes the destination identical to the source, deleting extra files.
+	ModeSync = "sync"
+	// ModeCopy copies new and changed files to the destination and never deletes.
+	ModeCopy = "copy"
+	// ModeMove moves files to the destination, deleting them from the source once copied.
+	ModeMove = "move"
+	// ModeBisync synchronises both ways: changes on either side are applied to the other.
+	ModeBisync = "bisync"
+)
+
+// Fan-out policies, how many destinations of a job must succeed for a run to succeed.
+const (
+	// RequireAll fails the run when any destination fails.
+	RequireAll = "all"
+	// RequireAny succeeds the run when at least one destination succeeds.
+	RequireAny = "any"
+)
+
+// SyncJob represents a sync job configuration (source, destination, schedule).
+// Configured via .env for a single job, or via the jobs file (BG_JOBS_FILE).
 type SyncJob struct {
 	Name        string
 	Source      string
 	Destination string
+	// Destinations fans the job out: when set, each run transfers the source to all of them in
+	// parallel, and Destination is ignored.
+	Destinations []string
+	// Require is the fan-out policy. Empty means RequireAll.
+	Require string
+	// Mode is the operation performed by the runs. Empty means ModeSync.
+	Mode string
+	// Interval between scheduled runs. Zero uses the runner's default interval.
+	Interval time.Duration
+	// CatchUp is the catch-up policy. Empty means CatchUpOnce.
+	CatchUp string
+	// Windows restricts when the job may run. Nil means at any time.
+	Windows *RunWindows
+	// Bandwidth limits the transfer rates of the job. Nil means unlimited.
+	Bandwidth *BandwidthSchedule
+	// Filters selects the synced files. Nil syncs everything under the source.
+	Filters *Filters
+	// Encryption encrypts the destinations with rclone crypt. Nil stores files as they are.
+	Encryption *Encryption
+	// RPO is the maximum age of the last successful run before the job is reported stale.
+	// Zero disables staleness alerting.
+	RPO time.Duration
+	// Heartbeat pings an external monitor on each run. Nil sends no ping.
+	Heartbeat *Heartbeat
+	// Hooks are run before and after each sync.
+	Hooks []*Hook
+	// After are the jobs this job depends on. A job with dependencies is not scheduled: it runs
+	// in the pipelines of its upstream jobs, once their runs satisfy all its dependencies.
+	After []*Dependency
+	// Capacity checks that the changes of each run fit on the destinations. Nil runs without
+	// checking.
+	Capacity *Capacity
+	// Anomaly flags the runs whose planned changes deviate from the previous runs of the job, as
+	// ransomware encrypting the source would, before they sync. Nil checks no run.
+	Anomaly *AnomalyDetection
+	// Canaries are verified before each run, which aborts when any is missing or altered.
+	Canaries []*Canary
+	// Manifest records a manifest of the destinations after each successful run. Destinations
+	// without cheap hashes are read back to hash the files that changed since the previous one.
+	Manifest bool
+	// Scrub makes the job a scrub job, which checks the destinations of another job instead of
+	// syncing. Its source, destinations and encryption are those of the scrubbed job.
+	Scrub *Scrub
+}
+
+// Validate validates the sync job.
+func (j *SyncJob) Validate() error {
+	if j.Name == "" {
+		return &errors.Error{Code: errors.CodeInvalid, Message: "Name must be set"}
+	}
+	// Scrub jobs get their source and destinations from the job they scrub.
+	if j.Source == "" && j.Scrub == nil {
+		return &errors.Error{Code: errors.CodeInvalid, Message: "Source must be set"}
+	}
+	if j.Destination == "" && len(j.Destinations) == 0 && j.Scrub == nil {
+		return &errors.Error{Code: errors.CodeInvalid, Message: "Destination must be set"}
+	}
+	seen := map[string]bool{}
+	for _, dest := range j.Destinations {
+		if dest == "" {
+			return &errors.Error{Code: errors.CodeInvalid, Message: "Destinations must not be empty"}
+		}
+		if seen[dest] {
+			return &errors.Error{Code: errors.CodeInvalid, Message: fmt.Sprintf("Destination %s is listed more than once", dest)}
+		}
+		seen[dest] = true
+	}
+	if j.Interval < 0 {
+		return &errors.Error{Code: errors.CodeInvalid, Message: "Interval must not be negative"}
+	}
+	if j.RPO < 0 {
+		return &errors.Error{Code: errors.CodeInvalid, Message: "RPO must not be negative"}
+	}
+
+	switch j.CatchUp {
+	case "", CatchUpOnce, CatchUpAll, CatchUpSkip:
+	default:
+		return &errors.Error{Code: errors.CodeInvalid, Message: "CatchUp must be one of once, all, skip"}
+	}
+
+	switch j.Mode {
+	case "", ModeSync, ModeCopy, ModeMove, ModeBisync:
+	default:
+		return &errors.Error{Code: errors.CodeInvalid, Message: "Mode must be one of sync, copy, move, bisync"}
+	}
+
+	switch j.Require {
+	case "", RequireAll, RequireAny:
+	default:
+		return &errors.Error{Code: errors.CodeInvalid, Message: "Require must be one of all, any"}
+	}
+
+	// A move empties the source after the first destination, and bisync pairs two sides.
+	if j.FanOut() && (j.Mode == ModeMove || j.Mode == ModeBisync) {
+		return &errors.Error{Code: errors.CodeInvalid, Message: fmt.Sprintf("Mode %s cannot fan out to several destinations", j.Mode)}
+	}
+
+	// bisync would decrypt the destination to the source, and its listings are keyed by remote
+	// names that all encrypted destinations share.
+	if j.Encryption != nil && j.Mode == ModeBisync {
+		return &errors.Error{Code: errors.CodeInvalid, Message: "Mode bisync cannot use encryption"}
+	}
+
+	if j.Capacity != nil {
+		if j.Capacity.Reserve < 0 {
+			return &errors.Error{Code: errors.CodeInvalid, Message: "Capacity reserve must not be negative"}
+		}
+		// The capacity check pre-scans the changes with a dry run, which bisync does not support.
+		if j.Capacity.Check && j.Mode == ModeBisync {
+			return &errors.Error{Code: errors.CodeInvalid, Message: "Mode bisync cannot check capacity"}
+		}
+	}
+
+	// bisync reports no change statistics to compare, and cannot fall back to copy mode.
+	if j.Anomaly != nil && j.Mode == ModeBisync {
+		return &errors.Error{Code: errors.CodeInvalid, Message: "Mode bisync cannot detect anomalies"}
+	}
+
+	if j.Windows != nil {
+		if err := j.Windows.Validate(); err != nil {
+			return err
+		}
+	}
+	if j.Bandwidth != nil {
+		if err := j.Bandwidth.Validate(); err != nil {
+			return err
+		}
+	}
+	if j.Filters != nil {
+		if err := j.Filters.Validate(); err != nil {
+			return err
+		}
+	}
+	if j.Encryption != nil {
+		if err := j.Encryption.Validate(); err != nil {
+			return err
+		}
+	}
+	if j.Heartbeat != nil {
+		if err := j.Heartbeat.Validate(); err != nil {
+			return err
+		}
+	}
+	if j.Anomaly != nil {
+		if err := j.Anomaly.Validate(); err != nil {
+			return err
+		}
+	}
+	// A move would take the canaries out of the source, failing the next runs.
+	if len(j.Canaries) > 0 && j.Mode == ModeMove {
+		return &errors.Error{Code: errors.CodeInvalid, Message: "Mode move cannot verify canaries"}
+	}
+	canaries := map[string]bool{}
+	for _, canary := range j.Canaries {
+		if err := canary.Validate(); err != nil {
+			return err
+		}
+		if canaries[canary.Path] {
+			return &errors.Error{Code: errors.CodeInvalid, Message: fmt.Sprintf("Canary %s is listed more than once", canary.Path)}
+		}
+		canaries[canary.Path] = true
+	}
+	for _, hook := range j.Hooks {
+		if err := hook.Validate(); err != nil {
+			return err
+		}
+	}
+	for _, dep := range j.After {
+		if err := dep.Validate(); err != nil {
+			return err
+		}
+		if dep.Job == j.Name {
+			return &errors.Error{Code: errors.CodeInvalid, Message: "Job must not depend on itself"}
+		}
+	}
+	if len(j.After) > 0 && j.Interval > 0 {
+		return &errors.Error{Code: errors.CodeInvalid, Message: "Jobs with dependencies run after them and cannot set an interval"}
+	}
+	if j.Scrub != nil {
+		if err := j.Scrub.Validate(); err != nil {
+			return err
+		}
+		if setting := j.syncSetting(); setting != "" {
+			return &errors.Error{Code: errors.CodeInvalid, Message: fmt.Sprintf("Scrub jobs cannot set %s", setting)}
+		}
+	}
+
+	return nil
+}
+
+// syncSetting returns the name of the first setting of the job that only applies to syncs, or
+// an empty string.
+func (j *SyncJob) syncSetting() string {
+	switch {
+	case j.Mode != "":
+		return "Mode"
+	case j.Require != "":
+		return "Require"
+	case j.Bandwidth != nil:
+		return "Bandwidth"
+	case j.Filters != nil:
+		return "Filters"
+	case j.Capacity != nil:
+		return "Capacity"
+	case j.Anomaly != nil:
+		return "Anomaly"
+	case len(j.Canaries) > 0:
+		return "Canaries"
+	case j.Manifest:
+		return "Manifest"
+	case len(j.Hooks) > 0:
+		return "Hooks"
+	}
+
+	return ""
+}
+
+// Secrets returns the secrets of the job configuration, to be redacted: the heartbeat and hook
+// URLs, which often carry tokens (Slack or ntfy webhooks), and the header values of hooks, such
+// as credentials.
+func (j *SyncJob) Secrets() []string {
+	var secrets []string
+	if j.Heartbeat != nil {
+		secrets = append(secrets, j.Heartbeat.StartURL, j.Heartbeat.SuccessURL, j.Heartbeat.FailURL, j.Heartbeat.DeferredURL)
+	}
+	for _, hook := range j.Hooks {
+		if hook.URL != "" {
+			secrets = append(secrets, hook.URL)
+		}
+		for _, value := range hook.Headers {
+			secrets = append(secrets, value)
+		}
+	}
+
+	return secrets
+}
+
+// Equal reports whether j and other have the same configuration. Time zones are compared by
+// name and blackout dates by instant, as each parse of the configuration loads zones anew.
+func (j *SyncJob) Equal(other *SyncJob) bool {
+	return reflect.DeepEqual(j.comparable(), other.comparable())
+}
+
+// comparableJob is a copy of a job whose time zones are replaced by their names.
+type comparableJob struct {
+	job           SyncJob
+	windowsZone   string
+	bandwidthZone string
+}
+
+func (j *SyncJob) comparable() comparableJob {
+	c := comparableJob{job: *j}
+	if j.Windows != nil {
+		windows := *j.Windows
+		c.windowsZone, windows.Location = windows.Location.String(), nil
+		windows.Blackouts = make([]DateRange, len(j.Windows.Blackouts))
+		for i, blackout := range j.Windows.Blackouts {
+			windows.Blackouts[i] = DateRange{From: blackout.From.UTC(), To: blackout.To.UTC()}
+		}
+		c.job.Windows = &windows
+	}
+	if j.Bandwidth != nil {
+		bandwidth := *j.Bandwidth
+		c.bandwidthZone, bandwidth.Location = bandwidth.Location.String(), nil
+		c.job.Bandwidth = &bandwidth
+	}
+
+	return c
+}
+
+// BandwidthLimitAt returns the bandwidth limit of the job at t.
+func (j *SyncJob) BandwidthLimitAt(t time.Time) BandwidthLimit {
+	if j.Bandwidth == nil {
+		return BandwidthLimit{}
+	}
+
+	return j.Bandwidth.LimitAt(t)
+}
+
+// AllDestinations returns the destinations of the job: Destinations when set, Destination
+// otherwise.
+func (j *SyncJob) AllDestinations() []string {
+	if len(j.Destinations) > 0 {
+		return j.Destinations
+	}
+
+	return []string{j.Destination}
+}
+
+// FanOut reports whether the runs of the job transfer to several destinations.
+func (j *SyncJob) FanOut() bool {
+	return len(j.Destinations) > 1
+}
+
+// RequirePolicy returns the fan-out policy of the job, defaulting to RequireAll.
+func (j *SyncJob) RequirePolicy() string {
+	if j.Require == "" {
+		return RequireAll
+	}
+
+	return j.Require
+}
+
+// FanOutStatus returns the status of a run of the job from the outcomes of its destinations.
+// Failed destinations fail the run unless the policy is RequireAny and another destination
+// succeeded or was deferred; deferred destinations defer the run, to resume them in the next
+// window.
+func (j *SyncJob) FanOutStatus(results []DestinationResult) string {
+	var succeeded, failed, deferred int
+	for _, result := range results {
+		switch result.Status {
+		case StatusSuccess:
+			succeeded++
+		case StatusDeferred:
+			deferred++
+		default:
+			failed++
+		}
+	}
+
+	switch {
+	case failed > 0 && (j.RequirePolicy() == RequireAll || succeeded+deferred == 0):
+		return StatusFailed
+	case deferred > 0:
+		return StatusDeferred
+	default:
+		return StatusSuccess
+	}
+}
+
+// Scheduled reports whether the job runs on a schedule, rather than after the jobs it depends on.
+func (j *SyncJob) Scheduled() bool {
+	return len(j.After) == 0
+}
+
+// SyncMode returns the mode of the job, defaulting to ModeSync. It is ModeScrub for scrub jobs.
+func (j *SyncJob) SyncMode() string {
+	if j.Scrub != nil {
+		return ModeScrub
+	}
+	if j.Mode == "" {
+		return ModeSync
+	}
+
+	return j.Mode
+}
+
+// CatchUpPolicy returns the catch-up policy of the job, defaulting to CatchUpOnce.
+func (j *SyncJob) CatchUpPolicy() string {
+	if j.CatchUp == "" {
+		return CatchUpOnce
+	}
+
+	return j.CatchUp
 }
@@ -0,0 +1,122 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSyncJob_Validate(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		j := &SyncJob{Name: "job", Source: "src:", Destination: "dst:", Interval: time.Hour, CatchUp: CatchUpAll}
+		require.NoError(t, j.Validate())
+	})
+
+	t.Run("empty Name", func(t *testing.T) {
+		j := &SyncJob{Source: "src:", Destination: "dst:"}
+		err := j.Validate()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "Name must be set")
+	})
+
+	t.Run("empty Destination", func(t *testing.T) {
+		j := &SyncJob{Name: "job", Source: "src:"}
+		err := j.Validate()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "Destination must be set")
+	})
+
+	t.Run("invalid CatchUp", func(t *testing.T) {
+		j := &SyncJob{Name: "job", Source: "src:", Destination: "dst:", CatchUp: "sometimes"}
+		err := j.Validate()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "CatchUp must be one of")
+	})
+
+	t.Run("encrypted bisync", func(t *testing.T) {
+		j := &SyncJob{Name: "job", Source: "src:", Destination: "dst:", Mode: ModeBisync, Encryption: &Encryption{PasswordEnv: "PW"}}
+		err := j.Validate()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "Mode bisync cannot use encryption")
+	})
+}
+
+func TestSyncJob_Secrets(t *testing.T) {
+	j := &SyncJob{
+		Heartbeat: HeartbeatFromURL("https://hc-ping.com/abc"),
+		Hooks:     []*Hook{{URL: "https://example.com", Headers: map[string]string{"Authorization": "Bearer xyz"}}, {Command: []string{"true"}}},
+	}
+	assert.ElementsMatch(t, []string{"https://hc-ping.com/abc/start", "https://hc-ping.com/abc", "https://hc-ping.com/abc/fail", "https://hc-ping.com/abc/log", "https://example.com", "Bearer xyz"}, j.Secrets())
+	assert.Empty(t, (&SyncJob{}).Secrets())
+}
+
+func TestSyncJob_SyncMode(t *testing.T) {
+	assert.Equal(t, ModeSync, (&SyncJob{}).SyncMode())
+	assert.Equal(t, ModeMove, (&SyncJob{Mode: ModeMove}).SyncMode())
+
+	err := (&SyncJob{Name: "job", Source: "src:", Destination: "dst:", Mode: "mirror"}).Validate()
+	assert.ErrorContains(t, err, "Mode must be one of")
+}
+
+func TestSyncJob_CatchUpPolicy(t *testing.T) {
+	assert.Equal(t, CatchUpOnce, (&SyncJob{}).CatchUpPolicy())
+	assert.Equal(t, CatchUpSkip, (&SyncJob{CatchUp: CatchUpSkip}).CatchUpPolicy())
+}
+
+func TestSyncJob_FanOut(t *testing.T) {
+	single := &SyncJob{Name: "job", Source: "src:", Destination: "dst:"}
+	assert.False(t, single.FanOut())
+	assert.Equal(t, []string{"dst:"}, single.AllDestinations())
+	assert.Equal(t, RequireAll, single.RequirePolicy())
+
+	fanOut := &SyncJob{Name: "job", Source: "src:", Destinations: []string{"s3:", "b2:"}, Require: RequireAny}
+	require.NoError(t, fanOut.Validate())
+	assert.True(t, fanOut.FanOut())
+	assert.Equal(t, []string{"s3:", "b2:"}, fanOut.AllDestinations())
+
+	tests := map[string]struct {
+		job  *SyncJob
+		want string
+	}{
+		"repeated destination": {job: &SyncJob{Name: "job", Source: "src:", Destinations: []string{"s3:", "s3:"}}, want: "listed more than once"},
+		"empty destination":    {job: &SyncJob{Name: "job", Source: "src:", Destinations: []string{"s3:", ""}}, want: "must not be empty"},
+		"bad require":          {job: &SyncJob{Name: "job", Source: "src:", Destinations: []string{"s3:", "b2:"}, Require: "most"}, want: "Require must be one of"},
+		"move":                 {job: &SyncJob{Name: "job", Source: "src:", Destinations: []string{"s3:", "b2:"}, Mode: ModeMove}, want: "cannot fan out"},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.ErrorContains(t, tt.job.Validate(), tt.want)
+		})
+	}
+}
+
+func TestSyncJob_FanOutStatus(t *testing.T) {
+	results := func(statuses ...string) []DestinationResult {
+		var results []DestinationResult
+		for _, status := range statuses {
+			results = append(results, DestinationResult{Status: status})
+		}
+		return results
+	}
+
+	tests := map[string]struct {
+		require  string
+		statuses []string
+		want     string
+	}{
+		"all succeed":           {require: RequireAll, statuses: []string{StatusSuccess, StatusSuccess}, want: StatusSuccess},
+		"all, one fails":        {require: RequireAll, statuses: []string{StatusSuccess, StatusFailed}, want: StatusFailed},
+		"all, one deferred":     {require: RequireAll, statuses: []string{StatusSuccess, StatusDeferred}, want: StatusDeferred},
+		"any, one fails":        {require: RequireAny, statuses: []string{StatusSuccess, StatusFailed}, want: StatusSuccess},
+		"any, all fail":         {require: RequireAny, statuses: []string{StatusFailed, StatusFailed}, want: StatusFailed},
+		"any, failed, deferred": {require: RequireAny, statuses: []string{StatusDeferred, StatusFailed}, want: StatusDeferred},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			job := &SyncJob{Require: tt.require}
+			assert.Equal(t, tt.want, job.FanOutStatus(results(tt.statuses...)))
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package domain
+
+// Kinds of sync runs.
+const (
+	// KindSync is a run that transfers files, scheduled or triggered.
+	KindSync = "sync"
+	// KindDryRun is a run that only plans the changes a sync would make. Dry runs never count
+	// as the latest or last successful run of a job.
+	KindDryRun = "dry-run"
+	// KindScrub is a run of a scrub job, which checks a sample of the files of the destinations
+	// of the job it scrubs.
+	KindScrub = "scrub"
+)
+
+// Actions of planned changes.
+const (
+	ChangeCopy   = "copy"
+	ChangeUpdate = "update"
+	ChangeDelete = "delete"
+)
+
+// MaxPlannedChanges caps the number of changes listed in a plan. The counters of the plan
+// include the changes left out.
+const MaxPlannedChanges = 10000
+
+// PlannedChange is a file a sync would copy, update or delete.
+type PlannedChange struct {
+	Action string `json:"action"`
+	Path   string `json:"path"`
+	// Size is the size of the source file for copies and updates, of the deleted file for
+	// deletions. -1 when unknown.
+	Size int64 `json:"size"`
+	// Destination is the destination of the change, for jobs fanning out to several
+	// destinations.
+	Destination string `json:"destination,omitempty"`
+}
+
+// SyncPlan is the result of a dry run: the changes a sync would make.
+type SyncPlan struct {
+	// Changes are the planned changes, at most MaxPlannedChanges.
+	Changes   []PlannedChange `json:"changes,omitempty"`
+	Truncated bool            `json:"truncated,omitempty"`
+
+	Copies      int64 `json:"copies"`
+	CopyBytes   int64 `json:"copy_bytes"`
+	Updates     int64 `json:"updates"`
+	UpdateBytes int64 `json:"update_bytes"`
+	Deletes     int64 `json:"deletes"`
+	DeleteBytes int64 `json:"delete_bytes"`
+}
+
+// Add records change in the plan.
+func (p *SyncPlan) Add(change PlannedChange) {
+	size := max(change.Size, 0)
+	switch change.Action {
+	case ChangeCopy:
+		p.Copies++
+		p.CopyBytes += size
+	case ChangeUpdate:
+		p.Updates++
+		p.UpdateBytes += size
+	case ChangeDelete:
+		p.Deletes++
+		p.DeleteBytes += size
+	}
+
+	if len(p.Changes) >= MaxPlannedChanges {
+		p.Truncated = true
+		return
+	}
+	p.Changes = append(p.Changes, change)
+}
+
+// Merge adds the changes of other, planned for destination dest, to the plan.
+func (p *SyncPlan) Merge(other *SyncPlan, dest string) {
+	if other == nil {
+		return
+	}
+
+	p.Copies += other.Copies
+	p.CopyBytes += other.CopyBytes
+	p.Updates += other.Updates
+	p.UpdateBytes += other.UpdateBytes
+	p.Deletes += other.Deletes
+	p.DeleteBytes += other.DeleteBytes
+	p.Truncated = p.Truncated || other.Truncated
+
+	for _, change := range other.Changes {
+		if len(p.Changes) >= MaxPlannedChanges {
+			p.Truncated = true
+			return
+		}
+		change.Destination = dest
+		p.Changes = append(p.Changes, change)
+	}
+}
+
+// Empty reports whether the plan has no change.
+func (p *SyncPlan) Empty() bool {
+	return p.Copies == 0 && p.Updates == 0 && p.Deletes == 0
+}
@@ -0,0 +1,39 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSyncPlan_Add(t *testing.T) {
+	plan := &SyncPlan{}
+	for range MaxPlannedChanges + 1 {
+		plan.Add(PlannedChange{Action: ChangeCopy, Path: "a", Size: 2})
+	}
+	plan.Add(PlannedChange{Action: ChangeDelete, Path: "b", Size: -1})
+
+	assert.Len(t, plan.Changes, MaxPlannedChanges)
+	assert.True(t, plan.Truncated)
+	assert.Equal(t, int64(MaxPlannedChanges+1), plan.Copies)
+	assert.Equal(t, int64(2*(MaxPlannedChanges+1)), plan.CopyBytes)
+	assert.Equal(t, int64(1), plan.Deletes)
+	assert.Zero(t, plan.DeleteBytes)
+	assert.False(t, plan.Empty())
+}
+
+func TestSyncPlan_Merge(t *testing.T) {
+	plan := &SyncPlan{}
+	plan.Merge(nil, "s3")
+	assert.True(t, plan.Empty())
+
+	plan.Merge(&SyncPlan{
+		Changes: []PlannedChange{{Action: ChangeUpdate, Path: "a", Size: 4}},
+		Updates: 2, UpdateBytes: 8, Truncated: true,
+	}, "s3")
+
+	assert.Equal(t, []PlannedChange{{Action: ChangeUpdate, Path: "a", Size: 4, Destination: "s3"}}, plan.Changes)
+	assert.Equal(t, int64(2), plan.Updates)
+	assert.Equal(t, int64(8), plan.UpdateBytes)
+	assert.True(t, plan.Truncated)
+}
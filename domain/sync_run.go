@@ -12,8 +12,18 @@ const (
 	StatusRunning = "running"
 	StatusSuccess = "success"
 	StatusFailed  = "failed"
+	// StatusDeferred marks a run stopped when its window closed, to be resumed in the next window.
+	StatusDeferred = "deferred"
+	// StatusCanaryFailed marks a run aborted before syncing because canary files of the source
+	// were missing or altered. It is a failure.
+	StatusCanaryFailed = "canary_failed"
 )
 
+// Failed reports whether status is the status of a failed run.
+func Failed(status string) bool {
+	return status == StatusFailed || status == StatusCanaryFailed
+}
+
 // SyncRun represents a single sync execution.
 type SyncRun struct {
 	ID               string
@@ -24,7 +34,43 @@ type SyncRun struct {
 	ErrorMessage     string
 	FilesTransferred int64
 	BytesTransferred int64
-	CreatedAt        time.Time
+	// UploadLimit and DownloadLimit are the bandwidth limits in effect when the run started,
+	// in bytes per second. Zero means unlimited.
+	UploadLimit   int64
+	DownloadLimit int64
+	// FilesTotal and BytesTotal are the amounts to transfer, as known at the last progress
+	// checkpoint or at the end of the run. Zero when unknown.
+	FilesTotal int64
+	BytesTotal int64
+	// Mode is the operation performed by the run (ModeSync, ModeCopy...).
+	Mode string
+	// Filters are the filters of the job when the run started. Nil when it had none.
+	Filters *Filters
+	// Hooks are the results of the hooks run so far, in order.
+	Hooks []HookResult
+	// Destinations are the outcomes of the destinations of a fan-out run, in the order of the
+	// job. Nil for single destination runs.
+	Destinations []DestinationResult
+	// PipelineID links the runs of a pipeline: it is the ID of the run of its first job. Empty
+	// for runs of jobs that have no dependency and no dependent.
+	PipelineID string
+	// Kind is KindSync, KindDryRun for runs that only plan changes, or KindScrub for the runs of
+	// scrub jobs.
+	Kind string
+	// Plan is the changes planned by a dry run. Nil for other runs.
+	Plan *SyncPlan
+	// Usage is the storage usage of the destinations, read by the capacity check of the run and
+	// at its end. Nil when their backends cannot tell.
+	Usage []DestinationUsage
+	// Changes counts the changes made by the run, recorded for jobs with anomaly detection.
+	// Nil for other runs.
+	Changes *ChangeStats
+	// Anomaly is how the changes planned for the run deviate from the previous runs of its job.
+	// Nil when they do not, or were not checked.
+	Anomaly *Anomaly
+	// Scrub is the outcome of the run of a scrub job. Nil for other runs.
+	Scrub     *ScrubResult
+	CreatedAt time.Time
 }
 
 // SyncRunSelector identifies a sync run for reads.
@@ -34,9 +80,18 @@ type SyncRunSelector struct {
 
 // SyncRunsSelector filters sync runs for listing.
 type SyncRunsSelector struct {
-	JobName string
-	Limit   int
-	Offset  int
+	// PipelineID lists the runs of a pipeline, oldest first. Other filters are then ignored.
+	PipelineID string
+	JobName    string
+	// Status filters runs by status. Only applied together with JobName.
+	Status string
+	Limit  int
+	Offset int
+	// DryRuns includes dry runs. They are left out by default, so that they never count as
+	// the latest or last successful run of a job. Ignored with PipelineID.
+	DryRuns bool
+	// Oldest lists the runs of JobName oldest first. Ignored with PipelineID or Status.
+	Oldest bool
 }
 
 // Validate validates the sync run.
@@ -54,6 +109,30 @@ func (r *SyncRun) Validate() error {
 	return nil
 }
 
+// DryRun reports whether the run is a dry run.
+func (r *SyncRun) DryRun() bool {
+	return r.Kind == KindDryRun
+}
+
+// Anomalous reports whether the run was flagged as anomalous.
+func (r *SyncRun) Anomalous() bool {
+	return r.Anomaly != nil
+}
+
+// BytesTransferredTo returns the bytes the run transferred to dest.
+func (r *SyncRun) BytesTransferredTo(dest string) int64 {
+	if len(r.Destinations) == 0 {
+		return r.BytesTransferred
+	}
+	for _, outcome := range r.Destinations {
+		if outcome.Destination == dest {
+			return outcome.BytesTransferred
+		}
+	}
+
+	return 0
+}
+
 // NewSyncRunID returns a new UUID for a sync run.
 func NewSyncRunID() string {
 	return uuid.New().String()
@@ -0,0 +1,47 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/eva01/backup-guardian/internal/errors"
+)
+
+// SyncRunLog holds the log lines emitted during a sync run, runner and rclone logs alike.
+type SyncRunLog struct {
+	RunID string
+	// Content is the text of the log, one record per line.
+	Content []byte
+	// Truncated reports that records were dropped once the log reached its size cap.
+	Truncated bool
+	CreatedAt time.Time
+}
+
+// SyncRunLogSelector identifies the log of a sync run.
+type SyncRunLogSelector struct {
+	RunID string
+}
+
+// Validate validates the sync run log.
+func (l *SyncRunLog) Validate() error {
+	if l.RunID == "" {
+		return &errors.Error{Code: errors.CodeInvalid, Message: "RunID must be set"}
+	}
+
+	return nil
+}
+
+// SyncRunLogsReadWriter combines read and write operations for sync run logs.
+type SyncRunLogsReadWriter interface {
+	SyncRunLogsReader
+	SyncRunLogsWriter
+}
+
+// SyncRunLogsReader defines read operations.
+type SyncRunLogsReader interface {
+	GetSyncRunLog(selector *SyncRunLogSelector) (*SyncRunLog, error)
+}
+
+// SyncRunLogsWriter defines write operations.
+type SyncRunLogsWriter interface {
+	CreateSyncRunLog(log *SyncRunLog) (*SyncRunLog, error)
+}
@@ -1,14 +1,24 @@
 // Package environment provides methods to interact with environment variables.
 // Please, make sure to update the .env.example file when modifying this structure.
+//
+// Fields tagged secret:"true" can be read from a file instead, named by the variable with a
+// _FILE suffix (e.g. BG_API_PASSWORD_FILE), as Docker and Kubernetes mount secrets.
 package environment
 
 import (
+	"errors"
 	"fmt"
+	"log/slog"
+	"os"
 	"path/filepath"
+	"reflect"
+	"strings"
 	"time"
 
 	"github.com/caarlos0/env/v11"
+	"github.com/eva01/backup-guardian/domain"
 	"github.com/joho/godotenv"
+	"github.com/rclone/rclone/fs"
 )
 
 // Variables represents the environment variables used by the application.
@@ -21,27 +31,201 @@ type Variables struct {
 	SyncDest   string `env:"BG_SYNC_DEST,required" envDefault:"s3:bucket-name/backups"`
 	SyncInterval string `env:"BG_SYNC_INTERVAL" envDefault:"6h"`
 
+	// SyncMode is the operation of the job above: sync, copy, move or bisync.
+	SyncMode string `env:"BG_SYNC_MODE" envDefault:"sync"`
+
+	// SyncCatchUp is the catch-up policy of the job above: once, all or skip.
+	SyncCatchUp string `env:"BG_SYNC_CATCH_UP" envDefault:"once"`
+
+	// SyncRPO is the maximum age of the last successful run of the job above before it is
+	// reported stale (e.g. 26h). Zero disables staleness alerting.
+	SyncRPO time.Duration `env:"BG_SYNC_RPO"`
+
+	// SyncHeartbeatURL is a healthchecks.io style check URL pinged by the job above: on success,
+	// with /start on start and /fail on failure. Empty sends no ping.
+	SyncHeartbeatURL string `env:"BG_SYNC_HEARTBEAT_URL" secret:"true"`
+
+	// JobsFile is an optional YAML file defining several jobs. When set, it replaces
+	// the single job defined by BG_SYNC_SOURCE and BG_SYNC_DEST.
+	JobsFile string `env:"BG_JOBS_FILE"`
+
 	LogLevel string `env:"BG_LOG_LEVEL" envDefault:"info"`
+
+	// RunLogLevel is the minimum level of the records captured and stored with each sync run,
+	// rclone's included: debug, info, warn, error, or off to disable run logs.
+	RunLogLevel string `env:"BG_RUN_LOG_LEVEL" envDefault:"info"`
+	// RunLogMaxSize caps the size of a run log (e.g. 512K, 1M). Records past it are dropped.
+	RunLogMaxSize string `env:"BG_RUN_LOG_MAX_SIZE" envDefault:"1M"`
+
+	// APIAddr is the listen address of the HTTP API and metrics endpoint. Empty disables it.
+	APIAddr string `env:"BG_API_ADDR" envDefault:"127.0.0.1:8080"`
+
+	// APIUsername and APIPassword enable basic auth on the HTTP API and dashboard.
+	APIUsername string `env:"BG_API_USERNAME" envDefault:"admin"`
+	APIPassword string `env:"BG_API_PASSWORD" secret:"true"`
+
+	// APIToken enables bearer token auth on the HTTP API, e.g. for Prometheus scrapes.
+	APIToken string `env:"BG_API_TOKEN" secret:"true"`
+
+	// NotifyWebhookURL receives notifications (e.g. RPO violations) as JSON POST requests.
+	// Empty sends notifications to the log only.
+	NotifyWebhookURL string `env:"BG_NOTIFY_WEBHOOK_URL" secret:"true"`
+
+	// RcloneConfig is the path of the rclone config file. Empty uses rclone's default,
+	// ~/.config/rclone/rclone.conf.
+	RcloneConfig string `env:"BG_RCLONE_CONFIG"`
+	// RcloneConfigPass is the password of an encrypted rclone config file.
+	RcloneConfigPass string `env:"BG_RCLONE_CONFIG_PASS" secret:"true"`
+
+	// StartupChecks are the checks run when the runner starts (see bgctl doctor): warn logs
+	// the failures, strict refuses to start on failure, off skips the checks.
+	StartupChecks string `env:"BG_STARTUP_CHECKS" envDefault:"warn"`
+
+	// ReloadOnChange reloads the configuration when the jobs file changes, as on SIGHUP.
+	ReloadOnChange bool `env:"BG_RELOAD_ON_CHANGE"`
 }
 
+// Startup check modes.
+const (
+	StartupChecksOff    = "off"
+	StartupChecksWarn   = "warn"
+	StartupChecksStrict = "strict"
+)
+
+// SyncJobName is the name of the job configured from BG_SYNC_SOURCE and BG_SYNC_DEST.
+const SyncJobName = "gdrive-to-s3"
+
 // DBPath returns the SQLite database path, derived from DataDir.
 func (v *Variables) DBPath() string {
 	return filepath.Join(v.DataDir, "backup-guardian.db")
 }
 
+// BisyncDir returns the directory of the bisync listings, derived from DataDir.
+func (v *Variables) BisyncDir() string {
+	return filepath.Join(v.DataDir, "bisync")
+}
+
+// SyncJob returns the sync job configured from the environment.
+func (v *Variables) SyncJob() *domain.SyncJob {
+	job := &domain.SyncJob{
+		Name:        SyncJobName,
+		Source:      v.SyncSource,
+		Destination: v.SyncDest,
+		Mode:        v.SyncMode,
+		CatchUp:     v.SyncCatchUp,
+		RPO:         v.SyncRPO,
+	}
+	if v.SyncHeartbeatURL != "" {
+		job.Heartbeat = domain.HeartbeatFromURL(v.SyncHeartbeatURL)
+	}
+
+	return job
+}
+
 // SyncIntervalDuration returns the parsed sync interval.
 func (v *Variables) SyncIntervalDuration() (time.Duration, error) {
 	return time.ParseDuration(v.SyncInterval)
 }
 
-// Parse environment variables.
+// Secrets returns the values of the secret variables that are set.
+func (v *Variables) Secrets() []string {
+	var secrets []string
+	value := reflect.ValueOf(v).Elem()
+	for _, field := range reflect.VisibleFields(value.Type()) {
+		secret := value.FieldByIndex(field.Index).String()
+		if field.Tag.Get("secret") == "true" && secret != "" {
+			secrets = append(secrets, secret)
+		}
+	}
+
+	return secrets
+}
+
+// Validate checks the variables that are parsed when they are used, so that their errors are
+// reported together at startup.
+func (v *Variables) Validate() error {
+	var errs []error
+	if _, err := v.SyncIntervalDuration(); err != nil {
+		errs = append(errs, fmt.Errorf("invalid BG_SYNC_INTERVAL %q: expected a duration such as 6h", v.SyncInterval))
+	}
+
+	switch strings.ToLower(v.LogLevel) {
+	case "debug", "info", "warn", "error":
+	default:
+		errs = append(errs, fmt.Errorf("invalid BG_LOG_LEVEL %q: expected debug, info, warn or error", v.LogLevel))
+	}
+
+	if !strings.EqualFold(v.RunLogLevel, "off") {
+		var level slog.Level
+		if err := level.UnmarshalText([]byte(v.RunLogLevel)); err != nil {
+			errs = append(errs, fmt.Errorf("invalid BG_RUN_LOG_LEVEL %q: expected debug, info, warn, error or off", v.RunLogLevel))
+		}
+	}
+
+	var size fs.SizeSuffix
+	if err := size.Set(v.RunLogMaxSize); err != nil || size <= 0 {
+		errs = append(errs, fmt.Errorf("invalid BG_RUN_LOG_MAX_SIZE %q: expected a size such as 1M", v.RunLogMaxSize))
+	}
+
+	switch v.StartupChecks {
+	case StartupChecksOff, StartupChecksWarn, StartupChecksStrict:
+	default:
+		errs = append(errs, fmt.Errorf("invalid BG_STARTUP_CHECKS %q: expected off, warn or strict", v.StartupChecks))
+	}
+
+	return errors.Join(errs...)
+}
+
+// Parse environment variables. It panics when they cannot be parsed: use Load to handle
+// the error.
 func Parse() *Variables {
+	result, err := Load()
+	if err != nil {
+		panic(err)
+	}
+
+	return result
+}
+
+// Load parses the environment variables, and the .env file of the working directory when
+// there is one.
+func Load() (*Variables, error) {
 	godotenv.Load() // Used for local development.
 
+	result, err := parse(env.ToMap(os.Environ()))
+	if err != nil {
+		return nil, fmt.Errorf("could not parse environment variables: %w", err)
+	}
+
+	return result, nil
+}
+
+// parse parses the variables of environment, reading secrets from their _FILE variants.
+func parse(environment map[string]string) (*Variables, error) {
+	for _, field := range reflect.VisibleFields(reflect.TypeFor[Variables]()) {
+		if field.Tag.Get("secret") != "true" {
+			continue
+		}
+		name, _, _ := strings.Cut(field.Tag.Get("env"), ",")
+		path := environment[name+"_FILE"]
+		if path == "" {
+			continue
+		}
+		if environment[name] != "" {
+			return nil, fmt.Errorf("%s and %s_FILE are mutually exclusive", name, name)
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("could not read %s_FILE: %w", name, err)
+		}
+		// Files written by editors and echo end with a newline that is not part of the secret.
+		environment[name] = strings.TrimRight(string(data), "\r\n")
+	}
+
 	result := &Variables{}
-	if err := env.Parse(result); err != nil {
-		panic(fmt.Errorf("could not parse environment variables: %w", err))
+	if err := env.ParseWithOptions(result, env.Options{Environment: environment}); err != nil {
+		return nil, err
 	}
 
-	return result
+	return result, nil
 }
@@ -1,6 +1,8 @@
 package environment
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -29,3 +31,76 @@ func TestVariables_SyncIntervalDuration(t *testing.T) {
 		require.Error(t, err)
 	})
 }
+
+func TestVariables_SyncJob(t *testing.T) {
+	v := &Variables{SyncSource: "gdrive:", SyncDest: "s3:bucket/backups"}
+	job := v.SyncJob()
+	assert.Equal(t, SyncJobName, job.Name)
+	assert.Equal(t, "gdrive:", job.Source)
+	assert.Equal(t, "s3:bucket/backups", job.Destination)
+}
+
+func TestVariables_SyncJob_Heartbeat(t *testing.T) {
+	v := &Variables{SyncSource: "gdrive:", SyncDest: "s3:bucket/backups", SyncHeartbeatURL: "https://hc-ping.com/abc"}
+	job := v.SyncJob()
+	require.NotNil(t, job.Heartbeat)
+	assert.Equal(t, "https://hc-ping.com/abc/start", job.Heartbeat.StartURL)
+	assert.Equal(t, "https://hc-ping.com/abc", job.Heartbeat.SuccessURL)
+	assert.Equal(t, "https://hc-ping.com/abc/fail", job.Heartbeat.FailURL)
+	assert.Equal(t, "https://hc-ping.com/abc/log", job.Heartbeat.DeferredURL)
+}
+
+func TestParse_SecretFiles(t *testing.T) {
+	dir := t.TempDir()
+	passwordFile := filepath.Join(dir, "api-password")
+	require.NoError(t, os.WriteFile(passwordFile, []byte("s3cr3t\n"), 0o600))
+
+	t.Run("file", func(t *testing.T) {
+		v, err := parse(map[string]string{"BG_API_PASSWORD_FILE": passwordFile, "BG_API_TOKEN": "token-123"})
+		require.NoError(t, err)
+		assert.Equal(t, "s3cr3t", v.APIPassword)
+		assert.ElementsMatch(t, []string{"s3cr3t", "token-123"}, v.Secrets())
+	})
+
+	t.Run("both", func(t *testing.T) {
+		_, err := parse(map[string]string{"BG_API_PASSWORD_FILE": passwordFile, "BG_API_PASSWORD": "other"})
+		assert.ErrorContains(t, err, "BG_API_PASSWORD and BG_API_PASSWORD_FILE are mutually exclusive")
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		_, err := parse(map[string]string{"BG_RCLONE_CONFIG_PASS_FILE": filepath.Join(dir, "missing")})
+		assert.ErrorContains(t, err, "could not read BG_RCLONE_CONFIG_PASS_FILE")
+	})
+
+	t.Run("not a secret", func(t *testing.T) {
+		v, err := parse(map[string]string{"BG_API_USERNAME_FILE": passwordFile})
+		require.NoError(t, err)
+		assert.Equal(t, "admin", v.APIUsername)
+	})
+}
+
+func TestVariables_Validate(t *testing.T) {
+	valid := func() *Variables {
+		return &Variables{SyncInterval: "6h", LogLevel: "INFO", RunLogLevel: "off", RunLogMaxSize: "1M", StartupChecks: StartupChecksWarn}
+	}
+	require.NoError(t, valid().Validate())
+
+	v := valid()
+	v.SyncInterval = "6 hours"
+	v.RunLogLevel = "verbose"
+	v.StartupChecks = "yes"
+	err := v.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `invalid BG_SYNC_INTERVAL "6 hours"`)
+	assert.Contains(t, err.Error(), `invalid BG_RUN_LOG_LEVEL "verbose"`)
+	assert.Contains(t, err.Error(), `invalid BG_STARTUP_CHECKS "yes"`)
+	assert.NotContains(t, err.Error(), "BG_LOG_LEVEL")
+
+	v = valid()
+	v.LogLevel = "trace"
+	v.RunLogMaxSize = "0"
+	err = v.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `invalid BG_LOG_LEVEL "trace"`)
+	assert.Contains(t, err.Error(), `invalid BG_RUN_LOG_MAX_SIZE "0"`)
+}
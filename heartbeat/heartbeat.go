@@ -0,0 +1,218 @@
+// Package heartbeat pings external monitors (healthchecks.io, Uptime Kuma...) when runs start
+// and finish. The monitor alerts when the pings stop, which also covers a dead process or host.
+package heartbeat
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/eva01/backup-guardian/domain"
+)
+
+const (
+	defaultTimeout = 10 * time.Second
+	// queueSize bounds the pings waiting to be sent. Pings are dropped when it is full.
+	queueSize = 64
+	// maxBodySize bounds the summary of the run in the ping body, monitors only keep a short
+	// excerpt anyway.
+	maxBodySize = 2000
+	// maxLogTail bounds the end of the run log added to the body of failure pings, within the
+	// 10 kB healthchecks.io keeps.
+	maxLogTail = 8000
+)
+
+// Pinger sends heartbeat pings in the background, one at a time and in order, so that a slow
+// or unreachable monitor never delays a run.
+type Pinger struct {
+	client *http.Client
+	logger *slog.Logger
+
+	mu     sync.Mutex
+	closed bool
+	queue  chan *ping
+	done   chan struct{}
+}
+
+type ping struct {
+	job     string
+	event   string
+	url     string
+	body    string
+	timeout time.Duration
+}
+
+// Option configures the pinger.
+type Option func(*Pinger)
+
+// New creates a pinger and starts sending pings. Call Close to flush pending pings.
+func New(options ...Option) *Pinger {
+	p := &Pinger{
+		client: &http.Client{},
+		logger: slog.Default(),
+		queue:  make(chan *ping, queueSize),
+		done:   make(chan struct{}),
+	}
+
+	for _, opt := range options {
+		opt(p)
+	}
+
+	go p.send()
+
+	return p
+}
+
+// WithHTTPClient sets the HTTP client used to send pings.
+func WithHTTPClient(client *http.Client) Option {
+	return func(p *Pinger) { p.client = client }
+}
+
+// WithLogger sets the logger.
+func WithLogger(logger *slog.Logger) Option {
+	return func(p *Pinger) { p.logger = logger }
+}
+
+// Start pings the start URL of job for run.
+func (p *Pinger) Start(job *domain.SyncJob, run *domain.SyncRun) {
+	if job.Heartbeat == nil {
+		return
+	}
+
+	p.enqueue(job, "start", job.Heartbeat.StartURL, run, nil)
+}
+
+// Finish pings the fail URL of job when run failed, with the end of its log, its deferred URL
+// when run stopped at the end of its window, and its success URL otherwise.
+func (p *Pinger) Finish(job *domain.SyncJob, run *domain.SyncRun, log *domain.SyncRunLog) {
+	if job.Heartbeat == nil {
+		return
+	}
+
+	switch {
+	case domain.Failed(run.Status):
+		p.enqueue(job, "fail", job.Heartbeat.FailURL, run, log)
+	case run.Status == domain.StatusDeferred:
+		p.enqueue(job, "deferred", job.Heartbeat.DeferredURL, run, nil)
+	default:
+		p.enqueue(job, "success", job.Heartbeat.SuccessURL, run, nil)
+	}
+}
+
+// Close stops accepting pings and waits until pending ones are sent or ctx is done.
+func (p *Pinger) Close(ctx context.Context) error {
+	p.mu.Lock()
+	if !p.closed {
+		p.closed = true
+		close(p.queue)
+	}
+	p.mu.Unlock()
+
+	select {
+	case <-p.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (p *Pinger) enqueue(job *domain.SyncJob, event, url string, run *domain.SyncRun, log *domain.SyncRunLog) {
+	if url == "" {
+		return
+	}
+
+	timeout := job.Heartbeat.Timeout
+	if timeout == 0 {
+		timeout = defaultTimeout
+	}
+	pi := &ping{job: job.Name, event: event, url: url, body: body(run, log), timeout: timeout}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.closed {
+		return
+	}
+
+	select {
+	case p.queue <- pi:
+	default:
+		p.logger.Warn("Heartbeat queue full, dropping ping", slog.String("job", job.Name), slog.String("event", event))
+	}
+}
+
+func (p *Pinger) send() {
+	defer close(p.done)
+
+	for pi := range p.queue {
+		if err := p.post(pi); err != nil {
+			p.logger.Warn("Failed to send heartbeat ping", slog.String("job", pi.job),
+				slog.String("event", pi.event), slog.Any("error", err))
+		}
+	}
+}
+
+func (p *Pinger) post(pi *ping) error {
+	ctx, cancel := context.WithTimeout(context.Background(), pi.timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, pi.url, strings.NewReader(pi.body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "text/plain; charset=utf-8")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("monitor returned %s", resp.Status)
+	}
+
+	return nil
+}
+
+// body returns the ping body: the run ID, a short excerpt of its outcome and the end of log,
+// when set.
+func body(run *domain.SyncRun, log *domain.SyncRunLog) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "job: %s\nrun_id: %s\nstatus: %s\n", run.JobName, run.ID, run.Status)
+	if !run.FinishedAt.IsZero() {
+		fmt.Fprintf(&b, "duration: %s\nfiles: %d\nbytes: %d\n",
+			run.FinishedAt.Sub(run.StartedAt).Round(time.Second), run.FilesTransferred, run.BytesTransferred)
+	}
+	if run.ErrorMessage != "" {
+		fmt.Fprintf(&b, "error: %s\n", run.ErrorMessage)
+	}
+
+	s := b.String()
+	if len(s) > maxBodySize {
+		s = s[:maxBodySize-len("...\n")] + "...\n"
+	}
+	if log != nil && len(log.Content) > 0 {
+		s += "log:\n" + logTail(log.Content)
+	}
+
+	return s
+}
+
+// logTail returns the last lines of content, within maxLogTail bytes.
+func logTail(content []byte) string {
+	if len(content) <= maxLogTail {
+		return string(content)
+	}
+
+	tail := content[len(content)-maxLogTail+len("...\n"):]
+	if i := bytes.IndexByte(tail, '\n'); i >= 0 {
+		tail = tail[i+1:]
+	}
+
+	return "...\n" + string(tail)
+}
@@ -0,0 +1,140 @@
+package heartbeat_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/eva01/backup-guardian/domain"
+	"github.com/eva01/backup-guardian/heartbeat"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type monitor struct {
+	mu    sync.Mutex
+	pings []string
+	body  map[string]string
+}
+
+func newMonitor(t *testing.T) (*monitor, *httptest.Server) {
+	m := &monitor{body: map[string]string{}}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		m.pings = append(m.pings, r.URL.Path)
+		m.body[r.URL.Path] = string(body)
+	}))
+	t.Cleanup(server.Close)
+
+	return m, server
+}
+
+func TestPinger_Success(t *testing.T) {
+	m, server := newMonitor(t)
+	job := &domain.SyncJob{Name: "job", Heartbeat: domain.HeartbeatFromURL(server.URL + "/ping/abc")}
+	started := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	run := &domain.SyncRun{ID: "run-1", JobName: "job", Status: domain.StatusRunning, StartedAt: started}
+
+	p := heartbeat.New()
+	p.Start(job, run)
+	run.Status = domain.StatusSuccess
+	run.FinishedAt = started.Add(time.Minute)
+	run.FilesTransferred = 3
+	p.Finish(job, run, &domain.SyncRunLog{Content: []byte("level=INFO msg=\"Sync completed\"\n")})
+	require.NoError(t, p.Close(context.Background()))
+
+	assert.Equal(t, []string{"/ping/abc/start", "/ping/abc"}, m.pings)
+	assert.Contains(t, m.body["/ping/abc/start"], "run_id: run-1")
+	assert.Contains(t, m.body["/ping/abc"], "status: success")
+	assert.Contains(t, m.body["/ping/abc"], "files: 3")
+	assert.NotContains(t, m.body["/ping/abc"], "Sync completed")
+}
+
+func TestPinger_Deferred(t *testing.T) {
+	m, server := newMonitor(t)
+	job := &domain.SyncJob{Name: "job", Heartbeat: domain.HeartbeatFromURL(server.URL + "/ping/abc")}
+	run := &domain.SyncRun{ID: "run-1", JobName: "job", Status: domain.StatusDeferred}
+
+	p := heartbeat.New()
+	p.Finish(job, run, nil)
+	// Without a deferred URL, nothing is sent: a deferred run is no success.
+	p.Finish(&domain.SyncJob{Name: "kuma", Heartbeat: &domain.Heartbeat{SuccessURL: server.URL + "/up"}}, run, nil)
+	require.NoError(t, p.Close(context.Background()))
+
+	assert.Equal(t, []string{"/ping/abc/log"}, m.pings)
+	assert.Contains(t, m.body["/ping/abc/log"], "status: deferred")
+}
+
+func TestPinger_Fail(t *testing.T) {
+	m, server := newMonitor(t)
+	job := &domain.SyncJob{Name: "job", Heartbeat: &domain.Heartbeat{FailURL: server.URL + "/fail"}}
+	run := &domain.SyncRun{ID: "run-1", JobName: "job", Status: domain.StatusFailed, ErrorMessage: strings.Repeat("x", 5000)}
+
+	p := heartbeat.New()
+	// No start URL: nothing is sent.
+	p.Start(job, run)
+	var log bytes.Buffer
+	for i := range 500 {
+		fmt.Fprintf(&log, "level=INFO msg=\"Copied (new)\" object=file-%03d.txt\n", i)
+	}
+	log.WriteString("level=ERROR msg=\"Sync failed\"\n")
+	p.Finish(job, run, &domain.SyncRunLog{Content: log.Bytes()})
+	// Runs aborted by canaries are failures too.
+	canaryJob := &domain.SyncJob{Name: "canary", Heartbeat: &domain.Heartbeat{FailURL: server.URL + "/canary/fail"}}
+	p.Finish(canaryJob, &domain.SyncRun{ID: "run-2", JobName: "canary", Status: domain.StatusCanaryFailed}, nil)
+	require.NoError(t, p.Close(context.Background()))
+
+	assert.ElementsMatch(t, []string{"/fail", "/canary/fail"}, m.pings)
+	assert.Contains(t, m.body["/fail"], "run_id: run-1")
+	assert.Contains(t, m.body["/fail"], "error: xxx")
+	// The summary is cut, the log is kept at its end, on whole lines.
+	summary, tail, ok := strings.Cut(m.body["/fail"], "log:\n...\n")
+	require.True(t, ok)
+	assert.LessOrEqual(t, len(summary), 2000)
+	assert.LessOrEqual(t, len(tail), 8000)
+	assert.True(t, strings.HasPrefix(tail, "level=INFO"), tail[:40])
+	assert.True(t, strings.HasSuffix(tail, "level=ERROR msg=\"Sync failed\"\n"))
+}
+
+func TestPinger_DoesNotBlock(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	job := &domain.SyncJob{
+		Name:      "job",
+		Heartbeat: &domain.Heartbeat{StartURL: server.URL, SuccessURL: server.URL, Timeout: 10 * time.Millisecond},
+	}
+	run := &domain.SyncRun{ID: "run-1", JobName: "job", Status: domain.StatusSuccess}
+
+	p := heartbeat.New()
+	start := time.Now()
+	for range 100 {
+		p.Start(job, run)
+		p.Finish(job, run, nil)
+	}
+	assert.Less(t, time.Since(start), 50*time.Millisecond)
+
+	// Pings time out, and the queue is bounded, so that Close does not hang.
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	require.NoError(t, p.Close(ctx))
+
+	// Pings after Close are ignored.
+	p.Start(job, run)
+}
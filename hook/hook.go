@@ -0,0 +1,259 @@
+// Package hook runs the hooks of jobs: external commands or HTTP calls made before and after
+// each sync, described by the run they belong to.
+package hook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/eva01/backup-guardian/domain"
+)
+
+const (
+	defaultTimeout = 5 * time.Minute
+	// maxOutputSize bounds the output recorded for each hook.
+	maxOutputSize = 64 * 1024
+	// waitDelay bounds the wait for the output of a timed out command, whose children may
+	// keep it open.
+	waitDelay = 5 * time.Second
+)
+
+// inheritedEnv are the variables of the runner's environment passed to every command hook.
+// The others, which hold the credentials of the runner, are only passed when a hook lists them.
+var inheritedEnv = []string{"PATH", "HOME"}
+
+// Executor runs hooks.
+type Executor struct {
+	client *http.Client
+}
+
+// Option configures the executor.
+type Option func(*Executor)
+
+// New creates an executor.
+func New(options ...Option) *Executor {
+	e := &Executor{client: &http.Client{}}
+
+	for _, opt := range options {
+		opt(e)
+	}
+
+	return e
+}
+
+// WithHTTPClient sets the HTTP client used by URL hooks.
+func WithHTTPClient(client *http.Client) Option {
+	return func(e *Executor) { e.client = client }
+}
+
+// Run runs hook for run of job and returns its result. It fails when the command exits with
+// a non-zero status, the URL answers with a status of 300 or above, or the timeout expires.
+func (e *Executor) Run(ctx context.Context, hook *domain.Hook, job *domain.SyncJob, run *domain.SyncRun) *domain.HookResult {
+	timeout := hook.Timeout
+	if timeout == 0 {
+		timeout = defaultTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	started := time.Now()
+	output := &limitedBuffer{limit: maxOutputSize}
+
+	var err error
+	if len(hook.Command) > 0 {
+		err = e.runCommand(ctx, hook, job, run, output)
+	} else {
+		err = e.call(ctx, hook, job, run, output)
+	}
+	if ctx.Err() == context.DeadlineExceeded {
+		err = fmt.Errorf("timed out after %s", timeout)
+	}
+
+	result := &domain.HookResult{
+		Name:     hook.DisplayName(),
+		Event:    hook.Event,
+		Output:   output.String(),
+		Duration: time.Since(started),
+	}
+	if err != nil {
+		result.Error = err.Error()
+	}
+
+	return result
+}
+
+func (e *Executor) runCommand(ctx context.Context, hook *domain.Hook, job *domain.SyncJob, run *domain.SyncRun, output io.Writer) error {
+	cmd := exec.CommandContext(ctx, hook.Command[0], hook.Command[1:]...)
+	cmd.Env = append(commandEnv(hook), Environment(hook.Event, job, run)...)
+	cmd.Stdout = output
+	cmd.Stderr = output
+	cmd.WaitDelay = waitDelay
+
+	return cmd.Run()
+}
+
+func (e *Executor) call(ctx context.Context, hook *domain.Hook, job *domain.SyncJob, run *domain.SyncRun, output io.Writer) error {
+	method := hook.Method
+	if method == "" {
+		method = http.MethodPost
+	}
+
+	var body io.Reader
+	if method != http.MethodGet {
+		data, err := json.Marshal(newPayload(hook.Event, job, run))
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, hook.URL, body)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	for name, value := range hook.Headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	fmt.Fprintf(output, "HTTP %s\n", resp.Status)
+	_, _ = io.Copy(output, resp.Body)
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("%s returned %s", hook.DisplayName(), resp.Status)
+	}
+
+	return nil
+}
+
+// commandEnv returns the variables of the runner's environment passed to the command of hook.
+func commandEnv(hook *domain.Hook) []string {
+	var env []string
+	for _, name := range slices.Concat(inheritedEnv, hook.Env) {
+		if value, ok := os.LookupEnv(name); ok {
+			env = append(env, name+"="+value)
+		}
+	}
+
+	return env
+}
+
+// Environment returns the variables describing run of job to a command hook run at event.
+func Environment(event string, job *domain.SyncJob, run *domain.SyncRun) []string {
+	env := []string{
+		"BG_HOOK_EVENT=" + event,
+		"BG_JOB=" + job.Name,
+		"BG_SOURCE=" + job.Source,
+		"BG_DESTINATION=" + job.AllDestinations()[0],
+		"BG_DESTINATIONS=" + strings.Join(job.AllDestinations(), "\n"),
+		"BG_RUN_ID=" + run.ID,
+		"BG_RUN_STATUS=" + run.Status,
+		"BG_RUN_STARTED_AT=" + formatTime(run.StartedAt),
+		"BG_RUN_FINISHED_AT=" + formatTime(run.FinishedAt),
+		"BG_RUN_ERROR=" + run.ErrorMessage,
+		"BG_FILES_TRANSFERRED=" + strconv.FormatInt(run.FilesTransferred, 10),
+		"BG_BYTES_TRANSFERRED=" + strconv.FormatInt(run.BytesTransferred, 10),
+		"BG_FILES_TOTAL=" + strconv.FormatInt(run.FilesTotal, 10),
+		"BG_BYTES_TOTAL=" + strconv.FormatInt(run.BytesTotal, 10),
+	}
+	if !run.FinishedAt.IsZero() {
+		env = append(env, "BG_RUN_DURATION="+strconv.FormatInt(int64(run.FinishedAt.Sub(run.StartedAt).Seconds()), 10))
+	}
+
+	return env
+}
+
+// payload is the JSON body sent to URL hooks.
+type payload struct {
+	Event            string     `json:"event"`
+	Job              string     `json:"job"`
+	Source           string     `json:"source"`
+	Destination      string     `json:"destination"`
+	Destinations     []string   `json:"destinations"`
+	RunID            string     `json:"run_id"`
+	Status           string     `json:"status"`
+	StartedAt        time.Time  `json:"started_at"`
+	FinishedAt       *time.Time `json:"finished_at,omitempty"`
+	Error            string     `json:"error,omitempty"`
+	FilesTransferred int64      `json:"files_transferred"`
+	BytesTransferred int64      `json:"bytes_transferred"`
+	FilesTotal       int64      `json:"files_total"`
+	BytesTotal       int64      `json:"bytes_total"`
+	// DestinationResults are the outcomes of the destinations of a fan-out run.
+	DestinationResults []domain.DestinationResult `json:"destination_results,omitempty"`
+}
+
+func newPayload(event string, job *domain.SyncJob, run *domain.SyncRun) *payload {
+	p := &payload{
+		Event:            event,
+		Job:              job.Name,
+		Source:           job.Source,
+		Destination:      job.AllDestinations()[0],
+		Destinations:     job.AllDestinations(),
+		RunID:            run.ID,
+		Status:           run.Status,
+		StartedAt:        run.StartedAt,
+		Error:            run.ErrorMessage,
+		FilesTransferred: run.FilesTransferred,
+		BytesTransferred: run.BytesTransferred,
+		FilesTotal:       run.FilesTotal,
+		BytesTotal:       run.BytesTotal,
+	}
+	p.DestinationResults = run.Destinations
+	if !run.FinishedAt.IsZero() {
+		p.FinishedAt = &run.FinishedAt
+	}
+
+	return p
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+
+	return t.UTC().Format(time.RFC3339)
+}
+
+// limitedBuffer keeps the first limit bytes written to it and notes that the rest was dropped.
+type limitedBuffer struct {
+	buf       bytes.Buffer
+	limit     int
+	truncated bool
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if room := b.limit - b.buf.Len(); room < len(p) {
+		b.truncated = true
+		b.buf.Write(p[:max(room, 0)])
+		return len(p), nil
+	}
+
+	return b.buf.Write(p)
+}
+
+func (b *limitedBuffer) String() string {
+	if b.truncated {
+		return b.buf.String() + fmt.Sprintf("\n... output truncated at %d bytes\n", b.limit)
+	}
+
+	return b.buf.String()
+}
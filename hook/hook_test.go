@@ -0,0 +1,130 @@
+package hook_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/eva01/backup-guardian/domain"
+	"github.com/eva01/backup-guardian/hook"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	testJob = &domain.SyncJob{Name: "test-job", Source: "src:", Destination: "dst:"}
+	testRun = &domain.SyncRun{
+		ID:               "run-1",
+		JobName:          "test-job",
+		Status:           domain.StatusSuccess,
+		StartedAt:        time.Date(2026, 1, 2, 3, 0, 0, 0, time.UTC),
+		FinishedAt:       time.Date(2026, 1, 2, 3, 1, 30, 0, time.UTC),
+		FilesTransferred: 3,
+		BytesTransferred: 1024,
+	}
+)
+
+func TestExecutor_Command(t *testing.T) {
+	e := hook.New()
+
+	t.Run("success", func(t *testing.T) {
+		h := &domain.Hook{Event: domain.HookAlways, Command: []string{"sh", "-c", `echo "$BG_JOB $BG_RUN_STATUS $BG_FILES_TRANSFERRED $BG_RUN_DURATION"; echo oops >&2`}}
+
+		result := e.Run(context.Background(), h, testJob, testRun)
+		assert.False(t, result.Failed(), result.Error)
+		assert.Equal(t, "sh", result.Name)
+		assert.Equal(t, domain.HookAlways, result.Event)
+		assert.Equal(t, "test-job success 3 90\noops\n", result.Output)
+	})
+
+	t.Run("environment", func(t *testing.T) {
+		t.Setenv("BG_API_PASSWORD", "secret")
+		t.Setenv("PGPASSWORD", "db-secret")
+		h := &domain.Hook{Event: domain.HookPreSync, Command: []string{"sh", "-c", `echo "[$BG_API_PASSWORD] [$PGPASSWORD] [$BG_JOB]"`},
+			Env: []string{"PGPASSWORD", "UNSET_VARIABLE"}}
+
+		result := e.Run(context.Background(), h, testJob, testRun)
+		assert.False(t, result.Failed(), result.Error)
+		assert.Equal(t, "[] [db-secret] [test-job]\n", result.Output)
+	})
+
+	t.Run("failure", func(t *testing.T) {
+		h := &domain.Hook{Name: "fails", Event: domain.HookPreSync, Command: []string{"sh", "-c", "echo no; exit 3"}}
+
+		result := e.Run(context.Background(), h, testJob, testRun)
+		assert.True(t, result.Failed())
+		assert.Equal(t, "exit status 3", result.Error)
+		assert.Equal(t, "no\n", result.Output)
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		h := &domain.Hook{Event: domain.HookPreSync, Command: []string{"sleep", "10"}, Timeout: 50 * time.Millisecond}
+
+		result := e.Run(context.Background(), h, testJob, testRun)
+		assert.Equal(t, "timed out after 50ms", result.Error)
+		assert.Less(t, result.Duration, 5*time.Second)
+	})
+
+	t.Run("missing program", func(t *testing.T) {
+		h := &domain.Hook{Event: domain.HookPreSync, Command: []string{"/nonexistent/hook"}}
+
+		result := e.Run(context.Background(), h, testJob, testRun)
+		assert.True(t, result.Failed())
+	})
+
+	t.Run("large output", func(t *testing.T) {
+		h := &domain.Hook{Event: domain.HookAlways, Command: []string{"sh", "-c", "head -c 100000 /dev/zero"}}
+
+		result := e.Run(context.Background(), h, testJob, testRun)
+		assert.False(t, result.Failed(), result.Error)
+		assert.Less(t, len(result.Output), 70000)
+		assert.True(t, strings.HasSuffix(result.Output, "... output truncated at 65536 bytes\n"))
+	})
+}
+
+func TestExecutor_URL(t *testing.T) {
+	var method, auth string
+	var body map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method, auth = r.Method, r.Header.Get("Authorization")
+		body = nil
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		if r.URL.Path == "/fail" {
+			http.Error(w, "nope", http.StatusInternalServerError)
+			return
+		}
+		_, _ = w.Write([]byte("recorded"))
+	}))
+	t.Cleanup(server.Close)
+
+	e := hook.New(hook.WithHTTPClient(server.Client()))
+
+	t.Run("success", func(t *testing.T) {
+		h := &domain.Hook{Event: domain.HookPostSuccess, URL: server.URL + "/ok", Headers: map[string]string{"Authorization": "Bearer x"}}
+
+		result := e.Run(context.Background(), h, testJob, testRun)
+		assert.False(t, result.Failed(), result.Error)
+		assert.Equal(t, "HTTP 200 OK\nrecorded", result.Output)
+		assert.Equal(t, strings.TrimPrefix(server.URL, "http://"), result.Name)
+		assert.Equal(t, http.MethodPost, method)
+		assert.Equal(t, "Bearer x", auth)
+		require.NotNil(t, body)
+		assert.Equal(t, "post_success", body["event"])
+		assert.Equal(t, "run-1", body["run_id"])
+		assert.Equal(t, float64(1024), body["bytes_transferred"])
+	})
+
+	t.Run("error status", func(t *testing.T) {
+		h := &domain.Hook{Event: domain.HookPostFailure, URL: server.URL + "/fail"}
+
+		result := e.Run(context.Background(), h, testJob, testRun)
+		assert.True(t, result.Failed())
+		assert.Contains(t, result.Error, "500 Internal Server Error")
+		assert.NotContains(t, result.Error, "/fail")
+		assert.Contains(t, result.Output, "nope")
+	})
+}
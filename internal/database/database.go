@@ -0,0 +1,63 @@
+// Package database opens the SQLite database shared by the runner and the CLI.
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/eva01/backup-guardian/migrations"
+	"github.com/pressly/goose/v3"
+	_ "modernc.org/sqlite"
+)
+
+// busyTimeout lets the runner and the CLI wait for each other's write locks instead of failing.
+const busyTimeout = "?_pragma=busy_timeout(5000)"
+
+// Open opens the SQLite database at path, creating its directory if needed,
+// and applies pending migrations.
+func Open(path string) (*sql.DB, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("could not create data directory: %w", err)
+	}
+
+	db, err := sql.Open("sqlite", path+busyTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("could not open database: %w", err)
+	}
+
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("could not ping database: %w", err)
+	}
+
+	goose.SetBaseFS(migrations.FS)
+	if err := goose.SetDialect("sqlite3"); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("could not set dialect: %w", err)
+	}
+	if err := goose.Up(db, "."); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("migration failed: %w", err)
+	}
+
+	return db, nil
+}
+
+// CheckWritable checks that the database at db can be written, with a write that is rolled
+// back.
+func CheckWritable(db *sql.DB) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("CREATE TABLE writable_check (id INTEGER)"); err != nil {
+		return err
+	}
+	_, err = tx.Exec("INSERT INTO writable_check (id) VALUES (1)")
+
+	return err
+}
@@ -0,0 +1,164 @@
+// Package metrics exposes backup-guardian state as Prometheus metrics.
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/eva01/backup-guardian/domain"
+	"github.com/eva01/backup-guardian/service"
+)
+
+const namespace = "backup_guardian"
+
+// StatusLister lists job statuses, and tells the outcome of the last reload of the
+// configuration. Implemented by service.Service.
+type StatusLister interface {
+	JobStatuses() ([]*service.JobStatus, error)
+	LastReload() *domain.ConfigReload
+}
+
+// Collector reads job statuses at scrape time, so metrics reflect the persisted
+// state even when it was changed by the CLI.
+type Collector struct {
+	statuses StatusLister
+
+	jobPaused        *prometheus.Desc
+	jobPauseInfo     *prometheus.Desc
+	jobPauseResumeAt *prometheus.Desc
+	jobLastRunAt     *prometheus.Desc
+	jobNextDueAt     *prometheus.Desc
+	jobRPO           *prometheus.Desc
+	jobRPOViolated   *prometheus.Desc
+	jobLastSuccessAt *prometheus.Desc
+
+	configReloadSuccessful *prometheus.Desc
+	configReloadAt         *prometheus.Desc
+}
+
+var _ prometheus.Collector = (*Collector)(nil)
+
+// NewCollector creates a collector reading from statuses.
+func NewCollector(statuses StatusLister) *Collector {
+	return &Collector{
+		statuses: statuses,
+		jobPaused: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "job", "paused"),
+			"Whether the job is paused (1) or not (0), either on its own or by a runner-wide pause.",
+			[]string{"job"}, nil,
+		),
+		jobPauseInfo: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "job", "pause_info"),
+			"Scope and reason of the pause in effect for a paused job. Always 1.",
+			[]string{"job", "scope", "reason"}, nil,
+		),
+		jobPauseResumeAt: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "job", "pause_resume_timestamp_seconds"),
+			"Auto-resume time of the pause in effect for a paused job, when one is set.",
+			[]string{"job"}, nil,
+		),
+		jobLastRunAt: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "job", "last_run_timestamp_seconds"),
+			"Start time of the last scheduled run of the job.",
+			[]string{"job"}, nil,
+		),
+		jobNextDueAt: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "job", "next_due_timestamp_seconds"),
+			"Time at which the job is next due. In the past while missed runs are pending.",
+			[]string{"job"}, nil,
+		),
+		jobRPO: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "job", "rpo_seconds"),
+			"Recovery point objective of the job: maximum age of its last successful run.",
+			[]string{"job"}, nil,
+		),
+		jobRPOViolated: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "job", "rpo_violated"),
+			"Whether the job has no successful run within its RPO (1) or not (0).",
+			[]string{"job"}, nil,
+		),
+		jobLastSuccessAt: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "job", "last_success_timestamp_seconds"),
+			"Start time of the last successful run of a job with an RPO.",
+			[]string{"job"}, nil,
+		),
+		configReloadSuccessful: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "config", "last_reload_successful"),
+			"Whether the last reload of the configuration succeeded (1) or failed (0). Absent until a reload.",
+			nil, nil,
+		),
+		configReloadAt: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "config", "last_reload_timestamp_seconds"),
+			"Time of the last reload of the configuration, successful or not.",
+			nil, nil,
+		),
+	}
+}
+
+// Describe implements prometheus.Collector.
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.jobPaused
+	ch <- c.jobPauseInfo
+	ch <- c.jobPauseResumeAt
+	ch <- c.jobLastRunAt
+	ch <- c.jobNextDueAt
+	ch <- c.jobRPO
+	ch <- c.jobRPOViolated
+	ch <- c.jobLastSuccessAt
+	ch <- c.configReloadSuccessful
+	ch <- c.configReloadAt
+}
+
+// Collect implements prometheus.Collector.
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	if reload := c.statuses.LastReload(); reload != nil {
+		successful := 1.0
+		if reload.Status == domain.ReloadFailed {
+			successful = 0
+		}
+		ch <- prometheus.MustNewConstMetric(c.configReloadSuccessful, prometheus.GaugeValue, successful)
+		ch <- prometheus.MustNewConstMetric(c.configReloadAt, prometheus.GaugeValue, float64(reload.ReloadedAt.Unix()))
+	}
+
+	statuses, err := c.statuses.JobStatuses()
+	if err != nil {
+		ch <- prometheus.NewInvalidMetric(c.jobPaused, err)
+		return
+	}
+
+	for _, status := range statuses {
+		job := status.Job.Name
+
+		paused := 0.0
+		if status.Paused() {
+			paused = 1
+		}
+		ch <- prometheus.MustNewConstMetric(c.jobPaused, prometheus.GaugeValue, paused, job)
+
+		if status.Schedule != nil {
+			if !status.Schedule.LastRunAt.IsZero() {
+				ch <- prometheus.MustNewConstMetric(c.jobLastRunAt, prometheus.GaugeValue, float64(status.Schedule.LastRunAt.Unix()), job)
+			}
+			ch <- prometheus.MustNewConstMetric(c.jobNextDueAt, prometheus.GaugeValue, float64(status.Schedule.NextDueAt.Unix()), job)
+		}
+
+		if status.RPO != nil {
+			violated := 0.0
+			if status.RPO.Violated {
+				violated = 1
+			}
+			ch <- prometheus.MustNewConstMetric(c.jobRPO, prometheus.GaugeValue, status.RPO.RPO.Seconds(), job)
+			ch <- prometheus.MustNewConstMetric(c.jobRPOViolated, prometheus.GaugeValue, violated, job)
+			if !status.RPO.LastSuccessAt.IsZero() {
+				ch <- prometheus.MustNewConstMetric(c.jobLastSuccessAt, prometheus.GaugeValue, float64(status.RPO.LastSuccessAt.Unix()), job)
+			}
+		}
+
+		if !status.Paused() {
+			continue
+		}
+		ch <- prometheus.MustNewConstMetric(c.jobPauseInfo, prometheus.GaugeValue, 1, job, status.Pause.Scope, status.Pause.Reason)
+		if !status.Pause.ResumeAt.IsZero() {
+			ch <- prometheus.MustNewConstMetric(c.jobPauseResumeAt, prometheus.GaugeValue, float64(status.Pause.ResumeAt.Unix()), job)
+		}
+	}
+}
@@ -0,0 +1,10 @@
+-- +goose Up
+CREATE TABLE job_pauses (
+    scope TEXT PRIMARY KEY,
+    reason TEXT,
+    paused_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    resume_at DATETIME
+);
+
+-- +goose Down
+DROP TABLE job_pauses;
@@ -0,0 +1,10 @@
+-- +goose Up
+CREATE TABLE job_schedules (
+    job_name TEXT PRIMARY KEY,
+    last_run_at DATETIME,
+    next_due_at DATETIME NOT NULL,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- +goose Down
+DROP TABLE job_schedules;
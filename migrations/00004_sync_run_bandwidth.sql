@@ -0,0 +1,7 @@
+-- +goose Up
+ALTER TABLE sync_runs ADD COLUMN upload_limit INTEGER;
+ALTER TABLE sync_runs ADD COLUMN download_limit INTEGER;
+
+-- +goose Down
+ALTER TABLE sync_runs DROP COLUMN download_limit;
+ALTER TABLE sync_runs DROP COLUMN upload_limit;
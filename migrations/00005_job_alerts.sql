@@ -0,0 +1,11 @@
+-- +goose Up
+CREATE TABLE job_alerts (
+    job_name TEXT NOT NULL,
+    kind TEXT NOT NULL,
+    message TEXT,
+    raised_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (job_name, kind)
+);
+
+-- +goose Down
+DROP TABLE job_alerts;
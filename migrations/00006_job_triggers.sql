@@ -0,0 +1,8 @@
+-- +goose Up
+CREATE TABLE job_triggers (
+    job_name TEXT PRIMARY KEY,
+    requested_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- +goose Down
+DROP TABLE job_triggers;
@@ -0,0 +1,7 @@
+-- +goose Up
+ALTER TABLE sync_runs ADD COLUMN files_total INTEGER;
+ALTER TABLE sync_runs ADD COLUMN bytes_total INTEGER;
+
+-- +goose Down
+ALTER TABLE sync_runs DROP COLUMN bytes_total;
+ALTER TABLE sync_runs DROP COLUMN files_total;
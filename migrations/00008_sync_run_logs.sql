@@ -0,0 +1,12 @@
+-- +goose Up
+CREATE TABLE sync_run_logs (
+    run_id TEXT PRIMARY KEY REFERENCES sync_runs (id) ON DELETE CASCADE,
+    -- content is the gzip-compressed log text.
+    content BLOB NOT NULL,
+    size INTEGER NOT NULL,
+    truncated BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- +goose Down
+DROP TABLE sync_run_logs;
@@ -0,0 +1,5 @@
+-- +goose Up
+ALTER TABLE sync_runs ADD COLUMN hook_results TEXT;
+
+-- +goose Down
+ALTER TABLE sync_runs DROP COLUMN hook_results;
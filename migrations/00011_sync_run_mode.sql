@@ -0,0 +1,5 @@
+-- +goose Up
+ALTER TABLE sync_runs ADD COLUMN mode TEXT NOT NULL DEFAULT 'sync';
+
+-- +goose Down
+ALTER TABLE sync_runs DROP COLUMN mode;
@@ -0,0 +1,5 @@
+-- +goose Up
+ALTER TABLE sync_runs ADD COLUMN destinations TEXT;
+
+-- +goose Down
+ALTER TABLE sync_runs DROP COLUMN destinations;
@@ -0,0 +1,9 @@
+-- +goose Up
+ALTER TABLE sync_runs ADD COLUMN pipeline_id TEXT;
+CREATE INDEX idx_sync_runs_pipeline_id ON sync_runs (pipeline_id);
+ALTER TABLE job_triggers ADD COLUMN pipeline_id TEXT;
+
+-- +goose Down
+ALTER TABLE job_triggers DROP COLUMN pipeline_id;
+DROP INDEX idx_sync_runs_pipeline_id;
+ALTER TABLE sync_runs DROP COLUMN pipeline_id;
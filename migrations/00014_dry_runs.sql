@@ -0,0 +1,7 @@
+-- +goose Up
+ALTER TABLE sync_runs ADD COLUMN kind TEXT NOT NULL DEFAULT 'sync';
+ALTER TABLE sync_runs ADD COLUMN plan TEXT;
+
+-- +goose Down
+ALTER TABLE sync_runs DROP COLUMN plan;
+ALTER TABLE sync_runs DROP COLUMN kind;
@@ -0,0 +1,13 @@
+-- +goose Up
+CREATE TABLE remotes (
+    name TEXT PRIMARY KEY,
+    type TEXT NOT NULL,
+    parameters TEXT NOT NULL DEFAULT '{}',
+    secret_files TEXT NOT NULL DEFAULT '{}',
+    secret_env TEXT NOT NULL DEFAULT '{}',
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- +goose Down
+DROP TABLE remotes;
@@ -0,0 +1,5 @@
+-- +goose Up
+ALTER TABLE sync_runs ADD COLUMN destination_usage TEXT;
+
+-- +goose Down
+ALTER TABLE sync_runs DROP COLUMN destination_usage;
@@ -0,0 +1,17 @@
+-- +goose Up
+ALTER TABLE sync_runs ADD COLUMN change_stats TEXT;
+ALTER TABLE sync_runs ADD COLUMN anomaly TEXT;
+
+CREATE TABLE job_anomalies (
+    job_name TEXT PRIMARY KEY,
+    run_id TEXT NOT NULL,
+    action TEXT NOT NULL,
+    -- reasons is the JSON array of the deviations of the run.
+    reasons TEXT,
+    detected_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- +goose Down
+DROP TABLE job_anomalies;
+ALTER TABLE sync_runs DROP COLUMN anomaly;
+ALTER TABLE sync_runs DROP COLUMN change_stats;
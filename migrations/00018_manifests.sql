@@ -0,0 +1,19 @@
+-- +goose Up
+CREATE TABLE manifests (
+    run_id TEXT PRIMARY KEY,
+    job_name TEXT NOT NULL,
+    sequence INTEGER NOT NULL,
+    previous_hash TEXT NOT NULL,
+    hash TEXT NOT NULL,
+    files INTEGER NOT NULL,
+    bytes INTEGER NOT NULL,
+    -- destinations is the JSON array of the destinations, without their entries.
+    destinations TEXT NOT NULL,
+    -- content is the gzip-compressed JSON of the manifest, as hashed.
+    content BLOB NOT NULL,
+    created_at DATETIME NOT NULL,
+    UNIQUE (job_name, sequence)
+);
+
+-- +goose Down
+DROP TABLE manifests;
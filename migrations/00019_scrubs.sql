@@ -0,0 +1,18 @@
+-- +goose Up
+ALTER TABLE sync_runs ADD COLUMN scrub TEXT;
+
+CREATE TABLE scrub_states (
+    job_name TEXT NOT NULL,
+    destination TEXT NOT NULL,
+    rotation INTEGER NOT NULL,
+    rotation_started_at DATETIME NOT NULL,
+    -- checked is the gzip-compressed JSON object of the paths checked in the rotation, with
+    -- when they were checked.
+    checked BLOB NOT NULL,
+    updated_at DATETIME NOT NULL,
+    PRIMARY KEY (job_name, destination)
+);
+
+-- +goose Down
+DROP TABLE scrub_states;
+ALTER TABLE sync_runs DROP COLUMN scrub;
@@ -0,0 +1,36 @@
+package notify
+
+import (
+	"context"
+	"log/slog"
+)
+
+// LogNotifier writes notifications to a logger.
+type LogNotifier struct {
+	logger *slog.Logger
+}
+
+var _ Notifier = (*LogNotifier)(nil)
+
+// NewLogNotifier creates a notifier writing to logger.
+func NewLogNotifier(logger *slog.Logger) *LogNotifier {
+	return &LogNotifier{logger: logger}
+}
+
+// Notify implements Notifier.
+func (l *LogNotifier) Notify(ctx context.Context, n *Notification) error {
+	level := slog.LevelInfo
+	switch n.Severity {
+	case SeverityWarning:
+		level = slog.LevelWarn
+	case SeverityCritical:
+		level = slog.LevelError
+	}
+
+	l.logger.Log(ctx, level, n.Title,
+		slog.String("event", n.Event),
+		slog.String("job", n.JobName),
+		slog.String("message", n.Message))
+
+	return nil
+}
@@ -0,0 +1,63 @@
+// Package notify delivers notifications about backup events to operators.
+package notify
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// Severities.
+const (
+	SeverityInfo     = "info"
+	SeverityWarning  = "warning"
+	SeverityCritical = "critical"
+)
+
+// Events.
+const (
+	// EventRPOViolated is sent when a job has no successful run within its RPO.
+	EventRPOViolated = "rpo_violated"
+	// EventRPORecovered is sent when a job violating its RPO succeeds again.
+	EventRPORecovered = "rpo_recovered"
+	// EventRunAnomalous is sent when a run deviates strongly from the previous runs of its job.
+	EventRunAnomalous = "run_anomalous"
+	// EventCanaryFailed is sent when a run is aborted because canary files of its source are
+	// missing or altered.
+	EventCanaryFailed = "canary_failed"
+	// EventScrubMismatch is sent when a scrub finds files of destinations whose content differs
+	// from the source or the manifest, or that cannot be downloaded.
+	EventScrubMismatch = "scrub_mismatch"
+)
+
+// Notification is a message about an event of a job.
+type Notification struct {
+	Event    string    `json:"event"`
+	Severity string    `json:"severity"`
+	JobName  string    `json:"job,omitempty"`
+	Title    string    `json:"title"`
+	Message  string    `json:"message"`
+	Time     time.Time `json:"time"`
+}
+
+// Notifier delivers notifications.
+type Notifier interface {
+	Notify(ctx context.Context, n *Notification) error
+}
+
+// Multi delivers notifications to every notifier, even when some of them fail.
+type Multi []Notifier
+
+var _ Notifier = Multi(nil)
+
+// Notify implements Notifier.
+func (m Multi) Notify(ctx context.Context, n *Notification) error {
+	var errs []error
+	for _, notifier := range m {
+		if err := notifier.Notify(ctx, n); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
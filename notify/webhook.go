@@ -0,0 +1,68 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+const defaultWebhookTimeout = 10 * time.Second
+
+// WebhookNotifier posts notifications as JSON to a URL.
+type WebhookNotifier struct {
+	url    string
+	client *http.Client
+}
+
+var _ Notifier = (*WebhookNotifier)(nil)
+
+// WebhookOption configures a WebhookNotifier.
+type WebhookOption func(*WebhookNotifier)
+
+// NewWebhookNotifier creates a notifier posting to url.
+func NewWebhookNotifier(url string, options ...WebhookOption) *WebhookNotifier {
+	w := &WebhookNotifier{
+		url:    url,
+		client: &http.Client{Timeout: defaultWebhookTimeout},
+	}
+
+	for _, opt := range options {
+		opt(w)
+	}
+
+	return w
+}
+
+// WithHTTPClient sets the HTTP client used to post notifications.
+func WithHTTPClient(client *http.Client) WebhookOption {
+	return func(w *WebhookNotifier) { w.client = client }
+}
+
+// Notify implements Notifier.
+func (w *WebhookNotifier) Notify(ctx context.Context, n *Notification) error {
+	body, err := json.Marshal(n)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("could not post notification: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("could not post notification: webhook returned %s", resp.Status)
+	}
+
+	return nil
+}
@@ -0,0 +1,49 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookNotifier_Notify(t *testing.T) {
+	received := make(chan *Notification, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+
+		var n Notification
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&n))
+		received <- &n
+	}))
+	defer server.Close()
+
+	n := &Notification{
+		Event:    EventRPOViolated,
+		Severity: SeverityCritical,
+		JobName:  "job",
+		Title:    "Job job violates its RPO",
+		Message:  "No successful run recorded",
+		Time:     time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+
+	require.NoError(t, NewWebhookNotifier(server.URL).Notify(context.Background(), n))
+	assert.Equal(t, n, <-received)
+}
+
+func TestWebhookNotifier_Notify_Error(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	err := NewWebhookNotifier(server.URL).Notify(context.Background(), &Notification{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "500")
+}
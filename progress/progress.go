@@ -0,0 +1,93 @@
+// Package progress tracks the live progress of running syncs and broadcasts it to subscribers,
+// e.g. the progress stream of the HTTP API.
+package progress
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/eva01/backup-guardian/domain"
+)
+
+// subscriberBuffer is the number of snapshots buffered per subscriber. Snapshots are dropped
+// for subscribers that fall behind, so that a slow client never blocks a sync.
+const subscriberBuffer = 16
+
+// Tracker holds the latest progress of each running sync.
+type Tracker struct {
+	mu          sync.Mutex
+	runs        map[string]*domain.Progress
+	subscribers map[chan *domain.Progress]struct{}
+}
+
+// NewTracker creates a tracker.
+func NewTracker() *Tracker {
+	return &Tracker{
+		runs:        map[string]*domain.Progress{},
+		subscribers: map[chan *domain.Progress]struct{}{},
+	}
+}
+
+// Update records progress as the latest snapshot of its run and broadcasts it.
+func (t *Tracker) Update(progress *domain.Progress) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.runs[progress.RunID] = progress
+	t.broadcast(progress)
+}
+
+// Done records the end of the run of progress, and broadcasts progress as its last snapshot.
+func (t *Tracker) Done(progress *domain.Progress) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	delete(t.runs, progress.RunID)
+	last := *progress
+	last.Done = true
+	t.broadcast(&last)
+}
+
+// List returns the latest snapshot of every running sync, oldest run first.
+func (t *Tracker) List() []*domain.Progress {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	result := make([]*domain.Progress, 0, len(t.runs))
+	for _, progress := range t.runs {
+		result = append(result, progress)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].StartedAt.Before(result[j].StartedAt) })
+
+	return result
+}
+
+// Subscribe returns a channel receiving every snapshot from now on, and a function to call
+// to unsubscribe. The channel is closed on unsubscribe.
+func (t *Tracker) Subscribe() (<-chan *domain.Progress, func()) {
+	ch := make(chan *domain.Progress, subscriberBuffer)
+
+	t.mu.Lock()
+	t.subscribers[ch] = struct{}{}
+	t.mu.Unlock()
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			t.mu.Lock()
+			delete(t.subscribers, ch)
+			t.mu.Unlock()
+			close(ch)
+		})
+	}
+}
+
+// broadcast sends progress to subscribers that are not behind. Call with the lock held.
+func (t *Tracker) broadcast(progress *domain.Progress) {
+	for ch := range t.subscribers {
+		select {
+		case ch <- progress:
+		default:
+		}
+	}
+}
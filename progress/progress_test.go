@@ -0,0 +1,52 @@
+package progress
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/eva01/backup-guardian/domain"
+)
+
+func TestTracker(t *testing.T) {
+	tracker := NewTracker()
+	ch, unsubscribe := tracker.Subscribe()
+
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	tracker.Update(&domain.Progress{RunID: "run-2", StartedAt: start.Add(time.Minute), BytesDone: 1})
+	tracker.Update(&domain.Progress{RunID: "run-1", StartedAt: start, BytesDone: 2})
+
+	list := tracker.List()
+	require.Len(t, list, 2)
+	assert.Equal(t, "run-1", list[0].RunID)
+
+	tracker.Done(&domain.Progress{RunID: "run-1", BytesDone: 3})
+	require.Len(t, tracker.List(), 1)
+
+	assert.Equal(t, "run-2", (<-ch).RunID)
+	assert.Equal(t, "run-1", (<-ch).RunID)
+	last := <-ch
+	assert.True(t, last.Done)
+	assert.Equal(t, int64(3), last.BytesDone)
+
+	unsubscribe()
+	unsubscribe()
+	_, open := <-ch
+	assert.False(t, open)
+}
+
+func TestTracker_SlowSubscriber(t *testing.T) {
+	tracker := NewTracker()
+	ch, unsubscribe := tracker.Subscribe()
+	defer unsubscribe()
+
+	// A subscriber that does not read never blocks updates.
+	for i := 0; i < 2*subscriberBuffer; i++ {
+		tracker.Update(&domain.Progress{RunID: "run", BytesDone: int64(i)})
+	}
+
+	assert.Len(t, ch, subscriberBuffer)
+	assert.Equal(t, int64(2*subscriberBuffer-1), tracker.List()[0].BytesDone)
+}
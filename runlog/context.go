@@ -0,0 +1,79 @@
+package runlog
+
+import (
+	"context"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+// rclonePackage prefixes the functions of rclone.
+const rclonePackage = "github.com/rclone/rclone/"
+
+type runKey struct{}
+
+// rclone operations in progress, by run ID ("" for operations outside runs).
+var (
+	rcloneMu  sync.Mutex
+	rcloneOps = map[string]int{}
+)
+
+// WithRun returns a copy of ctx whose records are captured in the log of the run runID.
+func WithRun(ctx context.Context, runID string) context.Context {
+	return context.WithValue(ctx, runKey{}, runID)
+}
+
+// RunID returns the run set on ctx with WithRun, or "".
+func RunID(ctx context.Context) string {
+	runID, _ := ctx.Value(runKey{}).(string)
+	return runID
+}
+
+// StartRclone records the start of an rclone operation run with ctx, and returns the function
+// to call once it is done. rclone logs without context, so its records are only captured while
+// the operations in progress all belong to the same run.
+func StartRclone(ctx context.Context) (done func()) {
+	runID := RunID(ctx)
+
+	rcloneMu.Lock()
+	rcloneOps[runID]++
+	rcloneMu.Unlock()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			rcloneMu.Lock()
+			defer rcloneMu.Unlock()
+
+			if rcloneOps[runID]--; rcloneOps[runID] == 0 {
+				delete(rcloneOps, runID)
+			}
+		})
+	}
+}
+
+// rcloneRun returns the run of the rclone operations in progress, or "" when there are none,
+// some are outside runs or they belong to several runs.
+func rcloneRun() string {
+	rcloneMu.Lock()
+	defer rcloneMu.Unlock()
+
+	if len(rcloneOps) != 1 {
+		return ""
+	}
+	for runID := range rcloneOps {
+		return runID
+	}
+
+	return ""
+}
+
+// fromRclone reports whether the record logged at pc was logged by rclone.
+func fromRclone(pc uintptr) bool {
+	if pc == 0 {
+		return false
+	}
+	frame, _ := runtime.CallersFrames([]uintptr{pc}).Next()
+
+	return strings.HasPrefix(frame.Function, rclonePackage)
+}
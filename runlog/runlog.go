@@ -0,0 +1,229 @@
+// Package runlog captures the log records emitted for sync runs, so that they can be stored
+// with each run.
+package runlog
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sync"
+
+	"github.com/eva01/backup-guardian/domain"
+)
+
+const (
+	defaultLevel   = slog.LevelInfo
+	defaultMaxSize = 1 << 20
+	// runIDKey is the attribute naming the run of a record.
+	runIDKey = "run_id"
+)
+
+// Recorder captures the records of a slog handler emitted for runs, between the Start and Stop
+// of each run. Records are captured whatever the level of the wrapped handler, down to the
+// recorder level.
+//
+// A record belongs to the run set on its context with WithRun, else to the run named by its
+// run_id attribute. rclone logs without context: its records belong to the run whose
+// operations are the only rclone operations in progress (see StartRclone).
+type Recorder struct {
+	level   slog.Level
+	maxSize int
+
+	mu       sync.Mutex
+	captures map[string]*capture
+}
+
+// Option configures the recorder.
+type Option func(*Recorder)
+
+// New creates a recorder.
+func New(options ...Option) *Recorder {
+	r := &Recorder{
+		level:    defaultLevel,
+		maxSize:  defaultMaxSize,
+		captures: map[string]*capture{},
+	}
+
+	for _, opt := range options {
+		opt(r)
+	}
+
+	return r
+}
+
+// WithLevel sets the minimum level of captured records. Defaults to info.
+func WithLevel(level slog.Level) Option {
+	return func(r *Recorder) { r.level = level }
+}
+
+// WithMaxSize sets the maximum size of a run log, in bytes. Records past it are dropped.
+// Defaults to 1 MiB.
+func WithMaxSize(maxSize int) Option {
+	return func(r *Recorder) { r.maxSize = maxSize }
+}
+
+// Level returns the minimum level of captured records.
+func (r *Recorder) Level() slog.Level {
+	return r.level
+}
+
+// Handler returns a handler passing records to next and capturing them.
+func (r *Recorder) Handler(next slog.Handler) slog.Handler {
+	return &handler{recorder: r, next: next}
+}
+
+// Start starts capturing the log of the run runID, discarding any capture of it in progress.
+func (r *Recorder) Start(runID string) {
+	c := &capture{runID: runID, maxSize: r.maxSize}
+	c.handler = slog.NewTextHandler(c, &slog.HandlerOptions{Level: r.level})
+
+	r.mu.Lock()
+	r.captures[runID] = c
+	r.mu.Unlock()
+}
+
+// Stop stops capturing the log of the run runID and returns it, or nil when it is not being
+// captured.
+func (r *Recorder) Stop(runID string) *domain.SyncRunLog {
+	r.mu.Lock()
+	c := r.captures[runID]
+	delete(r.captures, runID)
+	r.mu.Unlock()
+
+	if c == nil {
+		return nil
+	}
+
+	if c.truncated {
+		fmt.Fprintf(&c.buf, "... log truncated at %d bytes\n", c.maxSize)
+	}
+
+	return &domain.SyncRunLog{
+		RunID:     c.runID,
+		Content:   c.buf.Bytes(),
+		Truncated: c.truncated,
+	}
+}
+
+// enabled reports whether records of level may be captured.
+func (r *Recorder) enabled(level slog.Level) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return len(r.captures) > 0 && level >= r.level
+}
+
+// record captures record, with the attributes and groups added by ops, in the log of the run
+// runID.
+func (r *Recorder) record(ctx context.Context, runID string, record slog.Record, ops []func(slog.Handler) slog.Handler) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	c := r.captures[runID]
+	if c == nil || record.Level < r.level {
+		return nil
+	}
+
+	h := c.handler
+	for _, op := range ops {
+		h = op(h)
+	}
+
+	return h.Handle(ctx, record)
+}
+
+// capture is the log of a run. Writes past maxSize are dropped.
+type capture struct {
+	runID     string
+	maxSize   int
+	buf       bytes.Buffer
+	truncated bool
+	handler   slog.Handler
+}
+
+func (c *capture) Write(p []byte) (int, error) {
+	if c.truncated || c.buf.Len()+len(p) > c.maxSize {
+		c.truncated = true
+		return len(p), nil
+	}
+
+	return c.buf.Write(p)
+}
+
+type handler struct {
+	recorder *Recorder
+	next     slog.Handler
+	// ops replays the attributes and groups added to the handler on the capture handler.
+	ops []func(slog.Handler) slog.Handler
+	// runID is the run_id attribute added to the handler, if any.
+	runID string
+}
+
+func (h *handler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level) || h.recorder.enabled(level)
+}
+
+func (h *handler) Handle(ctx context.Context, record slog.Record) error {
+	var err error
+	if h.next.Enabled(ctx, record.Level) {
+		err = h.next.Handle(ctx, record)
+	}
+
+	runID := h.run(ctx, record)
+	if runID == "" {
+		return err
+	}
+	if captureErr := h.recorder.record(ctx, runID, record, h.ops); err == nil {
+		err = captureErr
+	}
+
+	return err
+}
+
+// run returns the run record belongs to, or "" when it belongs to none.
+func (h *handler) run(ctx context.Context, record slog.Record) string {
+	if runID := RunID(ctx); runID != "" {
+		return runID
+	}
+
+	runID := h.runID
+	record.Attrs(func(a slog.Attr) bool {
+		if a.Key == runIDKey {
+			runID = a.Value.String()
+			return false
+		}
+		return true
+	})
+	if runID == "" && fromRclone(record.PC) {
+		runID = rcloneRun()
+	}
+
+	return runID
+}
+
+func (h *handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	runID := h.runID
+	for _, a := range attrs {
+		if a.Key == runIDKey {
+			runID = a.Value.String()
+		}
+	}
+
+	return &handler{
+		recorder: h.recorder,
+		next:     h.next.WithAttrs(attrs),
+		ops:      append(slices.Clip(h.ops), func(h slog.Handler) slog.Handler { return h.WithAttrs(attrs) }),
+		runID:    runID,
+	}
+}
+
+func (h *handler) WithGroup(name string) slog.Handler {
+	return &handler{
+		recorder: h.recorder,
+		next:     h.next.WithGroup(name),
+		ops:      append(slices.Clip(h.ops), func(h slog.Handler) slog.Handler { return h.WithGroup(name) }),
+		runID:    h.runID,
+	}
+}
@@ -0,0 +1,100 @@
+package runlog
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/rclone/rclone/fs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecorder(t *testing.T) {
+	var out bytes.Buffer
+	recorder := New(WithLevel(slog.LevelDebug))
+	logger := slog.New(recorder.Handler(slog.NewJSONHandler(&out, &slog.HandlerOptions{Level: slog.LevelInfo})))
+
+	logger.Info("before the run", "run_id", "run-1")
+	assert.Nil(t, recorder.Stop("run-1"))
+
+	recorder.Start("run-1")
+	recorder.Start("run-2")
+	logger.With("run_id", "run-1", "job", "test-job").WithGroup("sync").Info("Starting sync", "files", 3)
+	logger.DebugContext(WithRun(context.Background(), "run-1"), "rclone detail")
+	logger.Info("other run", "run_id", "run-2")
+	logger.Info("outside runs")
+	log := recorder.Stop("run-1")
+	logger.Info("after the run", "run_id", "run-1")
+
+	require.NotNil(t, log)
+	assert.Equal(t, "run-1", log.RunID)
+	assert.False(t, log.Truncated)
+	content := string(log.Content)
+	assert.Contains(t, content, `msg="Starting sync" run_id=run-1 job=test-job sync.files=3`)
+	assert.Contains(t, content, `level=DEBUG msg="rclone detail"`)
+	assert.NotContains(t, content, "before the run")
+	assert.NotContains(t, content, "other run")
+	assert.NotContains(t, content, "outside runs")
+	assert.NotContains(t, content, "after the run")
+	assert.Contains(t, string(recorder.Stop("run-2").Content), "other run")
+
+	// The wrapped handler keeps its own level.
+	assert.Contains(t, out.String(), "Starting sync")
+	assert.NotContains(t, out.String(), "rclone detail")
+}
+
+func TestRecorder_Rclone(t *testing.T) {
+	recorder := New()
+	defaultLogger := slog.Default()
+	slog.SetDefault(slog.New(recorder.Handler(slog.NewTextHandler(io.Discard, nil))))
+	t.Cleanup(func() { slog.SetDefault(defaultLogger) })
+
+	recorder.Start("run-1")
+	fs.Logf(nil, "before the operations")
+	done := StartRclone(WithRun(context.Background(), "run-1"))
+	fs.Logf(nil, "during the run")
+	other := StartRclone(context.Background())
+	fs.Logf(nil, "during an operation outside runs")
+	other()
+	done()
+	fs.Logf(nil, "after the operations")
+	content := string(recorder.Stop("run-1").Content)
+
+	assert.Contains(t, content, "during the run")
+	assert.NotContains(t, content, "before the operations")
+	assert.NotContains(t, content, "outside runs")
+	assert.NotContains(t, content, "after the operations")
+}
+
+func TestRecorder_Level(t *testing.T) {
+	recorder := New(WithLevel(slog.LevelWarn))
+	logger := slog.New(recorder.Handler(slog.NewTextHandler(&bytes.Buffer{}, nil)))
+
+	recorder.Start("run-1")
+	logger.Info("skipped", "run_id", "run-1")
+	logger.Warn("kept", "run_id", "run-1")
+	content := string(recorder.Stop("run-1").Content)
+
+	assert.NotContains(t, content, "skipped")
+	assert.Contains(t, content, "kept")
+}
+
+func TestRecorder_MaxSize(t *testing.T) {
+	recorder := New(WithMaxSize(200))
+	logger := slog.New(recorder.Handler(slog.NewTextHandler(&bytes.Buffer{}, nil)))
+
+	recorder.Start("run-1")
+	for i := 0; i < 10; i++ {
+		logger.Info("a line long enough to fill the log quickly", "run_id", "run-1", "i", i)
+	}
+	log := recorder.Stop("run-1")
+
+	assert.True(t, log.Truncated)
+	lines := strings.Split(strings.TrimSpace(string(log.Content)), "\n")
+	assert.Equal(t, "... log truncated at 200 bytes", lines[len(lines)-1])
+	assert.LessOrEqual(t, len(log.Content)-len(lines[len(lines)-1])-1, 200)
+}
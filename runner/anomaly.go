@@ -0,0 +1,180 @@
+package runner
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/eva01/backup-guardian/domain"
+	"github.com/eva01/backup-guardian/internal/errors"
+	"github.com/eva01/backup-guardian/notify"
+)
+
+// WithNotifier sets the notifier of the anomalous runs and of the runs aborted by canary files.
+// Without it, they are only logged.
+func WithNotifier(notifier notify.Notifier) Option {
+	return func(r *Runner) { r.notifier = notifier }
+}
+
+// WithAnomalies sets the store of the anomalies awaiting acknowledgement, and the writer of the
+// pauses they set. Without it, anomalous runs are flagged and notified, but never pause a job
+// or switch it to copy mode.
+func WithAnomalies(anomalies domain.JobAnomaliesReadWriter, pauses domain.JobPausesWriter) Option {
+	return func(r *Runner) {
+		r.anomalies = anomalies
+		r.pauses = pauses
+	}
+}
+
+// heldJob returns the job to run for job: while an anomaly of job awaits acknowledgement, a
+// copy of job in copy mode, which never deletes from the destinations. Jobs run in copy mode
+// when the anomalies cannot be read, so that an unreadable database never lets a sync delete.
+func (r *Runner) heldJob(job *domain.SyncJob) *domain.SyncJob {
+	if r.anomalies == nil || (job.SyncMode() != domain.ModeSync && job.SyncMode() != domain.ModeMove) {
+		return job
+	}
+
+	anomaly, err := r.anomalies.GetJobAnomaly(&domain.JobAnomalySelector{JobName: job.Name})
+	switch {
+	case errors.ErrorCode(err) == errors.CodeNotFound:
+		return job
+	case err != nil:
+		r.logger.Error("Failed to read job anomalies, running in copy mode", slog.String("job", job.Name), slog.Any("error", err))
+	default:
+		r.logger.Warn("Anomaly not acknowledged, running in copy mode", slog.String("job", job.Name),
+			slog.String("anomalous_run_id", anomaly.RunID))
+	}
+
+	return copyMode(job)
+}
+
+// copyMode returns a copy of job in copy mode when job syncs or moves, job otherwise.
+func copyMode(job *domain.SyncJob) *domain.SyncJob {
+	if job.SyncMode() != domain.ModeSync && job.SyncMode() != domain.ModeMove {
+		return job
+	}
+
+	held := *job
+	held.Mode = domain.ModeCopy
+
+	return &held
+}
+
+// checkAnomaly compares the changes a run of job would make, planned with a dry run on each
+// destination before any is made, with the previous runs of job, and flags run when they
+// deviate strongly. Anomalous runs are notified and, as set by the anomaly detection of job,
+// are not started and pause job, or run in copy mode, as do the next runs of job until the
+// anomaly is acknowledged. It returns the job to run, and an error when run must not start.
+// Destinations are not scanned until job has enough runs to compare with.
+func (r *Runner) checkAnomaly(ctx context.Context, job *domain.SyncJob, run *domain.SyncRun, plans destinationPlans) (*domain.SyncJob, error) {
+	if job.Anomaly == nil {
+		return job, nil
+	}
+
+	runs, err := r.store.ListSyncRuns(&domain.SyncRunsSelector{JobName: job.Name, Status: domain.StatusSuccess,
+		Limit: domain.AnomalyBaselineRuns})
+	if err != nil {
+		r.logger.Error("Failed to read the run history, anomalies not checked", slog.String("run_id", run.ID),
+			slog.String("job", job.Name), slog.Any("error", err))
+		return job, nil
+	}
+	baseline := domain.NewBaseline(runs, run.Mode)
+	if baseline.Runs < domain.MinAnomalyBaselineRuns {
+		return job, nil
+	}
+
+	stats := &domain.ChangeStats{}
+	for _, dest := range job.AllDestinations() {
+		plan, err := r.plan(ctx, job, dest, plans)
+		if err != nil {
+			return job, fmt.Errorf("could not plan the changes on %s to check them for anomalies: %w", dest, err)
+		}
+		stats.Merge(domain.NewChangeStats(plan))
+	}
+
+	run.Anomaly = baseline.Check(stats, job.Anomaly)
+	if run.Anomaly == nil {
+		return job, nil
+	}
+
+	action := job.Anomaly.ActionOrDefault()
+	r.logger.Warn("Anomalous run", slog.String("run_id", run.ID), slog.String("job", job.Name),
+		slog.String("reasons", run.Anomaly.Message()), slog.Float64("score", run.Anomaly.Score), slog.String("action", action))
+
+	now := time.Now()
+	message := run.Anomaly.Message()
+	held := action != domain.AnomalyNotify && r.hold(job, run, action, now)
+	switch action {
+	case domain.AnomalyPause:
+		err = fmt.Errorf("anomalous changes, sync not started: %s", run.Anomaly.Message())
+		message += ". The run was not started"
+		if held {
+			message += ", and the job is paused until the anomaly is acknowledged"
+		}
+	case domain.AnomalyCopy:
+		if copied := copyMode(job); copied != job {
+			job = copied
+			run.Mode = domain.ModeCopy
+			message += ". The run is switched to copy mode, which never deletes from the destinations"
+		}
+		if held {
+			message += ". The job runs in copy mode until the anomaly is acknowledged"
+		}
+	}
+
+	r.notify(ctx, &notify.Notification{
+		Event:    notify.EventRunAnomalous,
+		Severity: notify.SeverityCritical,
+		JobName:  job.Name,
+		Title:    fmt.Sprintf("Run %s of job %s is anomalous", run.ID, job.Name),
+		Message:  message,
+		Time:     now,
+	})
+
+	return job, err
+}
+
+// hold records the anomaly of run, detected at at, so that job runs in copy mode until it is
+// acknowledged, and pauses job when action is AnomalyPause. An anomaly awaiting acknowledgement
+// is kept. It reports whether the anomaly is recorded.
+func (r *Runner) hold(job *domain.SyncJob, run *domain.SyncRun, action string, at time.Time) bool {
+	if r.anomalies == nil {
+		return false
+	}
+
+	held, err := r.anomalies.CreateJobAnomaly(&domain.JobAnomaly{
+		JobName:    job.Name,
+		RunID:      run.ID,
+		Action:     action,
+		Reasons:    run.Anomaly.Reasons,
+		DetectedAt: at,
+	})
+	if err != nil {
+		r.logger.Error("Failed to save job anomaly", slog.String("run_id", run.ID), slog.String("job", job.Name), slog.Any("error", err))
+		return false
+	}
+	if action != domain.AnomalyPause || r.pauses == nil {
+		return true
+	}
+
+	if _, err := r.pauses.UpsertJobPause(&domain.JobPause{Scope: job.Name, Reason: held.PauseReason(), PausedAt: at}); err != nil {
+		r.logger.Error("Failed to pause job", slog.String("run_id", run.ID), slog.String("job", job.Name), slog.Any("error", err))
+		return false
+	}
+	r.logger.Warn("Job paused until the anomaly is acknowledged", slog.String("run_id", run.ID), slog.String("job", job.Name),
+		slog.String("anomalous_run_id", held.RunID))
+
+	return true
+}
+
+func (r *Runner) notify(ctx context.Context, n *notify.Notification) {
+	if r.notifier == nil {
+		return
+	}
+
+	if err := r.notifier.Notify(ctx, n); err != nil {
+		r.logger.ErrorContext(ctx, "Failed to send notification", slog.String("event", n.Event),
+			slog.String("job", n.JobName), slog.Any("error", err))
+	}
+}
@@ -0,0 +1,66 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/eva01/backup-guardian/domain"
+	"github.com/eva01/backup-guardian/notify"
+)
+
+// errCanaries marks the errors of runs aborted because canary files were missing or altered.
+var errCanaries = errors.New("canary files missing or altered")
+
+// CanaryVerifier verifies the canary files of sources. RcloneProber implements it.
+type CanaryVerifier interface {
+	// VerifyCanaries returns the state of each canary of source, in order. It returns an error
+	// when the source cannot be read, or a canary cannot be read to the end.
+	VerifyCanaries(ctx context.Context, source string, canaries []*domain.Canary) ([]domain.CanaryResult, error)
+}
+
+// WithCanaries sets the verifier of the canary files of jobs. Without it, canaries are not
+// verified.
+func WithCanaries(verifier CanaryVerifier) Option {
+	return func(r *Runner) { r.canaries = verifier }
+}
+
+// checkCanaries verifies the canaries of job before run transfers anything. When any is
+// missing or altered, it notifies and returns an error wrapping errCanaries; the source may
+// have been encrypted by ransomware or tampered with, and syncing would spread the damage to
+// the destinations.
+func (r *Runner) checkCanaries(ctx context.Context, job *domain.SyncJob, run *domain.SyncRun) error {
+	if r.canaries == nil || len(job.Canaries) == 0 {
+		return nil
+	}
+
+	results, err := r.canaries.VerifyCanaries(ctx, job.Source, job.Canaries)
+	if err != nil {
+		return fmt.Errorf("could not verify canary files: %w", err)
+	}
+
+	failures := domain.CanaryFailures(results)
+	if len(failures) == 0 {
+		r.logger.Info("Canary files verified", slog.String("run_id", run.ID), slog.String("job", job.Name),
+			slog.Int("canaries", len(results)))
+		return nil
+	}
+
+	message := strings.Join(failures, "; ")
+	r.logger.Error("Canary files missing or altered, sync not started", slog.String("run_id", run.ID), slog.String("job", job.Name),
+		slog.String("canaries", message))
+	r.notify(ctx, &notify.Notification{
+		Event:    notify.EventCanaryFailed,
+		Severity: notify.SeverityCritical,
+		JobName:  job.Name,
+		Title:    fmt.Sprintf("Canary files of job %s are missing or altered", job.Name),
+		Message: fmt.Sprintf("Run %s was aborted before syncing: %s. The source may have been encrypted by ransomware or "+
+			"tampered with; check it before restoring the canaries", run.ID, message),
+		Time: time.Now(),
+	})
+
+	return fmt.Errorf("%w, sync not started: %s", errCanaries, message)
+}
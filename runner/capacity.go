@@ -0,0 +1,142 @@
+package runner
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/rclone/rclone/fs"
+
+	"github.com/eva01/backup-guardian/domain"
+	"github.com/eva01/backup-guardian/runner/options"
+)
+
+// UsageReader reads the storage usage of destinations. RcloneProber implements it.
+type UsageReader interface {
+	// Usage returns the usage of the storage of path, or nil when its backend cannot tell.
+	Usage(ctx context.Context, path string) (*domain.DestinationUsage, error)
+}
+
+// WithUsage sets the reader of the storage usage of destinations, recorded with each run and
+// used by capacity checks. Without it, usage is not recorded and capacity is not checked.
+func WithUsage(usage UsageReader) Option {
+	return func(r *Runner) { r.usage = usage }
+}
+
+// destinationPlans are the changes planned by dry runs on the destinations of a run, by
+// destination, shared by the checks made before the run so that each destination is scanned
+// once.
+type destinationPlans map[string]*domain.SyncPlan
+
+// plan returns the changes a run of job would make on dest, planned with a dry run unless
+// already in plans.
+func (r *Runner) plan(ctx context.Context, job *domain.SyncJob, dest string, plans destinationPlans) (*domain.SyncPlan, error) {
+	if plan, ok := plans[dest]; ok {
+		return plan, nil
+	}
+
+	opts := &options.RcloneOptions{Filters: job.Filters, Encryption: job.Encryption, DryRun: true}
+	res, err := r.execute(ctx, job, dest, opts)
+	if err != nil {
+		return nil, err
+	}
+	plan := &domain.SyncPlan{}
+	if res != nil && res.Plan != nil {
+		plan = res.Plan
+	}
+	plans[dest] = plan
+
+	return plan, nil
+}
+
+// checkCapacity pre-scans the changes of a run of job with a dry run on each destination and
+// returns an error when they need more space than the destination has free, keeping the reserve
+// of the job. Deletions are not deducted: rclone deletes after transferring, and replaces
+// updated files once their new version is written. Destinations whose free space is unknown are
+// not scanned. The usage read is recorded on run.
+func (r *Runner) checkCapacity(ctx context.Context, job *domain.SyncJob, run *domain.SyncRun, plans destinationPlans) error {
+	if r.usage == nil || job.Capacity == nil || !job.Capacity.Check {
+		return nil
+	}
+
+	var shortages []string
+	for _, dest := range job.AllDestinations() {
+		usage := r.readUsage(ctx, run, dest)
+		if usage == nil || usage.Free < 0 {
+			r.logger.Warn("Free space unknown, capacity not checked", slog.String("run_id", run.ID), slog.String("job", job.Name),
+				slog.String("dest", dest))
+			continue
+		}
+
+		plan, err := r.plan(ctx, job, dest, plans)
+		if err != nil {
+			return fmt.Errorf("could not estimate the space needed on %s: %w", dest, err)
+		}
+		usage.Required = plan.CopyBytes + plan.UpdateBytes
+		run.Usage = append(run.Usage, *usage)
+
+		attrs := []any{slog.String("run_id", run.ID), slog.String("job", job.Name), slog.String("dest", dest),
+			slog.Int64("required", usage.Required), slog.Int64("reserve", job.Capacity.Reserve), slog.Int64("free", usage.Free)}
+		if usage.Fits(usage.Required, job.Capacity.Reserve) {
+			r.logger.Info("Capacity checked", attrs...)
+			continue
+		}
+		r.logger.Error("Not enough free space", attrs...)
+		shortages = append(shortages, fmt.Sprintf("%s needs %s with a reserve of %s, %s free", dest, byteUnit(usage.Required),
+			byteUnit(job.Capacity.Reserve), byteUnit(usage.Free)))
+	}
+
+	if len(shortages) > 0 {
+		return fmt.Errorf("not enough free space, sync not started: %s", strings.Join(shortages, "; "))
+	}
+
+	return nil
+}
+
+// recordUsage reads the usage of the destinations of job at the end of run and records it on
+// run, keeping the space required by its capacity check. The usage read by the check is kept
+// for destinations that cannot be read anymore.
+func (r *Runner) recordUsage(ctx context.Context, job *domain.SyncJob, run *domain.SyncRun) {
+	if r.usage == nil || ctx.Err() != nil {
+		return
+	}
+
+	checked := map[string]domain.DestinationUsage{}
+	for _, usage := range run.Usage {
+		checked[usage.Destination] = usage
+	}
+
+	var usages []domain.DestinationUsage
+	for _, dest := range job.AllDestinations() {
+		before, ok := checked[dest]
+		switch usage := r.readUsage(ctx, run, dest); {
+		case usage != nil:
+			usage.Required = before.Required
+			usages = append(usages, *usage)
+		case ok:
+			usages = append(usages, before)
+		}
+	}
+	run.Usage = usages
+}
+
+// readUsage returns the usage of dest, or nil when it cannot be read.
+func (r *Runner) readUsage(ctx context.Context, run *domain.SyncRun, dest string) *domain.DestinationUsage {
+	usage, err := r.usage.Usage(ctx, dest)
+	if err != nil {
+		r.logger.Warn("Failed to read destination usage", slog.String("run_id", run.ID), slog.String("job", run.JobName),
+			slog.String("dest", dest), slog.Any("error", err))
+		return nil
+	}
+	if usage != nil {
+		usage.Destination = dest
+	}
+
+	return usage
+}
+
+// byteUnit formats size with binary units, e.g. 1.5 GiB.
+func byteUnit(size int64) string {
+	return fs.SizeSuffix(size).ByteUnit()
+}
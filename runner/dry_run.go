@@ -0,0 +1,73 @@
+package runner
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/eva01/backup-guardian/domain"
+	"github.com/eva01/backup-guardian/internal/errors"
+	"github.com/eva01/backup-guardian/internal/redact"
+	"github.com/eva01/backup-guardian/runner/options"
+	"github.com/eva01/backup-guardian/runner/result"
+)
+
+// DryRun runs job in rclone dry-run mode and records the changes a sync would make as a run
+// of kind dry-run. Dry runs ignore pauses and run windows, run no hook, send no heartbeat and
+// leave the schedule of the job untouched, so they can run next to the runner loop. A dry run
+// that fails is returned with status failed; an error is returned when it cannot be recorded.
+func (r *Runner) DryRun(ctx context.Context, job *domain.SyncJob) (*domain.SyncRun, error) {
+	switch job.SyncMode() {
+	case domain.ModeBisync:
+		return nil, &errors.Error{Code: errors.CodeInvalid, Message: "Dry runs are not supported for bisync jobs"}
+	case domain.ModeScrub:
+		return nil, &errors.Error{Code: errors.CodeInvalid, Message: "Dry runs are not supported for scrub jobs"}
+	}
+
+	run, err := r.store.CreateSyncRun(&domain.SyncRun{
+		ID:        domain.NewSyncRunID(),
+		JobName:   job.Name,
+		Mode:      job.SyncMode(),
+		Kind:      domain.KindDryRun,
+		Status:    domain.StatusRunning,
+		StartedAt: time.Now(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	run.Filters = job.Filters
+	r.logger.Info("Starting dry run", slog.String("run_id", run.ID), slog.String("job", job.Name), slog.String("mode", job.SyncMode()))
+
+	opts := &options.RcloneOptions{Filters: job.Filters, Encryption: job.Encryption, DryRun: true}
+	var res *result.RcloneResult
+	if job.FanOut() {
+		res, err = r.fanOut(ctx, job, run, opts)
+	} else {
+		res, err = r.execute(ctx, job, job.Destination, opts)
+	}
+
+	run.FinishedAt = time.Now()
+	if res != nil {
+		run.Plan = res.Plan
+	}
+	if err != nil {
+		run.Status = domain.StatusFailed
+		run.ErrorMessage = redact.String(err.Error())
+		r.logger.Error("Dry run failed", slog.String("run_id", run.ID), slog.Any("error", err))
+	} else {
+		run.Status = domain.StatusSuccess
+		attrs := []any{slog.String("run_id", run.ID), slog.String("job", job.Name)}
+		if run.Plan != nil {
+			attrs = append(attrs, slog.Int64("copies", run.Plan.Copies), slog.Int64("updates", run.Plan.Updates),
+				slog.Int64("deletes", run.Plan.Deletes), slog.Int64("copy_bytes", run.Plan.CopyBytes+run.Plan.UpdateBytes),
+				slog.Int64("delete_bytes", run.Plan.DeleteBytes))
+		}
+		r.logger.Info("Dry run completed", attrs...)
+	}
+
+	if err := r.store.UpdateSyncRun(run); err != nil {
+		return nil, err
+	}
+
+	return run, nil
+}
@@ -0,0 +1,154 @@
+package runner
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/eva01/backup-guardian/domain"
+	"github.com/eva01/backup-guardian/internal/redact"
+	"github.com/eva01/backup-guardian/runner/options"
+	"github.com/eva01/backup-guardian/runner/result"
+)
+
+// fanOut transfers the source of job to all its destinations in parallel, records their
+// outcomes on run and returns their combined result. It returns an error when the outcomes fail
+// the run under the job's policy; destinations failing without failing the run are reported in
+// the error message of run.
+func (r *Runner) fanOut(ctx context.Context, job *domain.SyncJob, run *domain.SyncRun, opts *options.RcloneOptions) (*result.RcloneResult, error) {
+	start := time.Now()
+	destinations := job.AllDestinations()
+	outcomes := make([]domain.DestinationResult, len(destinations))
+	results := make([]*result.RcloneResult, len(destinations))
+	reports := combineProgress(len(destinations), opts.Progress)
+	// The destinations share the listing of the source, so that it is listed once.
+	listing := options.NewSharedListing(len(destinations))
+
+	var wg sync.WaitGroup
+	for i, dest := range destinations {
+		destOpts := *opts
+		destOpts.Progress = reports[i]
+		destOpts.SharedSource = listing
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			res, err := r.execute(ctx, job, dest, &destOpts)
+			results[i] = res
+			outcomes[i] = destinationResult(dest, res, err)
+		}()
+	}
+	wg.Wait()
+
+	run.Destinations = outcomes
+	combined := &result.RcloneResult{Duration: time.Since(start)}
+	var failures []string
+	for i, outcome := range outcomes {
+		if res := results[i]; res != nil {
+			combined.FilesTransferred += res.FilesTransferred
+			combined.BytesTransferred += res.BytesTransferred
+			combined.FilesTotal += res.FilesTotal
+			combined.BytesTotal += res.BytesTotal
+			if res.Plan != nil {
+				if combined.Plan == nil {
+					combined.Plan = &domain.SyncPlan{}
+				}
+				combined.Plan.Merge(res.Plan, outcome.Destination)
+			}
+			if res.Changes != nil {
+				if combined.Changes == nil {
+					combined.Changes = &domain.ChangeStats{}
+				}
+				combined.Changes.Merge(res.Changes)
+			}
+		}
+
+		attrs := []any{slog.String("run_id", run.ID), slog.String("job", job.Name), slog.String("dest", outcome.Destination),
+			slog.String("status", outcome.Status), slog.Int64("files", outcome.FilesTransferred),
+			slog.Int64("bytes", outcome.BytesTransferred), slog.Duration("duration", outcome.Duration)}
+		if outcome.Status == domain.StatusFailed {
+			failures = append(failures, outcome.Destination+": "+outcome.Error)
+			r.logger.Error("Destination failed", append(attrs, slog.String("error", outcome.Error))...)
+			continue
+		}
+		r.logger.Info("Destination done", attrs...)
+	}
+
+	var failure error
+	if len(failures) > 0 {
+		failure = fmt.Errorf("%d of %d destinations failed: %s", len(failures), len(destinations), strings.Join(failures, "; "))
+	}
+
+	switch job.FanOutStatus(outcomes) {
+	case domain.StatusFailed:
+		return combined, failure
+	case domain.StatusDeferred:
+		combined.Stopped = true
+	}
+	if failure != nil {
+		run.ErrorMessage = redact.String(failure.Error())
+		r.logger.Warn("Sync succeeded on some destinations only", slog.String("run_id", run.ID),
+			slog.String("job", job.Name), slog.String("require", job.RequirePolicy()), slog.Any("error", failure))
+	}
+
+	return combined, nil
+}
+
+// destinationResult returns the outcome of the transfer to dest, which returned res and err.
+func destinationResult(dest string, res *result.RcloneResult, err error) domain.DestinationResult {
+	outcome := domain.DestinationResult{Destination: dest, Status: domain.StatusSuccess}
+	if res != nil {
+		outcome.FilesTransferred = res.FilesTransferred
+		outcome.BytesTransferred = res.BytesTransferred
+		outcome.Duration = res.Duration
+	}
+
+	switch {
+	case err != nil:
+		outcome.Status = domain.StatusFailed
+		outcome.Error = redact.String(err.Error())
+	case res != nil && res.Stopped:
+		outcome.Status = domain.StatusDeferred
+	}
+
+	return outcome
+}
+
+// combineProgress returns a progress callback for each of n concurrent operations, reporting
+// their combined progress to report. The callbacks are nil when report is nil.
+func combineProgress(n int, report func(*domain.Progress)) []func(*domain.Progress) {
+	reports := make([]func(*domain.Progress), n)
+	if report == nil {
+		return reports
+	}
+
+	var mu sync.Mutex
+	latest := make([]*domain.Progress, n)
+	for i := range reports {
+		reports[i] = func(progress *domain.Progress) {
+			mu.Lock()
+			defer mu.Unlock()
+
+			latest[i] = progress
+			combined := &domain.Progress{StartedAt: progress.StartedAt, UpdatedAt: progress.UpdatedAt}
+			for _, p := range latest {
+				if p == nil {
+					continue
+				}
+				combined.BytesDone += p.BytesDone
+				combined.BytesTotal += p.BytesTotal
+				combined.FilesDone += p.FilesDone
+				combined.FilesTotal += p.FilesTotal
+				combined.Speed += p.Speed
+				combined.ETA = max(combined.ETA, p.ETA)
+				combined.Transfers = append(combined.Transfers, p.Transfers...)
+			}
+			report(combined)
+		}
+	}
+
+	return reports
+}
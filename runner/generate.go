@@ -1,3 +1,11 @@
 package runner
 
 //go:generate mockery --name=RcloneExecutor --outpkg=mocks --output=./mocks --filename=rclone_executor_mock.go
+//go:generate mockery --name=Heartbeat --outpkg=mocks --output=./mocks --filename=heartbeat_mock.go
+//go:generate mockery --name=LogRecorder --outpkg=mocks --output=./mocks --filename=log_recorder_mock.go
+//go:generate mockery --name=ProgressReporter --outpkg=mocks --output=./mocks --filename=progress_reporter_mock.go
+//go:generate mockery --name=HookRunner --outpkg=mocks --output=./mocks --filename=hook_runner_mock.go
+//go:generate mockery --name=UsageReader --outpkg=mocks --output=./mocks --filename=usage_reader_mock.go
+//go:generate mockery --name=CanaryVerifier --outpkg=mocks --output=./mocks --filename=canary_verifier_mock.go
+//go:generate mockery --name=ManifestStorage --outpkg=mocks --output=./mocks --filename=manifest_storage_mock.go
+//go:generate mockery --name=Scrubber --outpkg=mocks --output=./mocks --filename=scrubber_mock.go
@@ -0,0 +1,10 @@
+package runner
+
+import "github.com/eva01/backup-guardian/domain"
+
+// Heartbeat reports runs to an external monitor. Implementations must return immediately.
+type Heartbeat interface {
+	Start(job *domain.SyncJob, run *domain.SyncRun)
+	// Finish reports the outcome of run. log is the log captured for run, nil when none was.
+	Finish(job *domain.SyncJob, run *domain.SyncRun, log *domain.SyncRunLog)
+}
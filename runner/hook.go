@@ -0,0 +1,12 @@
+package runner
+
+import (
+	"context"
+
+	"github.com/eva01/backup-guardian/domain"
+)
+
+// HookRunner runs the hooks of jobs.
+type HookRunner interface {
+	Run(ctx context.Context, hook *domain.Hook, job *domain.SyncJob, run *domain.SyncRun) *domain.HookResult
+}
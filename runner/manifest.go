@@ -0,0 +1,142 @@
+package runner
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+
+	"github.com/eva01/backup-guardian/domain"
+	"github.com/eva01/backup-guardian/internal/errors"
+)
+
+// ManifestStorage lists the files of destinations and keeps the copies of manifests on them.
+// RcloneManifestStorage implements it.
+type ManifestStorage interface {
+	// ListFiles lists the files of dest as stored, sorted by path, without domain.ReservedDir.
+	// previous is the listing of dest in the previous manifest, whose hashes may be reused for
+	// the files that did not change.
+	ListFiles(ctx context.Context, dest string, previous []domain.ManifestFile) ([]domain.ManifestFile, error)
+	// PutManifest writes a copy of manifest on dest.
+	PutManifest(ctx context.Context, dest string, manifest *domain.Manifest) error
+	// ReadManifests reads the copies of the manifests of the job jobName on dest.
+	ReadManifests(ctx context.Context, dest, jobName string) ([]*domain.Manifest, error)
+}
+
+// WithManifests sets the store of the manifests of runs, and the storage of their copies on the
+// destinations. Without it, no manifest is recorded.
+func WithManifests(manifests domain.ManifestsReadWriter, storage ManifestStorage) Option {
+	return func(r *Runner) {
+		r.manifests = manifests
+		r.manifestStorage = storage
+	}
+}
+
+// recordManifest records the manifest of run, a successful run of job, chained to the latest
+// manifest of job, and writes its copies on the destinations. Destinations that failed in a
+// fan-out run are left out. Failures are logged: they never fail the run.
+func (r *Runner) recordManifest(ctx context.Context, job *domain.SyncJob, run *domain.SyncRun) {
+	if r.manifests == nil || !job.Manifest || run.Status != domain.StatusSuccess || ctx.Err() != nil {
+		return
+	}
+
+	previous, err := r.manifests.GetManifest(&domain.ManifestSelector{JobName: job.Name})
+	switch {
+	case errors.ErrorCode(err) == errors.CodeNotFound:
+		previous = nil
+	case err != nil:
+		r.logger.Error("Failed to read the latest manifest, manifest not recorded", slog.String("run_id", run.ID),
+			slog.String("job", job.Name), slog.Any("error", err))
+		return
+	}
+
+	var destinations []domain.ManifestDestination
+	for _, dest := range manifestDestinations(job, run) {
+		var listed []domain.ManifestFile
+		if previous != nil {
+			if listing := previous.Destination(dest); listing != nil {
+				listed = listing.Entries
+			}
+		}
+		files, err := r.manifestStorage.ListFiles(ctx, dest, listed)
+		if err != nil {
+			r.logger.Error("Failed to list destination, manifest not recorded", slog.String("run_id", run.ID),
+				slog.String("job", job.Name), slog.String("destination", dest), slog.Any("error", err))
+			return
+		}
+		destinations = append(destinations, domain.ManifestDestination{Destination: dest, Entries: files})
+	}
+
+	manifest, err := r.manifests.CreateManifest(domain.NewManifest(run, previous, destinations, time.Now()))
+	if err != nil {
+		r.logger.Error("Failed to record manifest", slog.String("run_id", run.ID), slog.String("job", job.Name),
+			slog.Any("error", err))
+		return
+	}
+	r.logger.Info("Manifest recorded", slog.String("run_id", run.ID), slog.String("job", job.Name),
+		slog.Int64("sequence", manifest.Sequence), slog.Int64("files", manifest.Files), slog.String("hash", manifest.Hash))
+
+	for _, dest := range manifest.Destinations {
+		if err := r.manifestStorage.PutManifest(ctx, dest.Destination, manifest); err != nil {
+			r.logger.Error("Failed to write manifest to destination", slog.String("run_id", run.ID),
+				slog.String("job", job.Name), slog.String("destination", dest.Destination), slog.Any("error", err))
+		}
+	}
+}
+
+// manifestDestinations returns the destinations of job that run synced successfully.
+func manifestDestinations(job *domain.SyncJob, run *domain.SyncRun) []string {
+	if len(run.Destinations) == 0 {
+		return job.AllDestinations()
+	}
+
+	var destinations []string
+	for _, outcome := range run.Destinations {
+		if outcome.Status == domain.StatusSuccess {
+			destinations = append(destinations, outcome.Destination)
+		}
+	}
+
+	return destinations
+}
+
+// VerifyManifests verifies the chain of the manifests of job in the database, and compares
+// them with their copies on the current destinations of job and on those the manifests list.
+// Rewriting a manifest in the database breaks the chain, or makes it differ from its copies.
+func (r *Runner) VerifyManifests(ctx context.Context, job *domain.SyncJob) (*domain.ManifestVerification, error) {
+	if r.manifests == nil {
+		return nil, &errors.Error{Code: errors.CodeInvalid, Message: "Manifests are not supported"}
+	}
+
+	manifests, err := r.manifests.ListManifests(&domain.ManifestsSelector{JobName: job.Name, Entries: true})
+	if err != nil {
+		return nil, err
+	}
+
+	verification := &domain.ManifestVerification{JobName: job.Name, Manifests: len(manifests),
+		Problems: domain.VerifyManifestChain(manifests)}
+
+	destinations := slices.Clone(job.AllDestinations())
+	listed := make(map[string][]*domain.Manifest)
+	for _, m := range manifests {
+		for _, dest := range m.Destinations {
+			if !slices.Contains(destinations, dest.Destination) {
+				destinations = append(destinations, dest.Destination)
+			}
+			listed[dest.Destination] = append(listed[dest.Destination], m)
+		}
+	}
+
+	for _, dest := range destinations {
+		copies, err := r.manifestStorage.ReadManifests(ctx, dest, job.Name)
+		if err != nil {
+			verification.Problems = append(verification.Problems, fmt.Sprintf("could not read the manifests on %s: %v", dest, err))
+			continue
+		}
+		verification.Copies += len(copies)
+		verification.Problems = append(verification.Problems, domain.CompareManifestCopies(dest, listed[dest], copies)...)
+	}
+
+	return verification, nil
+}
@@ -5,8 +5,10 @@ package mocks
 import (
 	context "context"
 
-	result "github.com/eva01/backup-guardian/runner/result"
+	options "github.com/eva01/backup-guardian/runner/options"
 	mock "github.com/stretchr/testify/mock"
+
+	result "github.com/eva01/backup-guardian/runner/result"
 )
 
 // RcloneExecutor is an autogenerated mock type for the RcloneExecutor type
@@ -14,9 +16,129 @@ type RcloneExecutor struct {
 	mock.Mock
 }
 
-// Sync provides a mock function with given fields: ctx, source, dest
-func (_m *RcloneExecutor) Sync(ctx context.Context, source string, dest string) (*result.RcloneResult, error) {
-	ret := _m.Called(ctx, source, dest)
+// Bisync provides a mock function with given fields: ctx, source, dest, opts
+func (_m *RcloneExecutor) Bisync(ctx context.Context, source string, dest string, opts *options.RcloneOptions) (*result.RcloneResult, error) {
+	ret := _m.Called(ctx, source, dest, opts)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Bisync")
+	}
+
+	var r0 *result.RcloneResult
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, *options.RcloneOptions) (*result.RcloneResult, error)); ok {
+		return rf(ctx, source, dest, opts)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, *options.RcloneOptions) *result.RcloneResult); ok {
+		r0 = rf(ctx, source, dest, opts)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*result.RcloneResult)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, *options.RcloneOptions) error); ok {
+		r1 = rf(ctx, source, dest, opts)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Check provides a mock function with given fields: ctx, source, dest, opts
+func (_m *RcloneExecutor) Check(ctx context.Context, source string, dest string, opts *options.RcloneOptions) (*result.RcloneResult, error) {
+	ret := _m.Called(ctx, source, dest, opts)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Check")
+	}
+
+	var r0 *result.RcloneResult
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, *options.RcloneOptions) (*result.RcloneResult, error)); ok {
+		return rf(ctx, source, dest, opts)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, *options.RcloneOptions) *result.RcloneResult); ok {
+		r0 = rf(ctx, source, dest, opts)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*result.RcloneResult)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, *options.RcloneOptions) error); ok {
+		r1 = rf(ctx, source, dest, opts)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Copy provides a mock function with given fields: ctx, source, dest, opts
+func (_m *RcloneExecutor) Copy(ctx context.Context, source string, dest string, opts *options.RcloneOptions) (*result.RcloneResult, error) {
+	ret := _m.Called(ctx, source, dest, opts)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Copy")
+	}
+
+	var r0 *result.RcloneResult
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, *options.RcloneOptions) (*result.RcloneResult, error)); ok {
+		return rf(ctx, source, dest, opts)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, *options.RcloneOptions) *result.RcloneResult); ok {
+		r0 = rf(ctx, source, dest, opts)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*result.RcloneResult)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, *options.RcloneOptions) error); ok {
+		r1 = rf(ctx, source, dest, opts)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Move provides a mock function with given fields: ctx, source, dest, opts
+func (_m *RcloneExecutor) Move(ctx context.Context, source string, dest string, opts *options.RcloneOptions) (*result.RcloneResult, error) {
+	ret := _m.Called(ctx, source, dest, opts)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Move")
+	}
+
+	var r0 *result.RcloneResult
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, *options.RcloneOptions) (*result.RcloneResult, error)); ok {
+		return rf(ctx, source, dest, opts)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, *options.RcloneOptions) *result.RcloneResult); ok {
+		r0 = rf(ctx, source, dest, opts)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*result.RcloneResult)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, *options.RcloneOptions) error); ok {
+		r1 = rf(ctx, source, dest, opts)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Sync provides a mock function with given fields: ctx, source, dest, opts
+func (_m *RcloneExecutor) Sync(ctx context.Context, source string, dest string, opts *options.RcloneOptions) (*result.RcloneResult, error) {
+	ret := _m.Called(ctx, source, dest, opts)
 
 	if len(ret) == 0 {
 		panic("no return value specified for Sync")
@@ -24,19 +146,19 @@ func (_m *RcloneExecutor) Sync(ctx context.Context, source string, dest string)
 
 	var r0 *result.RcloneResult
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*result.RcloneResult, error)); ok {
-		return rf(ctx, source, dest)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, *options.RcloneOptions) (*result.RcloneResult, error)); ok {
+		return rf(ctx, source, dest, opts)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string, string) *result.RcloneResult); ok {
-		r0 = rf(ctx, source, dest)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, *options.RcloneOptions) *result.RcloneResult); ok {
+		r0 = rf(ctx, source, dest, opts)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*result.RcloneResult)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
-		r1 = rf(ctx, source, dest)
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, *options.RcloneOptions) error); ok {
+		r1 = rf(ctx, source, dest, opts)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -0,0 +1,58 @@
+package options
+
+import (
+	"github.com/rclone/rclone/fs"
+	"github.com/rclone/rclone/fs/filter"
+
+	"github.com/eva01/backup-guardian/domain"
+)
+
+// NewFilter compiles filters into an rclone filter. It reads the FilterFrom files and fails
+// on invalid rules, so it also validates filters. Nil filters include everything but
+// domain.ReservedDir, which every filter excludes.
+func NewFilter(filters *domain.Filters) (*filter.Filter, error) {
+	opt := filter.Options{
+		MinAge:  fs.DurationOff,
+		MaxAge:  fs.DurationOff,
+		MinSize: fs.SizeSuffix(-1),
+		MaxSize: fs.SizeSuffix(-1),
+	}
+
+	// The first matching rule wins: the reserved directory is excluded before the rules of the
+	// job, which could include it. rclone parses include and exclude rules before filter rules,
+	// so they are turned into filter rules, in the same order.
+	opt.FilterRule = []string{"- /" + domain.ReservedDir + "/**"}
+	if filters != nil {
+		for _, rule := range filters.Include {
+			opt.FilterRule = append(opt.FilterRule, "+ "+rule)
+		}
+		for _, rule := range filters.Exclude {
+			opt.FilterRule = append(opt.FilterRule, "- "+rule)
+		}
+		opt.FilterRule = append(opt.FilterRule, filters.Rules...)
+		opt.FilterFrom = filters.FilterFrom
+		opt.ExcludeFile = filters.ExcludeIfPresent
+		if filters.MinSize > 0 {
+			opt.MinSize = fs.SizeSuffix(filters.MinSize)
+		}
+		if filters.MaxSize > 0 {
+			opt.MaxSize = fs.SizeSuffix(filters.MaxSize)
+		}
+		if filters.MaxAge > 0 {
+			opt.MaxAge = fs.Duration(filters.MaxAge)
+		}
+	}
+
+	fi, err := filter.NewFilter(&opt)
+	if err != nil {
+		return nil, err
+	}
+	// As rclone does, include rules exclude everything else, after all the other rules.
+	if filters != nil && len(filters.Include) > 0 {
+		if err := fi.Add(false, "/**"); err != nil {
+			return nil, err
+		}
+	}
+
+	return fi, nil
+}
@@ -0,0 +1,41 @@
+package options
+
+import (
+	"time"
+
+	"github.com/eva01/backup-guardian/domain"
+)
+
+// RcloneOptions holds the per-run options of an rclone sync operation.
+type RcloneOptions struct {
+	// StopAt, when set, stops the sync gracefully at that time: no new transfer is
+	// started and in-flight transfers are allowed to finish.
+	StopAt time.Time
+	// Bandwidth, when set, limits transfer rates, following its timetable while the sync runs.
+	Bandwidth *domain.BandwidthSchedule
+	// Filters, when set, restricts the synced files. domain.ReservedDir is always excluded.
+	Filters *domain.Filters
+	// Progress, when set, is called with a snapshot of the sync progress every second while
+	// the sync runs. It is not called after Sync returns. RunID and JobName are not set.
+	Progress func(progress *domain.Progress)
+	// LogLevel, when set, is the rclone log level of the sync (e.g. "INFO" to log every
+	// transferred file). Empty keeps rclone's default, NOTICE.
+	LogLevel string
+	// SharedSource, when set, is the listing of the source shared by concurrent operations from
+	// the same source, as the destinations of a fan-out: the source is opened once, through
+	// rclone's Fs cache, and each of its directories is listed once for all of them.
+	SharedSource *SharedListing
+	// Encryption, when set, encrypts dest with rclone crypt. The key is checked against the
+	// key-check file of dest before the operation; operations other than dry runs write the
+	// key-check file when it is missing.
+	Encryption *domain.Encryption
+	// SourceEncryption, when set, decrypts source with rclone crypt, to restore or check an
+	// encrypted destination. The key is checked as for Encryption.
+	SourceEncryption *domain.Encryption
+	// DryRun runs the operation in rclone dry-run mode: nothing is changed, and the changes
+	// the operation would make are returned in RcloneResult.Plan.
+	DryRun bool
+	// RecordChanges counts the changes made by the operation in RcloneResult.Changes. Ignored
+	// by dry runs. Bisync reports none of its changes.
+	RecordChanges bool
+}
@@ -0,0 +1,60 @@
+package options
+
+import (
+	"slices"
+	"sync"
+
+	"github.com/rclone/rclone/fs"
+)
+
+// SharedListing is the listing of a source shared by the concurrent operations of a fan-out:
+// each directory is listed once, by the first operation to reach it, and handed to the others.
+// A directory is forgotten once every operation has read it.
+type SharedListing struct {
+	readers int
+
+	mu   sync.Mutex
+	dirs map[string]*sharedDir
+}
+
+// sharedDir is a directory of a SharedListing, listed or being listed.
+type sharedDir struct {
+	done    chan struct{}
+	entries fs.DirEntries
+	err     error
+	reads   int
+}
+
+// NewSharedListing returns a listing shared by readers operations.
+func NewSharedListing(readers int) *SharedListing {
+	return &SharedListing{readers: readers, dirs: map[string]*sharedDir{}}
+}
+
+// List returns the entries of dir, listed with list by the first caller; the others wait for
+// it and get the same entries, or error. Each caller gets its own copy of the entries, which
+// rclone filters and sorts in place.
+func (l *SharedListing) List(dir string, list func() (fs.DirEntries, error)) (fs.DirEntries, error) {
+	l.mu.Lock()
+	d, listed := l.dirs[dir]
+	if !listed {
+		d = &sharedDir{done: make(chan struct{})}
+		l.dirs[dir] = d
+	}
+	d.reads++
+	if d.reads >= l.readers {
+		delete(l.dirs, dir)
+	}
+	l.mu.Unlock()
+
+	if listed {
+		<-d.done
+	} else {
+		d.entries, d.err = list()
+		close(d.done)
+	}
+	if d.err != nil {
+		return nil, d.err
+	}
+
+	return slices.Clone(d.entries), nil
+}
@@ -0,0 +1,56 @@
+package options_test
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/rclone/rclone/fs"
+	"github.com/rclone/rclone/fs/object"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/eva01/backup-guardian/runner/options"
+)
+
+func TestSharedListing(t *testing.T) {
+	listing := options.NewSharedListing(3)
+	entries := fs.DirEntries{object.NewStaticObjectInfo("a.txt", time.Time{}, 1, true, nil, nil)}
+
+	var calls atomic.Int32
+	list := func() (fs.DirEntries, error) {
+		calls.Add(1)
+		return entries, nil
+	}
+
+	var wg sync.WaitGroup
+	for range 3 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			got, err := listing.List("dir", list)
+			assert.NoError(t, err)
+			assert.Equal(t, entries, got)
+			// Each reader gets its own copy, which rclone filters in place.
+			got[0] = nil
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, int32(1), calls.Load())
+
+	// Once read by every reader, the directory is forgotten and listed again.
+	_, err := listing.List("dir", list)
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), calls.Load())
+
+	t.Run("errors are shared", func(t *testing.T) {
+		listing := options.NewSharedListing(2)
+		failed := errors.New("rate limited")
+		_, err := listing.List("dir", func() (fs.DirEntries, error) { return nil, failed })
+		assert.ErrorIs(t, err, failed)
+		_, err = listing.List("dir", func() (fs.DirEntries, error) { return entries, nil })
+		assert.ErrorIs(t, err, failed)
+	})
+}
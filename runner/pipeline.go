@@ -0,0 +1,71 @@
+package runner
+
+import (
+	"log/slog"
+	"time"
+
+	"github.com/eva01/backup-guardian/domain"
+)
+
+// maxPipelineRuns bounds the runs read to evaluate a pipeline.
+const maxPipelineRuns = 1000
+
+// triggerDependents triggers the jobs of the pipeline of run whose dependencies are met now
+// that run finished.
+func (r *Runner) triggerDependents(run *domain.SyncRun) {
+	if run == nil || run.PipelineID == "" {
+		return
+	}
+
+	runs, err := r.store.ListSyncRuns(&domain.SyncRunsSelector{PipelineID: run.PipelineID, Limit: maxPipelineRuns})
+	if err != nil {
+		r.logger.Error("Failed to read pipeline runs", slog.String("pipeline_id", run.PipelineID), slog.Any("error", err))
+		return
+	}
+
+	pipeline := domain.NewPipeline(run.PipelineID, r.syncJobs(), runs, r.latestRun)
+	if pipeline == nil {
+		return
+	}
+
+	for _, step := range pipeline.Steps {
+		if step.State == domain.StepSkipped && dependsOn(step.Job, run.JobName) {
+			r.logger.Info("Pipeline step skipped", slog.String("pipeline_id", run.PipelineID), slog.String("job", step.Job.Name),
+				slog.String("after", run.JobName), slog.String("status", run.Status))
+		}
+	}
+
+	for _, step := range pipeline.Ready() {
+		trigger := &domain.JobTrigger{JobName: step.Job.Name, RequestedAt: time.Now(), PipelineID: run.PipelineID}
+		if err := r.scheduler.Trigger(trigger); err != nil {
+			r.logger.Error("Failed to trigger pipeline step", slog.String("pipeline_id", run.PipelineID),
+				slog.String("job", step.Job.Name), slog.Any("error", err))
+		}
+	}
+}
+
+// latestRun returns the latest run of the job named name, or nil when it never ran or its runs
+// cannot be read.
+func (r *Runner) latestRun(name string) *domain.SyncRun {
+	runs, err := r.store.ListSyncRuns(&domain.SyncRunsSelector{JobName: name, Limit: 1})
+	if err != nil {
+		r.logger.Warn("Failed to read latest run", slog.String("job", name), slog.Any("error", err))
+		return nil
+	}
+	if len(runs) == 0 {
+		return nil
+	}
+
+	return runs[0]
+}
+
+// dependsOn reports whether job depends directly on the job named name.
+func dependsOn(job *domain.SyncJob, name string) bool {
+	for _, dep := range job.After {
+		if dep.Job == name {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,9 @@
+package runner
+
+import "github.com/eva01/backup-guardian/domain"
+
+// ProgressReporter receives the live progress of runs. Implementations must return immediately.
+type ProgressReporter interface {
+	Update(progress *domain.Progress)
+	Done(progress *domain.Progress)
+}
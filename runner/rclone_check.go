@@ -0,0 +1,104 @@
+package runner
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/rclone/rclone/backend/crypt"
+	"github.com/rclone/rclone/fs"
+	"github.com/rclone/rclone/fs/hash"
+	"github.com/rclone/rclone/fs/operations"
+
+	"github.com/eva01/backup-guardian/runner/options"
+	"github.com/eva01/backup-guardian/runner/result"
+)
+
+// Check runs rclone check from source to dest using the rclone library. Files only in dest
+// are ignored, so that copy destinations check as well as sync ones. Encrypted destinations
+// are checked as rclone cryptcheck does, comparing the hashes of their encrypted files with
+// those of the source files encrypted with the same nonce, or by downloading the files when
+// the underlying remote has no hash.
+func (e *LibraryRcloneExecutor) Check(ctx context.Context, source, dest string, opts *options.RcloneOptions) (*result.RcloneResult, error) {
+	check := &checkRecorder{result: &result.CheckResult{}}
+	res, err := e.run(ctx, source, dest, opts, false, func(ctx context.Context, fdst, fsrc fs.Fs) error {
+		opt := &operations.CheckOpt{Fdst: fdst, Fsrc: fsrc, OneWay: true, Combined: check}
+
+		var err error
+		switch fcrypt, ok := fdst.(*crypt.Fs); {
+		case !ok:
+			err = operations.Check(ctx, opt)
+		case fcrypt.UnWrap().Hashes().GetOne() == hash.None:
+			err = operations.CheckDownload(ctx, opt)
+		default:
+			opt.Check = cryptCheck(fcrypt)
+			err = operations.CheckFn(ctx, opt)
+		}
+
+		// Differences are reported in the result: only fail when files could not be compared.
+		if err != nil && len(check.result.Errors) == 0 && !check.result.OK() {
+			return nil
+		}
+		return err
+	})
+	if res != nil {
+		res.Check = check.result
+	}
+
+	return res, err
+}
+
+// cryptCheck returns an rclone check function comparing the files of fcrypt with source files,
+// by hashing the source files encrypted with the nonce of the destination files.
+func cryptCheck(fcrypt *crypt.Fs) func(ctx context.Context, dst, src fs.Object) (differ bool, noHash bool, err error) {
+	hashType := fcrypt.UnWrap().Hashes().GetOne()
+
+	return func(ctx context.Context, dst, src fs.Object) (bool, bool, error) {
+		cryptDst := dst.(*crypt.Object)
+		underlyingHash, err := cryptDst.UnWrap().Hash(ctx, hashType)
+		if err != nil {
+			return true, false, fmt.Errorf("reading hash of %v: %w", dst, err)
+		}
+		if underlyingHash == "" {
+			return false, true, nil
+		}
+
+		cryptHash, err := fcrypt.ComputeHash(ctx, cryptDst, src, hashType)
+		if err != nil {
+			return true, false, fmt.Errorf("computing hash of %v: %w", src, err)
+		}
+		if cryptHash == "" {
+			return false, true, nil
+		}
+
+		return cryptHash != underlyingHash, false, nil
+	}
+}
+
+// checkRecorder records the combined output of an rclone check, one "<sigil> <path>" line per
+// file, in a CheckResult. rclone serialises the writes.
+type checkRecorder struct {
+	result *result.CheckResult
+}
+
+// Write implements io.Writer.
+func (c *checkRecorder) Write(p []byte) (int, error) {
+	for _, line := range strings.Split(strings.TrimSuffix(string(p), "\n"), "\n") {
+		if len(line) < 3 {
+			continue
+		}
+		path := line[2:]
+		switch line[0] {
+		case '=':
+			c.result.Matches++
+		case '*':
+			c.result.Differ = append(c.result.Differ, path)
+		case '+':
+			c.result.Missing = append(c.result.Missing, path)
+		case '!':
+			c.result.Errors = append(c.result.Errors, path)
+		}
+	}
+
+	return len(p), nil
+}
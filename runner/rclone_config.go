@@ -0,0 +1,82 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"os"
+
+	"github.com/rclone/rclone/fs"
+	"github.com/rclone/rclone/fs/config"
+	"github.com/rclone/rclone/fs/config/configfile"
+	"github.com/rclone/rclone/fs/config/obscure"
+
+	"github.com/eva01/backup-guardian/internal/redact"
+)
+
+// LoadRcloneConfig loads the rclone config file at path, at RCLONE_CONFIG when path is empty,
+// or at rclone's default location otherwise. An encrypted file is decrypted with password,
+// or with RCLONE_CONFIG_PASS when password is empty. A missing file is not an error: remotes
+// can also be defined by environment variables or connection strings. The secrets of the
+// remotes are registered for redaction.
+func LoadRcloneConfig(path, password string) error {
+	if path == "" {
+		path = os.Getenv("RCLONE_CONFIG")
+	}
+	if path != "" {
+		if err := config.SetConfigPath(path); err != nil {
+			return err
+		}
+	}
+	if password != "" {
+		if err := config.SetConfigPassword(password); err != nil {
+			return err
+		}
+	}
+	configfile.Install()
+
+	// rclone asks for the password of an encrypted file on the terminal by default, and exits
+	// when the file cannot be loaded: load it now, to fail with an error instead.
+	fs.GetConfig(context.Background()).AskPassword = false
+	if err := config.Data().Load(); err != nil && !errors.Is(err, config.ErrorConfigFileNotFound) {
+		return err
+	}
+	config.LoadedData()
+
+	for _, remote := range config.FileSections() {
+		redact.Add(remoteSecrets(remote)...)
+	}
+
+	return nil
+}
+
+// remoteSecrets returns the values of the secret options of remote in the rclone config file,
+// passwords both obscured and revealed.
+func remoteSecrets(remote string) []string {
+	backend, ok := config.FileGetValue(remote, "type")
+	if !ok {
+		return nil
+	}
+	info, err := fs.Find(backend)
+	if err != nil {
+		return nil
+	}
+
+	var secrets []string
+	for _, option := range info.Options {
+		if !secretOption(&option) {
+			continue
+		}
+		value, ok := config.FileGetValue(remote, option.Name)
+		if !ok || value == "" {
+			continue
+		}
+		secrets = append(secrets, value)
+		if option.IsPassword {
+			if revealed, err := obscure.Reveal(value); err == nil {
+				secrets = append(secrets, revealed)
+			}
+		}
+	}
+
+	return secrets
+}
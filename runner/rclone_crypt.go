@@ -0,0 +1,166 @@
+package runner
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rclone/rclone/backend/crypt"
+	"github.com/rclone/rclone/fs"
+	"github.com/rclone/rclone/fs/cache"
+	"github.com/rclone/rclone/fs/config/configmap"
+	"github.com/rclone/rclone/fs/config/obscure"
+	"github.com/rclone/rclone/fs/object"
+
+	"github.com/eva01/backup-guardian/domain"
+	"github.com/eva01/backup-guardian/internal/redact"
+)
+
+// cryptName is the name of the crypt remotes wrapping encrypted destinations, as shown in
+// rclone logs.
+const cryptName = "encrypted"
+
+// keyCheckText is the content of key-check files, before encryption.
+const keyCheckText = "backup-guardian key check\n"
+
+// errWrongKey is returned when the key-check file of a remote cannot be decrypted.
+var errWrongKey = errors.New("wrong encryption password or salt")
+
+// encryptedFs returns remote wrapped in an rclone crypt Fs configured by enc, once the key is
+// checked against the key-check file of remote. A missing key-check file is written when write
+// is true, and skipped otherwise.
+func encryptedFs(ctx context.Context, remote string, enc *domain.Encryption, write bool) (fs.Fs, error) {
+	config, err := cryptConfig(remote, enc)
+	if err != nil {
+		return nil, err
+	}
+
+	cipher, err := crypt.NewCipher(config)
+	if err != nil {
+		return nil, err
+	}
+
+	// The key-check file is in the wrapped remote, under a name that does not depend on the key.
+	fbase, err := cache.Get(ctx, remote)
+	if err != nil && !errors.Is(err, fs.ErrorIsFile) {
+		return nil, err
+	}
+	if err := checkKey(ctx, fbase, cipher, write); err != nil {
+		return nil, fmt.Errorf("key check of %s: %w", remote, err)
+	}
+
+	return crypt.NewFs(ctx, cryptName, "", config)
+}
+
+// cryptConfig returns the rclone crypt configuration of remote encrypted with enc, reading
+// its secrets. Options that enc does not set have rclone's defaults.
+func cryptConfig(remote string, enc *domain.Encryption) (configmap.Mapper, error) {
+	password, err := readSecret(enc.PasswordFile, enc.PasswordEnv)
+	if err != nil {
+		return nil, fmt.Errorf("encryption password: %w", err)
+	}
+
+	redact.Add(password)
+
+	options := configmap.Simple{
+		"remote":                    remote,
+		"password":                  obscure.MustObscure(password),
+		"filename_encryption":       enc.FilenameEncryptionMode(),
+		"directory_name_encryption": strconv.FormatBool(!enc.PlainDirectoryNames),
+	}
+	if enc.FilenameEncoding != "" {
+		options["filename_encoding"] = enc.FilenameEncoding
+	}
+	if enc.SaltFile != "" || enc.SaltEnv != "" {
+		salt, err := readSecret(enc.SaltFile, enc.SaltEnv)
+		if err != nil {
+			return nil, fmt.Errorf("encryption salt: %w", err)
+		}
+		redact.Add(salt)
+		options["password2"] = obscure.MustObscure(salt)
+	}
+
+	info, err := fs.Find("crypt")
+	if err != nil {
+		return nil, err
+	}
+
+	return fs.ConfigMap(info.Prefix, info.Options, "", options), nil
+}
+
+// readSecret returns the secret read from file when set, or from the environment variable env.
+// Trailing newlines of files are dropped.
+func readSecret(file, env string) (string, error) {
+	if file != "" {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return "", err
+		}
+		secret := strings.TrimRight(string(data), "\r\n")
+		if secret == "" {
+			return "", fmt.Errorf("%s is empty", file)
+		}
+		return secret, nil
+	}
+
+	secret := os.Getenv(env)
+	if secret == "" {
+		return "", fmt.Errorf("environment variable %s is not set", env)
+	}
+
+	return secret, nil
+}
+
+// checkKey checks that the key-check file of fbase decrypts with cipher. A missing key-check
+// file is written when write is true.
+func checkKey(ctx context.Context, fbase fs.Fs, cipher *crypt.Cipher, write bool) error {
+	obj, err := fbase.NewObject(ctx, domain.KeyCheckFile)
+	if errors.Is(err, fs.ErrorObjectNotFound) || errors.Is(err, fs.ErrorDirNotFound) {
+		if !write {
+			fs.Infof(fbase, "No key-check file, the encryption key cannot be checked")
+			return nil
+		}
+		return writeKeyCheck(ctx, fbase, cipher)
+	}
+	if err != nil {
+		return err
+	}
+
+	in, err := obj.Open(ctx)
+	if err != nil {
+		return err
+	}
+	decrypted, err := cipher.DecryptData(in)
+	if err != nil {
+		_ = in.Close()
+		return errWrongKey
+	}
+	defer decrypted.Close()
+
+	data, err := io.ReadAll(decrypted)
+	if err != nil || string(data) != keyCheckText {
+		return errWrongKey
+	}
+
+	return nil
+}
+
+// writeKeyCheck writes the key-check file of fbase, encrypted with cipher. It is not accounted
+// as a transfer of the run.
+func writeKeyCheck(ctx context.Context, fbase fs.Fs, cipher *crypt.Cipher) error {
+	encrypted, err := cipher.EncryptData(bytes.NewBufferString(keyCheckText))
+	if err != nil {
+		return err
+	}
+
+	size := cipher.EncryptedSize(int64(len(keyCheckText)))
+	info := object.NewStaticObjectInfo(domain.KeyCheckFile, time.Now(), size, true, nil, fbase)
+	_, err = fbase.Put(ctx, encrypted, info)
+	return err
+}
@@ -2,44 +2,399 @@ package runner
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	_ "github.com/rclone/rclone/backend/all"
+	"github.com/rclone/rclone/cmd/bisync"
+	"github.com/rclone/rclone/cmd/bisync/bilib"
 	"github.com/rclone/rclone/fs"
+	"github.com/rclone/rclone/fs/accounting"
+	"github.com/rclone/rclone/fs/cache"
+	"github.com/rclone/rclone/fs/filter"
+	"github.com/rclone/rclone/fs/operations"
+	"github.com/rclone/rclone/fs/rc"
 	"github.com/rclone/rclone/fs/sync"
 
+	"github.com/eva01/backup-guardian/domain"
+	"github.com/eva01/backup-guardian/runlog"
+	"github.com/eva01/backup-guardian/runner/options"
 	"github.com/eva01/backup-guardian/runner/result"
 )
 
-// RcloneExecutor executes rclone sync operations.
+// RcloneExecutor executes rclone operations, one method per job mode. opts may be nil.
 type RcloneExecutor interface {
-	Sync(ctx context.Context, source, dest string) (*result.RcloneResult, error)
+	// Sync makes dest identical to source, deleting extra files from dest.
+	Sync(ctx context.Context, source, dest string, opts *options.RcloneOptions) (*result.RcloneResult, error)
+	// Copy copies new and changed files from source to dest, without deleting anything.
+	Copy(ctx context.Context, source, dest string, opts *options.RcloneOptions) (*result.RcloneResult, error)
+	// Move moves files from source to dest, deleting them from source once copied.
+	Move(ctx context.Context, source, dest string, opts *options.RcloneOptions) (*result.RcloneResult, error)
+	// Bisync applies the changes made on either side since the previous run to the other.
+	Bisync(ctx context.Context, source, dest string, opts *options.RcloneOptions) (*result.RcloneResult, error)
+	// Check compares the files of source with those of dest, without changing anything.
+	// Differences are reported in RcloneResult.Check rather than as an error.
+	Check(ctx context.Context, source, dest string, opts *options.RcloneOptions) (*result.RcloneResult, error)
 }
 
 // LibraryRcloneExecutor implements RcloneExecutor using the rclone Go library.
-type LibraryRcloneExecutor struct{}
+type LibraryRcloneExecutor struct {
+	// BisyncWorkdir is where bisync keeps the listings of the previous runs. Empty uses
+	// rclone's default, in the user cache directory.
+	BisyncWorkdir string
+}
+
+// operation is an rclone operation from fsrc to fdst.
+type operation func(ctx context.Context, fdst, fsrc fs.Fs) error
 
 // Sync runs rclone sync from source to dest using the rclone library.
-func (e *LibraryRcloneExecutor) Sync(ctx context.Context, source, dest string) (*result.RcloneResult, error) {
+func (e *LibraryRcloneExecutor) Sync(ctx context.Context, source, dest string, opts *options.RcloneOptions) (*result.RcloneResult, error) {
+	return e.run(ctx, source, dest, opts, true, func(ctx context.Context, fdst, fsrc fs.Fs) error {
+		return sync.Sync(ctx, fdst, fsrc, true)
+	})
+}
+
+// Copy runs rclone copy from source to dest using the rclone library.
+func (e *LibraryRcloneExecutor) Copy(ctx context.Context, source, dest string, opts *options.RcloneOptions) (*result.RcloneResult, error) {
+	return e.run(ctx, source, dest, opts, true, func(ctx context.Context, fdst, fsrc fs.Fs) error {
+		return sync.CopyDir(ctx, fdst, fsrc, true)
+	})
+}
+
+// Move runs rclone move from source to dest using the rclone library. Source directories
+// are kept, so that an inbox keeps its layout.
+func (e *LibraryRcloneExecutor) Move(ctx context.Context, source, dest string, opts *options.RcloneOptions) (*result.RcloneResult, error) {
+	return e.run(ctx, source, dest, opts, true, func(ctx context.Context, fdst, fsrc fs.Fs) error {
+		return sync.MoveDir(ctx, fdst, fsrc, false, true)
+	})
+}
+
+// Bisync runs rclone bisync between source and dest using the rclone library. The first run
+// of a pair, without listings from a previous run, is a resync that copies the files missing
+// on either side. Later runs recover from interruptions without a resync.
+func (e *LibraryRcloneExecutor) Bisync(ctx context.Context, source, dest string, opts *options.RcloneOptions) (*result.RcloneResult, error) {
+	return e.run(ctx, source, dest, opts, true, func(ctx context.Context, fdst, fsrc fs.Fs) error {
+		bisyncOpt := &bisync.Options{
+			Workdir:            e.BisyncWorkdir,
+			MaxDelete:          bisync.DefaultMaxDelete,
+			CreateEmptySrcDirs: true,
+			Resilient:          true,
+			Recover:            true,
+		}
+		if bisyncOpt.Workdir == "" {
+			bisyncOpt.Workdir = bisync.DefaultWorkdir
+		}
+
+		basePath := bilib.BasePath(ctx, bisyncOpt.Workdir, fsrc, fdst)
+		if !bilib.FileExists(basePath+".path1.lst") && !bilib.FileExists(basePath+".path2.lst") {
+			bisyncOpt.Resync = true
+		}
+
+		return bisync.Bisync(ctx, fsrc, fdst, bisyncOpt)
+	})
+}
+
+// run runs op from source to dest, accounting its transfers and applying opts. write tells
+// whether op writes to dest, which then gets its missing key-check file when encrypted.
+func (e *LibraryRcloneExecutor) run(ctx context.Context, source, dest string, opts *options.RcloneOptions, write bool, op operation) (*result.RcloneResult, error) {
 	start := time.Now()
 
-	if err := fs.GlobalOptionsInit(); err != nil {
+	if opts == nil {
+		opts = &options.RcloneOptions{}
+	}
+	// rclone takes a max duration of zero or less for no limit: a window already closed stops
+	// the operation before it starts.
+	if !opts.StopAt.IsZero() && !time.Now().Before(opts.StopAt) {
+		return &result.RcloneResult{Duration: time.Since(start), Stopped: true}, nil
+	}
+
+	release, err := acquireGlobalConfig(opts.LogLevel)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	defer runlog.StartRclone(ctx)()
+
+	// Each run accounts its transfers in its own stats group.
+	ctx = accounting.WithStatsGroup(ctx, domain.NewSyncRunID())
+	stats := accounting.Stats(ctx)
+
+	if !opts.StopAt.IsZero() {
+		var ci *fs.ConfigInfo
+		ctx, ci = fs.AddConfig(ctx)
+		ci.MaxDuration = fs.Duration(time.Until(opts.StopAt))
+		ci.CutoffMode = fs.CutoffModeSoft
+	}
+
+	var plan *planRecorder
+	if opts.DryRun {
+		var ci *fs.ConfigInfo
+		ctx, ci = fs.AddConfig(ctx)
+		ci.DryRun = true
+		plan = &planRecorder{}
+		ctx = operations.WithSyncLogger(ctx, operations.LoggerOpt{LoggerFn: plan.record})
+	}
+
+	var changes *changeRecorder
+	if opts.RecordChanges && !opts.DryRun {
+		changes = &changeRecorder{}
+		ctx = operations.WithSyncLogger(ctx, operations.LoggerOpt{LoggerFn: changes.record})
+	}
+
+	// Always filtered, to exclude the reserved directory of destinations.
+	fi, err := options.NewFilter(opts.Filters)
+	if err != nil {
 		return nil, err
 	}
+	ctx = filter.ReplaceConfig(ctx, fi)
+
+	if opts.Bandwidth != nil {
+		stop := limitBandwidth(opts.Bandwidth)
+		defer stop()
+	}
 
-	fsrc, err := fs.NewFs(ctx, source)
+	if opts.Progress != nil {
+		stop := reportProgress(stats, start, opts.Progress)
+		defer stop()
+	}
+
+	newResult := func() *result.RcloneResult {
+		// A dry run accounts the transfers it skips: only its plan is returned.
+		if plan != nil {
+			return &result.RcloneResult{Duration: time.Since(start), Plan: plan.result()}
+		}
+
+		progress := snapshot(stats, start)
+		res := &result.RcloneResult{
+			FilesTransferred: stats.GetTransfers(),
+			BytesTransferred: stats.GetBytes(),
+			FilesTotal:       progress.FilesTotal,
+			BytesTotal:       progress.BytesTotal,
+			Duration:         time.Since(start),
+		}
+		if changes != nil {
+			res.Changes = changes.result()
+		}
+		return res
+	}
+
+	fsrc, fdst, err := openFs(ctx, source, dest, opts, write && !opts.DryRun)
 	if err != nil {
-		return &result.RcloneResult{Duration: time.Since(start)}, err
+		return newResult(), err
 	}
 
-	fdst, err := fs.NewFs(ctx, dest)
+	if err := op(ctx, fdst, fsrc); err != nil {
+		res := newResult()
+		if stoppedAt(opts, err) {
+			res.Stopped = true
+			return res, nil
+		}
+		return res, err
+	}
+
+	return newResult(), nil
+}
+
+// openFs opens source and dest, wrapping them in crypt remotes as set by opts. Missing
+// key-check files of dest are written when write is true.
+func openFs(ctx context.Context, source, dest string, opts *options.RcloneOptions, write bool) (fsrc, fdst fs.Fs, err error) {
+	switch {
+	case opts.SourceEncryption != nil:
+		fsrc, err = encryptedFs(ctx, source, opts.SourceEncryption, false)
+	case opts.SharedSource != nil:
+		fsrc, err = cache.Get(ctx, source)
+		if err == nil {
+			fsrc = newSharedSourceFs(fsrc, opts.SharedSource)
+		}
+	default:
+		fsrc, err = fs.NewFs(ctx, source)
+	}
 	if err != nil {
-		return &result.RcloneResult{Duration: time.Since(start)}, err
+		return nil, nil, err
+	}
+
+	if opts.Encryption != nil {
+		fdst, err = encryptedFs(ctx, dest, opts.Encryption, write)
+	} else {
+		fdst, err = fs.NewFs(ctx, dest)
 	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return fsrc, fdst, nil
+}
+
+// sharedSourceFs is a source listed through the listing it shares with the other operations of
+// a fan-out.
+type sharedSourceFs struct {
+	fs.Fs
+	listing  *options.SharedListing
+	features *fs.Features
+}
+
+// newSharedSourceFs returns f listed through listing.
+func newSharedSourceFs(f fs.Fs, listing *options.SharedListing) fs.Fs {
+	// Without the paged and recursive listings, rclone lists every directory with List.
+	features := *f.Features()
+	features.ListP = nil
+	features.ListR = nil
+
+	return &sharedSourceFs{Fs: f, listing: listing, features: &features}
+}
+
+// Features returns the features of the source, without the listings that bypass List.
+func (f *sharedSourceFs) Features() *fs.Features {
+	return f.features
+}
+
+// List lists dir through the shared listing.
+func (f *sharedSourceFs) List(ctx context.Context, dir string) (fs.DirEntries, error) {
+	return f.listing.List(dir, func() (fs.DirEntries, error) { return f.Fs.List(ctx, dir) })
+}
+
+// bandwidthCheckInterval is how often the bandwidth timetable is checked during a sync.
+const bandwidthCheckInterval = time.Minute
+
+// limitBandwidth applies schedule to rclone's token bucket, following its timetable until the
+// returned function is called. The token bucket is process-wide, which is fine as long as the
+// runner performs one job at a time: the destinations of a fan-out share the job's limit, which
+// is lifted once the last of them stops.
+func limitBandwidth(schedule *domain.BandwidthSchedule) (stop func()) {
+	var applied domain.BandwidthLimit
+	apply := func(limit domain.BandwidthLimit) {
+		if limit == applied {
+			return
+		}
+		accounting.TokenBucket.SetBwLimit(bwPair(limit))
+		applied = limit
+	}
+
+	apply(schedule.LimitAt(time.Now()))
+	holdBandwidth()
+
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		ticker := time.NewTicker(bandwidthCheckInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case now := <-ticker.C:
+				apply(schedule.LimitAt(now))
+			}
+		}
+	}()
+
+	return func() {
+		close(done)
+		<-stopped
+		if releaseBandwidth() {
+			apply(domain.BandwidthLimit{})
+		}
+	}
+}
+
+// progressInterval is how often the progress of a sync is reported.
+const progressInterval = time.Second
+
+// reportProgress calls report with a snapshot of stats every progressInterval until the
+// returned function is called. report is not called once the returned function returns.
+func reportProgress(stats *accounting.StatsInfo, start time.Time, report func(*domain.Progress)) (stop func()) {
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		ticker := time.NewTicker(progressInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				report(snapshot(stats, start))
+			}
+		}
+	}()
+
+	return func() {
+		close(done)
+		<-stopped
+	}
+}
+
+// snapshot returns the progress of the sync accounted in stats, started at start.
+func snapshot(stats *accounting.StatsInfo, start time.Time) *domain.Progress {
+	progress := &domain.Progress{StartedAt: start, UpdatedAt: time.Now()}
+
+	remote, err := stats.RemoteStats(false)
+	if err != nil {
+		return progress
+	}
+
+	progress.BytesDone = paramInt(remote, "bytes")
+	progress.BytesTotal = paramInt(remote, "totalBytes")
+	progress.FilesDone = paramInt(remote, "transfers")
+	progress.FilesTotal = paramInt(remote, "totalTransfers")
+	progress.Speed = paramFloat(remote, "speed")
+	progress.ETA = time.Duration(paramFloat(remote, "eta") * float64(time.Second))
+
+	transferring, _ := remote["transferring"].([]rc.Params)
+	for _, transfer := range transferring {
+		name, _ := transfer["name"].(string)
+		progress.Transfers = append(progress.Transfers, domain.FileTransfer{
+			Name:  name,
+			Size:  paramInt(transfer, "size"),
+			Bytes: paramInt(transfer, "bytes"),
+			Speed: paramFloat(transfer, "speed"),
+		})
+	}
+
+	return progress
+}
+
+// paramInt returns the integer value of key in params, or 0 when it is missing.
+func paramInt(params rc.Params, key string) int64 {
+	value, err := params.GetInt64(key)
+	if err != nil {
+		return 0
+	}
+
+	return value
+}
+
+// paramFloat returns the float value of key in params, or 0 when it is missing or null.
+func paramFloat(params rc.Params, key string) float64 {
+	value, err := params.GetFloat64(key)
+	if err != nil {
+		return 0
+	}
+
+	return value
+}
+
+// bwPair converts limit to an rclone bandwidth pair. Unlimited directions are set to -1, as
+// rclone's "off".
+func bwPair(limit domain.BandwidthLimit) fs.BwPair {
+	pair := fs.BwPair{Tx: -1, Rx: -1}
+	if limit.Upload > 0 {
+		pair.Tx = fs.SizeSuffix(limit.Upload)
+	}
+	if limit.Download > 0 {
+		pair.Rx = fs.SizeSuffix(limit.Download)
+	}
+
+	return pair
+}
 
-	if err := sync.Sync(ctx, fdst, fsrc, true); err != nil {
-		return &result.RcloneResult{Duration: time.Since(start)}, err
+// stoppedAt reports whether err results from the sync being stopped at opts.StopAt.
+func stoppedAt(opts *options.RcloneOptions, err error) bool {
+	if opts.StopAt.IsZero() || time.Now().Before(opts.StopAt) {
+		return false
 	}
 
-	return &result.RcloneResult{Duration: time.Since(start)}, nil
+	return errors.Is(err, sync.ErrorMaxDurationReached) || errors.Is(err, context.DeadlineExceeded)
 }
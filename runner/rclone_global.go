@@ -0,0 +1,61 @@
+package runner
+
+import (
+	"context"
+	"sync"
+
+	"github.com/rclone/rclone/fs"
+)
+
+// rclone's configuration and bandwidth limiter are process-wide, and shared by the concurrent
+// operations of a fan-out.
+var (
+	globalMu sync.Mutex
+	// globalUsers is the number of running operations using the global configuration.
+	globalUsers int
+	// bandwidthUsers is the number of running operations limiting the bandwidth.
+	bandwidthUsers int
+)
+
+// acquireGlobalConfig initialises rclone's global configuration with logLevel, unless running
+// operations already use it, and returns the function to call once the operation is done.
+func acquireGlobalConfig(logLevel string) (release func(), err error) {
+	globalMu.Lock()
+	defer globalMu.Unlock()
+
+	if globalUsers == 0 {
+		if err := fs.GlobalOptionsInit(); err != nil {
+			return nil, err
+		}
+		// rclone logs with the global config level, reset by GlobalOptionsInit above.
+		if logLevel != "" {
+			if err := fs.GetConfig(context.Background()).LogLevel.Set(logLevel); err != nil {
+				return nil, err
+			}
+		}
+	}
+	globalUsers++
+
+	return func() {
+		globalMu.Lock()
+		defer globalMu.Unlock()
+		globalUsers--
+	}, nil
+}
+
+// holdBandwidth records an operation limiting the bandwidth.
+func holdBandwidth() {
+	globalMu.Lock()
+	defer globalMu.Unlock()
+	bandwidthUsers++
+}
+
+// releaseBandwidth records the end of an operation limiting the bandwidth, and reports whether
+// it was the last one, so that the limit can be lifted.
+func releaseBandwidth() bool {
+	globalMu.Lock()
+	defer globalMu.Unlock()
+	bandwidthUsers--
+
+	return bandwidthUsers == 0
+}
@@ -6,10 +6,17 @@ import (
 	"context"
 	"os"
 	"path/filepath"
+	"slices"
 	"testing"
 	"time"
 
+	"github.com/rclone/rclone/fs/config"
+	"github.com/rclone/rclone/fs/config/obscure"
 	"github.com/stretchr/testify/require"
+
+	"github.com/eva01/backup-guardian/domain"
+	"github.com/eva01/backup-guardian/internal/redact"
+	"github.com/eva01/backup-guardian/runner/options"
 )
 
 func TestLibraryRcloneExecutor_Sync_Integration_Local(t *testing.T) {
@@ -26,7 +33,7 @@ func TestLibraryRcloneExecutor_Sync_Integration_Local(t *testing.T) {
 	source := srcDir
 	dest := dstDir
 
-	syncResult, err := e.Sync(ctx, source, dest)
+	syncResult, err := e.Sync(ctx, source, dest, nil)
 	require.NoError(t, err)
 	require.NotNil(t, syncResult)
 	require.GreaterOrEqual(t, syncResult.Duration, time.Duration(0))
@@ -37,6 +44,21 @@ func TestLibraryRcloneExecutor_Sync_Integration_Local(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestLibraryRcloneExecutor_Sync_Integration_WindowClosed(t *testing.T) {
+	srcDir := t.TempDir()
+	dstDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(srcDir, "test.txt"), []byte("hello"), 0644))
+
+	e := &LibraryRcloneExecutor{}
+	res, err := e.Sync(context.Background(), srcDir, dstDir, &options.RcloneOptions{StopAt: time.Now().Add(-time.Second)})
+	require.NoError(t, err)
+	require.True(t, res.Stopped)
+	require.Zero(t, res.FilesTransferred)
+
+	_, err = os.Stat(filepath.Join(dstDir, "test.txt"))
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
+
 // TestLibraryRcloneExecutor_Sync_Integration_Memory uses rclone's :memory: backend
 // (in-RAM, no config, no disk). Syncs local -> :memory:src -> :memory:dst -> local
 // and verifies the file is present.
@@ -51,15 +73,15 @@ func TestLibraryRcloneExecutor_Sync_Integration_Memory(t *testing.T) {
 	ctx := context.Background()
 
 	// Populate :memory:src from local (path only = local backend)
-	_, err = e.Sync(ctx, localSrc, ":memory:src")
+	_, err = e.Sync(ctx, localSrc, ":memory:src", nil)
 	require.NoError(t, err)
 
 	// Sync between two memory remotes (no disk, no credentials)
-	_, err = e.Sync(ctx, ":memory:src", ":memory:dst")
+	_, err = e.Sync(ctx, ":memory:src", ":memory:dst", nil)
 	require.NoError(t, err)
 
 	// Pull back to local to verify content
-	_, err = e.Sync(ctx, ":memory:dst", localDst)
+	_, err = e.Sync(ctx, ":memory:dst", localDst, nil)
 	require.NoError(t, err)
 
 	destPath := filepath.Join(localDst, "test.txt")
@@ -67,3 +89,562 @@ func TestLibraryRcloneExecutor_Sync_Integration_Memory(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, "hello memory", string(content))
 }
+
+func TestLibraryRcloneExecutor_Sync_Integration_Bandwidth(t *testing.T) {
+	srcDir := t.TempDir()
+	dstDir := t.TempDir()
+
+	err := os.WriteFile(filepath.Join(srcDir, "test.bin"), make([]byte, 1<<20), 0644)
+	require.NoError(t, err)
+
+	e := &LibraryRcloneExecutor{}
+	opts := &options.RcloneOptions{
+		Bandwidth: &domain.BandwidthSchedule{Limit: domain.BandwidthLimit{Upload: 512 << 10, Download: 512 << 10}},
+	}
+
+	syncResult, err := e.Sync(context.Background(), srcDir, dstDir, opts)
+	require.NoError(t, err)
+	// 1 MiB at 512 KiB/s takes about two seconds.
+	require.GreaterOrEqual(t, syncResult.Duration, time.Second)
+
+	// The limit is lifted once the sync is done.
+	start := time.Now()
+	_, err = e.Sync(context.Background(), srcDir, t.TempDir(), nil)
+	require.NoError(t, err)
+	require.Less(t, time.Since(start), time.Second)
+}
+
+func TestLibraryRcloneExecutor_Sync_Integration_Progress(t *testing.T) {
+	srcDir := t.TempDir()
+
+	err := os.WriteFile(filepath.Join(srcDir, "test.bin"), make([]byte, 1<<20), 0644)
+	require.NoError(t, err)
+
+	var snapshots []*domain.Progress
+	e := &LibraryRcloneExecutor{}
+	opts := &options.RcloneOptions{
+		// Slow the sync down so that progress is reported while it runs.
+		Bandwidth: &domain.BandwidthSchedule{Limit: domain.BandwidthLimit{Upload: 256 << 10, Download: 256 << 10}},
+		Progress:  func(p *domain.Progress) { snapshots = append(snapshots, p) },
+	}
+
+	syncResult, err := e.Sync(context.Background(), srcDir, t.TempDir(), opts)
+	require.NoError(t, err)
+	require.NotEmpty(t, snapshots)
+	require.Equal(t, int64(1<<20), snapshots[len(snapshots)-1].BytesTotal)
+	require.Equal(t, int64(1), syncResult.FilesTotal)
+	require.Equal(t, int64(1<<20), syncResult.BytesTotal)
+}
+
+func TestLibraryRcloneExecutor_Sync_Integration_Filters(t *testing.T) {
+	srcDir := t.TempDir()
+	dstDir := t.TempDir()
+
+	files := map[string]string{
+		"docs/report.txt":           "report",
+		"docs/draft.tmp":            "draft",
+		"app/node_modules/lib.js":   "lib",
+		"videos/raw/huge.mov":       "huge",
+		"videos/raw/.nobackup":      "",
+		"photos/holiday.jpg":        "a larger photo",
+		"photos/thumbs/holiday.jpg": "tiny",
+	}
+	for name, content := range files {
+		path := filepath.Join(srcDir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+
+	filterFile := filepath.Join(t.TempDir(), "filters.txt")
+	require.NoError(t, os.WriteFile(filterFile, []byte("# thumbnails are regenerated\n- thumbs/**\n"), 0644))
+
+	e := &LibraryRcloneExecutor{}
+	_, err := e.Sync(context.Background(), srcDir, dstDir, &options.RcloneOptions{Filters: &domain.Filters{
+		Rules:            []string{"- *.tmp", "- node_modules/**"},
+		FilterFrom:       []string{filterFile},
+		ExcludeIfPresent: []string{".nobackup"},
+		MinSize:          5,
+	}})
+	require.NoError(t, err)
+
+	var synced []string
+	require.NoError(t, filepath.WalkDir(dstDir, func(path string, d os.DirEntry, err error) error {
+		if err == nil && !d.IsDir() {
+			rel, _ := filepath.Rel(dstDir, path)
+			synced = append(synced, filepath.ToSlash(rel))
+		}
+		return err
+	}))
+	require.ElementsMatch(t, []string{"docs/report.txt", "photos/holiday.jpg"}, synced)
+}
+
+func TestLibraryRcloneExecutor_Copy_Integration(t *testing.T) {
+	srcDir := t.TempDir()
+	dstDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(srcDir, "new.txt"), []byte("new"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dstDir, "archived.txt"), []byte("old"), 0644))
+
+	e := &LibraryRcloneExecutor{}
+	syncResult, err := e.Copy(context.Background(), srcDir, dstDir, nil)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), syncResult.FilesTransferred)
+
+	// Copy never deletes from the destination.
+	require.FileExists(t, filepath.Join(dstDir, "new.txt"))
+	require.FileExists(t, filepath.Join(dstDir, "archived.txt"))
+}
+
+func TestLibraryRcloneExecutor_Move_Integration(t *testing.T) {
+	srcDir := t.TempDir()
+	dstDir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(srcDir, "scans"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(srcDir, "scans", "invoice.pdf"), []byte("pdf"), 0644))
+
+	e := &LibraryRcloneExecutor{}
+	_, err := e.Move(context.Background(), srcDir, dstDir, nil)
+	require.NoError(t, err)
+
+	require.FileExists(t, filepath.Join(dstDir, "scans", "invoice.pdf"))
+	require.NoFileExists(t, filepath.Join(srcDir, "scans", "invoice.pdf"))
+	// The inbox layout is kept.
+	require.DirExists(t, filepath.Join(srcDir, "scans"))
+}
+
+func TestLibraryRcloneExecutor_Bisync_Integration(t *testing.T) {
+	dir1 := t.TempDir()
+	dir2 := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir1, "one.txt"), []byte("one"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir2, "two.txt"), []byte("two"), 0644))
+
+	e := &LibraryRcloneExecutor{BisyncWorkdir: t.TempDir()}
+
+	// The first run resyncs: files missing on either side are copied.
+	_, err := e.Bisync(context.Background(), dir1, dir2, nil)
+	require.NoError(t, err)
+	require.FileExists(t, filepath.Join(dir2, "one.txt"))
+	require.FileExists(t, filepath.Join(dir1, "two.txt"))
+
+	// Later runs apply changes both ways, deletions included.
+	require.NoError(t, os.Remove(filepath.Join(dir2, "one.txt")))
+	require.NoError(t, os.WriteFile(filepath.Join(dir2, "three.txt"), []byte("three"), 0644))
+	_, err = e.Bisync(context.Background(), dir1, dir2, nil)
+	require.NoError(t, err)
+	require.NoFileExists(t, filepath.Join(dir1, "one.txt"))
+	require.FileExists(t, filepath.Join(dir1, "three.txt"))
+}
+
+// TestLibraryRcloneExecutor_Sync_Integration_SharedSource runs the syncs of a fan-out
+// concurrently from a shared source listing.
+func TestLibraryRcloneExecutor_Sync_Integration_SharedSource(t *testing.T) {
+	srcDir := t.TempDir()
+	dstDirs := []string{t.TempDir(), t.TempDir()}
+	require.NoError(t, os.MkdirAll(filepath.Join(srcDir, "sub"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(srcDir, "test.txt"), []byte("hello"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(srcDir, "sub", "nested.txt"), []byte("nested"), 0644))
+
+	e := &LibraryRcloneExecutor{}
+	listing := options.NewSharedListing(len(dstDirs))
+	errs := make(chan error, len(dstDirs))
+	for _, dstDir := range dstDirs {
+		go func() {
+			_, err := e.Sync(context.Background(), srcDir, dstDir, &options.RcloneOptions{SharedSource: listing})
+			errs <- err
+		}()
+	}
+
+	for range dstDirs {
+		require.NoError(t, <-errs)
+	}
+	for _, dstDir := range dstDirs {
+		require.FileExists(t, filepath.Join(dstDir, "test.txt"))
+		require.FileExists(t, filepath.Join(dstDir, "sub", "nested.txt"))
+	}
+}
+
+func TestLibraryRcloneExecutor_Sync_Integration_DryRun(t *testing.T) {
+	srcDir := t.TempDir()
+	dstDir := t.TempDir()
+
+	require.NoError(t, os.WriteFile(filepath.Join(srcDir, "new.txt"), []byte("new"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(srcDir, "changed.txt"), []byte("changed"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dstDir, "changed.txt"), []byte("old"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dstDir, "extra.txt"), []byte("extra"), 0644))
+
+	e := &LibraryRcloneExecutor{}
+	ctx := context.Background()
+
+	res, err := e.Sync(ctx, srcDir, dstDir, &options.RcloneOptions{DryRun: true})
+	require.NoError(t, err)
+	require.NotNil(t, res.Plan)
+	require.ElementsMatch(t, []domain.PlannedChange{
+		{Action: domain.ChangeCopy, Path: "new.txt", Size: 3},
+		{Action: domain.ChangeUpdate, Path: "changed.txt", Size: 7},
+		{Action: domain.ChangeDelete, Path: "extra.txt", Size: 5},
+	}, res.Plan.Changes)
+	require.Equal(t, int64(10), res.Plan.CopyBytes+res.Plan.UpdateBytes)
+	require.Zero(t, res.FilesTransferred)
+
+	// Nothing was changed.
+	_, err = os.Stat(filepath.Join(dstDir, "new.txt"))
+	require.True(t, os.IsNotExist(err))
+	_, err = os.Stat(filepath.Join(dstDir, "extra.txt"))
+	require.NoError(t, err)
+
+	// A copy deletes nothing.
+	res, err = e.Copy(ctx, srcDir, dstDir, &options.RcloneOptions{DryRun: true})
+	require.NoError(t, err)
+	require.Zero(t, res.Plan.Deletes)
+	require.Equal(t, int64(1), res.Plan.Copies)
+}
+
+func TestLibraryRcloneExecutor_Sync_Integration_RecordChanges(t *testing.T) {
+	srcDir := t.TempDir()
+	dstDir := t.TempDir()
+
+	require.NoError(t, os.WriteFile(filepath.Join(srcDir, "new.txt"), []byte("new"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(srcDir, "changed.PDF"), []byte("changed"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dstDir, "changed.PDF"), []byte("old"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dstDir, "extra.txt"), []byte("extra"), 0644))
+
+	e := &LibraryRcloneExecutor{}
+	res, err := e.Sync(context.Background(), srcDir, dstDir, &options.RcloneOptions{RecordChanges: true})
+	require.NoError(t, err)
+	require.Equal(t, &domain.ChangeStats{
+		Copies: 1, CopyBytes: 3, Updates: 1, UpdateBytes: 7, Deletes: 1, DeleteBytes: 5,
+		Extensions: map[string]int64{"txt": 1, "pdf": 1},
+	}, res.Changes)
+	require.NoFileExists(t, filepath.Join(dstDir, "extra.txt"))
+
+	// Without the option, changes are not counted.
+	res, err = e.Sync(context.Background(), srcDir, dstDir, nil)
+	require.NoError(t, err)
+	require.Nil(t, res.Changes)
+}
+
+func TestLibraryRcloneExecutor_Sync_Integration_Encryption(t *testing.T) {
+	srcDir := t.TempDir()
+	dstDir := t.TempDir()
+	restoreDir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(srcDir, "hr"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(srcDir, "hr", "salaries.txt"), []byte("confidential"), 0o644))
+
+	t.Setenv("BG_TEST_PASSWORD", "correct horse")
+	encryption := &domain.Encryption{PasswordEnv: "BG_TEST_PASSWORD"}
+	e := &LibraryRcloneExecutor{}
+	ctx := context.Background()
+
+	res, err := e.Sync(ctx, srcDir, dstDir, &options.RcloneOptions{Encryption: encryption})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), res.FilesTransferred)
+
+	// Names and contents are encrypted, next to the key-check file.
+	entries, err := os.ReadDir(dstDir)
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	require.FileExists(t, filepath.Join(dstDir, domain.KeyCheckFile))
+	_, err = os.Stat(filepath.Join(dstDir, "hr"))
+	require.True(t, os.IsNotExist(err))
+
+	// A second sync finds the key-check file and leaves it in place.
+	_, err = e.Sync(ctx, srcDir, dstDir, &options.RcloneOptions{Encryption: encryption})
+	require.NoError(t, err)
+	require.FileExists(t, filepath.Join(dstDir, domain.KeyCheckFile))
+
+	check, err := e.Check(ctx, srcDir, dstDir, &options.RcloneOptions{Encryption: encryption})
+	require.NoError(t, err)
+	require.True(t, check.Check.OK())
+	require.Equal(t, int64(1), check.Check.Matches)
+
+	_, err = e.Copy(ctx, dstDir, restoreDir, &options.RcloneOptions{SourceEncryption: encryption})
+	require.NoError(t, err)
+	content, err := os.ReadFile(filepath.Join(restoreDir, "hr", "salaries.txt"))
+	require.NoError(t, err)
+	require.Equal(t, "confidential", string(content))
+
+	// A wrong password is detected before anything is written.
+	t.Setenv("BG_TEST_PASSWORD", "wrong")
+	_, err = e.Sync(ctx, srcDir, dstDir, &options.RcloneOptions{Encryption: encryption})
+	require.ErrorContains(t, err, "wrong encryption password or salt")
+	entries, err = os.ReadDir(dstDir)
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+}
+
+func TestLibraryRcloneExecutor_Check_Integration(t *testing.T) {
+	srcDir := t.TempDir()
+	dstDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(srcDir, "same.txt"), []byte("same"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dstDir, "same.txt"), []byte("same"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(srcDir, "changed.txt"), []byte("new content"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dstDir, "changed.txt"), []byte("old content"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(srcDir, "missing.txt"), []byte("missing"), 0o644))
+
+	res, err := (&LibraryRcloneExecutor{}).Check(context.Background(), srcDir, dstDir, nil)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), res.Check.Matches)
+	require.Equal(t, []string{"changed.txt"}, res.Check.Differ)
+	require.Equal(t, []string{"missing.txt"}, res.Check.Missing)
+	require.Empty(t, res.Check.Errors)
+}
+
+func TestInstallRemotes_Integration(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "rclone.conf")
+	require.NoError(t, os.WriteFile(configPath, []byte("[file-remote]\ntype = memory\n"), 0600))
+	require.NoError(t, LoadRcloneConfig(configPath, ""))
+
+	root := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(root, "test.txt"), []byte("hello remote"), 0644))
+	passFile := filepath.Join(t.TempDir(), "pass")
+	require.NoError(t, os.WriteFile(passFile, []byte("s3cr3t-pass\n"), 0600))
+
+	err := InstallRemotes([]*domain.Remote{
+		{Name: "managed", Type: "alias", Parameters: map[string]string{"remote": root}},
+		{Name: "nas", Type: "sftp", Parameters: map[string]string{"host": "nas.local"}, SecretFiles: map[string]string{"pass": passFile}},
+	})
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	require.NoError(t, CheckRemote(ctx, "managed"))
+	res, err := (&LibraryRcloneExecutor{}).Copy(ctx, "managed:", t.TempDir(), nil)
+	require.NoError(t, err)
+	require.EqualValues(t, 1, res.FilesTransferred)
+
+	// Passwords are obscured, masked and never written to the config file.
+	obscured, ok := config.FileGetValue("nas", "pass")
+	require.True(t, ok)
+	require.Equal(t, "s3cr3t-pass", obscure.MustReveal(obscured))
+	require.Equal(t, "pass=***", redact.String("pass=s3cr3t-pass"))
+	require.Equal(t, "***", redact.String(obscured))
+	config.SaveConfig()
+	data, err := os.ReadFile(configPath)
+	require.NoError(t, err)
+	require.NotContains(t, string(data), "nas")
+	require.Equal(t, map[string]string{"host": "nas.local", "key_pem": "***", "pass": "*** (file " + passFile + ")"},
+		RemoteParameters(&domain.Remote{Type: "sftp", Parameters: map[string]string{"host": "nas.local", "key_pem": "-----BEGIN"}, SecretFiles: map[string]string{"pass": passFile}}))
+
+	// Installing again replaces the remotes.
+	require.NoError(t, InstallRemotes(nil))
+	require.Error(t, CheckRemote(ctx, "managed"))
+	require.True(t, config.LoadedData().HasSection("file-remote"))
+
+	require.ErrorContains(t, InstallRemotes([]*domain.Remote{{Name: "file-remote", Type: "memory"}}), "also defined in the rclone config file")
+	require.ErrorContains(t, InstallRemotes([]*domain.Remote{{Name: "x", Type: "nope"}}), "unknown type nope")
+	require.ErrorContains(t, InstallRemotes([]*domain.Remote{{Name: "x", Type: "sftp", Parameters: map[string]string{"hots": "nas"}}}), "backend sftp has no option hots")
+	require.ErrorContains(t, InstallRemotes([]*domain.Remote{{Name: "x", Type: "sftp", SecretEnv: map[string]string{"pass": "BG_TEST_UNSET_PASS"}}}), "environment variable BG_TEST_UNSET_PASS is not set")
+}
+
+func TestRcloneProber_Usage_Integration(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	usage, err := RcloneProber{}.Usage(ctx, dir)
+	require.NoError(t, err)
+	require.NotNil(t, usage)
+	require.Equal(t, dir, usage.Destination)
+	require.Positive(t, usage.Total)
+	require.Positive(t, usage.Free)
+
+	free, err := RcloneProber{}.FreeSpace(ctx, dir)
+	require.NoError(t, err)
+	require.Positive(t, free)
+
+	// The memory backend cannot tell its usage.
+	usage, err = RcloneProber{}.Usage(ctx, ":memory:bucket")
+	require.NoError(t, err)
+	require.Nil(t, usage)
+}
+
+func TestRcloneProber_VerifyCanaries_Integration(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "finance"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "finance", ".canary.txt"), []byte("test"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".canary.txt"), []byte("encrypted"), 0o644))
+
+	// sha256("test")
+	hash := "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"
+	results, err := RcloneProber{}.VerifyCanaries(ctx, dir, []*domain.Canary{
+		{Path: "finance/.canary.txt", SHA256: hash},
+		{Path: ".canary.txt", SHA256: hash},
+		{Path: "hr/.canary.txt", SHA256: hash},
+		{Path: "finance", SHA256: hash},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []domain.CanaryResult{
+		{Path: "finance/.canary.txt", State: domain.CanaryIntact},
+		{Path: ".canary.txt", State: domain.CanaryAltered},
+		{Path: "hr/.canary.txt", State: domain.CanaryMissing},
+		{Path: "finance", State: domain.CanaryMissing},
+	}, results)
+
+	// A source that is gone has no canary left.
+	results, err = RcloneProber{}.VerifyCanaries(ctx, filepath.Join(dir, "gone"), []*domain.Canary{{Path: ".canary.txt", SHA256: hash}})
+	require.NoError(t, err)
+	require.Equal(t, []domain.CanaryResult{{Path: ".canary.txt", State: domain.CanaryMissing}}, results)
+
+	_, err = RcloneProber{}.VerifyCanaries(ctx, filepath.Join(dir, ".canary.txt"), []*domain.Canary{{Path: "x", SHA256: hash}})
+	require.ErrorContains(t, err, "is a file")
+}
+
+func TestRcloneManifestStorage_Integration(t *testing.T) {
+	ctx := context.Background()
+	srcDir := t.TempDir()
+	dstDir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(srcDir, "docs"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(srcDir, "docs", "b.txt"), []byte("test"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(srcDir, "a.txt"), []byte("hello"), 0o644))
+
+	_, err := (&LibraryRcloneExecutor{}).Sync(ctx, srcDir, dstDir, nil)
+	require.NoError(t, err)
+
+	modTime := func(name string) time.Time {
+		info, err := os.Stat(filepath.Join(dstDir, name))
+		require.NoError(t, err)
+		return info.ModTime().UTC()
+	}
+
+	storage := RcloneManifestStorage{}
+	files, err := storage.ListFiles(ctx, dstDir, nil)
+	require.NoError(t, err)
+	require.Equal(t, []domain.ManifestFile{
+		{Path: "a.txt", Size: 5, Hash: "sha256:2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824", ModTime: modTime("a.txt")},
+		{Path: "docs/b.txt", Size: 4, Hash: "sha256:9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08", ModTime: modTime("docs/b.txt")},
+	}, files)
+
+	// Local files are hashed by reading them: the unchanged ones keep their previous hash.
+	previous := slices.Clone(files)
+	previous[0].Hash = "sha256:previous"
+	previous[1].Hash = "sha256:previous"
+	previous[1].Size = 3
+	listed, err := storage.ListFiles(ctx, dstDir, previous)
+	require.NoError(t, err)
+	require.Equal(t, "sha256:previous", listed[0].Hash)
+	require.Equal(t, files[1], listed[1])
+
+	manifest := domain.NewManifest(&domain.SyncRun{ID: "run-1", JobName: "job"}, nil,
+		[]domain.ManifestDestination{{Destination: dstDir, Entries: files}}, time.Now())
+	require.NoError(t, storage.PutManifest(ctx, dstDir, manifest))
+
+	// The copy is not listed, and a sync keeps it.
+	listed, err = storage.ListFiles(ctx, dstDir, nil)
+	require.NoError(t, err)
+	require.Equal(t, files, listed)
+	_, err = (&LibraryRcloneExecutor{}).Sync(ctx, srcDir, dstDir, nil)
+	require.NoError(t, err)
+	_, err = (&LibraryRcloneExecutor{}).Sync(ctx, srcDir, dstDir, &options.RcloneOptions{Filters: &domain.Filters{Include: []string{"*.txt"}}})
+	require.NoError(t, err)
+
+	copies, err := storage.ReadManifests(ctx, dstDir, "job")
+	require.NoError(t, err)
+	require.Len(t, copies, 1)
+	require.Equal(t, manifest.Hash, copies[0].Hash)
+	require.Equal(t, manifest.Hash, copies[0].ComputeHash())
+
+	copies, err = storage.ReadManifests(ctx, dstDir, "other-job")
+	require.NoError(t, err)
+	require.Empty(t, copies)
+
+	// Backends without SHA-256 list the hash they support.
+	_, err = (&LibraryRcloneExecutor{}).Sync(ctx, srcDir, ":memory:manifest", nil)
+	require.NoError(t, err)
+	files, err = storage.ListFiles(ctx, ":memory:manifest", nil)
+	require.NoError(t, err)
+	require.Len(t, files, 2)
+	require.Equal(t, "md5:5d41402abc4b2a76b9719d911017c592", files[0].Hash)
+	require.True(t, files[0].ModTime.IsZero())
+
+	// A destination not created yet has no file.
+	files, err = storage.ListFiles(ctx, filepath.Join(dstDir, "gone"), nil)
+	require.NoError(t, err)
+	require.Empty(t, files)
+}
+
+func TestRcloneScrubber_Integration(t *testing.T) {
+	ctx := context.Background()
+	srcDir := t.TempDir()
+	dstDir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(srcDir, "docs"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(srcDir, "a.txt"), []byte("hello"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(srcDir, "docs", "b.txt"), []byte("test"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(srcDir, "c.txt"), []byte("change me"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(srcDir, "d.txt"), []byte("delete me"), 0o644))
+
+	_, err := (&LibraryRcloneExecutor{}).Sync(ctx, srcDir, dstDir, nil)
+	require.NoError(t, err)
+
+	job := &domain.SyncJob{Name: "scrub", Source: srcDir, Destination: dstDir, Scrub: &domain.Scrub{Job: "job", Percent: 100}}
+	scrubber := RcloneScrubber{}
+	files, err := scrubber.ListFiles(ctx, job, dstDir)
+	require.NoError(t, err)
+	require.Equal(t, []domain.ScrubFile{{Path: "a.txt", Size: 5}, {Path: "c.txt", Size: 9}, {Path: "d.txt", Size: 9},
+		{Path: "docs/b.txt", Size: 4}}, files)
+
+	checks, err := scrubber.Scrub(ctx, job, dstDir, files, domain.ScrubAgainstSource)
+	require.NoError(t, err)
+	for _, check := range checks {
+		require.Equal(t, domain.ScrubMatched, check.Result, check.Path)
+	}
+
+	// Bit rot keeps the size and the modification time: only the content tells.
+	rotten := filepath.Join(dstDir, "a.txt")
+	info, err := os.Stat(rotten)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(rotten, []byte("jello"), 0o644))
+	require.NoError(t, os.Chtimes(rotten, info.ModTime(), info.ModTime()))
+	// Files changed or deleted in the source since the sync cannot be compared.
+	require.NoError(t, os.WriteFile(filepath.Join(srcDir, "c.txt"), []byte("changed!!"), 0o644))
+	require.NoError(t, os.Chtimes(filepath.Join(srcDir, "c.txt"), time.Now().Add(time.Hour), time.Now().Add(time.Hour)))
+	require.NoError(t, os.Remove(filepath.Join(srcDir, "d.txt")))
+
+	checks, err = scrubber.Scrub(ctx, job, dstDir, files, domain.ScrubAgainstSource)
+	require.NoError(t, err)
+	require.Len(t, checks, 4)
+	require.Equal(t, domain.ScrubMismatched, checks[0].Result)
+	require.Contains(t, checks[0].Reason, "content hashes to sha256:")
+	require.Equal(t, int64(5), checks[0].Size)
+	require.Equal(t, domain.ScrubCheck{Path: "c.txt", Result: domain.ScrubSkipped, Reason: "changed in the source since the last sync"}, checks[1])
+	require.Equal(t, domain.ScrubCheck{Path: "d.txt", Result: domain.ScrubSkipped, Reason: "not in the source anymore"}, checks[2])
+	require.Equal(t, domain.ScrubMatched, checks[3].Result)
+
+	t.Run("against a manifest", func(t *testing.T) {
+		entries, err := RcloneManifestStorage{}.ListFiles(ctx, dstDir, nil)
+		require.NoError(t, err)
+		files := make([]domain.ScrubFile, len(entries))
+		for i, entry := range entries {
+			files[i] = domain.ScrubFile{Path: entry.Path, Size: entry.Size, Hash: entry.Hash}
+		}
+
+		checks, err := scrubber.Scrub(ctx, job, dstDir, files, domain.ScrubAgainstManifest)
+		require.NoError(t, err)
+		for _, check := range checks {
+			require.Equal(t, domain.ScrubMatched, check.Result, check.Path)
+		}
+
+		require.NoError(t, os.WriteFile(filepath.Join(dstDir, "docs", "b.txt"), []byte("best"), 0o644))
+		require.NoError(t, os.Remove(filepath.Join(dstDir, "c.txt")))
+		checks, err = scrubber.Scrub(ctx, job, dstDir, files, domain.ScrubAgainstManifest)
+		require.NoError(t, err)
+		require.Equal(t, domain.ScrubCheck{Path: "c.txt", Result: domain.ScrubMismatched, Reason: "missing from the destination"}, checks[1])
+		require.Equal(t, domain.ScrubMismatched, checks[3].Result)
+		require.Contains(t, checks[3].Reason, "the manifest to sha256:")
+	})
+
+	t.Run("encrypted", func(t *testing.T) {
+		encDir := t.TempDir()
+		t.Setenv("BG_TEST_PASSWORD", "correct horse")
+		encrypted := &domain.SyncJob{Name: "scrub", Source: srcDir, Destination: encDir,
+			Encryption: &domain.Encryption{PasswordEnv: "BG_TEST_PASSWORD"}, Scrub: &domain.Scrub{Job: "job", Percent: 100}}
+		_, err := (&LibraryRcloneExecutor{}).Sync(ctx, srcDir, encDir, &options.RcloneOptions{Encryption: encrypted.Encryption})
+		require.NoError(t, err)
+
+		// Files are listed and compared decrypted.
+		files, err := scrubber.ListFiles(ctx, encrypted, encDir)
+		require.NoError(t, err)
+		require.Equal(t, []domain.ScrubFile{{Path: "a.txt", Size: 5}, {Path: "c.txt", Size: 9}, {Path: "docs/b.txt", Size: 4}}, files)
+		checks, err := scrubber.Scrub(ctx, encrypted, encDir, files, domain.ScrubAgainstSource)
+		require.NoError(t, err)
+		for _, check := range checks {
+			require.Equal(t, domain.ScrubMatched, check.Result, check.Path)
+		}
+	})
+}
@@ -0,0 +1,179 @@
+package runner
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/rclone/rclone/fs"
+	"github.com/rclone/rclone/fs/hash"
+	"github.com/rclone/rclone/fs/object"
+	"github.com/rclone/rclone/fs/walk"
+
+	"github.com/eva01/backup-guardian/domain"
+	"github.com/eva01/backup-guardian/runlog"
+)
+
+// RcloneManifestStorage lists destinations and keeps the copies of manifests on them with the
+// rclone library. Encrypted destinations are listed and written as stored, without decrypting
+// them. It implements ManifestStorage.
+type RcloneManifestStorage struct{}
+
+// ListFiles lists the files of dest with their hashes, sorted by path. Files are hashed with
+// SHA-256 when the backend supports it, with the hash it supports otherwise, and downloaded
+// and hashed with SHA-256 when it supports none. When hashing reads the files, as on local
+// and sftp destinations or without a hash, their modification times are listed too, and the
+// files of previous with the same size and modification time keep their hash unread.
+func (RcloneManifestStorage) ListFiles(ctx context.Context, dest string, previous []domain.ManifestFile) ([]domain.ManifestFile, error) {
+	defer runlog.StartRclone(ctx)()
+
+	f, err := fs.NewFs(ctx, dest)
+	if err != nil {
+		return nil, err
+	}
+
+	hashType := hash.SHA256
+	if hashes := f.Hashes(); !hashes.Contains(hash.SHA256) {
+		hashType = hashes.GetOne()
+	}
+	slowHash := hashType == hash.None || f.Features().SlowHash
+	known := make(map[string]domain.ManifestFile, len(previous))
+	for _, file := range previous {
+		known[file.Path] = file
+	}
+
+	var files []domain.ManifestFile
+	err = walk.ListR(ctx, f, "", true, -1, walk.ListObjects, func(entries fs.DirEntries) error {
+		for _, entry := range entries {
+			obj, ok := entry.(fs.Object)
+			if !ok || domain.Reserved(obj.Remote()) {
+				continue
+			}
+			file := domain.ManifestFile{Path: obj.Remote(), Size: obj.Size()}
+			if slowHash {
+				file.ModTime = obj.ModTime(ctx).UTC()
+				if prev, ok := known[file.Path]; ok && prev.Size == file.Size && !prev.ModTime.IsZero() && prev.ModTime.Equal(file.ModTime) {
+					file.Hash = prev.Hash
+				}
+			}
+			if file.Hash == "" {
+				sum, err := objectHash(ctx, obj, hashType)
+				if err != nil {
+					return fmt.Errorf("could not hash %s: %w", obj.Remote(), err)
+				}
+				file.Hash = sum
+			}
+			files = append(files, file)
+		}
+		return nil
+	})
+	if errors.Is(err, fs.ErrorDirNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	slices.SortFunc(files, func(a, b domain.ManifestFile) int { return strings.Compare(a.Path, b.Path) })
+
+	return files, nil
+}
+
+// objectHash returns the hash of obj prefixed with its type, computing its SHA-256 hash when
+// the backend has no hash of type hashType for it.
+func objectHash(ctx context.Context, obj fs.Object, hashType hash.Type) (string, error) {
+	if hashType != hash.None {
+		sum, err := obj.Hash(ctx, hashType)
+		if err != nil && !errors.Is(err, hash.ErrUnsupported) {
+			return "", err
+		}
+		if sum != "" {
+			return hashType.String() + ":" + sum, nil
+		}
+	}
+
+	in, err := obj.Open(ctx)
+	if err != nil {
+		return "", err
+	}
+	defer in.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, in); err != nil {
+		return "", err
+	}
+
+	return hash.SHA256.String() + ":" + hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// PutManifest writes a copy of manifest on dest, as JSON.
+func (RcloneManifestStorage) PutManifest(ctx context.Context, dest string, manifest *domain.Manifest) error {
+	defer runlog.StartRclone(ctx)()
+
+	f, err := fs.NewFs(ctx, dest)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(manifest)
+	if err != nil {
+		return err
+	}
+	info := object.NewStaticObjectInfo(manifest.ObjectName(), time.Now(), int64(len(data)), true, nil, f)
+	_, err = f.Put(ctx, bytes.NewReader(data), info)
+
+	return err
+}
+
+// ReadManifests reads the copies of the manifests of the job jobName on dest. A copy that is
+// not a manifest is an error.
+func (RcloneManifestStorage) ReadManifests(ctx context.Context, dest, jobName string) ([]*domain.Manifest, error) {
+	defer runlog.StartRclone(ctx)()
+
+	f, err := fs.NewFs(ctx, dest)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := f.List(ctx, path.Join(domain.ManifestDir, jobName))
+	if errors.Is(err, fs.ErrorDirNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var manifests []*domain.Manifest
+	for _, entry := range entries {
+		obj, ok := entry.(fs.Object)
+		if !ok || !strings.HasSuffix(obj.Remote(), ".json") {
+			continue
+		}
+
+		in, err := obj.Open(ctx)
+		if err != nil {
+			return nil, err
+		}
+		data, err := io.ReadAll(in)
+		in.Close()
+		if err != nil {
+			return nil, err
+		}
+
+		manifest := &domain.Manifest{}
+		if err := json.Unmarshal(data, manifest); err != nil {
+			return nil, fmt.Errorf("%s is not a manifest: %w", obj.Remote(), err)
+		}
+		manifests = append(manifests, manifest)
+	}
+
+	return manifests, nil
+}
@@ -0,0 +1,96 @@
+package runner
+
+import (
+	"context"
+	"sync"
+
+	"github.com/rclone/rclone/fs"
+	"github.com/rclone/rclone/fs/operations"
+
+	"github.com/eva01/backup-guardian/domain"
+)
+
+// reportedChange returns the change reported to rclone's sync logger, and false for
+// directories, errors and files left as they are.
+func reportedChange(ctx context.Context, sigil operations.Sigil, src, dst fs.DirEntry, err error) (domain.PlannedChange, bool) {
+	if err != nil {
+		return domain.PlannedChange{}, false
+	}
+
+	switch sigil {
+	case operations.MissingOnDst:
+		return domain.PlannedChange{Action: domain.ChangeCopy, Path: src.Remote(), Size: src.Size()}, true
+	case operations.Differ:
+		return domain.PlannedChange{Action: domain.ChangeUpdate, Path: src.Remote(), Size: src.Size()}, true
+	case operations.MissingOnSrc:
+		// Copies and moves report the files missing from the source without deleting them.
+		if operations.GetLoggerOpt(ctx).DeleteModeOff {
+			return domain.PlannedChange{}, false
+		}
+		return domain.PlannedChange{Action: domain.ChangeDelete, Path: dst.Remote(), Size: dst.Size()}, true
+	default:
+		return domain.PlannedChange{}, false
+	}
+}
+
+// planRecorder records the changes planned by a dry run, reported by rclone's sync logger.
+type planRecorder struct {
+	mu   sync.Mutex
+	plan domain.SyncPlan
+}
+
+// record is the rclone sync logger of a dry run.
+func (p *planRecorder) record(ctx context.Context, sigil operations.Sigil, src, dst fs.DirEntry, err error) {
+	change, ok := reportedChange(ctx, sigil, src, dst, err)
+	if !ok {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.plan.Add(change)
+}
+
+// result returns a copy of the recorded plan.
+func (p *planRecorder) result() *domain.SyncPlan {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	plan := p.plan
+	plan.Changes = append([]domain.PlannedChange(nil), p.plan.Changes...)
+
+	return &plan
+}
+
+// changeRecorder counts the changes made by an operation, reported by rclone's sync logger.
+// Failed transfers are reported as errors, and not counted.
+type changeRecorder struct {
+	mu    sync.Mutex
+	stats domain.ChangeStats
+}
+
+// record is the rclone sync logger of an operation recording its changes.
+func (c *changeRecorder) record(ctx context.Context, sigil operations.Sigil, src, dst fs.DirEntry, err error) {
+	change, ok := reportedChange(ctx, sigil, src, dst, err)
+	if !ok {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.stats.Add(change)
+}
+
+// result returns a copy of the recorded statistics.
+func (c *changeRecorder) result() *domain.ChangeStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	stats := c.stats
+	stats.Extensions = map[string]int64{}
+	for ext, count := range c.stats.Extensions {
+		stats.Extensions[ext] = count
+	}
+
+	return &stats
+}
@@ -0,0 +1,203 @@
+package runner
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rclone/rclone/fs"
+	"github.com/rclone/rclone/fs/object"
+
+	"github.com/eva01/backup-guardian/domain"
+	"github.com/eva01/backup-guardian/runlog"
+)
+
+// probeFile prefixes the names of the objects written by write probes.
+const probeFile = ".backup-guardian-probe-"
+
+// probeText is the content of the objects written by write probes.
+const probeText = "backup-guardian write probe, safe to delete\n"
+
+// RcloneProber probes the storage of jobs with the rclone library. It implements
+// doctor.Prober.
+type RcloneProber struct{}
+
+// CheckRemote lists the root of the remote named name.
+func (RcloneProber) CheckRemote(ctx context.Context, name string) error {
+	defer runlog.StartRclone(ctx)()
+
+	return CheckRemote(ctx, name)
+}
+
+// CheckRead lists the directory at path. A path naming a file is readable when it exists.
+func (RcloneProber) CheckRead(ctx context.Context, path string) error {
+	defer runlog.StartRclone(ctx)()
+
+	f, err := fs.NewFs(ctx, path)
+	if errors.Is(err, fs.ErrorIsFile) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	_, err = f.List(ctx, "")
+	if errors.Is(err, fs.ErrorDirNotFound) {
+		return fmt.Errorf("%s does not exist", path)
+	}
+
+	return err
+}
+
+// CheckWrite writes a small object in the directory at path, and deletes it. A directory
+// created for the probe is removed afterwards.
+func (RcloneProber) CheckWrite(ctx context.Context, path string) error {
+	defer runlog.StartRclone(ctx)()
+
+	f, err := fs.NewFs(ctx, path)
+	if err != nil {
+		return err
+	}
+
+	_, err = f.List(ctx, "")
+	created := errors.Is(err, fs.ErrorDirNotFound)
+	if err := f.Mkdir(ctx, ""); err != nil {
+		return fmt.Errorf("could not create directory: %w", err)
+	}
+
+	name := probeFile + uuid.NewString()
+	info := object.NewStaticObjectInfo(name, time.Now(), int64(len(probeText)), true, nil, f)
+	obj, err := f.Put(ctx, bytes.NewBufferString(probeText), info)
+	if err != nil {
+		return fmt.Errorf("could not write %s: %w", name, err)
+	}
+	if err := obj.Remove(ctx); err != nil {
+		return fmt.Errorf("could not delete %s: %w", name, err)
+	}
+
+	if created {
+		// Best effort: the first sync creates the directory anyway.
+		_ = f.Rmdir(ctx, "")
+	}
+
+	return nil
+}
+
+// CheckEncryption checks that the password and salt of the encrypted destination at path can
+// be read, and that they match the key of the files already there.
+func (RcloneProber) CheckEncryption(ctx context.Context, path string, encryption *domain.Encryption) error {
+	defer runlog.StartRclone(ctx)()
+
+	_, err := encryptedFs(ctx, path, encryption, false)
+	return err
+}
+
+// FreeSpace returns the free space of the storage of path, or -1 when its backend cannot
+// tell.
+func (p RcloneProber) FreeSpace(ctx context.Context, path string) (int64, error) {
+	usage, err := p.Usage(ctx, path)
+	if err != nil {
+		return 0, err
+	}
+	if usage == nil {
+		return -1, nil
+	}
+
+	return usage.Free, nil
+}
+
+// Usage returns the usage of the storage of path, with rclone's about, or nil when its
+// backend does not support it.
+func (RcloneProber) Usage(ctx context.Context, path string) (*domain.DestinationUsage, error) {
+	defer runlog.StartRclone(ctx)()
+
+	f, err := fs.NewFs(ctx, path)
+	if err != nil && !errors.Is(err, fs.ErrorIsFile) {
+		return nil, err
+	}
+
+	about := f.Features().About
+	if about == nil {
+		return nil, nil
+	}
+	usage, err := about(ctx)
+	if errors.Is(err, fs.ErrorNotImplemented) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	result := &domain.DestinationUsage{Destination: path, Total: -1, Used: -1, Free: -1, CheckedAt: time.Now()}
+	if usage.Total != nil {
+		result.Total = *usage.Total
+	}
+	if usage.Used != nil {
+		result.Used = *usage.Used
+	}
+	if usage.Free != nil {
+		result.Free = *usage.Free
+	}
+
+	return result, nil
+}
+
+// VerifyCanaries reads each canary of source to the end and compares the SHA-256 hash of its
+// content with the expected one. The hash is computed locally, as backends hashing with other
+// algorithms cannot be trusted to detect an alteration.
+func (RcloneProber) VerifyCanaries(ctx context.Context, source string, canaries []*domain.Canary) ([]domain.CanaryResult, error) {
+	defer runlog.StartRclone(ctx)()
+
+	f, err := fs.NewFs(ctx, source)
+	if errors.Is(err, fs.ErrorIsFile) {
+		return nil, fmt.Errorf("%s is a file, canaries need a directory", source)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]domain.CanaryResult, 0, len(canaries))
+	for _, canary := range canaries {
+		state, err := verifyCanary(ctx, f, canary)
+		if err != nil {
+			return nil, fmt.Errorf("could not read canary %s: %w", canary.Path, err)
+		}
+		results = append(results, domain.CanaryResult{Path: canary.Path, State: state})
+	}
+
+	return results, nil
+}
+
+// verifyCanary returns the state of canary in f.
+func verifyCanary(ctx context.Context, f fs.Fs, canary *domain.Canary) (string, error) {
+	obj, err := f.NewObject(ctx, canary.Path)
+	if errors.Is(err, fs.ErrorObjectNotFound) || errors.Is(err, fs.ErrorIsDir) || errors.Is(err, fs.ErrorDirNotFound) {
+		return domain.CanaryMissing, nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	in, err := obj.Open(ctx)
+	if err != nil {
+		return "", err
+	}
+	defer in.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, in); err != nil {
+		return "", err
+	}
+	if !strings.EqualFold(hex.EncodeToString(hash.Sum(nil)), canary.SHA256) {
+		return domain.CanaryAltered, nil
+	}
+
+	return domain.CanaryIntact, nil
+}
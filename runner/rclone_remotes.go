@@ -0,0 +1,294 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
+
+	"github.com/rclone/rclone/fs"
+	"github.com/rclone/rclone/fs/cache"
+	"github.com/rclone/rclone/fs/config"
+	"github.com/rclone/rclone/fs/config/obscure"
+
+	"github.com/eva01/backup-guardian/domain"
+	"github.com/eva01/backup-guardian/internal/redact"
+)
+
+// InstallRemotes injects remotes into rclone's configuration, replacing the remotes installed
+// before. Their parameters are checked against the options of their backend, secrets are read
+// from their files and environment variables, and passwords are obscured as rclone expects.
+// Remotes are kept in memory over the sections of the rclone config file, and never written
+// to it: call LoadRcloneConfig first.
+func InstallRemotes(remotes []*domain.Remote) error {
+	base := config.Data()
+	previous := map[string]map[string]string{}
+	if storage, ok := base.(*remoteStorage); ok {
+		base, previous = storage.Storage, storage.remotes
+	}
+
+	sections := make(map[string]map[string]string, len(remotes))
+	for _, remote := range remotes {
+		if base.HasSection(remote.Name) {
+			return fmt.Errorf("remote %s is also defined in the rclone config file %s", remote.Name, config.GetConfigPath())
+		}
+		if err := ValidateRemote(remote); err != nil {
+			return err
+		}
+		values, err := remoteConfig(remote)
+		if err != nil {
+			return fmt.Errorf("remote %s: %w", remote.Name, err)
+		}
+		sections[remote.Name] = values
+	}
+
+	storage := &remoteStorage{Storage: base, remotes: sections}
+	config.SetData(storage)
+	if config.Data() != storage {
+		return errors.New("rclone config has no file: remotes cannot be installed")
+	}
+	config.LoadedData()
+
+	// Drop the backends created with the previous definitions.
+	for name := range previous {
+		cache.ClearConfig(name)
+	}
+	for name := range sections {
+		cache.ClearConfig(name)
+	}
+
+	return nil
+}
+
+// ValidateRemote checks that the type of remote is an rclone backend, and that its parameters
+// are options of that backend. Secrets are not read.
+func ValidateRemote(remote *domain.Remote) error {
+	if err := remote.Validate(); err != nil {
+		return err
+	}
+
+	info, err := fs.Find(remote.Type)
+	if err != nil {
+		return fmt.Errorf("remote %s: unknown type %s", remote.Name, remote.Type)
+	}
+	for _, options := range []map[string]string{remote.Parameters, remote.SecretFiles, remote.SecretEnv} {
+		for key := range options {
+			if findOption(info, key) == nil {
+				return fmt.Errorf("remote %s: backend %s has no option %s", remote.Name, remote.Type, key)
+			}
+		}
+	}
+
+	return nil
+}
+
+// CheckRemote checks that the remote named name can be reached, by listing its root. A missing
+// root is not an error: it is created by the first sync.
+func CheckRemote(ctx context.Context, name string) error {
+	f, err := fs.NewFs(ctx, name+":")
+	if err != nil {
+		return err
+	}
+
+	_, err = f.List(ctx, "")
+	if errors.Is(err, fs.ErrorDirNotFound) {
+		return nil
+	}
+
+	return err
+}
+
+// RemoteParameters returns the parameters of remote for display: the values of secret options
+// are masked, and secrets read from files or environment variables are
+// shown by their origin.
+func RemoteParameters(remote *domain.Remote) map[string]string {
+	info, _ := fs.Find(remote.Type)
+
+	result := make(map[string]string, len(remote.Parameters)+len(remote.SecretFiles)+len(remote.SecretEnv))
+	for key, value := range remote.Parameters {
+		if option := findOption(info, key); (option != nil && secretOption(option)) || redact.SensitiveName(key) {
+			value = redact.Mask
+		}
+		result[key] = value
+	}
+	for key, file := range remote.SecretFiles {
+		result[key] = redact.Mask + " (file " + file + ")"
+	}
+	for key, env := range remote.SecretEnv {
+		result[key] = redact.Mask + " ($" + env + ")"
+	}
+
+	return result
+}
+
+// remoteConfig returns the rclone config section of remote, a valid remote, registering its
+// secrets for redaction.
+func remoteConfig(remote *domain.Remote) (map[string]string, error) {
+	info, err := fs.Find(remote.Type)
+	if err != nil {
+		return nil, err
+	}
+
+	values := map[string]string{"type": remote.Type}
+	set := func(key, value string, secret bool) error {
+		option := findOption(info, key)
+		if secret || secretOption(option) {
+			redact.Add(value)
+		}
+		if option.IsPassword {
+			obscured, err := obscure.Obscure(value)
+			if err != nil {
+				return fmt.Errorf("could not obscure %s: %w", key, err)
+			}
+			redact.Add(obscured)
+			value = obscured
+		}
+		values[key] = value
+		return nil
+	}
+
+	for key, value := range remote.Parameters {
+		if err := set(key, value, false); err != nil {
+			return nil, err
+		}
+	}
+	for key, file := range remote.SecretFiles {
+		value, err := readSecret(file, "")
+		if err != nil {
+			return nil, fmt.Errorf("could not read %s: %w", key, err)
+		}
+		if err := set(key, value, true); err != nil {
+			return nil, err
+		}
+	}
+	for key, env := range remote.SecretEnv {
+		value, err := readSecret("", env)
+		if err != nil {
+			return nil, fmt.Errorf("could not read %s: %w", key, err)
+		}
+		if err := set(key, value, true); err != nil {
+			return nil, err
+		}
+	}
+
+	return values, nil
+}
+
+// findOption returns the option named name of the backend described by info, or nil.
+func findOption(info *fs.RegInfo, name string) *fs.Option {
+	if info == nil {
+		return nil
+	}
+	for i := range info.Options {
+		if info.Options[i].Name == name {
+			return &info.Options[i]
+		}
+	}
+
+	return nil
+}
+
+// secretOption reports whether option holds a secret: passwords, and the sensitive options with
+// a secret name, such as tokens or access keys. Other sensitive options, such as hosts or user
+// names, identify an account without giving access to it, and are left in clear.
+func secretOption(option *fs.Option) bool {
+	return option.IsPassword || (option.Sensitive && redact.SensitiveName(option.Name))
+}
+
+// remoteStorage is the rclone config storage with the managed remotes laid over the sections
+// of the rclone config file. Only the sections of the file are loaded and saved, so managed
+// remotes are never written to it, even when rclone saves a refreshed token: such updates
+// only last until the runner stops.
+type remoteStorage struct {
+	config.Storage
+
+	mu      sync.RWMutex
+	remotes map[string]map[string]string
+}
+
+var _ config.Storage = (*remoteStorage)(nil)
+
+// GetSectionList implements config.Storage.
+func (s *remoteStorage) GetSectionList() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return append(s.Storage.GetSectionList(), slices.Sorted(maps.Keys(s.remotes))...)
+}
+
+// HasSection implements config.Storage.
+func (s *remoteStorage) HasSection(section string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if _, ok := s.remotes[section]; ok {
+		return true
+	}
+
+	return s.Storage.HasSection(section)
+}
+
+// DeleteSection implements config.Storage.
+func (s *remoteStorage) DeleteSection(section string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.remotes[section]; ok {
+		delete(s.remotes, section)
+		return
+	}
+	s.Storage.DeleteSection(section)
+}
+
+// GetKeyList implements config.Storage.
+func (s *remoteStorage) GetKeyList(section string) []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if values, ok := s.remotes[section]; ok {
+		return slices.Sorted(maps.Keys(values))
+	}
+
+	return s.Storage.GetKeyList(section)
+}
+
+// GetValue implements config.Storage.
+func (s *remoteStorage) GetValue(section string, key string) (string, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if values, ok := s.remotes[section]; ok {
+		value, found := values[key]
+		return value, found
+	}
+
+	return s.Storage.GetValue(section, key)
+}
+
+// SetValue implements config.Storage.
+func (s *remoteStorage) SetValue(section string, key string, value string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if values, ok := s.remotes[section]; ok {
+		values[key] = value
+		return
+	}
+	s.Storage.SetValue(section, key, value)
+}
+
+// DeleteKey implements config.Storage.
+func (s *remoteStorage) DeleteKey(section string, key string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if values, ok := s.remotes[section]; ok {
+		_, found := values[key]
+		delete(values, key)
+		return found
+	}
+
+	return s.Storage.DeleteKey(section, key)
+}
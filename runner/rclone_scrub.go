@@ -0,0 +1,202 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/rclone/rclone/fs"
+	"github.com/rclone/rclone/fs/hash"
+	"github.com/rclone/rclone/fs/walk"
+
+	"github.com/eva01/backup-guardian/domain"
+	"github.com/eva01/backup-guardian/runlog"
+)
+
+// RcloneScrubber downloads and checks the files of destinations with the rclone library. It
+// implements Scrubber.
+type RcloneScrubber struct{}
+
+// ListFiles lists the files of dest, decrypted with the encryption of job, sorted by path.
+func (RcloneScrubber) ListFiles(ctx context.Context, job *domain.SyncJob, dest string) ([]domain.ScrubFile, error) {
+	defer runlog.StartRclone(ctx)()
+
+	f, err := scrubFs(ctx, job, dest, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []domain.ScrubFile
+	err = walk.ListR(ctx, f, "", true, -1, walk.ListObjects, func(entries fs.DirEntries) error {
+		for _, entry := range entries {
+			if obj, ok := entry.(fs.Object); ok && !domain.Reserved(obj.Remote()) {
+				files = append(files, domain.ScrubFile{Path: obj.Remote(), Size: obj.Size()})
+			}
+		}
+		return nil
+	})
+	if errors.Is(err, fs.ErrorDirNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	slices.SortFunc(files, func(a, b domain.ScrubFile) int { return strings.Compare(a.Path, b.Path) })
+
+	return files, nil
+}
+
+// Scrub downloads files from dest and hashes them. Against the source, the files are decrypted
+// and compared with the SHA-256 hashes of the files of the source, skipping files changed in
+// the source since they were synced. Against a manifest, the files are hashed as stored with
+// the hash type of the manifest. A file that cannot be downloaded is a mismatch.
+func (RcloneScrubber) Scrub(ctx context.Context, job *domain.SyncJob, dest string, files []domain.ScrubFile, against string) ([]domain.ScrubCheck, error) {
+	defer runlog.StartRclone(ctx)()
+
+	againstSource := against == domain.ScrubAgainstSource
+	f, err := scrubFs(ctx, job, dest, againstSource)
+	if err != nil {
+		return nil, err
+	}
+	var src fs.Fs
+	if againstSource {
+		if src, err = fs.NewFs(ctx, job.Source); err != nil {
+			return nil, err
+		}
+	}
+
+	checks := make([]domain.ScrubCheck, 0, len(files))
+	for _, file := range files {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		var check domain.ScrubCheck
+		if againstSource {
+			check = scrubAgainstSource(ctx, src, f, file)
+		} else {
+			check = scrubAgainstManifest(ctx, f, file)
+		}
+		checks = append(checks, check)
+	}
+
+	return checks, nil
+}
+
+// scrubFs returns the Fs of dest, wrapped with the encryption of job when decrypt is true.
+func scrubFs(ctx context.Context, job *domain.SyncJob, dest string, decrypt bool) (fs.Fs, error) {
+	if decrypt && job.Encryption != nil {
+		return encryptedFs(ctx, dest, job.Encryption, false)
+	}
+
+	return fs.NewFs(ctx, dest)
+}
+
+// scrubAgainstSource checks file of f against its counterpart in src.
+func scrubAgainstSource(ctx context.Context, src, f fs.Fs, file domain.ScrubFile) domain.ScrubCheck {
+	check := domain.ScrubCheck{Path: file.Path}
+
+	srcObj, err := src.NewObject(ctx, file.Path)
+	if errors.Is(err, fs.ErrorObjectNotFound) {
+		check.Result, check.Reason = domain.ScrubSkipped, "not in the source anymore"
+		return check
+	}
+	if err != nil {
+		check.Result, check.Reason = domain.ScrubSkipped, fmt.Sprintf("could not read the source: %v", err)
+		return check
+	}
+
+	obj, err := f.NewObject(ctx, file.Path)
+	if err != nil {
+		check.Result, check.Reason = domain.ScrubMismatched, fmt.Sprintf("could not be read: %v", err)
+		return check
+	}
+	// A file modified in the source since the last sync differs for a good reason.
+	if dt := srcObj.ModTime(ctx).Sub(obj.ModTime(ctx)).Abs(); dt > fs.GetModifyWindow(ctx, src, f) {
+		check.Result, check.Reason = domain.ScrubSkipped, "changed in the source since the last sync"
+		return check
+	}
+
+	sum, size, err := downloadHash(ctx, obj, hash.SHA256)
+	check.Size = size
+	if err != nil {
+		check.Result, check.Reason = domain.ScrubMismatched, fmt.Sprintf("could not be read: %v", err)
+		return check
+	}
+	expected, err := objectHash(ctx, srcObj, hash.SHA256)
+	if err != nil {
+		check.Result, check.Reason = domain.ScrubSkipped, fmt.Sprintf("could not hash the source: %v", err)
+		return check
+	}
+
+	check.Result = domain.ScrubMatched
+	if sum != expected {
+		check.Result, check.Reason = domain.ScrubMismatched, fmt.Sprintf("content hashes to %s, the source to %s", sum, expected)
+	}
+
+	return check
+}
+
+// scrubAgainstManifest checks file of f, as stored, against its hash in the manifest.
+func scrubAgainstManifest(ctx context.Context, f fs.Fs, file domain.ScrubFile) domain.ScrubCheck {
+	check := domain.ScrubCheck{Path: file.Path}
+
+	name, _, _ := strings.Cut(file.Hash, ":")
+	var hashType hash.Type
+	if err := hashType.Set(name); err != nil || hashType == hash.None {
+		check.Result, check.Reason = domain.ScrubSkipped, fmt.Sprintf("unknown hash %q in the manifest", file.Hash)
+		return check
+	}
+
+	obj, err := f.NewObject(ctx, file.Path)
+	if errors.Is(err, fs.ErrorObjectNotFound) {
+		check.Result, check.Reason = domain.ScrubMismatched, "missing from the destination"
+		return check
+	}
+	if err != nil {
+		check.Result, check.Reason = domain.ScrubMismatched, fmt.Sprintf("could not be read: %v", err)
+		return check
+	}
+
+	sum, size, err := downloadHash(ctx, obj, hashType)
+	check.Size = size
+	if err != nil {
+		check.Result, check.Reason = domain.ScrubMismatched, fmt.Sprintf("could not be read: %v", err)
+		return check
+	}
+
+	check.Result = domain.ScrubMatched
+	if sum != file.Hash {
+		check.Result, check.Reason = domain.ScrubMismatched, fmt.Sprintf("content hashes to %s, the manifest to %s", sum, file.Hash)
+	}
+
+	return check
+}
+
+// downloadHash downloads obj and returns its hash of type hashType, prefixed with its type,
+// and the number of bytes read. It never uses the hashes the backend stores.
+func downloadHash(ctx context.Context, obj fs.Object, hashType hash.Type) (string, int64, error) {
+	hasher, err := hash.NewMultiHasherTypes(hash.NewHashSet(hashType))
+	if err != nil {
+		return "", 0, err
+	}
+
+	in, err := obj.Open(ctx)
+	if err != nil {
+		return "", 0, err
+	}
+	defer in.Close()
+
+	if _, err := io.Copy(hasher, in); err != nil {
+		return "", hasher.Size(), err
+	}
+	sum, err := hasher.SumString(hashType, false)
+	if err != nil {
+		return "", hasher.Size(), err
+	}
+
+	return hashType.String() + ":" + sum, hasher.Size(), nil
+}
@@ -0,0 +1,81 @@
+package runner
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+
+	"github.com/eva01/backup-guardian/domain"
+	"github.com/eva01/backup-guardian/internal/errors"
+	"github.com/eva01/backup-guardian/runner/options"
+	"github.com/eva01/backup-guardian/runner/result"
+)
+
+// Restore copies the files of dest, a destination of job, to target, decrypting them when the
+// job is encrypted. An empty dest uses the first destination of the job. Restores are not
+// recorded as runs, and run next to the runner loop.
+func (r *Runner) Restore(ctx context.Context, job *domain.SyncJob, dest, target string) (*result.RcloneResult, error) {
+	dest, err := jobDestination(job, dest)
+	if err != nil {
+		return nil, err
+	}
+
+	attrs := []any{slog.String("job", job.Name), slog.String("from", dest), slog.String("to", target)}
+	r.logger.Info("Starting restore", attrs...)
+
+	res, err := r.executor.Copy(ctx, dest, target, &options.RcloneOptions{SourceEncryption: job.Encryption})
+	if err != nil {
+		r.logger.Error("Restore failed", append(attrs, slog.Any("error", err))...)
+		return res, err
+	}
+
+	r.logger.Info("Restore completed", append(attrs, slog.Int64("files", res.FilesTransferred),
+		slog.Int64("bytes", res.BytesTransferred), slog.Duration("duration", res.Duration))...)
+	return res, nil
+}
+
+// Verify compares dest, a destination of job, with the source of the job, decrypting dest
+// when the job is encrypted, and returns the differences found. An empty dest uses the first
+// destination of the job.
+func (r *Runner) Verify(ctx context.Context, job *domain.SyncJob, dest string) (*result.CheckResult, error) {
+	dest, err := jobDestination(job, dest)
+	if err != nil {
+		return nil, err
+	}
+
+	attrs := []any{slog.String("job", job.Name), slog.String("dest", dest)}
+	r.logger.Info("Starting verification", attrs...)
+
+	opts := &options.RcloneOptions{Filters: job.Filters, Encryption: job.Encryption}
+	res, err := r.executor.Check(ctx, job.Source, dest, opts)
+	if err != nil {
+		r.logger.Error("Verification failed", append(attrs, slog.Any("error", err))...)
+		return nil, err
+	}
+
+	check := res.Check
+	attrs = append(attrs, slog.Int64("matches", check.Matches), slog.Int("differ", len(check.Differ)),
+		slog.Int("missing", len(check.Missing)), slog.Int("errors", len(check.Errors)))
+	if !check.OK() {
+		r.logger.Warn("Verification found differences", attrs...)
+		return check, nil
+	}
+	r.logger.Info("Verification completed", attrs...)
+
+	return check, nil
+}
+
+// jobDestination returns dest when it is a destination of job, or the first destination of job
+// when dest is empty.
+func jobDestination(job *domain.SyncJob, dest string) (string, error) {
+	destinations := job.AllDestinations()
+	if dest == "" {
+		return destinations[0], nil
+	}
+	if !slices.Contains(destinations, dest) {
+		return "", &errors.Error{Code: errors.CodeInvalid, Message: fmt.Sprintf("%s is not a destination of job %s", dest, job.Name)}
+	}
+
+	return dest, nil
+}
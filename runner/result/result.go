@@ -1,10 +1,42 @@
 package result
 
-import "time"
+import (
+	"time"
+
+	"github.com/eva01/backup-guardian/domain"
+)
 
 // RcloneResult holds the result of an rclone sync operation.
 type RcloneResult struct {
 	FilesTransferred int64
 	BytesTransferred int64
-	Duration         time.Duration
+	// FilesTotal and BytesTotal are the amounts that were to transfer, as known when the
+	// sync ended.
+	FilesTotal int64
+	BytesTotal int64
+	Duration   time.Duration
+	// Stopped reports that the sync was stopped at RcloneOptions.StopAt before completing.
+	Stopped bool
+	// Plan is the changes planned by a dry run. Nil for other operations.
+	Plan *domain.SyncPlan
+	// Changes counts the changes made, when RcloneOptions.RecordChanges is set. Nil otherwise.
+	Changes *domain.ChangeStats
+	// Check is the outcome of a check. Nil for other operations.
+	Check *CheckResult
+}
+
+// CheckResult is the outcome of the comparison of a destination with its source.
+type CheckResult struct {
+	// Matches counts the files found identical.
+	Matches int64
+	// Differ, Missing and Errors are the paths of the files that differ, are missing from the
+	// destination, or could not be compared.
+	Differ  []string
+	Missing []string
+	Errors  []string
+}
+
+// OK reports whether the check found no difference and no error.
+func (c *CheckResult) OK() bool {
+	return len(c.Differ) == 0 && len(c.Missing) == 0 && len(c.Errors) == 0
 }
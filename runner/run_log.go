@@ -0,0 +1,19 @@
+package runner
+
+import (
+	"log/slog"
+
+	"github.com/eva01/backup-guardian/domain"
+)
+
+// LogRecorder captures the logs emitted during runs.
+type LogRecorder interface {
+	// Level returns the minimum level of captured records.
+	Level() slog.Level
+	// Start starts capturing the records of the run runID: those logged with a context of
+	// runlog.WithRun or a run_id attribute, and the records of its rclone operations.
+	Start(runID string)
+	// Stop stops capturing the records of the run runID and returns its log, or nil when
+	// nothing was captured.
+	Stop(runID string) *domain.SyncRunLog
+}
@@ -2,23 +2,56 @@ package runner
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"log/slog"
 	"os"
 	"os/signal"
+	"slices"
+	"strings"
+	"sync"
 	"syscall"
 	"time"
 
 	"github.com/eva01/backup-guardian/domain"
 	"github.com/eva01/backup-guardian/environment"
+	"github.com/eva01/backup-guardian/internal/redact"
+	"github.com/eva01/backup-guardian/notify"
+	"github.com/eva01/backup-guardian/runlog"
+	"github.com/eva01/backup-guardian/runner/options"
+	"github.com/eva01/backup-guardian/runner/result"
 )
 
+// defaultCheckpointInterval is how often the progress of a running sync is saved to its run.
+const defaultCheckpointInterval = 30 * time.Second
+
 // Runner runs the backup sync loop.
 type Runner struct {
-	store     domain.SyncRunsReadWriter
-	executor  RcloneExecutor
-	scheduler *Scheduler
-	job       *domain.SyncJob
-	logger    *slog.Logger
+	store              domain.SyncRunsReadWriter
+	jobPauses          domain.JobPausesReader
+	executor           RcloneExecutor
+	heartbeat          Heartbeat
+	hooks              HookRunner
+	progress           ProgressReporter
+	logRecorder        LogRecorder
+	runLogs            domain.SyncRunLogsWriter
+	checkpointInterval time.Duration
+	usage              UsageReader
+	canaries           CanaryVerifier
+	manifests          domain.ManifestsReadWriter
+	manifestStorage    ManifestStorage
+	scrubStates        domain.ScrubStatesReadWriter
+	scrubber           Scrubber
+	anomalies          domain.JobAnomaliesReadWriter
+	pauses             domain.JobPausesWriter
+	notifier           notify.Notifier
+	scheduler          *Scheduler
+	jobs               []*domain.SyncJob
+	logger             *slog.Logger
+
+	// mu guards the jobs and the scheduler, replaced by reloads while the runner runs.
+	mu      sync.RWMutex
+	started bool
 }
 
 // Option configures the runner.
@@ -27,7 +60,8 @@ type Option func(*Runner)
 // New creates a new runner.
 func New(options ...Option) *Runner {
 	r := &Runner{
-		logger: slog.Default(),
+		checkpointInterval: defaultCheckpointInterval,
+		logger:             slog.Default(),
 	}
 
 	for _, opt := range options {
@@ -42,19 +76,58 @@ func WithStore(store domain.SyncRunsReadWriter) Option {
 	return func(r *Runner) { r.store = store }
 }
 
+// WithJobPauses sets the job pauses reader. Without it, jobs are never paused.
+func WithJobPauses(jobPauses domain.JobPausesReader) Option {
+	return func(r *Runner) { r.jobPauses = jobPauses }
+}
+
 // WithRcloneExecutor sets the rclone executor.
 func WithRcloneExecutor(executor RcloneExecutor) Option {
 	return func(r *Runner) { r.executor = executor }
 }
 
+// WithHeartbeat sets the heartbeat. Without it, no ping is sent.
+func WithHeartbeat(heartbeat Heartbeat) Option {
+	return func(r *Runner) { r.heartbeat = heartbeat }
+}
+
+// WithHooks sets the runner of job hooks. Without it, hooks are not run.
+func WithHooks(hooks HookRunner) Option {
+	return func(r *Runner) { r.hooks = hooks }
+}
+
+// WithProgress sets the reporter of the live progress of runs.
+func WithProgress(progress ProgressReporter) Option {
+	return func(r *Runner) { r.progress = progress }
+}
+
+// WithRunLogs captures the logs emitted during each run with recorder, and saves them to logs.
+func WithRunLogs(recorder LogRecorder, logs domain.SyncRunLogsWriter) Option {
+	return func(r *Runner) {
+		r.logRecorder = recorder
+		r.runLogs = logs
+	}
+}
+
+// WithCheckpointInterval sets how often the progress of a running sync is saved to its run,
+// so that a crash leaves partial counters. Defaults to 30 seconds.
+func WithCheckpointInterval(interval time.Duration) Option {
+	return func(r *Runner) { r.checkpointInterval = interval }
+}
+
 // WithScheduler sets the scheduler.
 func WithScheduler(scheduler *Scheduler) Option {
 	return func(r *Runner) { r.scheduler = scheduler }
 }
 
-// WithSyncJob sets the sync job config.
+// WithSyncJob sets a single sync job config.
 func WithSyncJob(job *domain.SyncJob) Option {
-	return func(r *Runner) { r.job = job }
+	return WithSyncJobs(job)
+}
+
+// WithSyncJobs sets the sync job configs.
+func WithSyncJobs(jobs ...*domain.SyncJob) Option {
+	return func(r *Runner) { r.jobs = jobs }
 }
 
 // WithLogger sets the logger.
@@ -63,6 +136,7 @@ func WithLogger(logger *slog.Logger) Option {
 }
 
 // Run starts the runner loop. Blocks until context is cancelled or a signal is received.
+// Jobs run one at a time, in the order they become due.
 func (r *Runner) Run(ctx context.Context, vars *environment.Variables) error {
 	if r.store == nil {
 		panic("runner requires store")
@@ -70,7 +144,7 @@ func (r *Runner) Run(ctx context.Context, vars *environment.Variables) error {
 	if r.executor == nil {
 		panic("runner requires rclone executor")
 	}
-	if r.job == nil {
+	if len(r.jobs) == 0 {
 		panic("runner requires sync job")
 	}
 
@@ -79,70 +153,253 @@ func (r *Runner) Run(ctx context.Context, vars *environment.Variables) error {
 		return err
 	}
 
-	if r.scheduler == nil {
-		r.scheduler = NewScheduler(interval)
+	if err := r.start(interval); err != nil {
+		return err
 	}
 
 	sigCh := make(chan os.Signal, 1)
 	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(sigCh)
 
 	runCtx, cancel := context.WithCancel(ctx)
 	defer cancel()
 
-	go r.scheduler.Run(runCtx)
-
-	r.logger.Info("Runner started", slog.String("source", r.job.Source), slog.String("dest", r.job.Destination), slog.Duration("interval", interval))
-
-	r.runSync(runCtx)
-
-	for {
+	go func() {
 		select {
-		case <-runCtx.Done():
-			r.logger.Info("Runner stopping")
-			return nil
 		case sig := <-sigCh:
 			r.logger.Info("Received signal, stopping", slog.String("signal", sig.String()))
 			cancel()
+		case <-runCtx.Done():
+		}
+	}()
+
+	for _, job := range r.syncJobs() {
+		r.logJob("Runner started", job)
+	}
+
+	for {
+		job, err := r.scheduler.Next(runCtx)
+		if err != nil {
+			r.logger.Info("Runner stopping")
 			return nil
-		case <-r.scheduler.C():
-			r.runSync(runCtx)
 		}
+
+		// The run goes on with the configuration of job, even if it is reloaded meanwhile.
+		startedAt := time.Now()
+		run, err := r.runSync(runCtx, job, r.scheduler.PipelineID(job))
+
+		// An interrupted run is not recorded, so that it is caught up after a restart.
+		if runCtx.Err() != nil {
+			continue
+		}
+		// Neither is a run that could not be started: it is retried at the next slot.
+		if err != nil {
+			r.scheduler.Missed(job, startedAt)
+			continue
+		}
+		// A deferred run stays due, so that it resumes when the next window opens.
+		if run != nil && run.Status == domain.StatusDeferred {
+			r.scheduler.Deferred(job)
+			r.logger.Info("Sync deferred to next window", slog.String("run_id", run.ID), slog.String("job", job.Name),
+				slog.Time("resume_at", r.scheduler.StartAt(job)))
+			continue
+		}
+		if err := r.scheduler.Done(job, startedAt); err != nil {
+			r.logger.Error("Failed to save job schedule", slog.String("job", job.Name), slog.Any("error", err))
+		}
+		r.triggerDependents(run)
+	}
+}
+
+// start starts the scheduler, with a scheduler on interval when none is set.
+func (r *Runner) start(interval time.Duration) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.scheduler == nil {
+		r.scheduler = NewScheduler(interval)
 	}
+	if err := r.scheduler.Start(r.jobs); err != nil {
+		return err
+	}
+	r.started = true
+
+	return nil
 }
 
-func (r *Runner) runSync(ctx context.Context) {
+// Reload replaces the jobs, when the configuration is reloaded. Running syncs are not
+// interrupted: they finish with the configuration they started with, and the next runs use
+// the new one.
+func (r *Runner) Reload(jobs []*domain.SyncJob) error {
+	if len(jobs) == 0 {
+		return fmt.Errorf("no job configured")
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	diff := domain.DiffJobs(r.jobs, jobs)
+	r.jobs = jobs
+	// Before Run, the scheduler starts with the jobs set here.
+	if !r.started {
+		return nil
+	}
+	r.scheduler.Update(jobs)
+
+	for _, name := range diff.Removed {
+		r.logger.Info("Job removed", slog.String("job", name))
+	}
+	for _, job := range jobs {
+		switch {
+		case slices.Contains(diff.Added, job.Name):
+			r.logJob("Job added", job)
+		case slices.Contains(diff.Changed, job.Name):
+			r.logJob("Job changed", job)
+		}
+	}
+
+	return nil
+}
+
+// syncJobs returns the jobs of the current configuration.
+func (r *Runner) syncJobs() []*domain.SyncJob {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.jobs
+}
+
+// logJob logs the configuration and the schedule of job with msg.
+func (r *Runner) logJob(msg string, job *domain.SyncJob) {
+	attrs := []any{slog.String("job", job.Name), slog.String("mode", job.SyncMode()), slog.String("source", job.Source),
+		slog.String("dest", strings.Join(job.AllDestinations(), ", "))}
+	if !job.Scheduled() {
+		var after []string
+		for _, dep := range job.After {
+			after = append(after, dep.Job+" "+dep.Condition())
+		}
+		r.logger.Info(msg, append(attrs, slog.String("after", strings.Join(after, ", ")))...)
+		return
+	}
+
+	r.logger.Info(msg, append(attrs, slog.Duration("interval", r.scheduler.jobInterval(job)),
+		slog.String("catch_up", job.CatchUpPolicy()), slog.Time("next_due_at", r.scheduler.NextDue(job)))...)
+	if job.Windows != nil && r.scheduler.StartAt(job).IsZero() {
+		r.logger.Warn("Job run windows never open, job will not run", slog.String("job", job.Name))
+	}
+}
+
+// runSync runs job and records it as a sync run, in pipeline pipelineID when set. It returns
+// the recorded run, or nil when the job is paused. It returns an error when the run could not
+// be started: the pauses could not be read, or the run could not be created.
+func (r *Runner) runSync(ctx context.Context, job *domain.SyncJob, pipelineID string) (*domain.SyncRun, error) {
+	if skip, err := r.skipPaused(job); skip || err != nil {
+		return nil, err
+	}
+	if job.Scrub != nil {
+		return r.runScrub(ctx, job, pipelineID)
+	}
+	job = r.heldJob(job)
+
 	run := &domain.SyncRun{
-		ID:        domain.NewSyncRunID(),
-		JobName:   r.job.Name,
-		Status:    domain.StatusRunning,
-		StartedAt: time.Now(),
+		ID:         domain.NewSyncRunID(),
+		JobName:    job.Name,
+		Mode:       job.SyncMode(),
+		Status:     domain.StatusRunning,
+		StartedAt:  time.Now(),
+		PipelineID: pipelineID,
+	}
+	// A run of a job that others depend on starts a pipeline.
+	if run.PipelineID == "" && len(domain.Dependents(r.syncJobs(), job.Name)) > 0 {
+		run.PipelineID = run.ID
 	}
 
+	limit := job.BandwidthLimitAt(run.StartedAt)
+
 	created, err := r.store.CreateSyncRun(run)
 	if err != nil {
-		r.logger.Error("Failed to create sync run", slog.Any("error", err))
-		return
+		r.logger.Error("Failed to create sync run", slog.String("job", job.Name), slog.Any("error", err))
+		// The run cannot be recorded (e.g. disk full): report it failed to the external monitor.
+		run.Status = domain.StatusFailed
+		run.FinishedAt = time.Now()
+		run.ErrorMessage = redact.String("could not record sync run: " + err.Error())
+		r.heartbeatFinish(job, run, nil)
+		return nil, err
+	}
+	// The filters in effect are saved with the run, so that its history shows what was synced.
+	created.Filters = job.Filters
+	r.heartbeatStart(job, created)
+	ctx = r.startLog(ctx, created)
+
+	attrs := []any{slog.String("run_id", created.ID), slog.String("job", job.Name), slog.String("mode", job.SyncMode())}
+	if created.PipelineID != "" {
+		attrs = append(attrs, slog.String("pipeline_id", created.PipelineID))
+	}
+	if !limit.Unlimited() {
+		attrs = append(attrs, slog.Int64("upload_limit", limit.Upload), slog.Int64("download_limit", limit.Download))
 	}
+	r.logger.Info("Starting sync", attrs...)
 
-	r.logger.Info("Starting sync", slog.String("run_id", created.ID), slog.String("job", r.job.Name))
+	var result *result.RcloneResult
+	err = r.runHooks(ctx, job, created, domain.HookPreSync)
+	if err == nil {
+		err = r.checkCanaries(ctx, job, created)
+	}
+	plans := destinationPlans{}
+	if err == nil {
+		job, err = r.checkAnomaly(ctx, job, created, plans)
+	}
+	if err == nil {
+		err = r.checkCapacity(ctx, job, created, plans)
+	}
+	if err == nil {
+		opts := r.rcloneOptions(job, run.StartedAt)
+		opts.Progress = r.trackProgress(created, limit)
+		opts.RecordChanges = job.Anomaly != nil
+		if r.logRecorder != nil {
+			opts.LogLevel = rcloneLogLevel(r.logRecorder.Level())
+		}
 
-	result, err := r.executor.Sync(ctx, r.job.Source, r.job.Destination)
+		if job.FanOut() {
+			result, err = r.fanOut(ctx, job, created, opts)
+		} else {
+			result, err = r.execute(ctx, job, job.Destination, opts)
+		}
+		r.recordUsage(ctx, job, created)
+	}
 
 	run = created
 	run.FinishedAt = time.Now()
 	run.FilesTransferred = 0
 	run.BytesTransferred = 0
+	run.FilesTotal = 0
+	run.BytesTotal = 0
+	run.UploadLimit = limit.Upload
+	run.DownloadLimit = limit.Download
 
 	if result != nil {
 		run.FilesTransferred = result.FilesTransferred
 		run.BytesTransferred = result.BytesTransferred
+		run.FilesTotal = result.FilesTotal
+		run.BytesTotal = result.BytesTotal
+		run.Changes = result.Changes
 	}
 
-	if err != nil {
+	switch {
+	case errors.Is(err, errCanaries):
+		run.Status = domain.StatusCanaryFailed
+		run.ErrorMessage = redact.String(err.Error())
+	case err != nil:
 		run.Status = domain.StatusFailed
-		run.ErrorMessage = err.Error()
+		run.ErrorMessage = redact.String(err.Error())
 		r.logger.Error("Sync failed", slog.String("run_id", created.ID), slog.Any("error", err))
-	} else {
+	case result != nil && result.Stopped:
+		run.Status = domain.StatusDeferred
+		r.logger.Info("Sync stopped, window closed", slog.String("run_id", created.ID),
+			slog.Int64("files", run.FilesTransferred),
+			slog.Int64("bytes", run.BytesTransferred),
+			slog.Duration("duration", result.Duration))
+	default:
 		run.Status = domain.StatusSuccess
 		r.logger.Info("Sync completed", slog.String("run_id", created.ID),
 			slog.Int64("files", run.FilesTransferred),
@@ -150,7 +407,209 @@ func (r *Runner) runSync(ctx context.Context) {
 			slog.Duration("duration", result.Duration))
 	}
 
+	r.recordManifest(ctx, job, run)
+
+	switch {
+	case run.Status == domain.StatusSuccess:
+		_ = r.runHooks(ctx, job, run, domain.HookPostSuccess)
+	case domain.Failed(run.Status):
+		_ = r.runHooks(ctx, job, run, domain.HookPostFailure)
+	}
+	_ = r.runHooks(ctx, job, run, domain.HookAlways)
+
 	if updateErr := r.store.UpdateSyncRun(run); updateErr != nil {
 		r.logger.Error("Failed to update sync run", slog.String("run_id", created.ID), slog.Any("error", updateErr))
 	}
+	log := r.saveLog(run)
+	r.heartbeatFinish(job, run, log)
+	if r.progress != nil {
+		r.progress.Done(&domain.Progress{
+			RunID:      run.ID,
+			JobName:    run.JobName,
+			StartedAt:  run.StartedAt,
+			UpdatedAt:  run.FinishedAt,
+			BytesDone:  run.BytesTransferred,
+			BytesTotal: run.BytesTotal,
+			FilesDone:  run.FilesTransferred,
+			FilesTotal: run.FilesTotal,
+		})
+	}
+
+	return run, nil
+}
+
+// execute runs the rclone operation of the mode of job from its source to dest.
+func (r *Runner) execute(ctx context.Context, job *domain.SyncJob, dest string, opts *options.RcloneOptions) (*result.RcloneResult, error) {
+	switch job.SyncMode() {
+	case domain.ModeCopy:
+		return r.executor.Copy(ctx, job.Source, dest, opts)
+	case domain.ModeMove:
+		return r.executor.Move(ctx, job.Source, dest, opts)
+	case domain.ModeBisync:
+		return r.executor.Bisync(ctx, job.Source, dest, opts)
+	default:
+		return r.executor.Sync(ctx, job.Source, dest, opts)
+	}
+}
+
+// trackProgress returns the executor progress callback of run: it reports progress and saves
+// it to the run every checkpoint interval.
+func (r *Runner) trackProgress(run *domain.SyncRun, limit domain.BandwidthLimit) func(*domain.Progress) {
+	lastCheckpoint := time.Now()
+
+	return func(progress *domain.Progress) {
+		progress.RunID = run.ID
+		progress.JobName = run.JobName
+		progress.StartedAt = run.StartedAt
+
+		if r.progress != nil {
+			r.progress.Update(progress)
+		}
+
+		if progress.UpdatedAt.Sub(lastCheckpoint) < r.checkpointInterval {
+			return
+		}
+		lastCheckpoint = progress.UpdatedAt
+
+		r.logger.Info("Sync progress", slog.String("run_id", run.ID), slog.String("job", run.JobName),
+			slog.Int64("bytes", progress.BytesDone), slog.Int64("bytes_total", progress.BytesTotal),
+			slog.Int64("files", progress.FilesDone), slog.Int64("files_total", progress.FilesTotal),
+			slog.Float64("speed", progress.Speed), slog.Duration("eta", progress.ETA))
+
+		checkpoint := *run
+		checkpoint.Status = domain.StatusRunning
+		checkpoint.FilesTransferred = progress.FilesDone
+		checkpoint.BytesTransferred = progress.BytesDone
+		checkpoint.FilesTotal = progress.FilesTotal
+		checkpoint.BytesTotal = progress.BytesTotal
+		checkpoint.UploadLimit = limit.Upload
+		checkpoint.DownloadLimit = limit.Download
+		if err := r.store.UpdateSyncRun(&checkpoint); err != nil {
+			r.logger.Warn("Failed to save sync progress", slog.String("run_id", run.ID), slog.Any("error", err))
+		}
+	}
+}
+
+// runHooks runs the hooks of job for event in order, and records their results on run.
+// It returns an error, and runs no further hook, when a hook set to fail the run fails.
+func (r *Runner) runHooks(ctx context.Context, job *domain.SyncJob, run *domain.SyncRun, event string) error {
+	if r.hooks == nil {
+		return nil
+	}
+
+	for _, hook := range job.HooksFor(event) {
+		result := r.hooks.Run(ctx, hook, job, run)
+		result.Name = redact.String(result.Name)
+		result.Output = redact.String(result.Output)
+		result.Error = redact.String(result.Error)
+		run.Hooks = append(run.Hooks, *result)
+
+		attrs := []any{slog.String("run_id", run.ID), slog.String("job", job.Name), slog.String("hook", result.Name),
+			slog.String("event", event), slog.Duration("duration", result.Duration)}
+		if !result.Failed() {
+			r.logger.Info("Hook completed", attrs...)
+			continue
+		}
+
+		r.logger.Warn("Hook failed", append(attrs, slog.String("error", result.Error))...)
+		if hook.FailRun {
+			return fmt.Errorf("%s hook %s failed: %s", event, result.Name, result.Error)
+		}
+	}
+
+	return nil
+}
+
+// startLog starts capturing the log of run, and returns the context of its operations, whose
+// records belong to run.
+func (r *Runner) startLog(ctx context.Context, run *domain.SyncRun) context.Context {
+	if r.logRecorder != nil {
+		r.logRecorder.Start(run.ID)
+	}
+
+	return runlog.WithRun(ctx, run.ID)
+}
+
+// saveLog stops capturing the log of run, saves it and returns it, or nil when none was
+// captured.
+func (r *Runner) saveLog(run *domain.SyncRun) *domain.SyncRunLog {
+	if r.logRecorder == nil {
+		return nil
+	}
+
+	log := r.logRecorder.Stop(run.ID)
+	if log == nil {
+		return nil
+	}
+	log.RunID = run.ID
+
+	if _, err := r.runLogs.CreateSyncRunLog(log); err != nil {
+		r.logger.Error("Failed to save sync run log", slog.String("run_id", run.ID), slog.Any("error", err))
+	}
+
+	return log
+}
+
+// rcloneLogLevel returns the rclone log level emitting the records of level, or "" when
+// rclone's default level is enough.
+func rcloneLogLevel(level slog.Level) string {
+	switch {
+	case level <= slog.LevelDebug:
+		return "DEBUG"
+	case level <= slog.LevelInfo:
+		return "INFO"
+	default:
+		return ""
+	}
+}
+
+func (r *Runner) heartbeatStart(job *domain.SyncJob, run *domain.SyncRun) {
+	if r.heartbeat != nil {
+		r.heartbeat.Start(job, run)
+	}
+}
+
+func (r *Runner) heartbeatFinish(job *domain.SyncJob, run *domain.SyncRun, log *domain.SyncRunLog) {
+	if r.heartbeat != nil {
+		r.heartbeat.Finish(job, run, log)
+	}
+}
+
+// rcloneOptions returns the executor options of a run of job started at startedAt.
+func (r *Runner) rcloneOptions(job *domain.SyncJob, startedAt time.Time) *options.RcloneOptions {
+	opts := &options.RcloneOptions{Bandwidth: job.Bandwidth, Filters: job.Filters, Encryption: job.Encryption}
+
+	if job.Windows != nil && job.Windows.StopOnClose {
+		opts.StopAt = job.Windows.CloseAt(startedAt)
+	}
+
+	return opts
+}
+
+// skipPaused reports whether job must be skipped because it, or the whole runner, is paused.
+// Runs are skipped, with an error, when the pause state cannot be read, so that an unreadable
+// database never resumes a paused job.
+func (r *Runner) skipPaused(job *domain.SyncJob) (bool, error) {
+	if r.jobPauses == nil {
+		return false, nil
+	}
+
+	pauses, err := r.jobPauses.ListJobPauses()
+	if err != nil {
+		r.logger.Error("Failed to read job pauses, skipping sync", slog.String("job", job.Name), slog.Any("error", err))
+		return true, err
+	}
+
+	pause := domain.ActiveJobPause(pauses, job.Name, time.Now())
+	if pause == nil {
+		return false, nil
+	}
+
+	attrs := []any{slog.String("job", job.Name), slog.String("scope", pause.Scope), slog.String("reason", pause.Reason)}
+	if !pause.ResumeAt.IsZero() {
+		attrs = append(attrs, slog.Time("resume_at", pause.ResumeAt))
+	}
+	r.logger.Info("Sync skipped, job paused", attrs...)
+
+	return true, nil
 }
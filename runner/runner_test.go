@@ -3,14 +3,20 @@ package runner_test
 import (
 	"context"
 	"errors"
+	"log/slog"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/eva01/backup-guardian/domain"
 	domainmocks "github.com/eva01/backup-guardian/domain/mocks"
 	"github.com/eva01/backup-guardian/environment"
+	bgerrors "github.com/eva01/backup-guardian/internal/errors"
+	"github.com/eva01/backup-guardian/notify"
+	"github.com/eva01/backup-guardian/runlog"
 	"github.com/eva01/backup-guardian/runner"
 	runnermocks "github.com/eva01/backup-guardian/runner/mocks"
+	"github.com/eva01/backup-guardian/runner/options"
 	"github.com/eva01/backup-guardian/runner/result"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -31,6 +37,7 @@ func TestRunner_Run_Success(t *testing.T) {
 		run := args.Get(0).(*domain.SyncRun)
 		require.NotEmpty(t, run.ID)
 		assert.Equal(t, "test-job", run.JobName)
+		assert.Equal(t, domain.ModeSync, run.Mode)
 		assert.Equal(t, domain.StatusRunning, run.Status)
 	}).Return(createdRun, nil).Once()
 
@@ -39,7 +46,7 @@ func TestRunner_Run_Success(t *testing.T) {
 		FilesTransferred: 10,
 		BytesTransferred: 100,
 	}
-	execMock.On("Sync", mock.Anything, "source", "dest").Return(syncResult, nil).Once()
+	execMock.On("Sync", mock.Anything, "source", "dest", mock.Anything).Return(syncResult, nil).Once()
 
 	syncDone := make(chan struct{})
 	storeMock.On("UpdateSyncRun", mock.Anything).Run(func(args mock.Arguments) {
@@ -72,6 +79,236 @@ func TestRunner_Run_Success(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestRunner_Run_Modes(t *testing.T) {
+	tests := map[string]string{
+		domain.ModeCopy:   "Copy",
+		domain.ModeMove:   "Move",
+		domain.ModeBisync: "Bisync",
+		domain.ModeSync:   "Sync",
+	}
+
+	for mode, method := range tests {
+		t.Run(mode, func(t *testing.T) {
+			storeMock := domainmocks.NewSyncRunsReadWriter(t)
+			execMock := runnermocks.NewRcloneExecutor(t)
+
+			storeMock.On("CreateSyncRun", mock.MatchedBy(func(run *domain.SyncRun) bool {
+				return run.Mode == mode
+			})).Return(func(run *domain.SyncRun) (*domain.SyncRun, error) { return run, nil }).Once()
+			execMock.On(method, mock.Anything, "source", "dest", mock.Anything).
+				Return(&result.RcloneResult{FilesTransferred: 1}, nil).Once()
+
+			syncDone := make(chan struct{})
+			storeMock.On("UpdateSyncRun", mock.MatchedBy(func(run *domain.SyncRun) bool {
+				return run.Mode == mode && run.Status == domain.StatusSuccess
+			})).Run(func(args mock.Arguments) {
+				close(syncDone)
+			}).Return(nil).Once()
+
+			vars := &environment.Variables{SyncInterval: "24h"}
+			job := &domain.SyncJob{Name: "test-job", Source: "source", Destination: "dest", Mode: mode}
+
+			r := runner.New(
+				runner.WithStore(storeMock),
+				runner.WithRcloneExecutor(execMock),
+				runner.WithSyncJob(job),
+				runner.WithScheduler(runner.NewScheduler(24*time.Hour)),
+			)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			errCh := make(chan error, 1)
+			go func() { errCh <- r.Run(ctx, vars) }()
+
+			<-syncDone
+			cancel()
+			err := <-errCh
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestRunner_Run_FanOut(t *testing.T) {
+	tests := map[string]struct {
+		require    string
+		wantStatus string
+	}{
+		"all": {require: domain.RequireAll, wantStatus: domain.StatusFailed},
+		"any": {require: domain.RequireAny, wantStatus: domain.StatusSuccess},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			storeMock := domainmocks.NewSyncRunsReadWriter(t)
+			execMock := runnermocks.NewRcloneExecutor(t)
+
+			storeMock.On("CreateSyncRun", mock.Anything).
+				Return(func(run *domain.SyncRun) (*domain.SyncRun, error) { return run, nil }).Once()
+			shared := mock.MatchedBy(func(opts *options.RcloneOptions) bool { return opts.SharedSource != nil })
+			execMock.On("Copy", mock.Anything, "source", "s3", shared).
+				Return(&result.RcloneResult{FilesTransferred: 2, BytesTransferred: 20}, nil).Once()
+			execMock.On("Copy", mock.Anything, "source", "b2", shared).
+				Return(&result.RcloneResult{FilesTransferred: 1, BytesTransferred: 10}, errors.New("quota exceeded")).Once()
+
+			syncDone := make(chan struct{})
+			storeMock.On("UpdateSyncRun", mock.Anything).Run(func(args mock.Arguments) {
+				run := args.Get(0).(*domain.SyncRun)
+				assert.Equal(t, tt.wantStatus, run.Status)
+				assert.Equal(t, "1 of 2 destinations failed: b2: quota exceeded", run.ErrorMessage)
+				assert.Equal(t, int64(3), run.FilesTransferred)
+				assert.Equal(t, int64(30), run.BytesTransferred)
+				require.Len(t, run.Destinations, 2)
+				assert.Equal(t, domain.DestinationResult{Destination: "s3", Status: domain.StatusSuccess, FilesTransferred: 2, BytesTransferred: 20}, run.Destinations[0])
+				assert.Equal(t, domain.DestinationResult{Destination: "b2", Status: domain.StatusFailed, Error: "quota exceeded", FilesTransferred: 1, BytesTransferred: 10}, run.Destinations[1])
+				close(syncDone)
+			}).Return(nil).Once()
+
+			vars := &environment.Variables{SyncInterval: "24h"}
+			job := &domain.SyncJob{Name: "test-job", Source: "source", Destinations: []string{"s3", "b2"}, Mode: domain.ModeCopy, Require: tt.require}
+
+			r := runner.New(
+				runner.WithStore(storeMock),
+				runner.WithRcloneExecutor(execMock),
+				runner.WithSyncJob(job),
+				runner.WithScheduler(runner.NewScheduler(24*time.Hour)),
+			)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			errCh := make(chan error, 1)
+			go func() { errCh <- r.Run(ctx, vars) }()
+
+			<-syncDone
+			cancel()
+			err := <-errCh
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestRunner_Run_Pipeline(t *testing.T) {
+	storeMock := domainmocks.NewSyncRunsReadWriter(t)
+	execMock := runnermocks.NewRcloneExecutor(t)
+
+	drive := &domain.SyncJob{Name: "drive", Source: "gdrive:", Destination: "nas:"}
+	nas := &domain.SyncJob{Name: "nas", Source: "nas:", Destination: "s3:", After: []*domain.Dependency{{Job: "drive"}}}
+	verify := &domain.SyncJob{Name: "verify", Source: "s3:", Destination: "check:", After: []*domain.Dependency{{Job: "nas"}}}
+	alert := &domain.SyncJob{Name: "alert", Source: "s3:", Destination: "alert:", After: []*domain.Dependency{{Job: "nas", On: domain.DependOnFailure}}}
+
+	var mu sync.Mutex
+	var runs []*domain.SyncRun
+	storeMock.On("CreateSyncRun", mock.Anything).Return(func(run *domain.SyncRun) (*domain.SyncRun, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		created := *run
+		runs = append(runs, &created)
+		return &created, nil
+	})
+	storeMock.On("ListSyncRuns", mock.Anything).Return(func(selector *domain.SyncRunsSelector) ([]*domain.SyncRun, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		var result []*domain.SyncRun
+		for _, run := range runs {
+			if run.PipelineID == selector.PipelineID {
+				result = append(result, run)
+			}
+		}
+		return result, nil
+	})
+
+	execMock.On("Sync", mock.Anything, "gdrive:", "nas:", mock.Anything).Return(&result.RcloneResult{}, nil).Once()
+	execMock.On("Sync", mock.Anything, "nas:", "s3:", mock.Anything).Return(nil, errors.New("s3 unreachable")).Once()
+	execMock.On("Sync", mock.Anything, "s3:", "alert:", mock.Anything).Return(&result.RcloneResult{}, nil).Once()
+
+	pipelineDone := make(chan struct{})
+	storeMock.On("UpdateSyncRun", mock.Anything).Run(func(args mock.Arguments) {
+		run := args.Get(0).(*domain.SyncRun)
+		if run.JobName == "alert" {
+			close(pipelineDone)
+		}
+	}).Return(nil)
+
+	vars := &environment.Variables{SyncInterval: "24h"}
+	r := runner.New(
+		runner.WithStore(storeMock),
+		runner.WithRcloneExecutor(execMock),
+		runner.WithSyncJobs(drive, nas, verify, alert),
+		runner.WithScheduler(runner.NewScheduler(24*time.Hour)),
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errCh := make(chan error, 1)
+	go func() { errCh <- r.Run(ctx, vars) }()
+
+	<-pipelineDone
+	cancel()
+	require.NoError(t, <-errCh)
+
+	// verify is skipped as nas failed; all runs share the pipeline of the drive run.
+	require.Len(t, runs, 3)
+	assert.Equal(t, []string{"drive", "nas", "alert"}, []string{runs[0].JobName, runs[1].JobName, runs[2].JobName})
+	for _, run := range runs {
+		assert.Equal(t, runs[0].ID, run.PipelineID)
+	}
+}
+
+func TestRunner_Reload(t *testing.T) {
+	storeMock := domainmocks.NewSyncRunsReadWriter(t)
+	execMock := runnermocks.NewRcloneExecutor(t)
+
+	storeMock.On("CreateSyncRun", mock.Anything).Return(func(run *domain.SyncRun) (*domain.SyncRun, error) {
+		created := *run
+		return &created, nil
+	})
+
+	// The sync of the old job is running when the configuration is reloaded.
+	syncing := make(chan struct{})
+	release := make(chan struct{})
+	execMock.On("Sync", mock.Anything, "old:", "dest:", mock.Anything).Run(func(args mock.Arguments) {
+		close(syncing)
+		<-release
+	}).Return(&result.RcloneResult{}, nil).Once()
+	execMock.On("Sync", mock.Anything, "new:", "dest:", mock.Anything).Return(&result.RcloneResult{}, nil).Once()
+
+	var mu sync.Mutex
+	var finished []*domain.SyncRun
+	newDone := make(chan struct{})
+	storeMock.On("UpdateSyncRun", mock.Anything).Run(func(args mock.Arguments) {
+		mu.Lock()
+		defer mu.Unlock()
+		run := args.Get(0).(*domain.SyncRun)
+		finished = append(finished, run)
+		if run.JobName == "new" {
+			close(newDone)
+		}
+	}).Return(nil)
+
+	vars := &environment.Variables{SyncInterval: "24h"}
+	r := runner.New(
+		runner.WithStore(storeMock),
+		runner.WithRcloneExecutor(execMock),
+		runner.WithSyncJob(&domain.SyncJob{Name: "old", Source: "old:", Destination: "dest:"}),
+		runner.WithScheduler(runner.NewScheduler(24*time.Hour)),
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errCh := make(chan error, 1)
+	go func() { errCh <- r.Run(ctx, vars) }()
+
+	<-syncing
+	require.NoError(t, r.Reload([]*domain.SyncJob{{Name: "new", Source: "new:", Destination: "dest:"}}))
+	close(release)
+
+	<-newDone
+	cancel()
+	require.NoError(t, <-errCh)
+
+	require.Len(t, finished, 2)
+	assert.Equal(t, "old", finished[0].JobName)
+	assert.Equal(t, domain.StatusSuccess, finished[0].Status)
+	assert.Equal(t, "new", finished[1].JobName)
+
+	require.Error(t, r.Reload(nil))
+}
+
 func TestRunner_Run_SyncFails(t *testing.T) {
 	storeMock := domainmocks.NewSyncRunsReadWriter(t)
 	execMock := runnermocks.NewRcloneExecutor(t)
@@ -85,7 +322,7 @@ func TestRunner_Run_SyncFails(t *testing.T) {
 	storeMock.On("CreateSyncRun", mock.Anything).Return(createdRun, nil).Once()
 
 	syncErr := errors.New("sync failed")
-	execMock.On("Sync", mock.Anything, "source", "dest").Return(nil, syncErr).Once()
+	execMock.On("Sync", mock.Anything, "source", "dest", mock.Anything).Return(nil, syncErr).Once()
 
 	syncDone := make(chan struct{})
 	storeMock.On("UpdateSyncRun", mock.Anything).Run(func(args mock.Arguments) {
@@ -159,7 +396,7 @@ func TestRunner_Run_UpdateSyncRunFails(t *testing.T) {
 	storeMock.On("CreateSyncRun", mock.Anything).Return(createdRun, nil).Once()
 
 	syncResult := &result.RcloneResult{FilesTransferred: 5, BytesTransferred: 50}
-	execMock.On("Sync", mock.Anything, "source", "dest").Return(syncResult, nil).Once()
+	execMock.On("Sync", mock.Anything, "source", "dest", mock.Anything).Return(syncResult, nil).Once()
 
 	updateErr := errors.New("db write failed")
 	syncDone := make(chan struct{})
@@ -207,3 +444,1157 @@ func TestRunner_Run_InvalidSyncInterval(t *testing.T) {
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "invalid")
 }
+
+func TestRunner_Run_JobPaused(t *testing.T) {
+	storeMock := domainmocks.NewSyncRunsReadWriter(t)
+	pausesMock := domainmocks.NewJobPausesReadWriter(t)
+	execMock := runnermocks.NewRcloneExecutor(t)
+
+	// CreateSyncRun, Sync et UpdateSyncRun ne doivent jamais être appelés
+	checked := make(chan struct{})
+	pausesMock.On("ListJobPauses").Run(func(args mock.Arguments) {
+		close(checked)
+	}).Return([]*domain.JobPause{{Scope: "test-job", Reason: "migration"}}, nil).Once()
+
+	vars := &environment.Variables{SyncInterval: "24h"}
+	job := &domain.SyncJob{Name: "test-job", Source: "source", Destination: "dest"}
+
+	r := runner.New(
+		runner.WithStore(storeMock),
+		runner.WithJobPauses(pausesMock),
+		runner.WithRcloneExecutor(execMock),
+		runner.WithSyncJob(job),
+		runner.WithScheduler(runner.NewScheduler(24*time.Hour)),
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errCh := make(chan error, 1)
+	go func() { errCh <- r.Run(ctx, vars) }()
+
+	<-checked
+	cancel()
+	err := <-errCh
+	require.NoError(t, err)
+}
+
+func TestRunner_Run_PauseExpired(t *testing.T) {
+	storeMock := domainmocks.NewSyncRunsReadWriter(t)
+	pausesMock := domainmocks.NewJobPausesReadWriter(t)
+	execMock := runnermocks.NewRcloneExecutor(t)
+
+	expired := &domain.JobPause{Scope: domain.PauseScopeAll, ResumeAt: time.Now().Add(-time.Minute)}
+	pausesMock.On("ListJobPauses").Return([]*domain.JobPause{expired}, nil).Once()
+
+	createdRun := &domain.SyncRun{ID: "test-run-id", JobName: "test-job", Status: domain.StatusRunning}
+	storeMock.On("CreateSyncRun", mock.Anything).Return(createdRun, nil).Once()
+	execMock.On("Sync", mock.Anything, "source", "dest", mock.Anything).Return(&result.RcloneResult{}, nil).Once()
+
+	syncDone := make(chan struct{})
+	storeMock.On("UpdateSyncRun", mock.Anything).Run(func(args mock.Arguments) {
+		close(syncDone)
+	}).Return(nil).Once()
+
+	vars := &environment.Variables{SyncInterval: "24h"}
+	job := &domain.SyncJob{Name: "test-job", Source: "source", Destination: "dest"}
+
+	r := runner.New(
+		runner.WithStore(storeMock),
+		runner.WithJobPauses(pausesMock),
+		runner.WithRcloneExecutor(execMock),
+		runner.WithSyncJob(job),
+		runner.WithScheduler(runner.NewScheduler(24*time.Hour)),
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errCh := make(chan error, 1)
+	go func() { errCh <- r.Run(ctx, vars) }()
+
+	<-syncDone
+	cancel()
+	err := <-errCh
+	require.NoError(t, err)
+}
+
+func TestRunner_Run_StoppedAtWindowClose(t *testing.T) {
+	storeMock := domainmocks.NewSyncRunsReadWriter(t)
+	execMock := runnermocks.NewRcloneExecutor(t)
+
+	createdRun := &domain.SyncRun{ID: "test-run-id", JobName: "test-job", Status: domain.StatusRunning, StartedAt: time.Now()}
+	storeMock.On("CreateSyncRun", mock.Anything).Return(createdRun, nil).Once()
+	execMock.On("Sync", mock.Anything, "source", "dest", mock.Anything).Return(&result.RcloneResult{
+		FilesTransferred: 3,
+		Stopped:          true,
+	}, nil).Once()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	storeMock.On("UpdateSyncRun", mock.Anything).Run(func(args mock.Arguments) {
+		run := args.Get(0).(*domain.SyncRun)
+		assert.Equal(t, domain.StatusDeferred, run.Status)
+		assert.Equal(t, int64(3), run.FilesTransferred)
+		// Without windows the deferred job is due again at once: stop here.
+		cancel()
+	}).Return(nil).Once()
+
+	vars := &environment.Variables{SyncInterval: "24h"}
+	job := &domain.SyncJob{Name: "test-job", Source: "source", Destination: "dest"}
+
+	r := runner.New(
+		runner.WithStore(storeMock),
+		runner.WithRcloneExecutor(execMock),
+		runner.WithSyncJob(job),
+		runner.WithScheduler(runner.NewScheduler(24*time.Hour)),
+	)
+
+	err := r.Run(ctx, vars)
+	require.NoError(t, err)
+}
+
+func TestRunner_Run_BandwidthLimit(t *testing.T) {
+	storeMock := domainmocks.NewSyncRunsReadWriter(t)
+	execMock := runnermocks.NewRcloneExecutor(t)
+
+	bandwidth := &domain.BandwidthSchedule{Limit: domain.BandwidthLimit{Upload: 1 << 20, Download: 2 << 20}}
+	job := &domain.SyncJob{Name: "test-job", Source: "source", Destination: "dest", Bandwidth: bandwidth}
+
+	createdRun := &domain.SyncRun{ID: "test-run-id", JobName: "test-job", Status: domain.StatusRunning}
+	storeMock.On("CreateSyncRun", mock.Anything).Return(createdRun, nil).Once()
+	execMock.On("Sync", mock.Anything, "source", "dest", mock.MatchedBy(func(opts *options.RcloneOptions) bool {
+		return opts.Bandwidth == bandwidth
+	})).Return(&result.RcloneResult{}, nil).Once()
+
+	syncDone := make(chan struct{})
+	storeMock.On("UpdateSyncRun", mock.Anything).Run(func(args mock.Arguments) {
+		run := args.Get(0).(*domain.SyncRun)
+		assert.Equal(t, int64(1<<20), run.UploadLimit)
+		assert.Equal(t, int64(2<<20), run.DownloadLimit)
+		close(syncDone)
+	}).Return(nil).Once()
+
+	vars := &environment.Variables{SyncInterval: "24h"}
+
+	r := runner.New(
+		runner.WithStore(storeMock),
+		runner.WithRcloneExecutor(execMock),
+		runner.WithSyncJob(job),
+		runner.WithScheduler(runner.NewScheduler(24*time.Hour)),
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errCh := make(chan error, 1)
+	go func() { errCh <- r.Run(ctx, vars) }()
+
+	<-syncDone
+	cancel()
+	err := <-errCh
+	require.NoError(t, err)
+}
+
+func TestRunner_Run_Filters(t *testing.T) {
+	storeMock := domainmocks.NewSyncRunsReadWriter(t)
+	execMock := runnermocks.NewRcloneExecutor(t)
+
+	filters := &domain.Filters{Exclude: []string{"*.tmp", "node_modules/**"}}
+	job := &domain.SyncJob{Name: "test-job", Source: "source", Destination: "dest", Filters: filters}
+
+	createdRun := &domain.SyncRun{ID: "test-run-id", JobName: "test-job", Status: domain.StatusRunning}
+	storeMock.On("CreateSyncRun", mock.Anything).Return(createdRun, nil).Once()
+	execMock.On("Sync", mock.Anything, "source", "dest", mock.MatchedBy(func(opts *options.RcloneOptions) bool {
+		return opts.Filters == filters
+	})).Return(&result.RcloneResult{}, nil).Once()
+
+	syncDone := make(chan struct{})
+	storeMock.On("UpdateSyncRun", mock.Anything).Run(func(args mock.Arguments) {
+		run := args.Get(0).(*domain.SyncRun)
+		assert.Equal(t, filters, run.Filters)
+		close(syncDone)
+	}).Return(nil).Once()
+
+	vars := &environment.Variables{SyncInterval: "24h"}
+
+	r := runner.New(
+		runner.WithStore(storeMock),
+		runner.WithRcloneExecutor(execMock),
+		runner.WithSyncJob(job),
+		runner.WithScheduler(runner.NewScheduler(24*time.Hour)),
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errCh := make(chan error, 1)
+	go func() { errCh <- r.Run(ctx, vars) }()
+
+	<-syncDone
+	cancel()
+	err := <-errCh
+	require.NoError(t, err)
+}
+
+func TestRunner_Run_Heartbeat(t *testing.T) {
+	storeMock := domainmocks.NewSyncRunsReadWriter(t)
+	execMock := runnermocks.NewRcloneExecutor(t)
+	heartbeatMock := runnermocks.NewHeartbeat(t)
+
+	job := &domain.SyncJob{Name: "test-job", Source: "source", Destination: "dest"}
+	createdRun := &domain.SyncRun{ID: "test-run-id", JobName: "test-job", Status: domain.StatusRunning}
+	storeMock.On("CreateSyncRun", mock.Anything).Return(createdRun, nil).Once()
+	heartbeatMock.On("Start", job, createdRun).Once()
+	execMock.On("Sync", mock.Anything, "source", "dest", mock.Anything).Return(&result.RcloneResult{}, nil).Once()
+	storeMock.On("UpdateSyncRun", mock.Anything).Return(nil).Once()
+
+	syncDone := make(chan struct{})
+	heartbeatMock.On("Finish", job, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		run := args.Get(1).(*domain.SyncRun)
+		assert.Equal(t, "test-run-id", run.ID)
+		assert.Equal(t, domain.StatusSuccess, run.Status)
+		close(syncDone)
+	}).Once()
+
+	vars := &environment.Variables{SyncInterval: "24h"}
+
+	r := runner.New(
+		runner.WithStore(storeMock),
+		runner.WithRcloneExecutor(execMock),
+		runner.WithHeartbeat(heartbeatMock),
+		runner.WithSyncJob(job),
+		runner.WithScheduler(runner.NewScheduler(24*time.Hour)),
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errCh := make(chan error, 1)
+	go func() { errCh <- r.Run(ctx, vars) }()
+
+	<-syncDone
+	cancel()
+	err := <-errCh
+	require.NoError(t, err)
+}
+
+func TestRunner_Run_HeartbeatCreateSyncRunFails(t *testing.T) {
+	storeMock := domainmocks.NewSyncRunsReadWriter(t)
+	execMock := runnermocks.NewRcloneExecutor(t)
+	heartbeatMock := runnermocks.NewHeartbeat(t)
+
+	job := &domain.SyncJob{Name: "test-job", Source: "source", Destination: "dest"}
+	storeMock.On("CreateSyncRun", mock.Anything).Return(nil, errors.New("disk full")).Once()
+
+	syncDone := make(chan struct{})
+	heartbeatMock.On("Finish", job, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		run := args.Get(1).(*domain.SyncRun)
+		assert.Equal(t, domain.StatusFailed, run.Status)
+		assert.Contains(t, run.ErrorMessage, "disk full")
+		close(syncDone)
+	}).Once()
+
+	vars := &environment.Variables{SyncInterval: "24h"}
+
+	r := runner.New(
+		runner.WithStore(storeMock),
+		runner.WithRcloneExecutor(execMock),
+		runner.WithHeartbeat(heartbeatMock),
+		runner.WithSyncJob(job),
+		runner.WithScheduler(runner.NewScheduler(24*time.Hour)),
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errCh := make(chan error, 1)
+	go func() { errCh <- r.Run(ctx, vars) }()
+
+	<-syncDone
+	cancel()
+	err := <-errCh
+	require.NoError(t, err)
+}
+
+func TestRunner_Run_CreateSyncRunFails_KeepsSchedule(t *testing.T) {
+	storeMock := domainmocks.NewSyncRunsReadWriter(t)
+	execMock := runnermocks.NewRcloneExecutor(t)
+	schedulesMock := domainmocks.NewJobSchedulesReadWriter(t)
+
+	job := &domain.SyncJob{Name: "test-job", Source: "source", Destination: "dest", CatchUp: domain.CatchUpAll}
+	schedulesMock.On("GetJobSchedule", mock.Anything).Return(nil, &bgerrors.Error{Code: bgerrors.CodeNotFound}).Once()
+	storeMock.On("CreateSyncRun", mock.Anything).Return(nil, errors.New("disk full")).Once()
+	// No UpsertJobSchedule: the slot stays due in the database, to be caught up after a restart.
+
+	scheduler := runner.NewScheduler(24*time.Hour, runner.WithJobSchedules(schedulesMock))
+	r := runner.New(
+		runner.WithStore(storeMock),
+		runner.WithRcloneExecutor(execMock),
+		runner.WithSyncJob(job),
+		runner.WithScheduler(scheduler),
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errCh := make(chan error, 1)
+	go func() { errCh <- r.Run(ctx, &environment.Variables{SyncInterval: "24h"}) }()
+
+	// The next slot is scheduled in memory, so that the runner does not retry at once.
+	assert.Eventually(t, func() bool {
+		return scheduler.NextDue(job).After(time.Now().Add(time.Hour))
+	}, 5*time.Second, 10*time.Millisecond)
+	cancel()
+	require.NoError(t, <-errCh)
+}
+
+func TestRunner_Run_Progress(t *testing.T) {
+	storeMock := domainmocks.NewSyncRunsReadWriter(t)
+	execMock := runnermocks.NewRcloneExecutor(t)
+	progressMock := runnermocks.NewProgressReporter(t)
+
+	job := &domain.SyncJob{Name: "test-job", Source: "source", Destination: "dest"}
+	createdRun := &domain.SyncRun{ID: "test-run-id", JobName: "test-job", Status: domain.StatusRunning}
+	storeMock.On("CreateSyncRun", mock.Anything).Return(createdRun, nil).Once()
+	execMock.On("Sync", mock.Anything, "source", "dest", mock.Anything).Run(func(args mock.Arguments) {
+		opts := args.Get(3).(*options.RcloneOptions)
+		// Only the snapshot taken a checkpoint interval after the start is saved.
+		opts.Progress(&domain.Progress{UpdatedAt: time.Now(), BytesDone: 10, BytesTotal: 100, FilesDone: 1, FilesTotal: 4})
+		opts.Progress(&domain.Progress{UpdatedAt: time.Now().Add(2 * time.Minute), BytesDone: 50, BytesTotal: 100, FilesDone: 2, FilesTotal: 4})
+	}).Return(&result.RcloneResult{FilesTransferred: 4, BytesTransferred: 100, FilesTotal: 4, BytesTotal: 100}, nil).Once()
+
+	progressMock.On("Update", mock.MatchedBy(func(p *domain.Progress) bool {
+		return p.RunID == "test-run-id" && p.JobName == "test-job"
+	})).Twice()
+	storeMock.On("UpdateSyncRun", mock.MatchedBy(func(run *domain.SyncRun) bool {
+		return run.Status == domain.StatusRunning
+	})).Run(func(args mock.Arguments) {
+		run := args.Get(0).(*domain.SyncRun)
+		assert.Equal(t, int64(50), run.BytesTransferred)
+		assert.Equal(t, int64(100), run.BytesTotal)
+		assert.Equal(t, int64(2), run.FilesTransferred)
+		assert.Equal(t, int64(4), run.FilesTotal)
+	}).Return(nil).Once()
+	storeMock.On("UpdateSyncRun", mock.MatchedBy(func(run *domain.SyncRun) bool {
+		return run.Status == domain.StatusSuccess
+	})).Return(nil).Once()
+
+	syncDone := make(chan struct{})
+	progressMock.On("Done", mock.Anything).Run(func(args mock.Arguments) {
+		progress := args.Get(0).(*domain.Progress)
+		assert.Equal(t, "test-run-id", progress.RunID)
+		assert.Equal(t, int64(100), progress.BytesDone)
+		close(syncDone)
+	}).Once()
+
+	vars := &environment.Variables{SyncInterval: "24h"}
+
+	r := runner.New(
+		runner.WithStore(storeMock),
+		runner.WithRcloneExecutor(execMock),
+		runner.WithProgress(progressMock),
+		runner.WithCheckpointInterval(time.Minute),
+		runner.WithSyncJob(job),
+		runner.WithScheduler(runner.NewScheduler(24*time.Hour)),
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errCh := make(chan error, 1)
+	go func() { errCh <- r.Run(ctx, vars) }()
+
+	<-syncDone
+	cancel()
+	err := <-errCh
+	require.NoError(t, err)
+}
+
+func TestRunner_Run_RunLogs(t *testing.T) {
+	storeMock := domainmocks.NewSyncRunsReadWriter(t)
+	logsMock := domainmocks.NewSyncRunLogsReadWriter(t)
+	execMock := runnermocks.NewRcloneExecutor(t)
+	recorderMock := runnermocks.NewLogRecorder(t)
+	heartbeatMock := runnermocks.NewHeartbeat(t)
+
+	job := &domain.SyncJob{Name: "test-job", Source: "source", Destination: "dest"}
+	createdRun := &domain.SyncRun{ID: "test-run-id", JobName: "test-job", Status: domain.StatusRunning}
+	storeMock.On("CreateSyncRun", mock.Anything).Return(createdRun, nil).Once()
+	recorderMock.On("Start", "test-run-id").Once()
+	recorderMock.On("Level").Return(slog.LevelInfo)
+	// The operations of the run are logged in its log.
+	execMock.On("Sync", mock.MatchedBy(func(ctx context.Context) bool {
+		return runlog.RunID(ctx) == "test-run-id"
+	}), "source", "dest", mock.MatchedBy(func(opts *options.RcloneOptions) bool {
+		return opts.LogLevel == "INFO"
+	})).Return(&result.RcloneResult{}, nil).Once()
+	storeMock.On("UpdateSyncRun", mock.Anything).Return(nil).Once()
+	runLog := &domain.SyncRunLog{Content: []byte("level=INFO msg=\"Sync completed\"\n")}
+	recorderMock.On("Stop", "test-run-id").Return(runLog).Once()
+	logsMock.On("CreateSyncRunLog", mock.MatchedBy(func(log *domain.SyncRunLog) bool {
+		return log.RunID == "test-run-id"
+	})).Return(nil, nil).Once()
+
+	// The heartbeat gets the saved log, to report the end of it on failure.
+	syncDone := make(chan struct{})
+	heartbeatMock.On("Start", job, createdRun).Once()
+	heartbeatMock.On("Finish", job, mock.Anything, runLog).Run(func(args mock.Arguments) {
+		close(syncDone)
+	}).Once()
+
+	vars := &environment.Variables{SyncInterval: "24h"}
+
+	r := runner.New(
+		runner.WithStore(storeMock),
+		runner.WithRcloneExecutor(execMock),
+		runner.WithRunLogs(recorderMock, logsMock),
+		runner.WithHeartbeat(heartbeatMock),
+		runner.WithSyncJob(job),
+		runner.WithScheduler(runner.NewScheduler(24*time.Hour)),
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errCh := make(chan error, 1)
+	go func() { errCh <- r.Run(ctx, vars) }()
+
+	<-syncDone
+	cancel()
+	err := <-errCh
+	require.NoError(t, err)
+}
+
+func TestRunner_Run_Hooks(t *testing.T) {
+	storeMock := domainmocks.NewSyncRunsReadWriter(t)
+	execMock := runnermocks.NewRcloneExecutor(t)
+	hooksMock := runnermocks.NewHookRunner(t)
+
+	pre := &domain.Hook{Name: "dump", Event: domain.HookPreSync, Command: []string{"dump"}}
+	success := &domain.Hook{Name: "report", Event: domain.HookPostSuccess, URL: "https://example.com"}
+	failure := &domain.Hook{Name: "alert", Event: domain.HookPostFailure, Command: []string{"alert"}}
+	always := &domain.Hook{Name: "cleanup", Event: domain.HookAlways, Command: []string{"cleanup"}}
+	job := &domain.SyncJob{Name: "test-job", Source: "source", Destination: "dest", Hooks: []*domain.Hook{always, failure, success, pre}}
+
+	createdRun := &domain.SyncRun{ID: "test-run-id", JobName: "test-job", Status: domain.StatusRunning}
+	storeMock.On("CreateSyncRun", mock.Anything).Return(createdRun, nil).Once()
+
+	var order []string
+	hookResult := func(ctx context.Context, hook *domain.Hook, job *domain.SyncJob, run *domain.SyncRun) *domain.HookResult {
+		order = append(order, hook.Name)
+		result := &domain.HookResult{Name: hook.Name, Event: hook.Event, Output: "ok"}
+		if hook == always {
+			result.Error = "exit status 1"
+		}
+		return result
+	}
+	hooksMock.On("Run", mock.Anything, pre, job, mock.MatchedBy(func(run *domain.SyncRun) bool {
+		return run.Status == domain.StatusRunning
+	})).Return(hookResult).Once()
+	execMock.On("Sync", mock.Anything, "source", "dest", mock.Anything).Return(&result.RcloneResult{FilesTransferred: 2}, nil).Once()
+	hooksMock.On("Run", mock.Anything, success, job, mock.MatchedBy(func(run *domain.SyncRun) bool {
+		return run.Status == domain.StatusSuccess && run.FilesTransferred == 2
+	})).Return(hookResult).Once()
+	hooksMock.On("Run", mock.Anything, always, job, mock.Anything).Return(hookResult).Once()
+
+	syncDone := make(chan struct{})
+	storeMock.On("UpdateSyncRun", mock.MatchedBy(func(run *domain.SyncRun) bool {
+		// A failed post-sync hook is recorded without failing the run.
+		return run.Status == domain.StatusSuccess && len(run.Hooks) == 3 &&
+			run.Hooks[0].Name == "dump" && run.Hooks[2].Error == "exit status 1"
+	})).Run(func(args mock.Arguments) {
+		close(syncDone)
+	}).Return(nil).Once()
+
+	vars := &environment.Variables{SyncInterval: "24h"}
+
+	r := runner.New(
+		runner.WithStore(storeMock),
+		runner.WithRcloneExecutor(execMock),
+		runner.WithHooks(hooksMock),
+		runner.WithSyncJob(job),
+		runner.WithScheduler(runner.NewScheduler(24*time.Hour)),
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errCh := make(chan error, 1)
+	go func() { errCh <- r.Run(ctx, vars) }()
+
+	<-syncDone
+	cancel()
+	err := <-errCh
+	require.NoError(t, err)
+	assert.Equal(t, []string{"dump", "report", "cleanup"}, order)
+}
+
+func TestRunner_Run_PreSyncHookFailsRun(t *testing.T) {
+	storeMock := domainmocks.NewSyncRunsReadWriter(t)
+	execMock := runnermocks.NewRcloneExecutor(t)
+	hooksMock := runnermocks.NewHookRunner(t)
+
+	pre := &domain.Hook{Name: "dump", Event: domain.HookPreSync, Command: []string{"dump"}, FailRun: true}
+	next := &domain.Hook{Name: "next", Event: domain.HookPreSync, Command: []string{"next"}}
+	failure := &domain.Hook{Name: "alert", Event: domain.HookPostFailure, Command: []string{"alert"}}
+	job := &domain.SyncJob{Name: "test-job", Source: "source", Destination: "dest", Hooks: []*domain.Hook{pre, next, failure}}
+
+	createdRun := &domain.SyncRun{ID: "test-run-id", JobName: "test-job", Status: domain.StatusRunning}
+	storeMock.On("CreateSyncRun", mock.Anything).Return(createdRun, nil).Once()
+	hooksMock.On("Run", mock.Anything, pre, job, mock.Anything).
+		Return(&domain.HookResult{Name: "dump", Event: domain.HookPreSync, Error: "exit status 2"}).Once()
+	hooksMock.On("Run", mock.Anything, failure, job, mock.Anything).
+		Return(&domain.HookResult{Name: "alert", Event: domain.HookPostFailure}).Once()
+
+	syncDone := make(chan struct{})
+	storeMock.On("UpdateSyncRun", mock.MatchedBy(func(run *domain.SyncRun) bool {
+		return run.Status == domain.StatusFailed && run.ErrorMessage == "pre_sync hook dump failed: exit status 2" &&
+			len(run.Hooks) == 2
+	})).Run(func(args mock.Arguments) {
+		close(syncDone)
+	}).Return(nil).Once()
+
+	vars := &environment.Variables{SyncInterval: "24h"}
+
+	r := runner.New(
+		runner.WithStore(storeMock),
+		runner.WithRcloneExecutor(execMock),
+		runner.WithHooks(hooksMock),
+		runner.WithSyncJob(job),
+		runner.WithScheduler(runner.NewScheduler(24*time.Hour)),
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errCh := make(chan error, 1)
+	go func() { errCh <- r.Run(ctx, vars) }()
+
+	<-syncDone
+	cancel()
+	err := <-errCh
+	require.NoError(t, err)
+	execMock.AssertNotCalled(t, "Sync", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestRunner_Run_CapacityCheck(t *testing.T) {
+	storeMock := domainmocks.NewSyncRunsReadWriter(t)
+	execMock := runnermocks.NewRcloneExecutor(t)
+	usageMock := runnermocks.NewUsageReader(t)
+
+	job := &domain.SyncJob{
+		Name: "test-job", Source: "source", Destinations: []string{"s3", "nas"},
+		Capacity: &domain.Capacity{Check: true, Reserve: 100},
+	}
+
+	createdRun := &domain.SyncRun{ID: "test-run-id", JobName: "test-job", Status: domain.StatusRunning}
+	storeMock.On("CreateSyncRun", mock.Anything).Return(createdRun, nil).Once()
+	// The free space of s3 is unknown: it is not scanned.
+	usageMock.On("Usage", mock.Anything, "s3").Return(&domain.DestinationUsage{Total: -1, Used: -1, Free: -1}, nil).Once()
+	usageMock.On("Usage", mock.Anything, "nas").Return(&domain.DestinationUsage{Total: 1000, Used: 500, Free: 500}, nil).Once()
+	dryRun := mock.MatchedBy(func(opts *options.RcloneOptions) bool { return opts.DryRun })
+	execMock.On("Sync", mock.Anything, "source", "nas", dryRun).
+		Return(&result.RcloneResult{Plan: &domain.SyncPlan{Copies: 1, CopyBytes: 300, Updates: 1, UpdateBytes: 150}}, nil).Once()
+
+	syncDone := make(chan struct{})
+	storeMock.On("UpdateSyncRun", mock.Anything).Run(func(args mock.Arguments) {
+		run := args.Get(0).(*domain.SyncRun)
+		assert.Equal(t, domain.StatusFailed, run.Status)
+		assert.Equal(t, "not enough free space, sync not started: nas needs 450 B with a reserve of 100 B, 500 B free", run.ErrorMessage)
+		require.Len(t, run.Usage, 1)
+		assert.Equal(t, "nas", run.Usage[0].Destination)
+		assert.Equal(t, int64(450), run.Usage[0].Required)
+		close(syncDone)
+	}).Return(nil).Once()
+
+	vars := &environment.Variables{SyncInterval: "24h"}
+
+	r := runner.New(
+		runner.WithStore(storeMock),
+		runner.WithRcloneExecutor(execMock),
+		runner.WithUsage(usageMock),
+		runner.WithSyncJob(job),
+		runner.WithScheduler(runner.NewScheduler(24*time.Hour)),
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errCh := make(chan error, 1)
+	go func() { errCh <- r.Run(ctx, vars) }()
+
+	<-syncDone
+	cancel()
+	err := <-errCh
+	require.NoError(t, err)
+}
+
+func TestRunner_Run_Usage(t *testing.T) {
+	storeMock := domainmocks.NewSyncRunsReadWriter(t)
+	execMock := runnermocks.NewRcloneExecutor(t)
+	usageMock := runnermocks.NewUsageReader(t)
+
+	job := &domain.SyncJob{
+		Name: "test-job", Source: "source", Destination: "nas",
+		Capacity: &domain.Capacity{Check: true},
+	}
+
+	createdRun := &domain.SyncRun{ID: "test-run-id", JobName: "test-job", Status: domain.StatusRunning}
+	storeMock.On("CreateSyncRun", mock.Anything).Return(createdRun, nil).Once()
+	usageMock.On("Usage", mock.Anything, "nas").Return(&domain.DestinationUsage{Total: 1000, Used: 500, Free: 500}, nil).Once()
+	dryRun := mock.MatchedBy(func(opts *options.RcloneOptions) bool { return opts.DryRun })
+	execMock.On("Sync", mock.Anything, "source", "nas", dryRun).
+		Return(&result.RcloneResult{Plan: &domain.SyncPlan{Copies: 1, CopyBytes: 300}}, nil).Once()
+	transfer := mock.MatchedBy(func(opts *options.RcloneOptions) bool { return !opts.DryRun })
+	execMock.On("Sync", mock.Anything, "source", "nas", transfer).
+		Return(&result.RcloneResult{FilesTransferred: 1, BytesTransferred: 300}, nil).Once()
+	usageMock.On("Usage", mock.Anything, "nas").Return(&domain.DestinationUsage{Total: 1000, Used: 800, Free: 200}, nil).Once()
+
+	syncDone := make(chan struct{})
+	storeMock.On("UpdateSyncRun", mock.Anything).Run(func(args mock.Arguments) {
+		run := args.Get(0).(*domain.SyncRun)
+		assert.Equal(t, domain.StatusSuccess, run.Status)
+		assert.Equal(t, []domain.DestinationUsage{{Destination: "nas", Total: 1000, Used: 800, Free: 200, Required: 300}}, run.Usage)
+		close(syncDone)
+	}).Return(nil).Once()
+
+	vars := &environment.Variables{SyncInterval: "24h"}
+
+	r := runner.New(
+		runner.WithStore(storeMock),
+		runner.WithRcloneExecutor(execMock),
+		runner.WithUsage(usageMock),
+		runner.WithSyncJob(job),
+		runner.WithScheduler(runner.NewScheduler(24*time.Hour)),
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errCh := make(chan error, 1)
+	go func() { errCh <- r.Run(ctx, vars) }()
+
+	<-syncDone
+	cancel()
+	err := <-errCh
+	require.NoError(t, err)
+}
+
+type notifications []*notify.Notification
+
+func (n *notifications) Notify(ctx context.Context, notification *notify.Notification) error {
+	*n = append(*n, notification)
+	return nil
+}
+
+func TestRunner_Run_Anomaly(t *testing.T) {
+	var history []*domain.SyncRun
+	for range 10 {
+		history = append(history, &domain.SyncRun{Status: domain.StatusSuccess, Mode: domain.ModeSync,
+			Changes: &domain.ChangeStats{Copies: 10, CopyBytes: 1 << 20, Extensions: map[string]int64{"txt": 10}}})
+	}
+	job := &domain.SyncJob{
+		Name: "test-job", Source: "source", Destination: "dest",
+		Anomaly: &domain.AnomalyDetection{Action: domain.AnomalyPause},
+	}
+	selector := &domain.JobAnomalySelector{JobName: "test-job"}
+
+	dryRun := mock.MatchedBy(func(opts *options.RcloneOptions) bool { return opts.DryRun })
+	ransom := &domain.SyncPlan{Copies: 10, Deletes: 900}
+
+	t.Run("flagged and paused before syncing", func(t *testing.T) {
+		storeMock := domainmocks.NewSyncRunsReadWriter(t)
+		execMock := runnermocks.NewRcloneExecutor(t)
+		anomaliesMock := domainmocks.NewJobAnomaliesReadWriter(t)
+		pausesMock := domainmocks.NewJobPausesReadWriter(t)
+		var sent notifications
+
+		storeMock.On("CreateSyncRun", mock.Anything).Return(&domain.SyncRun{ID: "test-run-id", JobName: "test-job", Mode: domain.ModeSync}, nil).Once()
+		anomaliesMock.On("GetJobAnomaly", selector).Return(nil, &bgerrors.Error{Code: bgerrors.CodeNotFound}).Once()
+		storeMock.On("ListSyncRuns", &domain.SyncRunsSelector{JobName: "test-job", Status: domain.StatusSuccess, Limit: domain.AnomalyBaselineRuns}).
+			Return(history, nil).Once()
+		// Only the dry run is executed: the sync never starts.
+		execMock.On("Sync", mock.Anything, "source", "dest", dryRun).Return(&result.RcloneResult{Plan: ransom}, nil).Once()
+		anomaliesMock.On("CreateJobAnomaly", mock.MatchedBy(func(a *domain.JobAnomaly) bool {
+			return a.RunID == "test-run-id" && a.Action == domain.AnomalyPause
+		})).Return(func(a *domain.JobAnomaly) (*domain.JobAnomaly, error) { return a, nil }).Once()
+		pausesMock.On("UpsertJobPause", mock.MatchedBy(func(p *domain.JobPause) bool {
+			return p.Scope == "test-job" && p.Reason == "anomalous run test-run-id, acknowledge to resume"
+		})).Return(func(p *domain.JobPause) (*domain.JobPause, error) { return p, nil }).Once()
+
+		syncDone := make(chan struct{})
+		storeMock.On("UpdateSyncRun", mock.Anything).Run(func(args mock.Arguments) {
+			run := args.Get(0).(*domain.SyncRun)
+			assert.Equal(t, domain.StatusFailed, run.Status)
+			assert.Equal(t, "anomalous changes, sync not started: 900 files deleted, usually 0", run.ErrorMessage)
+			assert.Nil(t, run.Changes)
+			require.True(t, run.Anomalous())
+			assert.Equal(t, []string{"900 files deleted, usually 0"}, run.Anomaly.Reasons)
+			close(syncDone)
+		}).Return(nil).Once()
+
+		r := runner.New(
+			runner.WithStore(storeMock),
+			runner.WithRcloneExecutor(execMock),
+			runner.WithAnomalies(anomaliesMock, pausesMock),
+			runner.WithNotifier(&sent),
+			runner.WithSyncJob(job),
+			runner.WithScheduler(runner.NewScheduler(24*time.Hour)),
+		)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		errCh := make(chan error, 1)
+		go func() { errCh <- r.Run(ctx, &environment.Variables{SyncInterval: "24h"}) }()
+
+		<-syncDone
+		cancel()
+		require.NoError(t, <-errCh)
+
+		require.Len(t, sent, 1)
+		assert.Equal(t, notify.EventRunAnomalous, sent[0].Event)
+		assert.Equal(t, notify.SeverityCritical, sent[0].Severity)
+		assert.Equal(t, "900 files deleted, usually 0. The run was not started, and the job is paused until the anomaly is acknowledged",
+			sent[0].Message)
+	})
+
+	t.Run("switched to copy mode before syncing", func(t *testing.T) {
+		storeMock := domainmocks.NewSyncRunsReadWriter(t)
+		execMock := runnermocks.NewRcloneExecutor(t)
+		anomaliesMock := domainmocks.NewJobAnomaliesReadWriter(t)
+		var sent notifications
+		job := *job
+		job.Anomaly = &domain.AnomalyDetection{Action: domain.AnomalyCopy}
+
+		storeMock.On("CreateSyncRun", mock.Anything).Return(&domain.SyncRun{ID: "test-run-id", JobName: "test-job", Mode: domain.ModeSync}, nil).Once()
+		anomaliesMock.On("GetJobAnomaly", selector).Return(nil, &bgerrors.Error{Code: bgerrors.CodeNotFound}).Once()
+		storeMock.On("ListSyncRuns", mock.Anything).Return(history, nil).Once()
+		execMock.On("Sync", mock.Anything, "source", "dest", dryRun).Return(&result.RcloneResult{Plan: ransom}, nil).Once()
+		anomaliesMock.On("CreateJobAnomaly", mock.MatchedBy(func(a *domain.JobAnomaly) bool { return a.Action == domain.AnomalyCopy })).
+			Return(func(a *domain.JobAnomaly) (*domain.JobAnomaly, error) { return a, nil }).Once()
+		// The run itself copies, without deleting.
+		execMock.On("Copy", mock.Anything, "source", "dest", mock.MatchedBy(func(opts *options.RcloneOptions) bool { return opts.RecordChanges })).
+			Return(&result.RcloneResult{Changes: &domain.ChangeStats{Copies: 10}}, nil).Once()
+
+		syncDone := make(chan struct{})
+		storeMock.On("UpdateSyncRun", mock.Anything).Run(func(args mock.Arguments) {
+			run := args.Get(0).(*domain.SyncRun)
+			assert.Equal(t, domain.StatusSuccess, run.Status)
+			assert.Equal(t, domain.ModeCopy, run.Mode)
+			assert.Equal(t, &domain.ChangeStats{Copies: 10}, run.Changes)
+			assert.True(t, run.Anomalous())
+			close(syncDone)
+		}).Return(nil).Once()
+
+		r := runner.New(
+			runner.WithStore(storeMock),
+			runner.WithRcloneExecutor(execMock),
+			runner.WithAnomalies(anomaliesMock, domainmocks.NewJobPausesReadWriter(t)),
+			runner.WithNotifier(&sent),
+			runner.WithSyncJob(&job),
+			runner.WithScheduler(runner.NewScheduler(24*time.Hour)),
+		)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		errCh := make(chan error, 1)
+		go func() { errCh <- r.Run(ctx, &environment.Variables{SyncInterval: "24h"}) }()
+
+		<-syncDone
+		cancel()
+		require.NoError(t, <-errCh)
+
+		require.Len(t, sent, 1)
+		assert.Equal(t, "900 files deleted, usually 0. The run is switched to copy mode, which never deletes from the destinations. "+
+			"The job runs in copy mode until the anomaly is acknowledged", sent[0].Message)
+	})
+
+	t.Run("copy mode until acknowledged", func(t *testing.T) {
+		storeMock := domainmocks.NewSyncRunsReadWriter(t)
+		execMock := runnermocks.NewRcloneExecutor(t)
+		anomaliesMock := domainmocks.NewJobAnomaliesReadWriter(t)
+
+		anomaliesMock.On("GetJobAnomaly", selector).
+			Return(&domain.JobAnomaly{JobName: "test-job", RunID: "previous-run", Action: domain.AnomalyCopy}, nil).Once()
+		storeMock.On("CreateSyncRun", mock.MatchedBy(func(run *domain.SyncRun) bool { return run.Mode == domain.ModeCopy })).
+			Return(&domain.SyncRun{ID: "test-run-id", JobName: "test-job", Mode: domain.ModeCopy}, nil).Once()
+		// The history holds no run in copy mode to compare with: the destination is not scanned.
+		storeMock.On("ListSyncRuns", mock.Anything).Return(history, nil).Once()
+		execMock.On("Copy", mock.Anything, "source", "dest", mock.Anything).
+			Return(&result.RcloneResult{Changes: &domain.ChangeStats{Copies: 12}}, nil).Once()
+
+		syncDone := make(chan struct{})
+		storeMock.On("UpdateSyncRun", mock.Anything).Run(func(args mock.Arguments) {
+			run := args.Get(0).(*domain.SyncRun)
+			assert.Equal(t, domain.StatusSuccess, run.Status)
+			assert.False(t, run.Anomalous())
+			close(syncDone)
+		}).Return(nil).Once()
+
+		r := runner.New(
+			runner.WithStore(storeMock),
+			runner.WithRcloneExecutor(execMock),
+			runner.WithAnomalies(anomaliesMock, domainmocks.NewJobPausesReadWriter(t)),
+			runner.WithSyncJob(job),
+			runner.WithScheduler(runner.NewScheduler(24*time.Hour)),
+		)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		errCh := make(chan error, 1)
+		go func() { errCh <- r.Run(ctx, &environment.Variables{SyncInterval: "24h"}) }()
+
+		<-syncDone
+		cancel()
+		require.NoError(t, <-errCh)
+	})
+}
+
+func TestRunner_Run_Canaries(t *testing.T) {
+	canaries := []*domain.Canary{
+		{Path: ".canary.txt", SHA256: "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"},
+		{Path: "finance/.canary.xlsx", SHA256: "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"},
+	}
+	job := &domain.SyncJob{Name: "test-job", Source: "source", Destination: "dest", Canaries: canaries}
+
+	run := func(t *testing.T, verifier runner.CanaryVerifier, execMock *runnermocks.RcloneExecutor, notifier notify.Notifier) *domain.SyncRun {
+		storeMock := domainmocks.NewSyncRunsReadWriter(t)
+		storeMock.On("CreateSyncRun", mock.Anything).Return(&domain.SyncRun{ID: "test-run-id", JobName: "test-job"}, nil).Once()
+
+		var finished *domain.SyncRun
+		syncDone := make(chan struct{})
+		storeMock.On("UpdateSyncRun", mock.Anything).Run(func(args mock.Arguments) {
+			finished = args.Get(0).(*domain.SyncRun)
+			close(syncDone)
+		}).Return(nil).Once()
+
+		r := runner.New(
+			runner.WithStore(storeMock),
+			runner.WithRcloneExecutor(execMock),
+			runner.WithCanaries(verifier),
+			runner.WithNotifier(notifier),
+			runner.WithSyncJob(job),
+			runner.WithScheduler(runner.NewScheduler(24*time.Hour)),
+		)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		errCh := make(chan error, 1)
+		go func() { errCh <- r.Run(ctx, &environment.Variables{SyncInterval: "24h"}) }()
+
+		<-syncDone
+		cancel()
+		require.NoError(t, <-errCh)
+
+		return finished
+	}
+
+	t.Run("intact", func(t *testing.T) {
+		verifierMock := runnermocks.NewCanaryVerifier(t)
+		execMock := runnermocks.NewRcloneExecutor(t)
+		var sent notifications
+
+		verifierMock.On("VerifyCanaries", mock.Anything, "source", canaries).Return([]domain.CanaryResult{
+			{Path: ".canary.txt", State: domain.CanaryIntact}, {Path: "finance/.canary.xlsx", State: domain.CanaryIntact},
+		}, nil).Once()
+		execMock.On("Sync", mock.Anything, "source", "dest", mock.Anything).Return(&result.RcloneResult{}, nil).Once()
+
+		finished := run(t, verifierMock, execMock, &sent)
+		assert.Equal(t, domain.StatusSuccess, finished.Status)
+		assert.Empty(t, sent)
+	})
+
+	t.Run("altered aborts before syncing", func(t *testing.T) {
+		verifierMock := runnermocks.NewCanaryVerifier(t)
+		// No transfer is expected.
+		execMock := runnermocks.NewRcloneExecutor(t)
+		var sent notifications
+
+		verifierMock.On("VerifyCanaries", mock.Anything, "source", canaries).Return([]domain.CanaryResult{
+			{Path: ".canary.txt", State: domain.CanaryAltered}, {Path: "finance/.canary.xlsx", State: domain.CanaryMissing},
+		}, nil).Once()
+
+		finished := run(t, verifierMock, execMock, &sent)
+		assert.Equal(t, domain.StatusCanaryFailed, finished.Status)
+		assert.Equal(t, "canary files missing or altered, sync not started: .canary.txt altered; finance/.canary.xlsx missing",
+			finished.ErrorMessage)
+
+		require.Len(t, sent, 1)
+		assert.Equal(t, notify.EventCanaryFailed, sent[0].Event)
+		assert.Equal(t, notify.SeverityCritical, sent[0].Severity)
+		assert.Equal(t, "test-job", sent[0].JobName)
+		assert.Contains(t, sent[0].Message, "Run test-run-id was aborted before syncing: .canary.txt altered; finance/.canary.xlsx missing")
+	})
+
+	t.Run("unreadable source fails", func(t *testing.T) {
+		verifierMock := runnermocks.NewCanaryVerifier(t)
+		execMock := runnermocks.NewRcloneExecutor(t)
+		var sent notifications
+
+		verifierMock.On("VerifyCanaries", mock.Anything, "source", canaries).Return(nil, errors.New("connection refused")).Once()
+
+		finished := run(t, verifierMock, execMock, &sent)
+		assert.Equal(t, domain.StatusFailed, finished.Status)
+		assert.Equal(t, "could not verify canary files: connection refused", finished.ErrorMessage)
+		assert.Empty(t, sent)
+	})
+}
+
+func TestRunner_Run_Manifest(t *testing.T) {
+	job := &domain.SyncJob{Name: "test-job", Source: "source", Destination: "dest", Manifest: true}
+	files := []domain.ManifestFile{{Path: "a.txt", Size: 3, Hash: "sha256:abc"}}
+	listed := []domain.ManifestFile{{Path: "a.txt", Size: 3, Hash: "sha256:abc", ModTime: time.Unix(1700000000, 0).UTC()}}
+	previous := domain.NewManifest(&domain.SyncRun{ID: "previous-run-id", JobName: "test-job"}, nil,
+		[]domain.ManifestDestination{{Destination: "dest", Entries: listed}}, time.Now())
+
+	storeMock := domainmocks.NewSyncRunsReadWriter(t)
+	storeMock.On("CreateSyncRun", mock.Anything).Return(&domain.SyncRun{ID: "test-run-id", JobName: "test-job"}, nil).Once()
+	syncDone := make(chan struct{})
+	storeMock.On("UpdateSyncRun", mock.Anything).Run(func(mock.Arguments) { close(syncDone) }).Return(nil).Once()
+	execMock := runnermocks.NewRcloneExecutor(t)
+	execMock.On("Sync", mock.Anything, "source", "dest", mock.Anything).Return(&result.RcloneResult{}, nil).Once()
+
+	var created *domain.Manifest
+	manifestsMock := domainmocks.NewManifestsReadWriter(t)
+	manifestsMock.On("GetManifest", &domain.ManifestSelector{JobName: "test-job"}).Return(previous, nil).Once()
+	manifestsMock.On("CreateManifest", mock.Anything).Run(func(args mock.Arguments) {
+		created = args.Get(0).(*domain.Manifest)
+	}).Return(func(m *domain.Manifest) (*domain.Manifest, error) { return m, nil }).Once()
+	storageMock := runnermocks.NewManifestStorage(t)
+	storageMock.On("ListFiles", mock.Anything, "dest", listed).Return(files, nil).Once()
+	storageMock.On("PutManifest", mock.Anything, "dest", mock.AnythingOfType("*domain.Manifest")).Return(nil).Once()
+
+	r := runner.New(
+		runner.WithStore(storeMock),
+		runner.WithRcloneExecutor(execMock),
+		runner.WithManifests(manifestsMock, storageMock),
+		runner.WithSyncJob(job),
+		runner.WithScheduler(runner.NewScheduler(24*time.Hour)),
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errCh := make(chan error, 1)
+	go func() { errCh <- r.Run(ctx, &environment.Variables{SyncInterval: "24h"}) }()
+
+	<-syncDone
+	cancel()
+	require.NoError(t, <-errCh)
+
+	require.NotNil(t, created)
+	assert.Equal(t, "test-run-id", created.RunID)
+	assert.Equal(t, int64(2), created.Sequence)
+	assert.Equal(t, previous.Hash, created.PreviousHash)
+	assert.Equal(t, files, created.Destination("dest").Entries)
+	assert.Equal(t, created.ComputeHash(), created.Hash)
+}
+
+func TestRunner_Run_Scrub(t *testing.T) {
+	files := []domain.ScrubFile{{Path: "a.txt", Size: 3}, {Path: "b.txt", Size: 5}}
+	selector := &domain.ScrubStateSelector{JobName: "scrub-job", Destination: "dest"}
+
+	run := func(t *testing.T, job *domain.SyncJob, statesMock *domainmocks.ScrubStatesReadWriter, scrubberMock *runnermocks.Scrubber,
+		manifestsMock *domainmocks.ManifestsReadWriter, sent *notifications) *domain.SyncRun {
+		storeMock := domainmocks.NewSyncRunsReadWriter(t)
+		storeMock.On("CreateSyncRun", mock.MatchedBy(func(run *domain.SyncRun) bool {
+			return run.Kind == domain.KindScrub && run.Mode == domain.ModeScrub
+		})).Return(&domain.SyncRun{ID: "test-run-id", JobName: "scrub-job", Kind: domain.KindScrub}, nil).Once()
+
+		var finished *domain.SyncRun
+		syncDone := make(chan struct{})
+		storeMock.On("UpdateSyncRun", mock.Anything).Run(func(args mock.Arguments) {
+			finished = args.Get(0).(*domain.SyncRun)
+			close(syncDone)
+		}).Return(nil).Once()
+
+		// The executor is never called: scrub jobs do not sync.
+		r := runner.New(
+			runner.WithStore(storeMock),
+			runner.WithRcloneExecutor(runnermocks.NewRcloneExecutor(t)),
+			runner.WithManifests(manifestsMock, runnermocks.NewManifestStorage(t)),
+			runner.WithScrubs(statesMock, scrubberMock),
+			runner.WithNotifier(sent),
+			runner.WithSyncJob(job),
+			runner.WithScheduler(runner.NewScheduler(24*time.Hour)),
+		)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		errCh := make(chan error, 1)
+		go func() { errCh <- r.Run(ctx, &environment.Variables{SyncInterval: "24h"}) }()
+
+		<-syncDone
+		cancel()
+		require.NoError(t, <-errCh)
+
+		return finished
+	}
+
+	t.Run("against the source", func(t *testing.T) {
+		job := &domain.SyncJob{Name: "scrub-job", Source: "source", Destination: "dest",
+			Scrub: &domain.Scrub{Job: "test-job", Percent: 50}}
+
+		// a.txt was checked in the rotation: b.txt is due.
+		statesMock := domainmocks.NewScrubStatesReadWriter(t)
+		statesMock.On("GetScrubState", selector).Return(&domain.ScrubState{JobName: "scrub-job", Destination: "dest", Rotation: 3,
+			Checked: map[string]time.Time{"a.txt": time.Now().Add(-time.Hour), "gone.txt": time.Now()}}, nil).Once()
+		statesMock.On("UpsertScrubState", mock.MatchedBy(func(state *domain.ScrubState) bool {
+			return state.Rotation == 3 && len(state.Checked) == 2 && !state.Checked["b.txt"].IsZero()
+		})).Return(func(state *domain.ScrubState) (*domain.ScrubState, error) { return state, nil }).Once()
+		scrubberMock := runnermocks.NewScrubber(t)
+		scrubberMock.On("ListFiles", mock.Anything, job, "dest").Return(files, nil).Once()
+		scrubberMock.On("Scrub", mock.Anything, job, "dest", files[1:], domain.ScrubAgainstSource).Return([]domain.ScrubCheck{
+			{Path: "b.txt", Size: 5, Result: domain.ScrubMismatched, Reason: "content hashes to sha256:x, the source to sha256:y"},
+		}, nil).Once()
+		var sent notifications
+
+		finished := run(t, job, statesMock, scrubberMock, nil, &sent)
+
+		assert.Equal(t, domain.StatusFailed, finished.Status)
+		assert.Equal(t, "scrub found problems: 1 mismatched files on dest: b.txt", finished.ErrorMessage)
+		assert.Equal(t, int64(1), finished.FilesTransferred)
+		assert.Equal(t, int64(5), finished.BytesTransferred)
+		require.NotNil(t, finished.Scrub)
+		assert.Equal(t, "test-job", finished.Scrub.Job)
+		assert.Equal(t, []domain.ScrubDestination{{
+			Destination:  "dest",
+			Files:        2,
+			Checked:      1,
+			CheckedBytes: 5,
+			Mismatches:   []domain.ScrubMismatch{{Path: "b.txt", Reason: "content hashes to sha256:x, the source to sha256:y"}},
+			Rotation:     3,
+			Coverage:     1,
+		}}, finished.Scrub.Destinations)
+
+		require.Len(t, sent, 1)
+		assert.Equal(t, notify.EventScrubMismatch, sent[0].Event)
+		assert.Equal(t, notify.SeverityCritical, sent[0].Severity)
+		assert.Equal(t, "Scrub of job test-job found 1 mismatched files", sent[0].Title)
+	})
+
+	t.Run("against the manifest", func(t *testing.T) {
+		job := &domain.SyncJob{Name: "scrub-job", Source: "source", Destination: "dest",
+			Scrub: &domain.Scrub{Job: "test-job", Bytes: 1 << 20, Against: domain.ScrubAgainstManifest}}
+		manifest := domain.NewManifest(&domain.SyncRun{ID: "run-1", JobName: "test-job"}, nil, []domain.ManifestDestination{
+			{Destination: "dest", Entries: []domain.ManifestFile{{Path: "a.txt", Size: 3, Hash: "md5:abc"}}},
+		}, time.Now())
+
+		manifestsMock := domainmocks.NewManifestsReadWriter(t)
+		manifestsMock.On("GetManifest", &domain.ManifestSelector{JobName: "test-job"}).Return(manifest, nil).Once()
+		statesMock := domainmocks.NewScrubStatesReadWriter(t)
+		statesMock.On("GetScrubState", selector).Return(nil, &bgerrors.Error{Code: bgerrors.CodeNotFound}).Once()
+		statesMock.On("UpsertScrubState", mock.MatchedBy(func(state *domain.ScrubState) bool {
+			return state.Rotation == 1 && len(state.Checked) == 1
+		})).Return(func(state *domain.ScrubState) (*domain.ScrubState, error) { return state, nil }).Once()
+		// The files are those of the manifest, with their hashes: the destination is not listed.
+		scrubberMock := runnermocks.NewScrubber(t)
+		scrubberMock.On("Scrub", mock.Anything, job, "dest", []domain.ScrubFile{{Path: "a.txt", Size: 3, Hash: "md5:abc"}},
+			domain.ScrubAgainstManifest).Return([]domain.ScrubCheck{{Path: "a.txt", Size: 3, Result: domain.ScrubMatched}}, nil).Once()
+		var sent notifications
+
+		finished := run(t, job, statesMock, scrubberMock, manifestsMock, &sent)
+
+		assert.Equal(t, domain.StatusSuccess, finished.Status)
+		assert.Empty(t, finished.ErrorMessage)
+		assert.Equal(t, domain.ScrubAgainstManifest, finished.Scrub.Against)
+		assert.Equal(t, 1.0, finished.Scrub.Destinations[0].Coverage)
+		assert.Empty(t, sent)
+	})
+}
+
+func TestRunner_VerifyManifests(t *testing.T) {
+	job := &domain.SyncJob{Name: "test-job", Source: "source", Destination: "dest", Manifest: true}
+	first := domain.NewManifest(&domain.SyncRun{ID: "run-1", JobName: "test-job"}, nil,
+		[]domain.ManifestDestination{{Destination: "old-dest"}}, time.Now())
+	second := domain.NewManifest(&domain.SyncRun{ID: "run-2", JobName: "test-job"}, first,
+		[]domain.ManifestDestination{{Destination: "dest"}}, time.Now())
+
+	t.Run("intact", func(t *testing.T) {
+		manifestsMock := domainmocks.NewManifestsReadWriter(t)
+		manifestsMock.On("ListManifests", &domain.ManifestsSelector{JobName: "test-job", Entries: true}).
+			Return([]*domain.Manifest{first, second}, nil).Once()
+		storageMock := runnermocks.NewManifestStorage(t)
+		storageMock.On("ReadManifests", mock.Anything, "dest", "test-job").Return([]*domain.Manifest{second}, nil).Once()
+		storageMock.On("ReadManifests", mock.Anything, "old-dest", "test-job").Return([]*domain.Manifest{first}, nil).Once()
+
+		r := runner.New(runner.WithManifests(manifestsMock, storageMock))
+		verification, err := r.VerifyManifests(context.Background(), job)
+		require.NoError(t, err)
+		assert.True(t, verification.OK(), verification.Problems)
+		assert.Equal(t, 2, verification.Manifests)
+		assert.Equal(t, 2, verification.Copies)
+	})
+
+	t.Run("rewritten history", func(t *testing.T) {
+		rewritten := *first
+		rewritten.Files = 100
+		rewritten.Hash = rewritten.ComputeHash()
+
+		manifestsMock := domainmocks.NewManifestsReadWriter(t)
+		manifestsMock.On("ListManifests", &domain.ManifestsSelector{JobName: "test-job", Entries: true}).
+			Return([]*domain.Manifest{&rewritten, second}, nil).Once()
+		storageMock := runnermocks.NewManifestStorage(t)
+		storageMock.On("ReadManifests", mock.Anything, "dest", "test-job").Return([]*domain.Manifest{second}, nil).Once()
+		storageMock.On("ReadManifests", mock.Anything, "old-dest", "test-job").Return(nil, errors.New("connection refused")).Once()
+
+		r := runner.New(runner.WithManifests(manifestsMock, storageMock))
+		verification, err := r.VerifyManifests(context.Background(), job)
+		require.NoError(t, err)
+		assert.Equal(t, []string{
+			"manifest 2 (run run-2) is not chained to the previous manifest",
+			"could not read the manifests on old-dest: connection refused",
+		}, verification.Problems)
+	})
+}
+
+func TestRunner_DryRun(t *testing.T) {
+	storeMock := domainmocks.NewSyncRunsReadWriter(t)
+	execMock := runnermocks.NewRcloneExecutor(t)
+
+	storeMock.On("CreateSyncRun", mock.MatchedBy(func(run *domain.SyncRun) bool { return run.Kind == domain.KindDryRun })).
+		Return(func(run *domain.SyncRun) (*domain.SyncRun, error) { return run, nil }).Once()
+	dryRun := mock.MatchedBy(func(opts *options.RcloneOptions) bool { return opts.DryRun && opts.StopAt.IsZero() })
+	execMock.On("Sync", mock.Anything, "source", "s3", dryRun).
+		Return(&result.RcloneResult{Plan: &domain.SyncPlan{
+			Changes: []domain.PlannedChange{{Action: domain.ChangeCopy, Path: "a.txt", Size: 3}},
+			Copies:  1, CopyBytes: 3,
+		}}, nil).Once()
+	execMock.On("Sync", mock.Anything, "source", "b2", dryRun).
+		Return(&result.RcloneResult{Plan: &domain.SyncPlan{
+			Changes: []domain.PlannedChange{{Action: domain.ChangeDelete, Path: "b.txt", Size: 5}},
+			Deletes: 1, DeleteBytes: 5,
+		}}, nil).Once()
+	storeMock.On("UpdateSyncRun", mock.Anything).Return(nil).Once()
+
+	job := &domain.SyncJob{
+		Name: "test-job", Source: "source", Destinations: []string{"s3", "b2"},
+		Hooks:   []*domain.Hook{{Event: domain.HookPreSync, Command: []string{"true"}}},
+		Windows: &domain.RunWindows{StopOnClose: true},
+	}
+	// Hooks and heartbeats are not run for dry runs: the mocks fail on any call.
+	r := runner.New(
+		runner.WithStore(storeMock),
+		runner.WithRcloneExecutor(execMock),
+		runner.WithHooks(runnermocks.NewHookRunner(t)),
+		runner.WithHeartbeat(runnermocks.NewHeartbeat(t)),
+		runner.WithSyncJob(job),
+	)
+
+	run, err := r.DryRun(context.Background(), job)
+	require.NoError(t, err)
+	assert.Equal(t, domain.KindDryRun, run.Kind)
+	assert.Equal(t, domain.StatusSuccess, run.Status)
+	assert.Equal(t, &domain.SyncPlan{
+		Changes: []domain.PlannedChange{
+			{Action: domain.ChangeCopy, Path: "a.txt", Size: 3, Destination: "s3"},
+			{Action: domain.ChangeDelete, Path: "b.txt", Size: 5, Destination: "b2"},
+		},
+		Copies: 1, CopyBytes: 3, Deletes: 1, DeleteBytes: 5,
+	}, run.Plan)
+
+	_, err = r.DryRun(context.Background(), &domain.SyncJob{Name: "two-way", Source: "a", Destination: "b", Mode: domain.ModeBisync})
+	require.Error(t, err)
+}
+
+func TestRunner_Restore(t *testing.T) {
+	execMock := runnermocks.NewRcloneExecutor(t)
+	encryption := &domain.Encryption{PasswordEnv: "BACKUP_PASSWORD"}
+	execMock.On("Copy", mock.Anything, "b2", "/tmp/restore", mock.MatchedBy(func(opts *options.RcloneOptions) bool {
+		return opts.SourceEncryption == encryption && opts.Encryption == nil
+	})).Return(&result.RcloneResult{FilesTransferred: 2, BytesTransferred: 10}, nil).Once()
+
+	job := &domain.SyncJob{Name: "test-job", Source: "source", Destinations: []string{"s3", "b2"}, Encryption: encryption}
+	r := runner.New(runner.WithRcloneExecutor(execMock), runner.WithSyncJob(job))
+
+	res, err := r.Restore(context.Background(), job, "b2", "/tmp/restore")
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), res.FilesTransferred)
+
+	_, err = r.Restore(context.Background(), job, "gcs", "/tmp/restore")
+	assert.ErrorContains(t, err, "gcs is not a destination of job test-job")
+}
+
+func TestRunner_Verify(t *testing.T) {
+	execMock := runnermocks.NewRcloneExecutor(t)
+	encryption := &domain.Encryption{PasswordEnv: "BACKUP_PASSWORD"}
+	check := &result.CheckResult{Matches: 3, Missing: []string{"new.txt"}}
+	execMock.On("Check", mock.Anything, "source", "s3", mock.MatchedBy(func(opts *options.RcloneOptions) bool {
+		return opts.Encryption == encryption
+	})).Return(&result.RcloneResult{Check: check}, nil).Once()
+
+	job := &domain.SyncJob{Name: "test-job", Source: "source", Destination: "s3", Encryption: encryption}
+	r := runner.New(runner.WithRcloneExecutor(execMock), runner.WithSyncJob(job))
+
+	got, err := r.Verify(context.Background(), job, "")
+	require.NoError(t, err)
+	assert.Equal(t, check, got)
+	assert.False(t, got.OK())
+}
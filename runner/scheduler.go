@@ -2,43 +2,562 @@ package runner
 
 import (
 	"context"
+	"log/slog"
+	"sync"
 	"time"
+
+	"github.com/eva01/backup-guardian/domain"
+	"github.com/eva01/backup-guardian/internal/errors"
 )
 
-// Scheduler triggers syncs at regular intervals.
+// maxCatchUpRuns caps the runs queued by the CatchUpAll policy after a long downtime.
+const maxCatchUpRuns = 24
+
+// defaultTriggerPollInterval is how often job triggers are polled while waiting for a job.
+const defaultTriggerPollInterval = 5 * time.Second
+
+// Scheduler decides when each job is due.
+//
+// With a job schedules store, it persists the last-run and next-due times of each job:
+// on startup, slots missed while the runner was down are caught up according to the
+// job's catch-up policy, and a restart before the next slot does not sync again.
+//
+// With a job triggers store, jobs triggered manually run as soon as possible, regardless
+// of their schedule and run windows.
+//
+// Jobs with dependencies are not scheduled: they run when triggered, by the runs of the jobs
+// they depend on.
+//
+// The jobs can be replaced while the scheduler runs, when the configuration is reloaded.
 type Scheduler struct {
-	interval time.Duration
-	tick     chan struct{}
+	interval     time.Duration
+	schedules    domain.JobSchedulesReadWriter
+	history      domain.SyncRunsReader
+	triggers     domain.JobTriggersReadWriter
+	pollInterval time.Duration
+	logger       *slog.Logger
+	now          func() time.Time
+
+	// mu guards the state below, updated by reloads while Next waits.
+	mu sync.Mutex
+	// wake interrupts the wait of Next when the jobs are replaced.
+	wake      chan struct{}
+	jobs      []*domain.SyncJob
+	nextDue   map[string]time.Time
+	triggered map[string]bool
+	// pipelines are the pipeline IDs of the triggers of the jobs running or deferred.
+	pipelines map[string]string
+	// queued are the triggers requested without a job triggers store, oldest first.
+	queued []*domain.JobTrigger
+}
+
+// SchedulerOption configures the scheduler.
+type SchedulerOption func(*Scheduler)
+
+// NewScheduler creates a scheduler. interval applies to jobs that do not set their own.
+func NewScheduler(interval time.Duration, options ...SchedulerOption) *Scheduler {
+	s := &Scheduler{
+		interval:     interval,
+		pollInterval: defaultTriggerPollInterval,
+		logger:       slog.Default(),
+		now:          time.Now,
+		wake:         make(chan struct{}, 1),
+		nextDue:      map[string]time.Time{},
+		triggered:    map[string]bool{},
+		pipelines:    map[string]string{},
+	}
+
+	for _, opt := range options {
+		opt(s)
+	}
+
+	return s
+}
+
+// WithJobSchedules sets the store persisting the scheduler state.
+// Without it, state is kept in memory and every job is due at startup.
+func WithJobSchedules(schedules domain.JobSchedulesReadWriter) SchedulerOption {
+	return func(s *Scheduler) { s.schedules = schedules }
+}
+
+// WithRunHistory sets the sync runs used to derive the state of jobs that have no
+// persisted schedule yet, e.g. after upgrading from a version without one.
+func WithRunHistory(history domain.SyncRunsReader) SchedulerOption {
+	return func(s *Scheduler) { s.history = history }
+}
+
+// WithJobTriggers sets the store of manual job triggers, polled every pollInterval
+// (5 seconds when zero). Without it, jobs only run on schedule.
+func WithJobTriggers(triggers domain.JobTriggersReadWriter, pollInterval time.Duration) SchedulerOption {
+	return func(s *Scheduler) {
+		s.triggers = triggers
+		if pollInterval > 0 {
+			s.pollInterval = pollInterval
+		}
+	}
+}
+
+// WithSchedulerLogger sets the logger.
+func WithSchedulerLogger(logger *slog.Logger) SchedulerOption {
+	return func(s *Scheduler) { s.logger = logger }
 }
 
-// NewScheduler creates a scheduler that fires at the given interval.
-func NewScheduler(interval time.Duration) *Scheduler {
-	return &Scheduler{
-		interval: interval,
-		tick:     make(chan struct{}, 1),
+// WithSchedulerClock sets the function used to read the current time.
+func WithSchedulerClock(now func() time.Time) SchedulerOption {
+	return func(s *Scheduler) { s.now = now }
+}
+
+// Start loads the persisted state of jobs and computes when each one is first due.
+func (s *Scheduler) Start(jobs []*domain.SyncJob) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.jobs = jobs
+	now := s.now()
+
+	for _, job := range jobs {
+		if !job.Scheduled() {
+			continue
+		}
+
+		schedule, err := s.loadSchedule(job)
+		if err != nil {
+			return err
+		}
+
+		if schedule == nil {
+			s.nextDue[job.Name] = now
+			continue
+		}
+
+		s.nextDue[job.Name] = s.catchUp(job, schedule, now)
 	}
+
+	return nil
 }
 
-// Run starts the scheduler loop, sending on C() at each interval.
-// Stops when ctx is cancelled.
-func (scheduler *Scheduler) Run(ctx context.Context) {
-	ticker := time.NewTicker(scheduler.interval)
-	defer ticker.Stop()
+// Update replaces the jobs, when the configuration is reloaded. The state of removed jobs is
+// dropped, and added jobs are scheduled as on Start. Changed jobs keep their state, except
+// jobs becoming scheduled or no longer scheduled, and jobs whose interval changed: their next
+// slot follows their last run by their new interval. A job running keeps its state, and is
+// rescheduled with its new configuration once done.
+func (s *Scheduler) Update(jobs []*domain.SyncJob) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 
+	previous := s.jobs
+	s.jobs = jobs
+	now := s.now()
+
+	for _, job := range previous {
+		if s.job(job.Name) == nil {
+			delete(s.nextDue, job.Name)
+			delete(s.triggered, job.Name)
+			delete(s.pipelines, job.Name)
+		}
+	}
+
+	for _, job := range jobs {
+		old := findJob(previous, job.Name)
+		if old != nil && old.Scheduled() == job.Scheduled() && s.jobInterval(old) == s.jobInterval(job) {
+			continue
+		}
+		if !job.Scheduled() {
+			// Wait for the next trigger, unless deferred in a pipeline.
+			if !s.triggered[job.Name] {
+				delete(s.nextDue, job.Name)
+			}
+			continue
+		}
+
+		schedule, err := s.loadSchedule(job)
+		if err != nil {
+			s.logger.Error("Failed to read job schedule", slog.String("job", job.Name), slog.Any("error", err))
+		}
+		switch due, ok := s.nextDue[job.Name]; {
+		case schedule != nil && old != nil && !schedule.LastRunAt.IsZero():
+			// The next slot follows the last run by the new interval.
+			s.nextDue[job.Name] = s.catchUp(job, &domain.JobSchedule{
+				JobName:   job.Name,
+				LastRunAt: schedule.LastRunAt,
+				NextDueAt: schedule.LastRunAt.Add(s.jobInterval(job)),
+			}, now)
+		case schedule != nil:
+			s.nextDue[job.Name] = s.catchUp(job, schedule, now)
+		case ok && old != nil && old.Scheduled():
+			// Without a persisted schedule, move the next slot by the change of interval.
+			s.nextDue[job.Name] = due.Add(s.jobInterval(job) - s.jobInterval(old))
+		default:
+			s.nextDue[job.Name] = now
+		}
+	}
+
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// catchUp returns the first due time of job from its persisted schedule, applying the
+// job's catch-up policy to the slots missed before now.
+func (s *Scheduler) catchUp(job *domain.SyncJob, schedule *domain.JobSchedule, now time.Time) time.Time {
+	interval := s.jobInterval(job)
+
+	due := schedule.NextDueAt
+	// An interval shortened since the last run takes effect right away.
+	if !schedule.LastRunAt.IsZero() && schedule.LastRunAt.Add(interval).Before(due) {
+		due = schedule.LastRunAt.Add(interval)
+	}
+
+	missed := (&domain.JobSchedule{NextDueAt: due}).MissedRuns(interval, now)
+	if missed == 0 {
+		return due
+	}
+
+	attrs := []any{
+		slog.String("job", job.Name),
+		slog.String("catch_up", job.CatchUpPolicy()),
+		slog.Int("missed", missed),
+		slog.Time("last_run_at", schedule.LastRunAt),
+	}
+
+	switch job.CatchUpPolicy() {
+	case domain.CatchUpSkip:
+		due = due.Add(time.Duration(missed) * interval)
+		s.logger.Info("Skipping missed runs", append(attrs, slog.Time("next_due_at", due))...)
+	case domain.CatchUpAll:
+		if missed > maxCatchUpRuns {
+			due = due.Add(time.Duration(missed-maxCatchUpRuns) * interval)
+			missed = maxCatchUpRuns
+		}
+		s.logger.Info("Catching up missed runs", append(attrs, slog.Int("runs", missed))...)
+	default:
+		s.logger.Info("Catching up missed runs", append(attrs, slog.Int("runs", 1))...)
+	}
+
+	return due
+}
+
+// loadSchedule returns the persisted schedule of job, or one derived from its latest
+// sync run. It returns nil when the job never ran.
+func (s *Scheduler) loadSchedule(job *domain.SyncJob) (*domain.JobSchedule, error) {
+	if s.schedules == nil {
+		return nil, nil
+	}
+
+	schedule, err := s.schedules.GetJobSchedule(&domain.JobScheduleSelector{JobName: job.Name})
+	if err == nil {
+		return schedule, nil
+	}
+	if errors.ErrorCode(err) != errors.CodeNotFound {
+		return nil, err
+	}
+
+	if s.history == nil {
+		return nil, nil
+	}
+
+	runs, err := s.history.ListSyncRuns(&domain.SyncRunsSelector{JobName: job.Name, Limit: 1})
+	if err != nil {
+		return nil, err
+	}
+	if len(runs) == 0 || runs[0].StartedAt.IsZero() {
+		return nil, nil
+	}
+
+	return &domain.JobSchedule{
+		JobName:   job.Name,
+		LastRunAt: runs[0].StartedAt,
+		NextDueAt: runs[0].StartedAt.Add(s.jobInterval(job)),
+	}, nil
+}
+
+// Next blocks until a job is triggered, or is due and inside one of its run windows, and
+// returns it. Triggered jobs come first, in the order they were triggered. When several
+// jobs are due, the one that has been due the longest comes first.
+// It returns ctx.Err() when ctx is cancelled.
+func (s *Scheduler) Next(ctx context.Context) (*domain.SyncJob, error) {
 	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		job, wait := s.poll()
+		if job != nil {
+			return job, nil
+		}
+
+		// Without a wait, only a reload can make a job due.
+		timer := time.NewTimer(wait)
+		if wait <= 0 {
+			timer.Stop()
+		}
 		select {
 		case <-ctx.Done():
-			return
-		case <-ticker.C:
-			select {
-			case scheduler.tick <- struct{}{}:
-			default:
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-s.wake:
+			timer.Stop()
+		case <-timer.C:
+		}
+	}
+}
+
+// poll returns the next job to run, or how long to wait before polling again. It returns no
+// job and a zero wait when there is nothing to wait for but a reload.
+func (s *Scheduler) poll() (*domain.SyncJob, time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if job := s.nextTriggered(); job != nil {
+		return job, 0
+	}
+
+	job, due := s.earliest()
+	if job == nil && s.triggers == nil {
+		return nil, 0
+	}
+
+	wait := s.pollInterval
+	if job != nil {
+		wait = due.Sub(s.now())
+		if wait <= 0 {
+			return job, 0
+		}
+	}
+	if s.triggers != nil && wait > s.pollInterval {
+		wait = s.pollInterval
+	}
+
+	return nil, wait
+}
+
+// Trigger requests a run of the job of trigger as soon as possible. Without a job triggers
+// store, the trigger is kept in memory.
+func (s *Scheduler) Trigger(trigger *domain.JobTrigger) error {
+	if s.triggers == nil {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		// As with the store, a pending trigger of the job is kept as is.
+		for _, queued := range s.queued {
+			if queued.JobName == trigger.JobName {
+				return nil
 			}
 		}
+		s.queued = append(s.queued, trigger)
+		return nil
+	}
+
+	_, err := s.triggers.UpsertJobTrigger(trigger)
+
+	return err
+}
+
+// PipelineID returns the pipeline ID of the trigger of job, running or deferred, or "" when
+// the job was not triggered by a pipeline.
+func (s *Scheduler) PipelineID(job *domain.SyncJob) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.pipelines[job.Name]
+}
+
+// nextTriggered consumes the oldest trigger of a configured job and returns the job, or nil
+// when no job is triggered. Triggers of unknown jobs are dropped.
+func (s *Scheduler) nextTriggered() *domain.SyncJob {
+	if len(s.queued) > 0 {
+		trigger := s.queued[0]
+		s.queued = s.queued[1:]
+		return s.triggerJob(trigger)
+	}
+	if s.triggers == nil {
+		return nil
+	}
+
+	triggers, err := s.triggers.ListJobTriggers()
+	if err != nil {
+		s.logger.Error("Failed to read job triggers", slog.Any("error", err))
+		return nil
+	}
+
+	for _, trigger := range triggers {
+		// A trigger that cannot be consumed is not run, so that it does not run in a loop.
+		if err := s.triggers.DeleteJobTrigger(&domain.JobTriggerSelector{JobName: trigger.JobName}); err != nil {
+			s.logger.Error("Failed to delete job trigger", slog.String("job", trigger.JobName), slog.Any("error", err))
+			continue
+		}
+
+		if job := s.triggerJob(trigger); job != nil {
+			return job
+		}
 	}
+
+	return nil
+}
+
+// triggerJob records the consumed trigger and returns its job, or nil when the job is unknown.
+func (s *Scheduler) triggerJob(trigger *domain.JobTrigger) *domain.SyncJob {
+	job := s.job(trigger.JobName)
+	if job == nil {
+		s.logger.Warn("Dropping trigger of unknown job", slog.String("job", trigger.JobName))
+		return nil
+	}
+
+	attrs := []any{slog.String("job", job.Name), slog.Time("requested_at", trigger.RequestedAt)}
+	if trigger.PipelineID != "" {
+		attrs = append(attrs, slog.String("pipeline_id", trigger.PipelineID))
+	}
+	s.logger.Info("Job triggered", attrs...)
+	s.triggered[job.Name] = true
+	s.pipelines[job.Name] = trigger.PipelineID
+
+	return job
 }
 
-// C returns the channel that receives a signal at each scheduled tick.
-func (scheduler *Scheduler) C() <-chan struct{} {
-	return scheduler.tick
+// Deferred records that the run of job was deferred to its next run window. Scheduled jobs
+// stay due; jobs with dependencies become due, to resume in their pipeline.
+func (s *Scheduler) Deferred(job *domain.SyncJob) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.job(job.Name) == nil {
+		return
+	}
+	if !job.Scheduled() && s.nextDue[job.Name].IsZero() {
+		s.nextDue[job.Name] = s.now()
+	}
+}
+
+// Done records a run of job started at startedAt and schedules its next slot.
+// The state is updated in memory even when persisting it fails. When the configuration was
+// reloaded during the run, the next slot follows the new configuration of the job; a job
+// removed is forgotten.
+func (s *Scheduler) Done(job *domain.SyncJob, startedAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	next := s.reschedule(job, startedAt)
+	if next.IsZero() || s.schedules == nil {
+		return nil
+	}
+
+	_, err := s.schedules.UpsertJobSchedule(&domain.JobSchedule{
+		JobName:   job.Name,
+		LastRunAt: startedAt,
+		NextDueAt: next,
+	})
+
+	return err
+}
+
+// Missed schedules the next slot of job after a run due at startedAt that did not happen, as
+// it could not be recorded. Unlike Done, the schedule is not persisted: the slot stays due
+// in the database, and is caught up after a restart.
+func (s *Scheduler) Missed(job *domain.SyncJob, startedAt time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.reschedule(job, startedAt)
+}
+
+// reschedule schedules the next slot of job after a run started at startedAt, and returns it,
+// or zero when job is not scheduled anymore. Called with s.mu held.
+func (s *Scheduler) reschedule(job *domain.SyncJob, startedAt time.Time) time.Time {
+	job = s.job(job.Name)
+	if job == nil {
+		return time.Time{}
+	}
+	interval := s.jobInterval(job)
+
+	triggered := s.triggered[job.Name]
+	delete(s.triggered, job.Name)
+	delete(s.pipelines, job.Name)
+
+	// Jobs with dependencies wait for the next trigger.
+	if !job.Scheduled() {
+		delete(s.nextDue, job.Name)
+		return time.Time{}
+	}
+
+	next := startedAt.Add(interval)
+	// A triggered run restarts the schedule, so that it does not replay missed slots.
+	if job.CatchUpPolicy() == domain.CatchUpAll && !triggered {
+		// Stay on the slot grid: remaining missed slots are due immediately.
+		next = s.nextDue[job.Name].Add(interval)
+	}
+	s.nextDue[job.Name] = next
+
+	return next
+}
+
+// NextDue returns when job is next due.
+func (s *Scheduler) NextDue(job *domain.SyncJob) time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.nextDue[job.Name]
+}
+
+// StartAt returns when job may next start: its next due time, postponed to the opening
+// of its next run window. It returns the zero time when no window opens within a year.
+func (s *Scheduler) StartAt(job *domain.SyncJob) time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.startAt(job)
+}
+
+func (s *Scheduler) startAt(job *domain.SyncJob) time.Time {
+	due := s.nextDue[job.Name]
+	if job.Windows == nil || due.IsZero() {
+		return due
+	}
+
+	// A slot missed while the window was open must not start once it has closed.
+	if now := s.now(); due.Before(now) {
+		due = now
+	}
+
+	return job.Windows.NextOpen(due)
+}
+
+func (s *Scheduler) earliest() (*domain.SyncJob, time.Time) {
+	var job *domain.SyncJob
+	var start time.Time
+
+	for _, j := range s.jobs {
+		st := s.startAt(j)
+		if st.IsZero() {
+			continue
+		}
+		if job == nil || st.Before(start) {
+			job, start = j, st
+		}
+	}
+
+	return job, start
+}
+
+func (s *Scheduler) job(name string) *domain.SyncJob {
+	return findJob(s.jobs, name)
+}
+
+// findJob returns the job named name, or nil.
+func findJob(jobs []*domain.SyncJob, name string) *domain.SyncJob {
+	for _, job := range jobs {
+		if job.Name == name {
+			return job
+		}
+	}
+
+	return nil
+}
+
+func (s *Scheduler) jobInterval(job *domain.SyncJob) time.Duration {
+	if job.Interval > 0 {
+		return job.Interval
+	}
+
+	return s.interval
 }
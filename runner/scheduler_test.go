@@ -5,6 +5,11 @@ import (
 	"testing"
 	"time"
 
+	"github.com/eva01/backup-guardian/domain"
+	domainmocks "github.com/eva01/backup-guardian/domain/mocks"
+	"github.com/eva01/backup-guardian/internal/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 )
 
@@ -13,28 +18,340 @@ func TestScheduler(t *testing.T) {
 	s := NewScheduler(interval)
 	require.NotNil(t, s)
 
+	job := &domain.SyncJob{Name: "job"}
+	require.NoError(t, s.Start([]*domain.SyncJob{job}))
+
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
-	go s.Run(ctx)
+	// Without persisted state, the job is due at startup.
+	next, err := s.Next(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, job, next)
+
+	require.NoError(t, s.Done(job, time.Now()))
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		next, err = s.Next(ctx)
+	}()
 
 	select {
-	case <-s.C():
-		// received at least one tick
-	case <-time.After(2 * interval):
-		t.Fatal("expected at least one tick within 2 intervals")
+	case <-done:
+		require.NoError(t, err)
+		assert.Equal(t, job, next)
+	case <-time.After(20 * interval):
+		t.Fatal("expected the job to be due again within a few intervals")
 	}
 }
 
 func TestScheduler_StopsOnContextCancel(t *testing.T) {
-	interval := 10 * time.Millisecond
-	s := NewScheduler(interval)
-	ctx, cancel := context.WithCancel(context.Background())
+	s := NewScheduler(time.Hour)
+	job := &domain.SyncJob{Name: "job"}
+	require.NoError(t, s.Start([]*domain.SyncJob{job}))
+	require.NoError(t, s.Done(job, time.Now()))
 
-	go s.Run(ctx)
+	ctx, cancel := context.WithCancel(context.Background())
 	cancel()
 
-	// Run() should return; no panic or hang. Give it a moment.
-	time.Sleep(2 * interval)
-	// If we get here without deadlock, the goroutine exited.
+	_, err := s.Next(ctx)
+	require.ErrorIs(t, err, context.Canceled)
+}
+
+func TestScheduler_EarliestJobFirst(t *testing.T) {
+	s := NewScheduler(time.Hour)
+	job1 := &domain.SyncJob{Name: "job-1"}
+	job2 := &domain.SyncJob{Name: "job-2", Interval: time.Minute}
+	require.NoError(t, s.Start([]*domain.SyncJob{job1, job2}))
+
+	start := time.Now()
+	require.NoError(t, s.Done(job1, start))
+	require.NoError(t, s.Done(job2, start))
+
+	assert.Equal(t, start.Add(time.Hour), s.NextDue(job1))
+	assert.Equal(t, start.Add(time.Minute), s.NextDue(job2))
+
+	job, _ := s.earliest()
+	assert.Equal(t, job2, job)
+}
+
+func TestScheduler_Update(t *testing.T) {
+	s := NewScheduler(time.Hour)
+	kept := &domain.SyncJob{Name: "kept"}
+	changed := &domain.SyncJob{Name: "changed"}
+	removed := &domain.SyncJob{Name: "removed"}
+	require.NoError(t, s.Start([]*domain.SyncJob{kept, changed, removed}))
+
+	start := time.Now()
+	for _, job := range []*domain.SyncJob{kept, changed, removed} {
+		require.NoError(t, s.Done(job, start))
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	type next struct {
+		job *domain.SyncJob
+		err error
+	}
+	nextCh := make(chan next, 1)
+	go func() {
+		job, err := s.Next(ctx)
+		nextCh <- next{job, err}
+	}()
+
+	changedAgain := &domain.SyncJob{Name: "changed", Interval: 2 * time.Hour}
+	added := &domain.SyncJob{Name: "added"}
+	s.Update([]*domain.SyncJob{kept, changedAgain, added})
+
+	// The wait is interrupted, and the added job is due.
+	select {
+	case got := <-nextCh:
+		require.NoError(t, got.err)
+		assert.Equal(t, added, got.job)
+	case <-time.After(time.Second):
+		t.Fatal("expected the added job to be due after the update")
+	}
+
+	assert.Equal(t, start.Add(time.Hour), s.NextDue(kept))
+	assert.Equal(t, start.Add(2*time.Hour), s.NextDue(changedAgain))
+	assert.True(t, s.NextDue(removed).IsZero())
+
+	// A run of a removed job, started before the update, is forgotten once done.
+	require.NoError(t, s.Done(removed, time.Now()))
+	assert.True(t, s.NextDue(removed).IsZero())
+	// A run of a changed job is rescheduled with its new interval.
+	require.NoError(t, s.Done(changed, start))
+	assert.Equal(t, start.Add(2*time.Hour), s.NextDue(changedAgain))
+}
+
+func TestScheduler_Start_PersistedState(t *testing.T) {
+	now := time.Date(2026, 1, 3, 0, 30, 0, 0, time.UTC)
+	interval := 6 * time.Hour
+	lastRun := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	// Down for two days: slots from 06:00 on January 1st were missed.
+	schedule := &domain.JobSchedule{JobName: "job", LastRunAt: lastRun, NextDueAt: lastRun.Add(interval)}
+
+	newScheduler := func(t *testing.T, schedule *domain.JobSchedule) *Scheduler {
+		schedulesMock := domainmocks.NewJobSchedulesReadWriter(t)
+		schedulesMock.On("GetJobSchedule", &domain.JobScheduleSelector{JobName: "job"}).Return(schedule, nil).Once()
+		schedulesMock.On("UpsertJobSchedule", mock.Anything).Return(func(s *domain.JobSchedule) (*domain.JobSchedule, error) {
+			return s, nil
+		}).Maybe()
+
+		return NewScheduler(interval, WithJobSchedules(schedulesMock), WithSchedulerClock(func() time.Time { return now }))
+	}
+
+	t.Run("not due yet", func(t *testing.T) {
+		job := &domain.SyncJob{Name: "job"}
+		s := newScheduler(t, &domain.JobSchedule{JobName: "job", LastRunAt: now.Add(-time.Hour), NextDueAt: now.Add(5 * time.Hour)})
+		require.NoError(t, s.Start([]*domain.SyncJob{job}))
+		assert.Equal(t, now.Add(5*time.Hour), s.NextDue(job))
+	})
+
+	t.Run("interval shortened", func(t *testing.T) {
+		job := &domain.SyncJob{Name: "job", Interval: time.Hour}
+		s := newScheduler(t, &domain.JobSchedule{JobName: "job", LastRunAt: now.Add(-30 * time.Minute), NextDueAt: now.Add(5 * time.Hour)})
+		require.NoError(t, s.Start([]*domain.SyncJob{job}))
+		assert.Equal(t, now.Add(30*time.Minute), s.NextDue(job))
+	})
+
+	t.Run("interval lengthened by a reload", func(t *testing.T) {
+		persisted := &domain.JobSchedule{JobName: "job", LastRunAt: now.Add(-time.Hour), NextDueAt: now.Add(5 * time.Hour)}
+		schedulesMock := domainmocks.NewJobSchedulesReadWriter(t)
+		schedulesMock.On("GetJobSchedule", &domain.JobScheduleSelector{JobName: "job"}).Return(persisted, nil).Twice()
+		s := NewScheduler(interval, WithJobSchedules(schedulesMock), WithSchedulerClock(func() time.Time { return now }))
+
+		require.NoError(t, s.Start([]*domain.SyncJob{{Name: "job"}}))
+		assert.Equal(t, now.Add(5*time.Hour), s.NextDue(&domain.SyncJob{Name: "job"}))
+
+		longer := &domain.SyncJob{Name: "job", Interval: 12 * time.Hour}
+		s.Update([]*domain.SyncJob{longer})
+		assert.Equal(t, now.Add(11*time.Hour), s.NextDue(longer))
+	})
+
+	t.Run("catch up once", func(t *testing.T) {
+		job := &domain.SyncJob{Name: "job", CatchUp: domain.CatchUpOnce}
+		s := newScheduler(t, schedule)
+		require.NoError(t, s.Start([]*domain.SyncJob{job}))
+		assert.False(t, s.NextDue(job).After(now))
+
+		require.NoError(t, s.Done(job, now))
+		assert.Equal(t, now.Add(interval), s.NextDue(job))
+	})
+
+	t.Run("catch up all", func(t *testing.T) {
+		job := &domain.SyncJob{Name: "job", CatchUp: domain.CatchUpAll}
+		s := newScheduler(t, schedule)
+		require.NoError(t, s.Start([]*domain.SyncJob{job}))
+
+		runs := 0
+		for !s.NextDue(job).After(now) {
+			runs++
+			require.NoError(t, s.Done(job, now))
+		}
+		assert.Equal(t, 8, runs)
+		assert.Equal(t, time.Date(2026, 1, 3, 6, 0, 0, 0, time.UTC), s.NextDue(job))
+	})
+
+	t.Run("catch up all is capped", func(t *testing.T) {
+		job := &domain.SyncJob{Name: "job", CatchUp: domain.CatchUpAll}
+		s := newScheduler(t, &domain.JobSchedule{JobName: "job", NextDueAt: now.Add(-100 * interval)})
+		require.NoError(t, s.Start([]*domain.SyncJob{job}))
+		assert.Equal(t, now.Add(-(maxCatchUpRuns-1)*interval), s.NextDue(job))
+	})
+
+	t.Run("skip", func(t *testing.T) {
+		job := &domain.SyncJob{Name: "job", CatchUp: domain.CatchUpSkip}
+		s := newScheduler(t, schedule)
+		require.NoError(t, s.Start([]*domain.SyncJob{job}))
+		assert.Equal(t, time.Date(2026, 1, 3, 6, 0, 0, 0, time.UTC), s.NextDue(job))
+	})
+}
+
+func TestScheduler_Start_RunHistory(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	job := &domain.SyncJob{Name: "job"}
+
+	schedulesMock := domainmocks.NewJobSchedulesReadWriter(t)
+	schedulesMock.On("GetJobSchedule", mock.Anything).Return(nil, &errors.Error{Code: errors.CodeNotFound}).Once()
+	runsMock := domainmocks.NewSyncRunsReadWriter(t)
+	runsMock.On("ListSyncRuns", &domain.SyncRunsSelector{JobName: "job", Limit: 1}).
+		Return([]*domain.SyncRun{{ID: "run", JobName: "job", StartedAt: now.Add(-time.Hour)}}, nil).Once()
+
+	s := NewScheduler(6*time.Hour, WithJobSchedules(schedulesMock), WithRunHistory(runsMock),
+		WithSchedulerClock(func() time.Time { return now }))
+	require.NoError(t, s.Start([]*domain.SyncJob{job}))
+	assert.Equal(t, now.Add(5*time.Hour), s.NextDue(job))
+}
+
+func TestScheduler_Done_Persists(t *testing.T) {
+	startedAt := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	job := &domain.SyncJob{Name: "job"}
+
+	schedulesMock := domainmocks.NewJobSchedulesReadWriter(t)
+	schedulesMock.On("GetJobSchedule", mock.Anything).Return(nil, &errors.Error{Code: errors.CodeNotFound}).Once()
+	schedulesMock.On("UpsertJobSchedule", &domain.JobSchedule{
+		JobName:   "job",
+		LastRunAt: startedAt,
+		NextDueAt: startedAt.Add(time.Hour),
+	}).Return(nil, nil).Once()
+
+	s := NewScheduler(time.Hour, WithJobSchedules(schedulesMock))
+	require.NoError(t, s.Start([]*domain.SyncJob{job}))
+	require.NoError(t, s.Done(job, startedAt))
+}
+
+func TestScheduler_Missed_NotPersisted(t *testing.T) {
+	startedAt := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	job := &domain.SyncJob{Name: "job"}
+
+	schedulesMock := domainmocks.NewJobSchedulesReadWriter(t)
+	schedulesMock.On("GetJobSchedule", mock.Anything).Return(nil, &errors.Error{Code: errors.CodeNotFound}).Once()
+
+	s := NewScheduler(time.Hour, WithJobSchedules(schedulesMock))
+	require.NoError(t, s.Start([]*domain.SyncJob{job}))
+	s.Missed(job, startedAt)
+	assert.Equal(t, startedAt.Add(time.Hour), s.NextDue(job))
+}
+
+func TestScheduler_StartAt_Windows(t *testing.T) {
+	// Wednesday 2026-01-07 12:00 UTC.
+	now := time.Date(2026, 1, 7, 12, 0, 0, 0, time.UTC)
+	nightly := &domain.RunWindows{Windows: []domain.TimeWindow{{Start: 22 * time.Hour, End: 6 * time.Hour}}}
+	job := &domain.SyncJob{Name: "job", Windows: nightly}
+	never := &domain.SyncJob{
+		Name:    "never",
+		Windows: &domain.RunWindows{Windows: []domain.TimeWindow{{Days: []time.Weekday{time.Monday}}}},
+	}
+	never.Windows.Blackouts = []domain.DateRange{{From: now.AddDate(-1, 0, 0).Truncate(24 * time.Hour), To: now.AddDate(2, 0, 0).Truncate(24 * time.Hour)}}
+
+	s := NewScheduler(time.Hour, WithSchedulerClock(func() time.Time { return now }))
+	require.NoError(t, s.Start([]*domain.SyncJob{job, never}))
+
+	// Due now, but postponed to the opening of tonight's window.
+	assert.Equal(t, time.Date(2026, 1, 7, 22, 0, 0, 0, time.UTC), s.StartAt(job))
+
+	// Due inside the window: starts when due.
+	require.NoError(t, s.Done(job, time.Date(2026, 1, 7, 22, 0, 0, 0, time.UTC)))
+	assert.Equal(t, time.Date(2026, 1, 7, 23, 0, 0, 0, time.UTC), s.StartAt(job))
+
+	// No window opens within a year.
+	assert.True(t, s.StartAt(never).IsZero())
+
+	next, _ := s.earliest()
+	assert.Equal(t, job, next)
+}
+
+func TestScheduler_Next_Triggered(t *testing.T) {
+	job := &domain.SyncJob{Name: "job", CatchUp: domain.CatchUpAll}
+	// Triggers bypass run windows.
+	closed := &domain.RunWindows{Blackouts: []domain.DateRange{{From: time.Now().AddDate(-1, 0, 0), To: time.Now().AddDate(1, 0, 0)}}}
+	windowed := &domain.SyncJob{Name: "windowed", Windows: closed}
+
+	triggersMock := domainmocks.NewJobTriggersReadWriter(t)
+	triggersMock.On("ListJobTriggers").Return([]*domain.JobTrigger{{JobName: "unknown"}, {JobName: "windowed"}}, nil).Once()
+	triggersMock.On("DeleteJobTrigger", &domain.JobTriggerSelector{JobName: "unknown"}).Return(nil).Once()
+	triggersMock.On("DeleteJobTrigger", &domain.JobTriggerSelector{JobName: "windowed"}).Return(nil).Once()
+	triggersMock.On("ListJobTriggers").Return([]*domain.JobTrigger{}, nil).Once()
+	triggersMock.On("ListJobTriggers").Return([]*domain.JobTrigger{{JobName: "job"}}, nil).Once()
+	triggersMock.On("DeleteJobTrigger", &domain.JobTriggerSelector{JobName: "job"}).Return(nil).Once()
+
+	s := NewScheduler(time.Hour, WithJobTriggers(triggersMock, time.Millisecond))
+	require.NoError(t, s.Start([]*domain.SyncJob{job, windowed}))
+	start := time.Now()
+	require.NoError(t, s.Done(job, start))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	next, err := s.Next(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, windowed, next)
+
+	// The job is not due for an hour: it runs when triggered on a later poll.
+	next, err = s.Next(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, job, next)
+
+	// A triggered run restarts the schedule, even with the CatchUpAll policy.
+	triggeredAt := start.Add(time.Minute)
+	require.NoError(t, s.Done(job, triggeredAt))
+	assert.Equal(t, triggeredAt.Add(time.Hour), s.NextDue(job))
+}
+
+func TestScheduler_Dependents(t *testing.T) {
+	upstream := &domain.SyncJob{Name: "drive"}
+	dependent := &domain.SyncJob{Name: "nas", After: []*domain.Dependency{{Job: "drive"}}}
+
+	s := NewScheduler(time.Hour)
+	require.NoError(t, s.Start([]*domain.SyncJob{upstream, dependent}))
+	// Jobs with dependencies are not scheduled.
+	assert.True(t, s.NextDue(dependent).IsZero())
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	next, err := s.Next(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, upstream, next)
+	require.NoError(t, s.Done(upstream, time.Now()))
+
+	// Without a triggers store, triggers are queued in memory, once per job.
+	require.NoError(t, s.Trigger(&domain.JobTrigger{JobName: "nas", PipelineID: "p1"}))
+	require.NoError(t, s.Trigger(&domain.JobTrigger{JobName: "nas", PipelineID: "p2"}))
+
+	next, err = s.Next(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, dependent, next)
+	assert.Equal(t, "p1", s.PipelineID(dependent))
+
+	require.NoError(t, s.Done(dependent, time.Now()))
+	assert.Empty(t, s.PipelineID(dependent))
+	assert.True(t, s.NextDue(dependent).IsZero())
+
+	short, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = s.Next(short)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
 }
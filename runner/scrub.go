@@ -0,0 +1,204 @@
+package runner
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math/rand/v2"
+	"strings"
+	"time"
+
+	"github.com/eva01/backup-guardian/domain"
+	"github.com/eva01/backup-guardian/internal/errors"
+	"github.com/eva01/backup-guardian/internal/redact"
+	"github.com/eva01/backup-guardian/notify"
+)
+
+// Scrubber lists and checks the files of destinations for scrub jobs. RcloneScrubber
+// implements it.
+type Scrubber interface {
+	// ListFiles lists the files of dest, decrypted with the encryption of job, sorted by path,
+	// without domain.ReservedDir.
+	ListFiles(ctx context.Context, job *domain.SyncJob, dest string) ([]domain.ScrubFile, error)
+	// Scrub downloads files from dest, hashes them and compares them with against, the source
+	// of job or the hashes of the files. Files listed by ListFiles are compared with the source,
+	// and manifest files, as stored, with their hashes.
+	Scrub(ctx context.Context, job *domain.SyncJob, dest string, files []domain.ScrubFile, against string) ([]domain.ScrubCheck, error)
+}
+
+// WithScrubs sets the store of the rotations of scrub jobs, and the scrubber of their
+// destinations. Without it, the runs of scrub jobs fail.
+func WithScrubs(states domain.ScrubStatesReadWriter, scrubber Scrubber) Option {
+	return func(r *Runner) {
+		r.scrubStates = states
+		r.scrubber = scrubber
+	}
+}
+
+// runScrub runs job, a scrub job: it checks a sample of the files of each destination of the
+// scrubbed job, records the rotations, and records the outcome as a run of kind scrub. Runs
+// that find mismatched files fail and send a critical notification. It returns an error when
+// the run could not be created.
+func (r *Runner) runScrub(ctx context.Context, job *domain.SyncJob, pipelineID string) (*domain.SyncRun, error) {
+	run := &domain.SyncRun{
+		ID:         domain.NewSyncRunID(),
+		JobName:    job.Name,
+		Mode:       job.SyncMode(),
+		Kind:       domain.KindScrub,
+		Status:     domain.StatusRunning,
+		StartedAt:  time.Now(),
+		PipelineID: pipelineID,
+	}
+	if run.PipelineID == "" && len(domain.Dependents(r.syncJobs(), job.Name)) > 0 {
+		run.PipelineID = run.ID
+	}
+
+	created, err := r.store.CreateSyncRun(run)
+	if err != nil {
+		r.logger.Error("Failed to create sync run", slog.String("job", job.Name), slog.Any("error", err))
+		run.Status = domain.StatusFailed
+		run.FinishedAt = time.Now()
+		run.ErrorMessage = redact.String("could not record sync run: " + err.Error())
+		r.heartbeatFinish(job, run, nil)
+		return nil, err
+	}
+	run = created
+	r.heartbeatStart(job, run)
+	ctx = r.startLog(ctx, run)
+
+	against := job.Scrub.AgainstOrDefault()
+	r.logger.Info("Starting scrub", slog.String("run_id", run.ID), slog.String("job", job.Name),
+		slog.String("scrubbed_job", job.Scrub.Job), slog.String("against", against))
+
+	run.Scrub = &domain.ScrubResult{Job: job.Scrub.Job, Against: against}
+	err = r.scrub(ctx, job, run)
+
+	run.FinishedAt = time.Now()
+	for _, dest := range run.Scrub.Destinations {
+		run.FilesTransferred += dest.Checked
+		run.BytesTransferred += dest.CheckedBytes
+	}
+
+	switch {
+	case err != nil:
+		run.Status = domain.StatusFailed
+		run.ErrorMessage = redact.String(err.Error())
+		r.logger.Error("Scrub failed", slog.String("run_id", run.ID), slog.Any("error", err))
+	default:
+		run.Status = domain.StatusSuccess
+		r.logger.Info("Scrub completed", slog.String("run_id", run.ID),
+			slog.Int64("files", run.FilesTransferred),
+			slog.Int64("bytes", run.BytesTransferred),
+			slog.Duration("duration", run.FinishedAt.Sub(run.StartedAt)))
+	}
+	if mismatches := run.Scrub.MismatchCount(); mismatches > 0 {
+		r.notify(ctx, &notify.Notification{
+			Event:    notify.EventScrubMismatch,
+			Severity: notify.SeverityCritical,
+			JobName:  job.Name,
+			Title:    fmt.Sprintf("Scrub of job %s found %d mismatched files", job.Scrub.Job, mismatches),
+			Message: fmt.Sprintf("Run %s: %s. The destinations may be corrupted; restore the files from another copy "+
+				"or resync them", run.ID, run.ErrorMessage),
+			Time: time.Now(),
+		})
+	}
+
+	if updateErr := r.store.UpdateSyncRun(run); updateErr != nil {
+		r.logger.Error("Failed to update sync run", slog.String("run_id", run.ID), slog.Any("error", updateErr))
+	}
+	log := r.saveLog(run)
+	r.heartbeatFinish(job, run, log)
+
+	return run, nil
+}
+
+// scrub scrubs the destinations of job into run.Scrub. It returns an error when a destination
+// could not be scrubbed or has mismatched files.
+func (r *Runner) scrub(ctx context.Context, job *domain.SyncJob, run *domain.SyncRun) error {
+	if r.scrubber == nil {
+		return &errors.Error{Code: errors.CodeInvalid, Message: "Scrubs are not supported"}
+	}
+
+	var manifest *domain.Manifest
+	if run.Scrub.Against == domain.ScrubAgainstManifest {
+		if r.manifests == nil {
+			return &errors.Error{Code: errors.CodeInvalid, Message: "Manifests are not supported"}
+		}
+		var err error
+		if manifest, err = r.manifests.GetManifest(&domain.ManifestSelector{JobName: job.Scrub.Job}); err != nil {
+			if errors.ErrorCode(err) == errors.CodeNotFound {
+				return fmt.Errorf("job %s has no manifest yet", job.Scrub.Job)
+			}
+			return fmt.Errorf("could not read the latest manifest of job %s: %w", job.Scrub.Job, err)
+		}
+	}
+
+	var problems []string
+	for _, dest := range job.AllDestinations() {
+		result, err := r.scrubDestination(ctx, job, dest, manifest)
+		if err != nil {
+			result.Error = redact.String(err.Error())
+			problems = append(problems, fmt.Sprintf("could not scrub %s: %v", dest, err))
+		} else if len(result.Mismatches) > 0 {
+			paths := make([]string, len(result.Mismatches))
+			for i, mismatch := range result.Mismatches {
+				paths[i] = mismatch.Path
+			}
+			problems = append(problems, fmt.Sprintf("%d mismatched files on %s: %s", len(paths), dest, strings.Join(paths, ", ")))
+		}
+		run.Scrub.Destinations = append(run.Scrub.Destinations, result)
+
+		r.logger.Info("Destination scrubbed", slog.String("run_id", run.ID), slog.String("destination", dest),
+			slog.Int64("checked", result.Checked), slog.Int64("skipped", result.Skipped),
+			slog.Int("mismatches", len(result.Mismatches)), slog.Int64("rotation", result.Rotation),
+			slog.Float64("coverage", result.Coverage))
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("scrub found problems: %s", strings.Join(problems, "; "))
+	}
+
+	return nil
+}
+
+// scrubDestination checks the next sample of the files of dest, and records it in the rotation
+// of dest. The files are listed from dest, or from manifest when scrubbing against it.
+func (r *Runner) scrubDestination(ctx context.Context, job *domain.SyncJob, dest string, manifest *domain.Manifest) (domain.ScrubDestination, error) {
+	result := domain.ScrubDestination{Destination: dest}
+
+	var files []domain.ScrubFile
+	if manifest != nil {
+		listing := manifest.Destination(dest)
+		if listing == nil {
+			return result, fmt.Errorf("manifest %d of job %s does not list it", manifest.Sequence, manifest.JobName)
+		}
+		for _, entry := range listing.Entries {
+			files = append(files, domain.ScrubFile{Path: entry.Path, Size: entry.Size, Hash: entry.Hash})
+		}
+	} else {
+		var err error
+		if files, err = r.scrubber.ListFiles(ctx, job, dest); err != nil {
+			return result, err
+		}
+	}
+
+	now := time.Now()
+	state, err := r.scrubStates.GetScrubState(&domain.ScrubStateSelector{JobName: job.Name, Destination: dest})
+	switch {
+	case errors.ErrorCode(err) == errors.CodeNotFound:
+		state = domain.NewScrubState(job.Name, dest, now)
+	case err != nil:
+		return result, err
+	}
+
+	sample := state.Sample(files, job.Scrub, now, rand.Shuffle)
+	checks, err := r.scrubber.Scrub(ctx, job, dest, sample, job.Scrub.AgainstOrDefault())
+	if err != nil {
+		return result, err
+	}
+	state.Record(files, checks, now)
+	if _, err := r.scrubStates.UpsertScrubState(state); err != nil {
+		return result, fmt.Errorf("could not record the rotation: %w", err)
+	}
+
+	return domain.NewScrubDestination(dest, files, checks, state), nil
+}
@@ -0,0 +1,44 @@
+package service
+
+import (
+	"fmt"
+
+	"github.com/eva01/backup-guardian/domain"
+	"github.com/eva01/backup-guardian/internal/errors"
+)
+
+// Acknowledge acknowledges the anomaly awaiting acknowledgement of the job named jobName, and
+// returns it: the job runs in its own mode again, and its pause set by the anomaly is lifted.
+// Pauses set by an operator are kept.
+func (s *Service) Acknowledge(jobName string) (*domain.JobAnomaly, error) {
+	if _, err := s.Job(jobName); err != nil {
+		return nil, err
+	}
+	if s.jobAnomalies == nil {
+		return nil, &errors.Error{Code: errors.CodeNotFound, Message: fmt.Sprintf("Job %s has no anomaly to acknowledge", jobName)}
+	}
+
+	anomaly, err := s.jobAnomalies.GetJobAnomaly(&domain.JobAnomalySelector{JobName: jobName})
+	if errors.ErrorCode(err) == errors.CodeNotFound {
+		return nil, &errors.Error{Code: errors.CodeNotFound, Message: fmt.Sprintf("Job %s has no anomaly to acknowledge", jobName)}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	pause, err := s.jobPauses.GetJobPause(&domain.JobPauseSelector{Scope: jobName})
+	switch {
+	case err == nil && pause.Reason == anomaly.PauseReason():
+		if err := s.jobPauses.DeleteJobPause(&domain.JobPauseSelector{Scope: jobName}); err != nil {
+			return nil, err
+		}
+	case err != nil && errors.ErrorCode(err) != errors.CodeNotFound:
+		return nil, err
+	}
+
+	if err := s.jobAnomalies.DeleteJobAnomaly(&domain.JobAnomalySelector{JobName: jobName}); err != nil {
+		return nil, err
+	}
+
+	return anomaly, nil
+}
@@ -0,0 +1,62 @@
+package service_test
+
+import (
+	"testing"
+
+	"github.com/eva01/backup-guardian/domain"
+	domainmocks "github.com/eva01/backup-guardian/domain/mocks"
+	"github.com/eva01/backup-guardian/internal/errors"
+	"github.com/eva01/backup-guardian/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestService_Acknowledge(t *testing.T) {
+	job := &domain.SyncJob{Name: "test-job"}
+	selector := &domain.JobAnomalySelector{JobName: "test-job"}
+	anomaly := &domain.JobAnomaly{JobName: "test-job", RunID: "run-1", Action: domain.AnomalyPause, Reasons: []string{"900 files deleted, usually 2"}}
+
+	t.Run("lifts the pause of the anomaly", func(t *testing.T) {
+		anomaliesMock := domainmocks.NewJobAnomaliesReadWriter(t)
+		anomaliesMock.On("GetJobAnomaly", selector).Return(anomaly, nil).Once()
+		anomaliesMock.On("DeleteJobAnomaly", selector).Return(nil).Once()
+		pausesMock := domainmocks.NewJobPausesReadWriter(t)
+		pausesMock.On("GetJobPause", &domain.JobPauseSelector{Scope: "test-job"}).
+			Return(&domain.JobPause{Scope: "test-job", Reason: anomaly.PauseReason()}, nil).Once()
+		pausesMock.On("DeleteJobPause", &domain.JobPauseSelector{Scope: "test-job"}).Return(nil).Once()
+
+		svc := service.New(service.WithJobAnomalies(anomaliesMock), service.WithJobPauses(pausesMock), service.WithJobs(job))
+		acknowledged, err := svc.Acknowledge("test-job")
+		require.NoError(t, err)
+		assert.Equal(t, anomaly, acknowledged)
+	})
+
+	t.Run("keeps other pauses", func(t *testing.T) {
+		anomaliesMock := domainmocks.NewJobAnomaliesReadWriter(t)
+		anomaliesMock.On("GetJobAnomaly", selector).Return(anomaly, nil).Once()
+		anomaliesMock.On("DeleteJobAnomaly", selector).Return(nil).Once()
+		pausesMock := domainmocks.NewJobPausesReadWriter(t)
+		pausesMock.On("GetJobPause", &domain.JobPauseSelector{Scope: "test-job"}).
+			Return(&domain.JobPause{Scope: "test-job", Reason: "investigating"}, nil).Once()
+
+		svc := service.New(service.WithJobAnomalies(anomaliesMock), service.WithJobPauses(pausesMock), service.WithJobs(job))
+		_, err := svc.Acknowledge("test-job")
+		require.NoError(t, err)
+	})
+
+	t.Run("no anomaly", func(t *testing.T) {
+		anomaliesMock := domainmocks.NewJobAnomaliesReadWriter(t)
+		anomaliesMock.On("GetJobAnomaly", selector).Return(nil, &errors.Error{Code: errors.CodeNotFound}).Once()
+
+		svc := service.New(service.WithJobAnomalies(anomaliesMock), service.WithJobs(job))
+		_, err := svc.Acknowledge("test-job")
+		assert.Equal(t, errors.CodeNotFound, errors.ErrorCode(err))
+		assert.ErrorContains(t, err, "no anomaly to acknowledge")
+	})
+
+	t.Run("unknown job", func(t *testing.T) {
+		svc := service.New(service.WithJobs(job))
+		_, err := svc.Acknowledge("other-job")
+		assert.Equal(t, errors.CodeNotFound, errors.ErrorCode(err))
+	})
+}
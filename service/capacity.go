@@ -0,0 +1,30 @@
+package service
+
+import (
+	"github.com/eva01/backup-guardian/domain"
+)
+
+// capacityRuns is the number of recent runs the capacity forecasts are computed from.
+const capacityRuns = 500
+
+// Capacity returns the forecast of when each destination of the job named jobName fills up,
+// from the usage recorded by its recent runs.
+func (s *Service) Capacity(jobName string) ([]*domain.CapacityForecast, error) {
+	job, err := s.Job(jobName)
+	if err != nil {
+		return nil, err
+	}
+
+	runs, err := s.syncRuns.ListSyncRuns(&domain.SyncRunsSelector{JobName: jobName, Limit: capacityRuns})
+	if err != nil {
+		return nil, err
+	}
+
+	destinations := job.AllDestinations()
+	result := make([]*domain.CapacityForecast, len(destinations))
+	for i, dest := range destinations {
+		result[i] = domain.ForecastCapacity(runs, dest)
+	}
+
+	return result, nil
+}
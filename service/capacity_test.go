@@ -0,0 +1,38 @@
+package service_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/eva01/backup-guardian/domain"
+	domainmocks "github.com/eva01/backup-guardian/domain/mocks"
+	"github.com/eva01/backup-guardian/internal/errors"
+	"github.com/eva01/backup-guardian/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestService_Capacity(t *testing.T) {
+	job := &domain.SyncJob{Name: "test-job", Source: "source", Destinations: []string{"nas", "s3"}}
+	checkedAt := time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC)
+	runsMock := domainmocks.NewSyncRunsReadWriter(t)
+	runsMock.On("ListSyncRuns", &domain.SyncRunsSelector{JobName: "test-job", Limit: 500}).
+		Return([]*domain.SyncRun{
+			{Usage: []domain.DestinationUsage{{Destination: "nas", Used: 200, Free: 800, CheckedAt: checkedAt}}},
+			{Usage: []domain.DestinationUsage{{Destination: "nas", Used: 100, Free: 900, CheckedAt: checkedAt.Add(-24 * time.Hour)}}},
+		}, nil).Once()
+
+	svc := service.New(service.WithSyncRuns(runsMock), service.WithJobs(job))
+
+	forecasts, err := svc.Capacity("test-job")
+	require.NoError(t, err)
+	require.Len(t, forecasts, 2)
+	assert.Equal(t, "nas", forecasts[0].Destination)
+	assert.InDelta(t, 100, forecasts[0].Growth, 0.001)
+	assert.Equal(t, checkedAt.Add(8*24*time.Hour), forecasts[0].FullAt)
+	assert.Equal(t, &domain.CapacityForecast{Destination: "s3"}, forecasts[1])
+
+	_, err = svc.Capacity("other-job")
+	require.Error(t, err)
+	assert.Equal(t, errors.CodeNotFound, errors.ErrorCode(err))
+}
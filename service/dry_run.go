@@ -0,0 +1,29 @@
+package service
+
+import (
+	"context"
+
+	"github.com/eva01/backup-guardian/domain"
+	"github.com/eva01/backup-guardian/internal/errors"
+)
+
+// DryRunner runs jobs in dry-run mode. Implemented by runner.Runner.
+type DryRunner interface {
+	// DryRun records and returns a dry run of job, with the changes a sync would make.
+	DryRun(ctx context.Context, job *domain.SyncJob) (*domain.SyncRun, error)
+}
+
+// DryRun runs the job named jobName in dry-run mode and returns the recorded run, with the
+// changes a sync would make in its plan. Paused jobs can be dry run, e.g. before enabling them.
+func (s *Service) DryRun(ctx context.Context, jobName string) (*domain.SyncRun, error) {
+	if s.dryRunner == nil {
+		return nil, &errors.Error{Code: errors.CodeInvalid, Message: "Dry runs are not supported"}
+	}
+
+	job, err := s.Job(jobName)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.dryRunner.DryRun(ctx, job)
+}
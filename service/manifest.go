@@ -0,0 +1,59 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/eva01/backup-guardian/domain"
+	"github.com/eva01/backup-guardian/internal/errors"
+)
+
+// ManifestVerifier verifies the manifests of jobs against their copies on the destinations.
+// Implemented by runner.Runner.
+type ManifestVerifier interface {
+	// VerifyManifests verifies the chain of the manifests of job, and compares them with their
+	// copies on the destinations.
+	VerifyManifests(ctx context.Context, job *domain.SyncJob) (*domain.ManifestVerification, error)
+}
+
+// Manifests returns the summaries of the manifests of the job named jobName, sorted by
+// sequence: their destinations have no entries.
+func (s *Service) Manifests(jobName string) ([]*domain.Manifest, error) {
+	if _, err := s.Job(jobName); err != nil {
+		return nil, err
+	}
+	if s.manifests == nil {
+		return nil, nil
+	}
+
+	return s.manifests.ListManifests(&domain.ManifestsSelector{JobName: jobName})
+}
+
+// Manifest returns the manifest of the run runID, with the files of its destinations.
+func (s *Service) Manifest(runID string) (*domain.Manifest, error) {
+	if s.manifests == nil {
+		return nil, &errors.Error{Code: errors.CodeNotFound, Message: fmt.Sprintf("Run %s has no manifest", runID)}
+	}
+
+	manifest, err := s.manifests.GetManifest(&domain.ManifestSelector{RunID: runID})
+	if errors.ErrorCode(err) == errors.CodeNotFound {
+		return nil, &errors.Error{Code: errors.CodeNotFound, Message: fmt.Sprintf("Run %s has no manifest", runID)}
+	}
+
+	return manifest, err
+}
+
+// VerifyManifests verifies the manifests of the job named jobName: it re-validates their chain
+// and compares them with their copies on the destinations, detecting rewritten history.
+func (s *Service) VerifyManifests(ctx context.Context, jobName string) (*domain.ManifestVerification, error) {
+	if s.manifestVerifier == nil {
+		return nil, &errors.Error{Code: errors.CodeInvalid, Message: "Manifest verification is not supported"}
+	}
+
+	job, err := s.Job(jobName)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.manifestVerifier.VerifyManifests(ctx, job)
+}
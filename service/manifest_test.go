@@ -0,0 +1,66 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/eva01/backup-guardian/domain"
+	domainmocks "github.com/eva01/backup-guardian/domain/mocks"
+	"github.com/eva01/backup-guardian/internal/errors"
+	"github.com/eva01/backup-guardian/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type manifestVerifierFunc func(ctx context.Context, job *domain.SyncJob) (*domain.ManifestVerification, error)
+
+func (f manifestVerifierFunc) VerifyManifests(ctx context.Context, job *domain.SyncJob) (*domain.ManifestVerification, error) {
+	return f(ctx, job)
+}
+
+func TestService_Manifests(t *testing.T) {
+	job := &domain.SyncJob{Name: "test-job", Source: "src:", Destination: "dst:", Manifest: true}
+	manifest := domain.NewManifest(&domain.SyncRun{ID: "run-1", JobName: "test-job"}, nil, nil, time.Now())
+
+	manifestsMock := domainmocks.NewManifestsReadWriter(t)
+	manifestsMock.On("ListManifests", &domain.ManifestsSelector{JobName: "test-job"}).Return([]*domain.Manifest{manifest}, nil).Once()
+	manifestsMock.On("GetManifest", &domain.ManifestSelector{RunID: "run-1"}).Return(manifest, nil).Once()
+	manifestsMock.On("GetManifest", &domain.ManifestSelector{RunID: "run-2"}).Return(nil, &errors.Error{Code: errors.CodeNotFound}).Once()
+
+	svc := service.New(service.WithManifests(manifestsMock), service.WithJobs(job))
+
+	manifests, err := svc.Manifests("test-job")
+	require.NoError(t, err)
+	assert.Equal(t, []*domain.Manifest{manifest}, manifests)
+
+	_, err = svc.Manifests("other-job")
+	assert.Equal(t, errors.CodeNotFound, errors.ErrorCode(err))
+
+	got, err := svc.Manifest("run-1")
+	require.NoError(t, err)
+	assert.Equal(t, manifest, got)
+
+	_, err = svc.Manifest("run-2")
+	assert.Equal(t, errors.CodeNotFound, errors.ErrorCode(err))
+	assert.ErrorContains(t, err, "Run run-2 has no manifest")
+}
+
+func TestService_VerifyManifests(t *testing.T) {
+	job := &domain.SyncJob{Name: "test-job", Source: "src:", Destination: "dst:", Manifest: true}
+	verifier := manifestVerifierFunc(func(_ context.Context, job *domain.SyncJob) (*domain.ManifestVerification, error) {
+		return &domain.ManifestVerification{JobName: job.Name, Manifests: 3, Copies: 3}, nil
+	})
+
+	svc := service.New(service.WithManifestVerifier(verifier), service.WithJobs(job))
+	verification, err := svc.VerifyManifests(context.Background(), "test-job")
+	require.NoError(t, err)
+	assert.True(t, verification.OK())
+	assert.Equal(t, 3, verification.Manifests)
+
+	_, err = svc.VerifyManifests(context.Background(), "other-job")
+	assert.Equal(t, errors.CodeNotFound, errors.ErrorCode(err))
+
+	_, err = service.New(service.WithJobs(job)).VerifyManifests(context.Background(), "test-job")
+	assert.Equal(t, errors.CodeInvalid, errors.ErrorCode(err))
+}
@@ -0,0 +1,85 @@
+package service
+
+import (
+	"time"
+
+	"github.com/eva01/backup-guardian/domain"
+	"github.com/eva01/backup-guardian/internal/errors"
+)
+
+// Pause pauses the job named scope, or the whole runner when scope is domain.PauseScopeAll.
+// A zero resumeAt keeps the pause until Resume is called. Pausing an already paused scope
+// replaces its reason and auto-resume time.
+func (s *Service) Pause(scope, reason string, resumeAt time.Time) (*domain.JobPause, error) {
+	if err := s.checkScope(scope); err != nil {
+		return nil, err
+	}
+
+	return s.jobPauses.UpsertJobPause(&domain.JobPause{
+		Scope:    scope,
+		Reason:   reason,
+		PausedAt: s.now(),
+		ResumeAt: resumeAt,
+	})
+}
+
+// Resume lifts the pause of the job named scope, or of the whole runner when scope is
+// domain.PauseScopeAll. Resuming a scope that is not paused is a no-op.
+func (s *Service) Resume(scope string) error {
+	if err := s.checkScope(scope); err != nil {
+		return err
+	}
+
+	return s.jobPauses.DeleteJobPause(&domain.JobPauseSelector{Scope: scope})
+}
+
+// RunnerPause returns the runner-wide pause in effect, or nil when the runner is not paused.
+func (s *Service) RunnerPause() (*domain.JobPause, error) {
+	pause, err := s.jobPauses.GetJobPause(&domain.JobPauseSelector{Scope: domain.PauseScopeAll})
+	if errors.ErrorCode(err) == errors.CodeNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !pause.Active(s.now()) {
+		return nil, nil
+	}
+
+	return pause, nil
+}
+
+func (s *Service) checkScope(scope string) error {
+	if scope == domain.PauseScopeAll {
+		return nil
+	}
+
+	_, err := s.Job(scope)
+
+	return err
+}
+
+// ParseResumeAt parses an auto-resume time given either as a duration relative to now
+// (e.g. "48h") or as an RFC 3339 timestamp. An empty value returns the zero time.
+func ParseResumeAt(value string, now time.Time) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+
+	if d, err := time.ParseDuration(value); err == nil {
+		if d <= 0 {
+			return time.Time{}, &errors.Error{Code: errors.CodeInvalid, Message: "Resume duration must be positive"}
+		}
+		return now.Add(d), nil
+	}
+
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, &errors.Error{
+			Code:    errors.CodeInvalid,
+			Message: "Resume time must be a duration (e.g. 48h) or an RFC 3339 timestamp",
+		}
+	}
+
+	return t, nil
+}
@@ -0,0 +1,128 @@
+package service_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/eva01/backup-guardian/domain"
+	domainmocks "github.com/eva01/backup-guardian/domain/mocks"
+	"github.com/eva01/backup-guardian/internal/errors"
+	"github.com/eva01/backup-guardian/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestService_Pause(t *testing.T) {
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	job := &domain.SyncJob{Name: "test-job", Source: "source", Destination: "dest"}
+
+	t.Run("job", func(t *testing.T) {
+		pausesMock := domainmocks.NewJobPausesReadWriter(t)
+		pausesMock.On("UpsertJobPause", mock.Anything).Return(func(p *domain.JobPause) (*domain.JobPause, error) {
+			return p, nil
+		}).Once()
+
+		svc := service.New(service.WithJobPauses(pausesMock), service.WithJobs(job), service.WithClock(func() time.Time { return now }))
+		pause, err := svc.Pause("test-job", "migration", now.Add(time.Hour))
+		require.NoError(t, err)
+		assert.Equal(t, "test-job", pause.Scope)
+		assert.Equal(t, "migration", pause.Reason)
+		assert.Equal(t, now, pause.PausedAt)
+		assert.Equal(t, now.Add(time.Hour), pause.ResumeAt)
+	})
+
+	t.Run("runner", func(t *testing.T) {
+		pausesMock := domainmocks.NewJobPausesReadWriter(t)
+		pausesMock.On("UpsertJobPause", mock.Anything).Return(func(p *domain.JobPause) (*domain.JobPause, error) {
+			return p, nil
+		}).Once()
+
+		svc := service.New(service.WithJobPauses(pausesMock), service.WithJobs(job))
+		pause, err := svc.Pause(domain.PauseScopeAll, "", time.Time{})
+		require.NoError(t, err)
+		assert.True(t, pause.IsRunnerScope())
+	})
+
+	t.Run("unknown job", func(t *testing.T) {
+		pausesMock := domainmocks.NewJobPausesReadWriter(t)
+
+		svc := service.New(service.WithJobPauses(pausesMock), service.WithJobs(job))
+		_, err := svc.Pause("other-job", "", time.Time{})
+		require.Error(t, err)
+		assert.Equal(t, errors.CodeNotFound, errors.ErrorCode(err))
+	})
+}
+
+func TestService_Resume(t *testing.T) {
+	job := &domain.SyncJob{Name: "test-job"}
+	pausesMock := domainmocks.NewJobPausesReadWriter(t)
+	pausesMock.On("DeleteJobPause", &domain.JobPauseSelector{Scope: "test-job"}).Return(nil).Once()
+
+	svc := service.New(service.WithJobPauses(pausesMock), service.WithJobs(job))
+	require.NoError(t, svc.Resume("test-job"))
+}
+
+func TestService_JobStatuses(t *testing.T) {
+	jobs := []*domain.SyncJob{{Name: "job-1"}, {Name: "job-2"}}
+	runnerPause := &domain.JobPause{Scope: domain.PauseScopeAll, Reason: "maintenance"}
+	lastRun := &domain.SyncRun{ID: "run-1", JobName: "job-1", Status: domain.StatusSuccess}
+
+	pausesMock := domainmocks.NewJobPausesReadWriter(t)
+	pausesMock.On("ListJobPauses").Return([]*domain.JobPause{runnerPause}, nil).Once()
+
+	runsMock := domainmocks.NewSyncRunsReadWriter(t)
+	runsMock.On("ListSyncRuns", &domain.SyncRunsSelector{JobName: "job-1", Limit: 1}).Return([]*domain.SyncRun{lastRun}, nil).Once()
+	runsMock.On("ListSyncRuns", &domain.SyncRunsSelector{JobName: "job-2", Limit: 1}).Return([]*domain.SyncRun{}, nil).Once()
+
+	schedule := &domain.JobSchedule{JobName: "job-2", NextDueAt: time.Now()}
+	schedulesMock := domainmocks.NewJobSchedulesReadWriter(t)
+	schedulesMock.On("ListJobSchedules").Return([]*domain.JobSchedule{schedule}, nil).Once()
+
+	svc := service.New(service.WithJobPauses(pausesMock), service.WithSyncRuns(runsMock),
+		service.WithJobSchedules(schedulesMock), service.WithJobs(jobs...))
+	statuses, err := svc.JobStatuses()
+	require.NoError(t, err)
+	require.Len(t, statuses, 2)
+
+	assert.True(t, statuses[0].Paused())
+	assert.Equal(t, runnerPause, statuses[0].Pause)
+	assert.Equal(t, lastRun, statuses[0].LastRun)
+	assert.Nil(t, statuses[0].Schedule)
+	assert.True(t, statuses[1].Paused())
+	assert.Nil(t, statuses[1].LastRun)
+	assert.Equal(t, schedule, statuses[1].Schedule)
+}
+
+func TestParseResumeAt(t *testing.T) {
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	t.Run("empty", func(t *testing.T) {
+		resumeAt, err := service.ParseResumeAt("", now)
+		require.NoError(t, err)
+		assert.True(t, resumeAt.IsZero())
+	})
+
+	t.Run("duration", func(t *testing.T) {
+		resumeAt, err := service.ParseResumeAt("48h", now)
+		require.NoError(t, err)
+		assert.Equal(t, now.Add(48*time.Hour), resumeAt)
+	})
+
+	t.Run("timestamp", func(t *testing.T) {
+		resumeAt, err := service.ParseResumeAt("2026-01-05T08:00:00Z", now)
+		require.NoError(t, err)
+		assert.Equal(t, time.Date(2026, 1, 5, 8, 0, 0, 0, time.UTC), resumeAt)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		_, err := service.ParseResumeAt("tomorrow", now)
+		require.Error(t, err)
+		assert.Equal(t, errors.CodeInvalid, errors.ErrorCode(err))
+	})
+
+	t.Run("negative duration", func(t *testing.T) {
+		_, err := service.ParseResumeAt("-1h", now)
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,36 @@
+package service
+
+import (
+	"github.com/eva01/backup-guardian/domain"
+	"github.com/eva01/backup-guardian/internal/errors"
+)
+
+// maxPipelineRuns caps the number of sync runs read for a pipeline.
+const maxPipelineRuns = 1000
+
+// Pipeline returns the state of the pipeline with the given ID, the ID of the run of its
+// first job.
+func (s *Service) Pipeline(id string) (*domain.Pipeline, error) {
+	runs, err := s.syncRuns.ListSyncRuns(&domain.SyncRunsSelector{PipelineID: id, Limit: maxPipelineRuns})
+	if err != nil {
+		return nil, err
+	}
+
+	pipeline := domain.NewPipeline(id, s.Jobs(), runs, s.latestRun)
+	if pipeline == nil {
+		return nil, &errors.Error{Code: errors.CodeNotFound, Message: "Pipeline " + id + " not found"}
+	}
+
+	return pipeline, nil
+}
+
+// latestRun returns the latest run of the job named name, or nil when it never ran or its runs
+// cannot be read.
+func (s *Service) latestRun(name string) *domain.SyncRun {
+	runs, err := s.syncRuns.ListSyncRuns(&domain.SyncRunsSelector{JobName: name, Limit: 1})
+	if err != nil || len(runs) == 0 {
+		return nil
+	}
+
+	return runs[0]
+}
@@ -0,0 +1,35 @@
+package service_test
+
+import (
+	"testing"
+
+	"github.com/eva01/backup-guardian/domain"
+	domainmocks "github.com/eva01/backup-guardian/domain/mocks"
+	"github.com/eva01/backup-guardian/internal/errors"
+	"github.com/eva01/backup-guardian/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestService_Pipeline(t *testing.T) {
+	drive := &domain.SyncJob{Name: "drive"}
+	nas := &domain.SyncJob{Name: "nas", After: []*domain.Dependency{{Job: "drive"}}}
+
+	runsMock := domainmocks.NewSyncRunsReadWriter(t)
+	runsMock.On("ListSyncRuns", &domain.SyncRunsSelector{PipelineID: "p1", Limit: 1000}).
+		Return([]*domain.SyncRun{{ID: "p1", JobName: "drive", Status: domain.StatusSuccess, PipelineID: "p1"}}, nil).Once()
+	runsMock.On("ListSyncRuns", &domain.SyncRunsSelector{PipelineID: "missing", Limit: 1000}).
+		Return([]*domain.SyncRun{}, nil).Once()
+
+	svc := service.New(service.WithSyncRuns(runsMock), service.WithJobs(drive, nas))
+
+	pipeline, err := svc.Pipeline("p1")
+	require.NoError(t, err)
+	require.Len(t, pipeline.Steps, 2)
+	assert.Equal(t, domain.StatusSuccess, pipeline.Steps[0].State)
+	assert.Equal(t, domain.StepPending, pipeline.Steps[1].State)
+	assert.Equal(t, domain.DependenciesMet, pipeline.Steps[1].Dependencies)
+
+	_, err = svc.Pipeline("missing")
+	assert.Equal(t, errors.CodeNotFound, errors.ErrorCode(err))
+}
@@ -0,0 +1,37 @@
+package service
+
+import (
+	"github.com/eva01/backup-guardian/domain"
+	"github.com/eva01/backup-guardian/internal/errors"
+)
+
+// ProgressSource provides the live progress of running syncs.
+type ProgressSource interface {
+	// List returns the latest snapshot of every running sync.
+	List() []*domain.Progress
+	// Subscribe returns a channel receiving every snapshot from now on, and a function to
+	// call to unsubscribe.
+	Subscribe() (<-chan *domain.Progress, func())
+}
+
+// Progress returns the latest progress of every running sync. It returns nil when progress
+// is not available, e.g. outside of the runner process.
+func (s *Service) Progress() []*domain.Progress {
+	if s.progress == nil {
+		return nil
+	}
+
+	return s.progress.List()
+}
+
+// SubscribeProgress returns a channel receiving the progress snapshots of running syncs, and
+// a function to call to unsubscribe.
+func (s *Service) SubscribeProgress() (<-chan *domain.Progress, func(), error) {
+	if s.progress == nil {
+		return nil, nil, &errors.Error{Code: errors.CodeInvalid, Message: "Live progress is only available from the runner"}
+	}
+
+	ch, unsubscribe := s.progress.Subscribe()
+
+	return ch, unsubscribe, nil
+}
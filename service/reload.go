@@ -0,0 +1,112 @@
+package service
+
+import (
+	"fmt"
+
+	"github.com/eva01/backup-guardian/domain"
+	"github.com/eva01/backup-guardian/internal/errors"
+)
+
+// ConfigLoader parses and validates the configuration: the jobs, and the remotes of the jobs
+// file.
+type ConfigLoader func() ([]*domain.SyncJob, []*domain.Remote, error)
+
+// RemoteInstaller injects remotes into rclone's configuration, replacing the remotes installed
+// before, or changes nothing when one is invalid. Implemented by runner.InstallRemotes.
+type RemoteInstaller func(remotes []*domain.Remote) error
+
+// Reloader applies the jobs of a reloaded configuration. Implemented by runner.Runner.
+type Reloader interface {
+	// Reload replaces the jobs, without interrupting running syncs.
+	Reload(jobs []*domain.SyncJob) error
+}
+
+// InstallRemotes installs the remotes of the jobs file and of the database, and returns them.
+func (s *Service) InstallRemotes() ([]*domain.Remote, error) {
+	if s.remoteInstaller == nil {
+		return nil, &errors.Error{Code: errors.CodeInvalid, Message: "Remotes cannot be installed"}
+	}
+
+	remotes, err := s.Remotes()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.remoteInstaller(remotes); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	s.installedRemotes = remotes
+	s.mu.Unlock()
+
+	return remotes, nil
+}
+
+// Reload loads the configuration again, installs the remotes of the jobs file and of the
+// database, and replaces the jobs of the runner, trigger telling what requested it. When the
+// configuration is invalid, the running one is kept. The outcome is returned, and kept as the
+// last reload, failed or not; the error tells why a reload failed.
+func (s *Service) Reload(trigger string) (*domain.ConfigReload, error) {
+	if s.configLoader == nil || s.reloader == nil || s.remoteInstaller == nil {
+		return nil, &errors.Error{Code: errors.CodeInvalid, Message: "Configuration reloads are not supported"}
+	}
+
+	s.reloadMu.Lock()
+	defer s.reloadMu.Unlock()
+
+	reload := &domain.ConfigReload{Trigger: trigger, ReloadedAt: s.now()}
+	err := s.reload(reload)
+	if err != nil {
+		reload.Status = domain.ReloadFailed
+		reload.Error = errors.ErrorMessage(err)
+	}
+
+	s.mu.Lock()
+	s.lastReload = reload
+	s.mu.Unlock()
+
+	return reload, err
+}
+
+func (s *Service) reload(reload *domain.ConfigReload) error {
+	jobs, configRemotes, err := s.configLoader()
+	if err == nil && len(jobs) == 0 {
+		err = fmt.Errorf("no job configured")
+	}
+	if err != nil {
+		return &errors.Error{Code: errors.CodeInvalid, Message: err.Error()}
+	}
+
+	remotes, err := s.mergeRemotes(configRemotes)
+	if err != nil {
+		return err
+	}
+	if err := s.remoteInstaller(remotes); err != nil {
+		return &errors.Error{Code: errors.CodeInvalid, Message: fmt.Sprintf("invalid remote configuration: %v", err)}
+	}
+	if err := s.reloader.Reload(jobs); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	reload.Jobs = domain.DiffJobs(s.jobs, jobs)
+	reload.Remotes = domain.DiffRemotes(s.installedRemotes, remotes)
+	s.jobs, s.configRemotes, s.installedRemotes = jobs, configRemotes, remotes
+	s.mu.Unlock()
+
+	reload.Status = domain.ReloadApplied
+	if reload.Jobs.Empty() && reload.Remotes.Empty() {
+		reload.Status = domain.ReloadUnchanged
+	}
+
+	return nil
+}
+
+// LastReload returns the outcome of the last reload of the configuration, or nil when it was
+// not reloaded.
+func (s *Service) LastReload() *domain.ConfigReload {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.lastReload
+}
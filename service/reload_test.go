@@ -0,0 +1,102 @@
+package service_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/eva01/backup-guardian/domain"
+	domainmocks "github.com/eva01/backup-guardian/domain/mocks"
+	"github.com/eva01/backup-guardian/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// reloaderFunc implements service.Reloader.
+type reloaderFunc func(jobs []*domain.SyncJob) error
+
+func (f reloaderFunc) Reload(jobs []*domain.SyncJob) error { return f(jobs) }
+
+func TestService_Reload(t *testing.T) {
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	kept := &domain.SyncJob{Name: "kept", Source: "a:", Destination: "b:"}
+	removed := &domain.SyncJob{Name: "removed", Source: "a:", Destination: "b:"}
+	nas := &domain.Remote{Name: "nas", Type: "sftp", Parameters: map[string]string{"host": "nas"}, Origin: domain.RemoteOriginDatabase}
+	offsite := &domain.Remote{Name: "offsite", Type: "s3", Origin: domain.RemoteOriginConfig}
+
+	next := []*domain.SyncJob{kept, {Name: "added", Source: "a:", Destination: "b:"}}
+	nextRemotes := []*domain.Remote{{Name: "offsite", Type: "s3", Parameters: map[string]string{"region": "eu"}, Origin: domain.RemoteOriginConfig}}
+	var loadErr error
+	loader := func() ([]*domain.SyncJob, []*domain.Remote, error) {
+		if loadErr != nil {
+			return nil, nil, loadErr
+		}
+		return next, nextRemotes, nil
+	}
+	var installed [][]*domain.Remote
+	installer := func(remotes []*domain.Remote) error {
+		installed = append(installed, remotes)
+		return nil
+	}
+	var reloaded [][]*domain.SyncJob
+	reloader := reloaderFunc(func(jobs []*domain.SyncJob) error {
+		reloaded = append(reloaded, jobs)
+		return nil
+	})
+
+	remotesMock := domainmocks.NewRemotesReadWriter(t)
+	remotesMock.On("ListRemotes").Return([]*domain.Remote{nas}, nil)
+
+	svc := service.New(
+		service.WithClock(func() time.Time { return now }),
+		service.WithJobs(kept, removed),
+		service.WithRemotes(remotesMock),
+		service.WithConfigRemotes(offsite),
+		service.WithConfigLoader(loader),
+		service.WithRemoteInstaller(installer),
+		service.WithReloader(reloader),
+	)
+	assert.Nil(t, svc.LastReload())
+	_, err := svc.InstallRemotes()
+	require.NoError(t, err)
+
+	reload, err := svc.Reload(domain.ReloadTriggerSignal)
+	require.NoError(t, err)
+	assert.Equal(t, &domain.ConfigReload{
+		Trigger:    domain.ReloadTriggerSignal,
+		Status:     domain.ReloadApplied,
+		ReloadedAt: now,
+		Jobs:       domain.ConfigDiff{Added: []string{"added"}, Removed: []string{"removed"}},
+		Remotes:    domain.ConfigDiff{Changed: []string{"offsite"}},
+	}, reload)
+	assert.Equal(t, reload, svc.LastReload())
+	assert.Equal(t, next, svc.Jobs())
+	assert.Equal(t, [][]*domain.SyncJob{next}, reloaded)
+	require.Len(t, installed, 2)
+	assert.Equal(t, []*domain.Remote{nas, nextRemotes[0]}, installed[1])
+	remote, err := svc.Remote("offsite")
+	require.NoError(t, err)
+	assert.Equal(t, nextRemotes[0], remote)
+
+	reload, err = svc.Reload(domain.ReloadTriggerFile)
+	require.NoError(t, err)
+	assert.Equal(t, domain.ReloadUnchanged, reload.Status)
+
+	// An invalid configuration is not applied.
+	loadErr = errors.New(`job "x": interval: invalid duration`)
+	reload, err = svc.Reload(domain.ReloadTriggerSignal)
+	require.Error(t, err)
+	assert.Equal(t, domain.ReloadFailed, reload.Status)
+	assert.Contains(t, reload.Error, "invalid duration")
+	assert.Equal(t, reload, svc.LastReload())
+	assert.Equal(t, next, svc.Jobs())
+	assert.Len(t, reloaded, 2)
+	assert.Len(t, installed, 3)
+}
+
+func TestService_Reload_Unsupported(t *testing.T) {
+	svc := service.New()
+	_, err := svc.Reload(domain.ReloadTriggerAPI)
+	require.Error(t, err)
+	assert.Nil(t, svc.LastReload())
+}
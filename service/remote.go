@@ -0,0 +1,129 @@
+package service
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/eva01/backup-guardian/domain"
+	"github.com/eva01/backup-guardian/internal/errors"
+)
+
+// Remotes returns the remotes defined in the jobs file and in the database, sorted by name.
+// A remote defined in both is a conflict, to be solved by deleting one of the definitions.
+func (s *Service) Remotes() ([]*domain.Remote, error) {
+	s.mu.RLock()
+	configRemotes := s.configRemotes
+	s.mu.RUnlock()
+
+	return s.mergeRemotes(configRemotes)
+}
+
+// mergeRemotes returns the remotes configRemotes of the jobs file and the remotes of the
+// database, sorted by name.
+func (s *Service) mergeRemotes(configRemotes []*domain.Remote) ([]*domain.Remote, error) {
+	result := slices.Clone(configRemotes)
+	if s.remotes != nil {
+		stored, err := s.remotes.ListRemotes()
+		if err != nil {
+			return nil, err
+		}
+		for _, remote := range stored {
+			if findRemote(configRemotes, remote.Name) != nil {
+				return nil, &errors.Error{Code: errors.CodeConflict, Message: fmt.Sprintf("Remote %s is defined in both the jobs file and the database", remote.Name)}
+			}
+			result = append(result, remote)
+		}
+	}
+
+	slices.SortFunc(result, func(a, b *domain.Remote) int { return strings.Compare(a.Name, b.Name) })
+
+	return result, nil
+}
+
+// Remote returns the remote with the given name, from the jobs file or the database.
+func (s *Service) Remote(name string) (*domain.Remote, error) {
+	if remote := s.configRemote(name); remote != nil {
+		return remote, nil
+	}
+	if s.remotes == nil {
+		return nil, &errors.Error{Code: errors.CodeNotFound, Message: "Unknown remote " + name}
+	}
+
+	remote, err := s.remotes.GetRemote(&domain.RemoteSelector{Name: name})
+	if errors.ErrorCode(err) == errors.CodeNotFound {
+		return nil, &errors.Error{Code: errors.CodeNotFound, Message: "Unknown remote " + name}
+	}
+
+	return remote, err
+}
+
+// SaveRemote creates or replaces a remote of the database. Remotes of the jobs file cannot be
+// replaced.
+func (s *Service) SaveRemote(remote *domain.Remote) (*domain.Remote, error) {
+	if s.configRemote(remote.Name) != nil {
+		return nil, &errors.Error{Code: errors.CodeConflict, Message: fmt.Sprintf("Remote %s is defined in the jobs file", remote.Name)}
+	}
+
+	now := s.now()
+	remote.CreatedAt, remote.UpdatedAt = now, now
+	existing, err := s.remotes.GetRemote(&domain.RemoteSelector{Name: remote.Name})
+	switch {
+	case err == nil:
+		remote.CreatedAt = existing.CreatedAt
+	case errors.ErrorCode(err) != errors.CodeNotFound:
+		return nil, err
+	}
+
+	return s.remotes.UpsertRemote(remote)
+}
+
+// DeleteRemote deletes a remote of the database. Remotes of the jobs file, and remotes used by
+// a job, cannot be deleted.
+func (s *Service) DeleteRemote(name string) error {
+	if s.configRemote(name) != nil {
+		return &errors.Error{Code: errors.CodeConflict, Message: fmt.Sprintf("Remote %s is defined in the jobs file", name)}
+	}
+	if _, err := s.remotes.GetRemote(&domain.RemoteSelector{Name: name}); err != nil {
+		if errors.ErrorCode(err) == errors.CodeNotFound {
+			return &errors.Error{Code: errors.CodeNotFound, Message: "Unknown remote " + name}
+		}
+		return err
+	}
+	for _, job := range s.Jobs() {
+		if usesRemote(job, name) {
+			return &errors.Error{Code: errors.CodeConflict, Message: fmt.Sprintf("Remote %s is used by job %s", name, job.Name)}
+		}
+	}
+
+	return s.remotes.DeleteRemote(&domain.RemoteSelector{Name: name})
+}
+
+func (s *Service) configRemote(name string) *domain.Remote {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return findRemote(s.configRemotes, name)
+}
+
+// findRemote returns the remote named name, or nil.
+func findRemote(remotes []*domain.Remote, name string) *domain.Remote {
+	for _, remote := range remotes {
+		if remote.Name == name {
+			return remote
+		}
+	}
+
+	return nil
+}
+
+// usesRemote reports whether the source or a destination of job is on the remote named name.
+func usesRemote(job *domain.SyncJob, name string) bool {
+	for _, path := range append([]string{job.Source}, job.AllDestinations()...) {
+		if strings.HasPrefix(path, name+":") {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,111 @@
+package service_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/eva01/backup-guardian/domain"
+	domainmocks "github.com/eva01/backup-guardian/domain/mocks"
+	"github.com/eva01/backup-guardian/internal/errors"
+	"github.com/eva01/backup-guardian/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestService_Remotes(t *testing.T) {
+	configured := &domain.Remote{Name: "offsite", Type: "s3", Origin: domain.RemoteOriginConfig}
+	stored := &domain.Remote{Name: "nas", Type: "sftp", Origin: domain.RemoteOriginDatabase}
+
+	t.Run("merged", func(t *testing.T) {
+		remotesMock := domainmocks.NewRemotesReadWriter(t)
+		remotesMock.On("ListRemotes").Return([]*domain.Remote{stored}, nil).Once()
+
+		svc := service.New(service.WithRemotes(remotesMock), service.WithConfigRemotes(configured))
+		remotes, err := svc.Remotes()
+		require.NoError(t, err)
+		assert.Equal(t, []*domain.Remote{stored, configured}, remotes)
+	})
+
+	t.Run("defined twice", func(t *testing.T) {
+		remotesMock := domainmocks.NewRemotesReadWriter(t)
+		remotesMock.On("ListRemotes").Return([]*domain.Remote{{Name: "offsite", Type: "b2"}}, nil).Once()
+
+		svc := service.New(service.WithRemotes(remotesMock), service.WithConfigRemotes(configured))
+		_, err := svc.Remotes()
+		assert.Equal(t, errors.CodeConflict, errors.ErrorCode(err))
+	})
+
+	t.Run("get", func(t *testing.T) {
+		remotesMock := domainmocks.NewRemotesReadWriter(t)
+		remotesMock.On("GetRemote", &domain.RemoteSelector{Name: "nas"}).Return(stored, nil).Once()
+		remotesMock.On("GetRemote", &domain.RemoteSelector{Name: "other"}).Return(nil, &errors.Error{Code: errors.CodeNotFound}).Once()
+
+		svc := service.New(service.WithRemotes(remotesMock), service.WithConfigRemotes(configured))
+		remote, err := svc.Remote("offsite")
+		require.NoError(t, err)
+		assert.Equal(t, configured, remote)
+		remote, err = svc.Remote("nas")
+		require.NoError(t, err)
+		assert.Equal(t, stored, remote)
+		_, err = svc.Remote("other")
+		assert.EqualError(t, err, "<notFound> Unknown remote other")
+	})
+}
+
+func TestService_SaveRemote(t *testing.T) {
+	created := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	clock := service.WithClock(func() time.Time { return now })
+
+	t.Run("replace", func(t *testing.T) {
+		remotesMock := domainmocks.NewRemotesReadWriter(t)
+		remotesMock.On("GetRemote", &domain.RemoteSelector{Name: "nas"}).Return(&domain.Remote{Name: "nas", CreatedAt: created}, nil).Once()
+		remotesMock.On("UpsertRemote", mock.Anything).Return(func(r *domain.Remote) (*domain.Remote, error) {
+			return r, nil
+		}).Once()
+
+		svc := service.New(service.WithRemotes(remotesMock), clock)
+		remote, err := svc.SaveRemote(&domain.Remote{Name: "nas", Type: "sftp"})
+		require.NoError(t, err)
+		assert.Equal(t, created, remote.CreatedAt)
+		assert.Equal(t, now, remote.UpdatedAt)
+	})
+
+	t.Run("jobs file remote", func(t *testing.T) {
+		remotesMock := domainmocks.NewRemotesReadWriter(t)
+
+		svc := service.New(service.WithRemotes(remotesMock), service.WithConfigRemotes(&domain.Remote{Name: "nas"}), clock)
+		_, err := svc.SaveRemote(&domain.Remote{Name: "nas", Type: "sftp"})
+		assert.EqualError(t, err, "<conflict> Remote nas is defined in the jobs file")
+	})
+}
+
+func TestService_DeleteRemote(t *testing.T) {
+	job := &domain.SyncJob{Name: "test-job", Source: "/data", Destinations: []string{"b2:bucket", "nas:backups"}}
+
+	t.Run("delete", func(t *testing.T) {
+		remotesMock := domainmocks.NewRemotesReadWriter(t)
+		remotesMock.On("GetRemote", &domain.RemoteSelector{Name: "old"}).Return(&domain.Remote{Name: "old"}, nil).Once()
+		remotesMock.On("DeleteRemote", &domain.RemoteSelector{Name: "old"}).Return(nil).Once()
+
+		svc := service.New(service.WithRemotes(remotesMock), service.WithJobs(job))
+		require.NoError(t, svc.DeleteRemote("old"))
+	})
+
+	t.Run("used by a job", func(t *testing.T) {
+		remotesMock := domainmocks.NewRemotesReadWriter(t)
+		remotesMock.On("GetRemote", &domain.RemoteSelector{Name: "nas"}).Return(&domain.Remote{Name: "nas"}, nil).Once()
+
+		svc := service.New(service.WithRemotes(remotesMock), service.WithJobs(job))
+		assert.EqualError(t, svc.DeleteRemote("nas"), "<conflict> Remote nas is used by job test-job")
+	})
+
+	t.Run("unknown", func(t *testing.T) {
+		remotesMock := domainmocks.NewRemotesReadWriter(t)
+		remotesMock.On("GetRemote", &domain.RemoteSelector{Name: "nas"}).Return(nil, &errors.Error{Code: errors.CodeNotFound}).Once()
+
+		svc := service.New(service.WithRemotes(remotesMock), service.WithJobs(job))
+		assert.Equal(t, errors.CodeNotFound, errors.ErrorCode(svc.DeleteRemote("nas")))
+	})
+}
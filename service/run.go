@@ -0,0 +1,53 @@
+package service
+
+import (
+	"github.com/eva01/backup-guardian/domain"
+	"github.com/eva01/backup-guardian/internal/errors"
+)
+
+// maxRunsLimit caps the number of sync runs returned by Runs.
+const maxRunsLimit = 500
+
+// Trigger requests a run of the job named jobName as soon as possible, regardless of its
+// schedule and run windows. Triggering a job that is already triggered keeps the pending
+// request. Paused jobs cannot be triggered.
+func (s *Service) Trigger(jobName string) (*domain.JobTrigger, error) {
+	if s.jobTriggers == nil {
+		return nil, &errors.Error{Code: errors.CodeInvalid, Message: "Triggering jobs is not supported"}
+	}
+
+	if _, err := s.Job(jobName); err != nil {
+		return nil, err
+	}
+
+	pauses, err := s.jobPauses.ListJobPauses()
+	if err != nil {
+		return nil, err
+	}
+	if pause := domain.ActiveJobPause(pauses, jobName, s.now()); pause != nil {
+		return nil, &errors.Error{Code: errors.CodeConflict, Message: "Job " + jobName + " is paused"}
+	}
+
+	return s.jobTriggers.UpsertJobTrigger(&domain.JobTrigger{JobName: jobName, RequestedAt: s.now()})
+}
+
+// Runs returns the sync runs of the job named jobName, dry runs included, most recent first.
+func (s *Service) Runs(jobName string, limit, offset int) ([]*domain.SyncRun, error) {
+	if _, err := s.Job(jobName); err != nil {
+		return nil, err
+	}
+
+	if limit <= 0 || limit > maxRunsLimit {
+		limit = maxRunsLimit
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	return s.syncRuns.ListSyncRuns(&domain.SyncRunsSelector{JobName: jobName, Limit: limit, Offset: offset, DryRuns: true})
+}
+
+// Run returns the sync run with the given ID.
+func (s *Service) Run(id string) (*domain.SyncRun, error) {
+	return s.syncRuns.GetSyncRun(&domain.SyncRunSelector{ID: id})
+}
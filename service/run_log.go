@@ -0,0 +1,82 @@
+package service
+
+import (
+	"bufio"
+	"bytes"
+	"log/slog"
+	"math"
+	"strings"
+
+	"github.com/eva01/backup-guardian/domain"
+	"github.com/eva01/backup-guardian/internal/errors"
+)
+
+// RunLog returns the log of the sync run with the given ID, keeping the records of level
+// minLevel and above.
+func (s *Service) RunLog(id string, minLevel slog.Level) (*domain.SyncRunLog, error) {
+	if s.syncRunLogs == nil {
+		return nil, &errors.Error{Code: errors.CodeNotFound, Message: "Run logs are not recorded"}
+	}
+
+	log, err := s.syncRunLogs.GetSyncRunLog(&domain.SyncRunLogSelector{RunID: id})
+	if errors.ErrorCode(err) == errors.CodeNotFound {
+		return nil, &errors.Error{Code: errors.CodeNotFound, Message: "No log recorded for run " + id, UnderlyingError: err}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	log.Content = filterLogLevel(log.Content, minLevel)
+
+	return log, nil
+}
+
+// ParseLogLevel parses a log level name (debug, info, warn, error, case-insensitive).
+// An empty value returns the lowest level, keeping every record.
+func ParseLogLevel(value string) (slog.Level, error) {
+	if value == "" {
+		return slog.Level(math.MinInt), nil
+	}
+
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(value)); err != nil {
+		return 0, &errors.Error{Code: errors.CodeInvalid, Message: "Invalid log level " + value, UnderlyingError: err}
+	}
+
+	return level, nil
+}
+
+// filterLogLevel returns the lines of content, in slog text format, whose level is minLevel
+// or above. Lines without a level, such as the truncation note, are kept.
+func filterLogLevel(content []byte, minLevel slog.Level) []byte {
+	var result bytes.Buffer
+
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	scanner.Buffer(make([]byte, 64*1024), len(content)+1)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if level, ok := lineLevel(line); ok && level < minLevel {
+			continue
+		}
+		result.WriteString(line)
+		result.WriteByte('\n')
+	}
+
+	return result.Bytes()
+}
+
+// lineLevel returns the level of a log line in slog text format.
+func lineLevel(line string) (slog.Level, bool) {
+	_, rest, ok := strings.Cut(line, " level=")
+	if !ok {
+		return 0, false
+	}
+	name, _, _ := strings.Cut(rest, " ")
+
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(name)); err != nil {
+		return 0, false
+	}
+
+	return level, true
+}
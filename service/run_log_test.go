@@ -0,0 +1,55 @@
+package service_test
+
+import (
+	"log/slog"
+	"testing"
+
+	"github.com/eva01/backup-guardian/domain"
+	domainmocks "github.com/eva01/backup-guardian/domain/mocks"
+	"github.com/eva01/backup-guardian/internal/errors"
+	"github.com/eva01/backup-guardian/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestService_RunLog(t *testing.T) {
+	content := `time=2026-01-01T00:00:00.000Z level=DEBUG msg="listing source"
+time=2026-01-01T00:00:01.000Z level=INFO msg="Starting sync" run_id=run-1
+time=2026-01-01T00:00:02.000Z level=INFO+2 msg="rclone notice"
+time=2026-01-01T00:00:03.000Z level=ERROR msg="Sync failed" error="level=DEBUG in an error"
+... log truncated at 1048576 bytes
+`
+	logsMock := domainmocks.NewSyncRunLogsReadWriter(t)
+	logsMock.On("GetSyncRunLog", &domain.SyncRunLogSelector{RunID: "run-1"}).
+		Return(func(*domain.SyncRunLogSelector) (*domain.SyncRunLog, error) {
+			return &domain.SyncRunLog{RunID: "run-1", Content: []byte(content), Truncated: true}, nil
+		})
+	logsMock.On("GetSyncRunLog", &domain.SyncRunLogSelector{RunID: "run-2"}).Return(nil, &errors.Error{Code: errors.CodeNotFound})
+
+	svc := service.New(service.WithSyncRunLogs(logsMock))
+
+	all, err := service.ParseLogLevel("")
+	require.NoError(t, err)
+	log, err := svc.RunLog("run-1", all)
+	require.NoError(t, err)
+	assert.Equal(t, content, string(log.Content))
+
+	warn, err := service.ParseLogLevel("warn")
+	require.NoError(t, err)
+	log, err = svc.RunLog("run-1", warn)
+	require.NoError(t, err)
+	assert.Equal(t, `time=2026-01-01T00:00:03.000Z level=ERROR msg="Sync failed" error="level=DEBUG in an error"
+... log truncated at 1048576 bytes
+`, string(log.Content))
+
+	log, err = svc.RunLog("run-1", slog.LevelInfo)
+	require.NoError(t, err)
+	assert.NotContains(t, string(log.Content), "listing source")
+	assert.Contains(t, string(log.Content), "rclone notice")
+
+	_, err = svc.RunLog("run-2", all)
+	assert.Equal(t, errors.CodeNotFound, errors.ErrorCode(err))
+
+	_, err = service.ParseLogLevel("verbose")
+	assert.Equal(t, errors.CodeInvalid, errors.ErrorCode(err))
+}
@@ -0,0 +1,66 @@
+package service_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/eva01/backup-guardian/domain"
+	domainmocks "github.com/eva01/backup-guardian/domain/mocks"
+	"github.com/eva01/backup-guardian/internal/errors"
+	"github.com/eva01/backup-guardian/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestService_Trigger(t *testing.T) {
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	job := &domain.SyncJob{Name: "test-job", Source: "source", Destination: "dest"}
+
+	newService := func(pausesMock *domainmocks.JobPausesReadWriter, triggersMock *domainmocks.JobTriggersReadWriter) *service.Service {
+		return service.New(service.WithJobPauses(pausesMock), service.WithJobTriggers(triggersMock), service.WithJobs(job),
+			service.WithClock(func() time.Time { return now }))
+	}
+
+	t.Run("triggered", func(t *testing.T) {
+		pausesMock := domainmocks.NewJobPausesReadWriter(t)
+		pausesMock.On("ListJobPauses").Return([]*domain.JobPause{}, nil).Once()
+		triggersMock := domainmocks.NewJobTriggersReadWriter(t)
+		triggersMock.On("UpsertJobTrigger", &domain.JobTrigger{JobName: "test-job", RequestedAt: now}).
+			Return(func(t *domain.JobTrigger) (*domain.JobTrigger, error) { return t, nil }).Once()
+
+		trigger, err := newService(pausesMock, triggersMock).Trigger("test-job")
+		require.NoError(t, err)
+		assert.Equal(t, now, trigger.RequestedAt)
+	})
+
+	t.Run("paused", func(t *testing.T) {
+		pausesMock := domainmocks.NewJobPausesReadWriter(t)
+		pausesMock.On("ListJobPauses").Return([]*domain.JobPause{{Scope: domain.PauseScopeAll}}, nil).Once()
+
+		_, err := newService(pausesMock, domainmocks.NewJobTriggersReadWriter(t)).Trigger("test-job")
+		require.Error(t, err)
+		assert.Equal(t, errors.CodeConflict, errors.ErrorCode(err))
+	})
+
+	t.Run("unknown job", func(t *testing.T) {
+		_, err := newService(domainmocks.NewJobPausesReadWriter(t), domainmocks.NewJobTriggersReadWriter(t)).Trigger("other-job")
+		require.Error(t, err)
+		assert.Equal(t, errors.CodeNotFound, errors.ErrorCode(err))
+	})
+}
+
+func TestService_Runs(t *testing.T) {
+	job := &domain.SyncJob{Name: "test-job"}
+	runsMock := domainmocks.NewSyncRunsReadWriter(t)
+	runsMock.On("ListSyncRuns", &domain.SyncRunsSelector{JobName: "test-job", Limit: 500, Offset: 10, DryRuns: true}).
+		Return([]*domain.SyncRun{{ID: "run-1"}}, nil).Once()
+
+	svc := service.New(service.WithSyncRuns(runsMock), service.WithJobs(job))
+
+	runs, err := svc.Runs("test-job", 0, 10)
+	require.NoError(t, err)
+	require.Len(t, runs, 1)
+
+	_, err = svc.Runs("other-job", 10, 0)
+	assert.Equal(t, errors.CodeNotFound, errors.ErrorCode(err))
+}
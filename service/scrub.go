@@ -0,0 +1,33 @@
+package service
+
+import (
+	"github.com/eva01/backup-guardian/domain"
+	"github.com/eva01/backup-guardian/internal/errors"
+)
+
+// scrubRuns is the number of recent runs searched for the latest finished scrub.
+const scrubRuns = 10
+
+// Scrub returns the latest finished run of the scrub job named jobName, with the coverage and
+// mismatches of the destinations it scrubbed.
+func (s *Service) Scrub(jobName string) (*domain.SyncRun, error) {
+	job, err := s.Job(jobName)
+	if err != nil {
+		return nil, err
+	}
+	if job.Scrub == nil {
+		return nil, &errors.Error{Code: errors.CodeInvalid, Message: "Job " + jobName + " is not a scrub job"}
+	}
+
+	runs, err := s.syncRuns.ListSyncRuns(&domain.SyncRunsSelector{JobName: jobName, Limit: scrubRuns})
+	if err != nil {
+		return nil, err
+	}
+	for _, run := range runs {
+		if run.Scrub != nil {
+			return run, nil
+		}
+	}
+
+	return nil, &errors.Error{Code: errors.CodeNotFound, Message: "Job " + jobName + " has not scrubbed yet"}
+}
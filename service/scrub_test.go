@@ -0,0 +1,47 @@
+package service_test
+
+import (
+	"testing"
+
+	"github.com/eva01/backup-guardian/domain"
+	domainmocks "github.com/eva01/backup-guardian/domain/mocks"
+	"github.com/eva01/backup-guardian/internal/errors"
+	"github.com/eva01/backup-guardian/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestService_Scrub(t *testing.T) {
+	target := &domain.SyncJob{Name: "test-job", Source: "source", Destination: "dest"}
+	scrub := &domain.SyncJob{Name: "scrub-job", Source: "source", Destination: "dest", Scrub: &domain.Scrub{Job: "test-job", Percent: 5}}
+	finished := &domain.SyncRun{ID: "run-1", JobName: "scrub-job", Kind: domain.KindScrub, Status: domain.StatusSuccess,
+		Scrub: &domain.ScrubResult{Job: "test-job", Against: domain.ScrubAgainstSource}}
+
+	t.Run("latest finished scrub", func(t *testing.T) {
+		runsMock := domainmocks.NewSyncRunsReadWriter(t)
+		// The running scrub has no result yet.
+		runsMock.On("ListSyncRuns", &domain.SyncRunsSelector{JobName: "scrub-job", Limit: 10}).
+			Return([]*domain.SyncRun{{ID: "run-2", Status: domain.StatusRunning}, finished}, nil).Once()
+		svc := service.New(service.WithSyncRuns(runsMock), service.WithJobs(target, scrub))
+
+		run, err := svc.Scrub("scrub-job")
+		require.NoError(t, err)
+		assert.Equal(t, finished, run)
+	})
+
+	t.Run("never scrubbed", func(t *testing.T) {
+		runsMock := domainmocks.NewSyncRunsReadWriter(t)
+		runsMock.On("ListSyncRuns", &domain.SyncRunsSelector{JobName: "scrub-job", Limit: 10}).Return([]*domain.SyncRun{}, nil).Once()
+		svc := service.New(service.WithSyncRuns(runsMock), service.WithJobs(target, scrub))
+
+		_, err := svc.Scrub("scrub-job")
+		assert.Equal(t, errors.CodeNotFound, errors.ErrorCode(err))
+	})
+
+	t.Run("not a scrub job", func(t *testing.T) {
+		svc := service.New(service.WithSyncRuns(domainmocks.NewSyncRunsReadWriter(t)), service.WithJobs(target, scrub))
+
+		_, err := svc.Scrub("test-job")
+		assert.Equal(t, errors.CodeInvalid, errors.ErrorCode(err))
+	})
+}
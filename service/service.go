@@ -0,0 +1,167 @@
+// Package service implements the operations shared by the HTTP API and the bgctl CLI.
+package service
+
+import (
+	"sync"
+	"time"
+
+	"github.com/eva01/backup-guardian/domain"
+	"github.com/eva01/backup-guardian/internal/errors"
+)
+
+// Service exposes job control and status operations on top of the store.
+type Service struct {
+	syncRuns     domain.SyncRunsReader
+	syncRunLogs  domain.SyncRunLogsReader
+	jobPauses    domain.JobPausesReadWriter
+	jobSchedules domain.JobSchedulesReader
+	jobTriggers  domain.JobTriggersReadWriter
+	jobAnomalies domain.JobAnomaliesReadWriter
+	manifests    domain.ManifestsReader
+	remotes      domain.RemotesReadWriter
+	progress     ProgressSource
+	dryRunner    DryRunner
+	jobs         []*domain.SyncJob
+	now          func() time.Time
+	// startedAt is when the service was created. Jobs that never ran are measured against
+	// their RPO from then.
+	startedAt time.Time
+
+	// configRemotes are the remotes defined in the jobs file.
+	configRemotes []*domain.Remote
+
+	// manifestVerifier verifies the manifests against their copies on the destinations.
+	manifestVerifier ManifestVerifier
+
+	configLoader    ConfigLoader
+	remoteInstaller RemoteInstaller
+	reloader        Reloader
+	// mu guards the jobs, the remotes and the last reload, replaced by reloads.
+	mu       sync.RWMutex
+	reloadMu sync.Mutex
+	// installedRemotes are the remotes installed by InstallRemotes or the last reload.
+	installedRemotes []*domain.Remote
+	lastReload       *domain.ConfigReload
+}
+
+// Option configures the service.
+type Option func(*Service)
+
+// New creates a new service.
+func New(options ...Option) *Service {
+	s := &Service{
+		now: time.Now,
+	}
+
+	for _, opt := range options {
+		opt(s)
+	}
+	s.startedAt = s.now()
+
+	return s
+}
+
+// WithSyncRuns sets the sync runs reader.
+func WithSyncRuns(syncRuns domain.SyncRunsReader) Option {
+	return func(s *Service) { s.syncRuns = syncRuns }
+}
+
+// WithSyncRunLogs sets the sync run logs reader.
+func WithSyncRunLogs(syncRunLogs domain.SyncRunLogsReader) Option {
+	return func(s *Service) { s.syncRunLogs = syncRunLogs }
+}
+
+// WithJobPauses sets the job pauses store.
+func WithJobPauses(jobPauses domain.JobPausesReadWriter) Option {
+	return func(s *Service) { s.jobPauses = jobPauses }
+}
+
+// WithJobSchedules sets the job schedules reader.
+func WithJobSchedules(jobSchedules domain.JobSchedulesReader) Option {
+	return func(s *Service) { s.jobSchedules = jobSchedules }
+}
+
+// WithJobTriggers sets the job triggers store.
+func WithJobTriggers(jobTriggers domain.JobTriggersReadWriter) Option {
+	return func(s *Service) { s.jobTriggers = jobTriggers }
+}
+
+// WithJobAnomalies sets the store of the anomalies awaiting acknowledgement.
+func WithJobAnomalies(jobAnomalies domain.JobAnomaliesReadWriter) Option {
+	return func(s *Service) { s.jobAnomalies = jobAnomalies }
+}
+
+// WithManifests sets the manifests reader.
+func WithManifests(manifests domain.ManifestsReader) Option {
+	return func(s *Service) { s.manifests = manifests }
+}
+
+// WithRemotes sets the store of the remotes defined in the database.
+func WithRemotes(remotes domain.RemotesReadWriter) Option {
+	return func(s *Service) { s.remotes = remotes }
+}
+
+// WithConfigRemotes sets the remotes defined in the jobs file.
+func WithConfigRemotes(remotes ...*domain.Remote) Option {
+	return func(s *Service) { s.configRemotes = remotes }
+}
+
+// WithProgress sets the source of the live progress of running syncs.
+func WithProgress(progress ProgressSource) Option {
+	return func(s *Service) { s.progress = progress }
+}
+
+// WithDryRunner sets the runner of dry runs. Without it, dry runs are not supported.
+func WithDryRunner(dryRunner DryRunner) Option {
+	return func(s *Service) { s.dryRunner = dryRunner }
+}
+
+// WithManifestVerifier sets the verifier of manifests. Without it, manifests are not verified.
+func WithManifestVerifier(verifier ManifestVerifier) Option {
+	return func(s *Service) { s.manifestVerifier = verifier }
+}
+
+// WithJobs sets the configured jobs.
+func WithJobs(jobs ...*domain.SyncJob) Option {
+	return func(s *Service) { s.jobs = jobs }
+}
+
+// WithConfigLoader sets the loader of the configuration, read again by reloads. Without it,
+// the configuration cannot be reloaded.
+func WithConfigLoader(loader ConfigLoader) Option {
+	return func(s *Service) { s.configLoader = loader }
+}
+
+// WithRemoteInstaller sets the installer of remotes into rclone's configuration.
+func WithRemoteInstaller(installer RemoteInstaller) Option {
+	return func(s *Service) { s.remoteInstaller = installer }
+}
+
+// WithReloader sets the runner applying the jobs of reloaded configurations.
+func WithReloader(reloader Reloader) Option {
+	return func(s *Service) { s.reloader = reloader }
+}
+
+// WithClock sets the function used to read the current time.
+func WithClock(now func() time.Time) Option {
+	return func(s *Service) { s.now = now }
+}
+
+// Jobs returns the configured jobs.
+func (s *Service) Jobs() []*domain.SyncJob {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.jobs
+}
+
+// Job returns the configured job with the given name.
+func (s *Service) Job(name string) (*domain.SyncJob, error) {
+	for _, job := range s.Jobs() {
+		if job.Name == name {
+			return job, nil
+		}
+	}
+
+	return nil, &errors.Error{Code: errors.CodeNotFound, Message: "Unknown job " + name}
+}
@@ -0,0 +1,149 @@
+package service
+
+import (
+	"time"
+
+	"github.com/eva01/backup-guardian/domain"
+)
+
+// JobStatus summarizes the state of a job.
+type JobStatus struct {
+	Job *domain.SyncJob
+	// Pause is the pause in effect for the job, runner-wide or its own. Nil when the job may run.
+	Pause *domain.JobPause
+	// LastRun is the most recent sync run of the job. Nil when the job never ran.
+	LastRun *domain.SyncRun
+	// Schedule is the persisted scheduler state of the job. Nil when the job was never scheduled.
+	Schedule *domain.JobSchedule
+	// RPO is the evaluation of the job's RPO. Nil when the job has no RPO.
+	RPO *domain.RPOStatus
+	// Trigger is the pending manual run request of the job. Nil when the job is not triggered.
+	Trigger *domain.JobTrigger
+	// Progress is the live progress of the running sync of the job. Nil when the job is not
+	// running or progress is not available.
+	Progress *domain.Progress
+	// Anomaly is the anomalous run of the job awaiting acknowledgement. Nil when there is none.
+	Anomaly *domain.JobAnomaly
+}
+
+// Stale reports whether the job has no successful run within its RPO.
+func (s *JobStatus) Stale() bool {
+	return s.RPO != nil && s.RPO.Violated
+}
+
+// Paused reports whether the job is currently paused.
+func (s *JobStatus) Paused() bool {
+	return s.Pause != nil
+}
+
+// JobStatuses returns the status of every configured job.
+func (s *Service) JobStatuses() ([]*JobStatus, error) {
+	pauses, err := s.jobPauses.ListJobPauses()
+	if err != nil {
+		return nil, err
+	}
+
+	schedules := map[string]*domain.JobSchedule{}
+	if s.jobSchedules != nil {
+		list, err := s.jobSchedules.ListJobSchedules()
+		if err != nil {
+			return nil, err
+		}
+		for _, schedule := range list {
+			schedules[schedule.JobName] = schedule
+		}
+	}
+
+	triggers := map[string]*domain.JobTrigger{}
+	if s.jobTriggers != nil {
+		list, err := s.jobTriggers.ListJobTriggers()
+		if err != nil {
+			return nil, err
+		}
+		for _, trigger := range list {
+			triggers[trigger.JobName] = trigger
+		}
+	}
+
+	anomalies := map[string]*domain.JobAnomaly{}
+	if s.jobAnomalies != nil {
+		list, err := s.jobAnomalies.ListJobAnomalies()
+		if err != nil {
+			return nil, err
+		}
+		for _, anomaly := range list {
+			anomalies[anomaly.JobName] = anomaly
+		}
+	}
+
+	progress := map[string]*domain.Progress{}
+	for _, p := range s.Progress() {
+		progress[p.JobName] = p
+	}
+
+	now := s.now()
+	jobs := s.Jobs()
+	result := make([]*JobStatus, len(jobs))
+	for i, job := range jobs {
+		status := &JobStatus{
+			Job:      job,
+			Pause:    domain.ActiveJobPause(pauses, job.Name, now),
+			Schedule: schedules[job.Name],
+			Trigger:  triggers[job.Name],
+			Progress: progress[job.Name],
+			Anomaly:  anomalies[job.Name],
+		}
+
+		runs, err := s.syncRuns.ListSyncRuns(&domain.SyncRunsSelector{JobName: job.Name, Limit: 1})
+		if err != nil {
+			return nil, err
+		}
+		if len(runs) > 0 {
+			status.LastRun = runs[0]
+		}
+
+		if job.RPO > 0 {
+			if status.RPO, err = s.checkRPO(job, status.LastRun, now); err != nil {
+				return nil, err
+			}
+		}
+
+		result[i] = status
+	}
+
+	return result, nil
+}
+
+// checkRPO evaluates the RPO of job at now. lastRun is its most recent run, if any.
+func (s *Service) checkRPO(job *domain.SyncJob, lastRun *domain.SyncRun, now time.Time) (*domain.RPOStatus, error) {
+	lastSuccess := lastRun
+	if lastSuccess == nil || lastSuccess.Status != domain.StatusSuccess {
+		runs, err := s.syncRuns.ListSyncRuns(&domain.SyncRunsSelector{JobName: job.Name, Status: domain.StatusSuccess, Limit: 1})
+		if err != nil {
+			return nil, err
+		}
+		lastSuccess = nil
+		if len(runs) > 0 {
+			lastSuccess = runs[0]
+		}
+	}
+
+	if lastSuccess != nil {
+		return domain.CheckRPO(job.RPO, lastSuccess.StartedAt, time.Time{}, now), nil
+	}
+
+	// A job that never succeeded is measured from its first run, recorded across restarts of
+	// the runner, or from when the service started when it never ran.
+	since := s.startedAt
+	if lastRun != nil {
+		runs, err := s.syncRuns.ListSyncRuns(&domain.SyncRunsSelector{JobName: job.Name, Oldest: true, Limit: 1})
+		if err != nil {
+			return nil, err
+		}
+		if len(runs) > 0 {
+			since = runs[0].StartedAt
+		}
+	}
+
+	return domain.CheckRPO(job.RPO, time.Time{}, since, now), nil
+}
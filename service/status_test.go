@@ -0,0 +1,64 @@
+package service_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/eva01/backup-guardian/domain"
+	domainmocks "github.com/eva01/backup-guardian/domain/mocks"
+	"github.com/eva01/backup-guardian/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestService_JobStatuses_RPO(t *testing.T) {
+	now := time.Date(2026, 1, 2, 12, 0, 0, 0, time.UTC)
+	jobs := []*domain.SyncJob{
+		{Name: "fresh", RPO: 26 * time.Hour},
+		{Name: "failing", RPO: 26 * time.Hour},
+		{Name: "never", RPO: 26 * time.Hour},
+		{Name: "crash-looping", RPO: 26 * time.Hour},
+	}
+	fresh := &domain.SyncRun{ID: "run-1", JobName: "fresh", Status: domain.StatusSuccess, StartedAt: now.Add(-time.Hour)}
+	failed := &domain.SyncRun{ID: "run-2", JobName: "failing", Status: domain.StatusFailed, StartedAt: now.Add(-time.Hour)}
+	oldSuccess := &domain.SyncRun{ID: "run-3", JobName: "failing", Status: domain.StatusSuccess, StartedAt: now.Add(-30 * time.Hour)}
+
+	pausesMock := domainmocks.NewJobPausesReadWriter(t)
+	pausesMock.On("ListJobPauses").Return([]*domain.JobPause{}, nil).Once()
+
+	runsMock := domainmocks.NewSyncRunsReadWriter(t)
+	runsMock.On("ListSyncRuns", &domain.SyncRunsSelector{JobName: "fresh", Limit: 1}).Return([]*domain.SyncRun{fresh}, nil).Once()
+	runsMock.On("ListSyncRuns", &domain.SyncRunsSelector{JobName: "failing", Limit: 1}).Return([]*domain.SyncRun{failed}, nil).Once()
+	runsMock.On("ListSyncRuns", &domain.SyncRunsSelector{JobName: "failing", Status: domain.StatusSuccess, Limit: 1}).
+		Return([]*domain.SyncRun{oldSuccess}, nil).Once()
+	runsMock.On("ListSyncRuns", &domain.SyncRunsSelector{JobName: "never", Limit: 1}).Return([]*domain.SyncRun{}, nil).Once()
+	runsMock.On("ListSyncRuns", &domain.SyncRunsSelector{JobName: "never", Status: domain.StatusSuccess, Limit: 1}).
+		Return([]*domain.SyncRun{}, nil).Once()
+	interrupted := &domain.SyncRun{ID: "run-4", JobName: "crash-looping", Status: domain.StatusRunning, StartedAt: now.Add(-10 * time.Minute)}
+	first := &domain.SyncRun{ID: "run-5", JobName: "crash-looping", Status: domain.StatusRunning, StartedAt: now.Add(-27 * time.Hour)}
+	runsMock.On("ListSyncRuns", &domain.SyncRunsSelector{JobName: "crash-looping", Limit: 1}).Return([]*domain.SyncRun{interrupted}, nil).Once()
+	runsMock.On("ListSyncRuns", &domain.SyncRunsSelector{JobName: "crash-looping", Status: domain.StatusSuccess, Limit: 1}).
+		Return([]*domain.SyncRun{}, nil).Once()
+	runsMock.On("ListSyncRuns", &domain.SyncRunsSelector{JobName: "crash-looping", Oldest: true, Limit: 1}).
+		Return([]*domain.SyncRun{first}, nil).Once()
+
+	svc := service.New(service.WithJobPauses(pausesMock), service.WithSyncRuns(runsMock),
+		service.WithJobs(jobs...), service.WithClock(func() time.Time { return now }))
+	statuses, err := svc.JobStatuses()
+	require.NoError(t, err)
+	require.Len(t, statuses, 4)
+
+	assert.False(t, statuses[0].Stale())
+	assert.Equal(t, fresh.StartedAt, statuses[0].RPO.LastSuccessAt)
+
+	assert.True(t, statuses[1].Stale())
+	assert.Equal(t, oldSuccess.StartedAt, statuses[1].RPO.LastSuccessAt)
+
+	// Never ran: measured from when the service started.
+	assert.False(t, statuses[2].Stale())
+	assert.Equal(t, now.Add(26*time.Hour), statuses[2].RPO.DeadlineAt)
+
+	// Never succeeded: measured from its first run, whatever the restarts since.
+	assert.True(t, statuses[3].Stale())
+	assert.Equal(t, first.StartedAt.Add(26*time.Hour), statuses[3].RPO.DeadlineAt)
+}
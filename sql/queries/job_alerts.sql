@@ -0,0 +1,18 @@
+-- name: UpsertJobAlert :one
+INSERT INTO job_alerts (job_name, kind, message, raised_at)
+VALUES (?, ?, ?, ?)
+ON CONFLICT (job_name, kind) DO UPDATE
+SET message = excluded.message
+RETURNING *;
+
+-- name: GetJobAlert :one
+SELECT * FROM job_alerts
+WHERE job_name = ? AND kind = ?;
+
+-- name: ListJobAlerts :many
+SELECT * FROM job_alerts
+ORDER BY job_name, kind;
+
+-- name: DeleteJobAlert :exec
+DELETE FROM job_alerts
+WHERE job_name = ? AND kind = ?;
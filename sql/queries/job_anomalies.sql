@@ -0,0 +1,18 @@
+-- name: CreateJobAnomaly :one
+INSERT INTO job_anomalies (job_name, run_id, action, reasons, detected_at)
+VALUES (?, ?, ?, ?, ?)
+ON CONFLICT (job_name) DO UPDATE
+SET job_name = job_anomalies.job_name
+RETURNING *;
+
+-- name: GetJobAnomaly :one
+SELECT * FROM job_anomalies
+WHERE job_name = ?;
+
+-- name: ListJobAnomalies :many
+SELECT * FROM job_anomalies
+ORDER BY job_name;
+
+-- name: DeleteJobAnomaly :exec
+DELETE FROM job_anomalies
+WHERE job_name = ?;
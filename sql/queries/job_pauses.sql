@@ -0,0 +1,20 @@
+-- name: UpsertJobPause :one
+INSERT INTO job_pauses (scope, reason, paused_at, resume_at)
+VALUES (?, ?, ?, ?)
+ON CONFLICT (scope) DO UPDATE
+SET reason = excluded.reason,
+    paused_at = excluded.paused_at,
+    resume_at = excluded.resume_at
+RETURNING *;
+
+-- name: GetJobPause :one
+SELECT * FROM job_pauses
+WHERE scope = ?;
+
+-- name: ListJobPauses :many
+SELECT * FROM job_pauses
+ORDER BY scope;
+
+-- name: DeleteJobPause :exec
+DELETE FROM job_pauses
+WHERE scope = ?;
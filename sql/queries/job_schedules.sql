@@ -0,0 +1,16 @@
+-- name: UpsertJobSchedule :one
+INSERT INTO job_schedules (job_name, last_run_at, next_due_at)
+VALUES (?, ?, ?)
+ON CONFLICT (job_name) DO UPDATE
+SET last_run_at = excluded.last_run_at,
+    next_due_at = excluded.next_due_at,
+    updated_at = CURRENT_TIMESTAMP
+RETURNING *;
+
+-- name: GetJobSchedule :one
+SELECT * FROM job_schedules
+WHERE job_name = ?;
+
+-- name: ListJobSchedules :many
+SELECT * FROM job_schedules
+ORDER BY job_name;
@@ -0,0 +1,18 @@
+-- name: UpsertJobTrigger :one
+INSERT INTO job_triggers (job_name, requested_at, pipeline_id)
+VALUES (?, ?, ?)
+ON CONFLICT (job_name) DO UPDATE
+SET job_name = excluded.job_name
+RETURNING *;
+
+-- name: GetJobTrigger :one
+SELECT * FROM job_triggers
+WHERE job_name = ?;
+
+-- name: ListJobTriggers :many
+SELECT * FROM job_triggers
+ORDER BY requested_at, job_name;
+
+-- name: DeleteJobTrigger :exec
+DELETE FROM job_triggers
+WHERE job_name = ?;
@@ -0,0 +1,24 @@
+-- name: CreateManifest :one
+INSERT INTO manifests (run_id, job_name, sequence, previous_hash, hash, files, bytes, destinations, content, created_at)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+RETURNING *;
+
+-- name: GetManifest :one
+SELECT * FROM manifests
+WHERE run_id = ?;
+
+-- name: GetLatestManifest :one
+SELECT * FROM manifests
+WHERE job_name = ?
+ORDER BY sequence DESC
+LIMIT 1;
+
+-- name: ListManifests :many
+SELECT * FROM manifests
+WHERE job_name = ?
+ORDER BY sequence;
+
+-- name: ListManifestSummaries :many
+SELECT run_id, job_name, sequence, previous_hash, hash, files, bytes, destinations, created_at FROM manifests
+WHERE job_name = ?
+ORDER BY sequence;
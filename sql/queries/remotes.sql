@@ -0,0 +1,22 @@
+-- name: UpsertRemote :one
+INSERT INTO remotes (name, type, parameters, secret_files, secret_env, created_at, updated_at)
+VALUES (?, ?, ?, ?, ?, ?, ?)
+ON CONFLICT (name) DO UPDATE
+SET type = excluded.type,
+    parameters = excluded.parameters,
+    secret_files = excluded.secret_files,
+    secret_env = excluded.secret_env,
+    updated_at = excluded.updated_at
+RETURNING *;
+
+-- name: GetRemote :one
+SELECT * FROM remotes
+WHERE name = ?;
+
+-- name: ListRemotes :many
+SELECT * FROM remotes
+ORDER BY name;
+
+-- name: DeleteRemote :exec
+DELETE FROM remotes
+WHERE name = ?;
@@ -0,0 +1,13 @@
+-- name: UpsertScrubState :one
+INSERT INTO scrub_states (job_name, destination, rotation, rotation_started_at, checked, updated_at)
+VALUES (?, ?, ?, ?, ?, ?)
+ON CONFLICT (job_name, destination) DO UPDATE
+SET rotation = excluded.rotation,
+    rotation_started_at = excluded.rotation_started_at,
+    checked = excluded.checked,
+    updated_at = excluded.updated_at
+RETURNING *;
+
+-- name: GetScrubState :one
+SELECT * FROM scrub_states
+WHERE job_name = ? AND destination = ?;
@@ -0,0 +1,8 @@
+-- name: CreateSyncRunLog :one
+INSERT INTO sync_run_logs (run_id, content, size, truncated)
+VALUES (?, ?, ?, ?)
+RETURNING *;
+
+-- name: GetSyncRunLog :one
+SELECT * FROM sync_run_logs
+WHERE run_id = ?;
@@ -1,6 +1,6 @@
 -- name: CreateSyncRun :one
-INSERT INTO sync_runs (id, job_name, status, started_at)
-VALUES (?, ?, 'running', ?)
+INSERT INTO sync_runs (id, job_name, mode, pipeline_id, kind, status, started_at)
+VALUES (?, ?, ?, ?, ?, 'running', ?)
 RETURNING *;
 
 -- name: UpdateSyncRun :exec
@@ -9,7 +9,19 @@ SET status = ?,
     finished_at = ?,
     error_message = ?,
     files_transferred = ?,
-    bytes_transferred = ?
+    bytes_transferred = ?,
+    upload_limit = ?,
+    download_limit = ?,
+    files_total = ?,
+    bytes_total = ?,
+    hook_results = ?,
+    filters = ?,
+    destinations = ?,
+    plan = ?,
+    destination_usage = ?,
+    change_stats = ?,
+    anomaly = ?,
+    scrub = ?
 WHERE id = ?;
 
 -- name: GetSyncRun :one
@@ -18,5 +30,24 @@ WHERE id = ?;
 
 -- name: ListSyncRuns :many
 SELECT * FROM sync_runs
+WHERE (kind <> 'dry-run' OR CAST(sqlc.arg(dry_runs) AS BOOLEAN))
 ORDER BY created_at DESC
 LIMIT ? OFFSET ?;
+
+-- name: ListSyncRunsByJob :many
+SELECT * FROM sync_runs
+WHERE job_name = ? AND (kind <> 'dry-run' OR CAST(sqlc.arg(dry_runs) AS BOOLEAN))
+ORDER BY CASE WHEN CAST(sqlc.arg(oldest) AS BOOLEAN) THEN created_at END, created_at DESC
+LIMIT ? OFFSET ?;
+
+-- name: ListSyncRunsByJobAndStatus :many
+SELECT * FROM sync_runs
+WHERE job_name = ? AND status = ? AND (kind <> 'dry-run' OR CAST(sqlc.arg(dry_runs) AS BOOLEAN))
+ORDER BY created_at DESC
+LIMIT ? OFFSET ?;
+
+-- name: ListSyncRunsByPipeline :many
+SELECT * FROM sync_runs
+WHERE pipeline_id = ?
+ORDER BY created_at
+LIMIT ? OFFSET ?;
@@ -7,5 +7,107 @@ CREATE TABLE sync_runs (
     error_message TEXT,
     files_transferred INTEGER DEFAULT 0,
     bytes_transferred INTEGER DEFAULT 0,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    upload_limit INTEGER,
+    download_limit INTEGER,
+    files_total INTEGER,
+    bytes_total INTEGER,
+    hook_results TEXT,
+    filters TEXT,
+    mode TEXT NOT NULL DEFAULT 'sync',
+    destinations TEXT,
+    pipeline_id TEXT,
+    kind TEXT NOT NULL DEFAULT 'sync',
+    plan TEXT,
+    destination_usage TEXT,
+    change_stats TEXT,
+    anomaly TEXT,
+    scrub TEXT
+);
+
+CREATE INDEX idx_sync_runs_pipeline_id ON sync_runs (pipeline_id);
+
+CREATE TABLE job_pauses (
+    scope TEXT PRIMARY KEY,
+    reason TEXT,
+    paused_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    resume_at DATETIME
+);
+
+CREATE TABLE job_schedules (
+    job_name TEXT PRIMARY KEY,
+    last_run_at DATETIME,
+    next_due_at DATETIME NOT NULL,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE job_alerts (
+    job_name TEXT NOT NULL,
+    kind TEXT NOT NULL,
+    message TEXT,
+    raised_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (job_name, kind)
+);
+
+CREATE TABLE job_anomalies (
+    job_name TEXT PRIMARY KEY,
+    run_id TEXT NOT NULL,
+    action TEXT NOT NULL,
+    -- reasons is the JSON array of the deviations of the run.
+    reasons TEXT,
+    detected_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE job_triggers (
+    job_name TEXT PRIMARY KEY,
+    requested_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    pipeline_id TEXT
+);
+
+CREATE TABLE sync_run_logs (
+    run_id TEXT PRIMARY KEY REFERENCES sync_runs (id) ON DELETE CASCADE,
+    -- content is the gzip-compressed log text.
+    content BLOB NOT NULL,
+    size INTEGER NOT NULL,
+    truncated BOOLEAN NOT NULL DEFAULT FALSE,
     created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
 );
+
+CREATE TABLE remotes (
+    name TEXT PRIMARY KEY,
+    type TEXT NOT NULL,
+    -- parameters, secret_files and secret_env are JSON objects of backend options.
+    parameters TEXT NOT NULL DEFAULT '{}',
+    secret_files TEXT NOT NULL DEFAULT '{}',
+    secret_env TEXT NOT NULL DEFAULT '{}',
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE manifests (
+    run_id TEXT PRIMARY KEY,
+    job_name TEXT NOT NULL,
+    sequence INTEGER NOT NULL,
+    previous_hash TEXT NOT NULL,
+    hash TEXT NOT NULL,
+    files INTEGER NOT NULL,
+    bytes INTEGER NOT NULL,
+    -- destinations is the JSON array of the destinations, without their entries.
+    destinations TEXT NOT NULL,
+    -- content is the gzip-compressed JSON of the manifest, as hashed.
+    content BLOB NOT NULL,
+    created_at DATETIME NOT NULL,
+    UNIQUE (job_name, sequence)
+);
+
+CREATE TABLE scrub_states (
+    job_name TEXT NOT NULL,
+    destination TEXT NOT NULL,
+    rotation INTEGER NOT NULL,
+    rotation_started_at DATETIME NOT NULL,
+    -- checked is the gzip-compressed JSON object of the paths checked in the rotation, with
+    -- when they were checked.
+    checked BLOB NOT NULL,
+    updated_at DATETIME NOT NULL,
+    PRIMARY KEY (job_name, destination)
+);
@@ -0,0 +1,94 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/eva01/backup-guardian/domain"
+	"github.com/eva01/backup-guardian/internal/errors"
+	"github.com/eva01/backup-guardian/store/sqlc"
+)
+
+type jobAlertsStore struct {
+	baseStore *Store
+}
+
+var _ domain.JobAlertsReadWriter = (*jobAlertsStore)(nil)
+
+func (s *jobAlertsStore) UpsertJobAlert(alert *domain.JobAlert) (*domain.JobAlert, error) {
+	if err := alert.Validate(); err != nil {
+		return nil, err
+	}
+
+	q := sqlc.New(s.baseStore.db)
+
+	var message sql.NullString
+	if alert.Message != "" {
+		message = sql.NullString{String: alert.Message, Valid: true}
+	}
+
+	row, err := q.UpsertJobAlert(context.Background(), sqlc.UpsertJobAlertParams{
+		JobName:  alert.JobName,
+		Kind:     alert.Kind,
+		Message:  message,
+		RaisedAt: alert.RaisedAt,
+	})
+	if err != nil {
+		return nil, errors.MapSQLError(err)
+	}
+
+	return mapSQLcToJobAlert(&row), nil
+}
+
+func (s *jobAlertsStore) DeleteJobAlert(selector *domain.JobAlertSelector) error {
+	q := sqlc.New(s.baseStore.db)
+
+	return errors.MapSQLError(q.DeleteJobAlert(context.Background(), sqlc.DeleteJobAlertParams{
+		JobName: selector.JobName,
+		Kind:    selector.Kind,
+	}))
+}
+
+func (s *jobAlertsStore) GetJobAlert(selector *domain.JobAlertSelector) (*domain.JobAlert, error) {
+	q := sqlc.New(s.baseStore.db)
+
+	row, err := q.GetJobAlert(context.Background(), sqlc.GetJobAlertParams{
+		JobName: selector.JobName,
+		Kind:    selector.Kind,
+	})
+	if err != nil {
+		return nil, errors.MapSQLError(err)
+	}
+
+	return mapSQLcToJobAlert(&row), nil
+}
+
+func (s *jobAlertsStore) ListJobAlerts() ([]*domain.JobAlert, error) {
+	q := sqlc.New(s.baseStore.db)
+
+	rows, err := q.ListJobAlerts(context.Background())
+	if err != nil {
+		return nil, errors.MapSQLError(err)
+	}
+
+	result := make([]*domain.JobAlert, len(rows))
+	for i := range rows {
+		result[i] = mapSQLcToJobAlert(&rows[i])
+	}
+
+	return result, nil
+}
+
+func mapSQLcToJobAlert(row *sqlc.JobAlert) *domain.JobAlert {
+	alert := &domain.JobAlert{
+		JobName:  row.JobName,
+		Kind:     row.Kind,
+		RaisedAt: row.RaisedAt,
+	}
+
+	if row.Message.Valid {
+		alert.Message = row.Message.String
+	}
+
+	return alert
+}
@@ -0,0 +1,95 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+
+	"github.com/eva01/backup-guardian/domain"
+	"github.com/eva01/backup-guardian/internal/errors"
+	"github.com/eva01/backup-guardian/store/sqlc"
+)
+
+type jobAnomaliesStore struct {
+	baseStore *Store
+}
+
+var _ domain.JobAnomaliesReadWriter = (*jobAnomaliesStore)(nil)
+
+func (s *jobAnomaliesStore) CreateJobAnomaly(anomaly *domain.JobAnomaly) (*domain.JobAnomaly, error) {
+	if err := anomaly.Validate(); err != nil {
+		return nil, err
+	}
+
+	q := sqlc.New(s.baseStore.db)
+
+	var reasons sql.NullString
+	if len(anomaly.Reasons) > 0 {
+		data, err := json.Marshal(anomaly.Reasons)
+		if err != nil {
+			return nil, err
+		}
+		reasons = sql.NullString{String: string(data), Valid: true}
+	}
+
+	row, err := q.CreateJobAnomaly(context.Background(), sqlc.CreateJobAnomalyParams{
+		JobName:    anomaly.JobName,
+		RunID:      anomaly.RunID,
+		Action:     anomaly.Action,
+		Reasons:    reasons,
+		DetectedAt: anomaly.DetectedAt,
+	})
+	if err != nil {
+		return nil, errors.MapSQLError(err)
+	}
+
+	return mapSQLcToJobAnomaly(&row), nil
+}
+
+func (s *jobAnomaliesStore) DeleteJobAnomaly(selector *domain.JobAnomalySelector) error {
+	q := sqlc.New(s.baseStore.db)
+
+	return errors.MapSQLError(q.DeleteJobAnomaly(context.Background(), selector.JobName))
+}
+
+func (s *jobAnomaliesStore) GetJobAnomaly(selector *domain.JobAnomalySelector) (*domain.JobAnomaly, error) {
+	q := sqlc.New(s.baseStore.db)
+
+	row, err := q.GetJobAnomaly(context.Background(), selector.JobName)
+	if err != nil {
+		return nil, errors.MapSQLError(err)
+	}
+
+	return mapSQLcToJobAnomaly(&row), nil
+}
+
+func (s *jobAnomaliesStore) ListJobAnomalies() ([]*domain.JobAnomaly, error) {
+	q := sqlc.New(s.baseStore.db)
+
+	rows, err := q.ListJobAnomalies(context.Background())
+	if err != nil {
+		return nil, errors.MapSQLError(err)
+	}
+
+	result := make([]*domain.JobAnomaly, len(rows))
+	for i := range rows {
+		result[i] = mapSQLcToJobAnomaly(&rows[i])
+	}
+
+	return result, nil
+}
+
+func mapSQLcToJobAnomaly(row *sqlc.JobAnomaly) *domain.JobAnomaly {
+	anomaly := &domain.JobAnomaly{
+		JobName:    row.JobName,
+		RunID:      row.RunID,
+		Action:     row.Action,
+		DetectedAt: row.DetectedAt,
+	}
+
+	if row.Reasons.Valid {
+		_ = json.Unmarshal([]byte(row.Reasons.String), &anomaly.Reasons)
+	}
+
+	return anomaly
+}
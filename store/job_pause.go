@@ -0,0 +1,91 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/eva01/backup-guardian/domain"
+	"github.com/eva01/backup-guardian/internal/errors"
+	"github.com/eva01/backup-guardian/store/sqlc"
+)
+
+type jobPausesStore struct {
+	baseStore *Store
+}
+
+var _ domain.JobPausesReadWriter = (*jobPausesStore)(nil)
+
+func (s *jobPausesStore) UpsertJobPause(pause *domain.JobPause) (*domain.JobPause, error) {
+	if err := pause.Validate(); err != nil {
+		return nil, err
+	}
+
+	q := sqlc.New(s.baseStore.db)
+
+	var reason sql.NullString
+	if pause.Reason != "" {
+		reason = sql.NullString{String: pause.Reason, Valid: true}
+	}
+	resumeAt := sql.NullTime{Time: pause.ResumeAt, Valid: !pause.ResumeAt.IsZero()}
+
+	row, err := q.UpsertJobPause(context.Background(), sqlc.UpsertJobPauseParams{
+		Scope:    pause.Scope,
+		Reason:   reason,
+		PausedAt: pause.PausedAt,
+		ResumeAt: resumeAt,
+	})
+	if err != nil {
+		return nil, errors.MapSQLError(err)
+	}
+
+	return mapSQLcToJobPause(&row), nil
+}
+
+func (s *jobPausesStore) DeleteJobPause(selector *domain.JobPauseSelector) error {
+	q := sqlc.New(s.baseStore.db)
+
+	return errors.MapSQLError(q.DeleteJobPause(context.Background(), selector.Scope))
+}
+
+func (s *jobPausesStore) GetJobPause(selector *domain.JobPauseSelector) (*domain.JobPause, error) {
+	q := sqlc.New(s.baseStore.db)
+
+	row, err := q.GetJobPause(context.Background(), selector.Scope)
+	if err != nil {
+		return nil, errors.MapSQLError(err)
+	}
+
+	return mapSQLcToJobPause(&row), nil
+}
+
+func (s *jobPausesStore) ListJobPauses() ([]*domain.JobPause, error) {
+	q := sqlc.New(s.baseStore.db)
+
+	rows, err := q.ListJobPauses(context.Background())
+	if err != nil {
+		return nil, errors.MapSQLError(err)
+	}
+
+	result := make([]*domain.JobPause, len(rows))
+	for i := range rows {
+		result[i] = mapSQLcToJobPause(&rows[i])
+	}
+
+	return result, nil
+}
+
+func mapSQLcToJobPause(row *sqlc.JobPause) *domain.JobPause {
+	pause := &domain.JobPause{
+		Scope:    row.Scope,
+		PausedAt: row.PausedAt,
+	}
+
+	if row.Reason.Valid {
+		pause.Reason = row.Reason.String
+	}
+	if row.ResumeAt.Valid {
+		pause.ResumeAt = row.ResumeAt.Time
+	}
+
+	return pause
+}
@@ -0,0 +1,76 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/eva01/backup-guardian/domain"
+	"github.com/eva01/backup-guardian/internal/errors"
+	"github.com/eva01/backup-guardian/store/sqlc"
+)
+
+type jobSchedulesStore struct {
+	baseStore *Store
+}
+
+var _ domain.JobSchedulesReadWriter = (*jobSchedulesStore)(nil)
+
+func (s *jobSchedulesStore) UpsertJobSchedule(schedule *domain.JobSchedule) (*domain.JobSchedule, error) {
+	if err := schedule.Validate(); err != nil {
+		return nil, err
+	}
+
+	q := sqlc.New(s.baseStore.db)
+
+	lastRunAt := sql.NullTime{Time: schedule.LastRunAt, Valid: !schedule.LastRunAt.IsZero()}
+	row, err := q.UpsertJobSchedule(context.Background(), sqlc.UpsertJobScheduleParams{
+		JobName:   schedule.JobName,
+		LastRunAt: lastRunAt,
+		NextDueAt: schedule.NextDueAt,
+	})
+	if err != nil {
+		return nil, errors.MapSQLError(err)
+	}
+
+	return mapSQLcToJobSchedule(&row), nil
+}
+
+func (s *jobSchedulesStore) GetJobSchedule(selector *domain.JobScheduleSelector) (*domain.JobSchedule, error) {
+	q := sqlc.New(s.baseStore.db)
+
+	row, err := q.GetJobSchedule(context.Background(), selector.JobName)
+	if err != nil {
+		return nil, errors.MapSQLError(err)
+	}
+
+	return mapSQLcToJobSchedule(&row), nil
+}
+
+func (s *jobSchedulesStore) ListJobSchedules() ([]*domain.JobSchedule, error) {
+	q := sqlc.New(s.baseStore.db)
+
+	rows, err := q.ListJobSchedules(context.Background())
+	if err != nil {
+		return nil, errors.MapSQLError(err)
+	}
+
+	result := make([]*domain.JobSchedule, len(rows))
+	for i := range rows {
+		result[i] = mapSQLcToJobSchedule(&rows[i])
+	}
+
+	return result, nil
+}
+
+func mapSQLcToJobSchedule(row *sqlc.JobSchedule) *domain.JobSchedule {
+	schedule := &domain.JobSchedule{
+		JobName:   row.JobName,
+		NextDueAt: row.NextDueAt,
+	}
+
+	if row.LastRunAt.Valid {
+		schedule.LastRunAt = row.LastRunAt.Time
+	}
+
+	return schedule
+}
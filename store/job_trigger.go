@@ -0,0 +1,76 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/eva01/backup-guardian/domain"
+	"github.com/eva01/backup-guardian/internal/errors"
+	"github.com/eva01/backup-guardian/store/sqlc"
+)
+
+type jobTriggersStore struct {
+	baseStore *Store
+}
+
+var _ domain.JobTriggersReadWriter = (*jobTriggersStore)(nil)
+
+func (s *jobTriggersStore) UpsertJobTrigger(trigger *domain.JobTrigger) (*domain.JobTrigger, error) {
+	if err := trigger.Validate(); err != nil {
+		return nil, err
+	}
+
+	q := sqlc.New(s.baseStore.db)
+
+	row, err := q.UpsertJobTrigger(context.Background(), sqlc.UpsertJobTriggerParams{
+		JobName:     trigger.JobName,
+		RequestedAt: trigger.RequestedAt,
+		PipelineID:  sql.NullString{String: trigger.PipelineID, Valid: trigger.PipelineID != ""},
+	})
+	if err != nil {
+		return nil, errors.MapSQLError(err)
+	}
+
+	return mapSQLcToJobTrigger(&row), nil
+}
+
+func (s *jobTriggersStore) DeleteJobTrigger(selector *domain.JobTriggerSelector) error {
+	q := sqlc.New(s.baseStore.db)
+
+	return errors.MapSQLError(q.DeleteJobTrigger(context.Background(), selector.JobName))
+}
+
+func (s *jobTriggersStore) GetJobTrigger(selector *domain.JobTriggerSelector) (*domain.JobTrigger, error) {
+	q := sqlc.New(s.baseStore.db)
+
+	row, err := q.GetJobTrigger(context.Background(), selector.JobName)
+	if err != nil {
+		return nil, errors.MapSQLError(err)
+	}
+
+	return mapSQLcToJobTrigger(&row), nil
+}
+
+func (s *jobTriggersStore) ListJobTriggers() ([]*domain.JobTrigger, error) {
+	q := sqlc.New(s.baseStore.db)
+
+	rows, err := q.ListJobTriggers(context.Background())
+	if err != nil {
+		return nil, errors.MapSQLError(err)
+	}
+
+	result := make([]*domain.JobTrigger, len(rows))
+	for i := range rows {
+		result[i] = mapSQLcToJobTrigger(&rows[i])
+	}
+
+	return result, nil
+}
+
+func mapSQLcToJobTrigger(row *sqlc.JobTrigger) *domain.JobTrigger {
+	return &domain.JobTrigger{
+		JobName:     row.JobName,
+		RequestedAt: row.RequestedAt,
+		PipelineID:  row.PipelineID.String,
+	}
+}
@@ -0,0 +1,153 @@
+package store
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"io"
+
+	"github.com/eva01/backup-guardian/domain"
+	"github.com/eva01/backup-guardian/internal/errors"
+	"github.com/eva01/backup-guardian/store/sqlc"
+)
+
+type manifestsStore struct {
+	baseStore *Store
+}
+
+var _ domain.ManifestsReadWriter = (*manifestsStore)(nil)
+
+// CreateManifest stores manifest, its JSON gzip-compressed. A manifest with the sequence of
+// another manifest of the job is a conflict.
+func (s *manifestsStore) CreateManifest(manifest *domain.Manifest) (*domain.Manifest, error) {
+	if err := manifest.Validate(); err != nil {
+		return nil, err
+	}
+
+	content, err := json.Marshal(manifest)
+	if err != nil {
+		return nil, err
+	}
+	var compressed bytes.Buffer
+	zw := gzip.NewWriter(&compressed)
+	if _, err := zw.Write(content); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+
+	summaries := make([]domain.ManifestDestination, len(manifest.Destinations))
+	for i, dest := range manifest.Destinations {
+		summaries[i] = domain.ManifestDestination{Destination: dest.Destination, Files: dest.Files, Bytes: dest.Bytes}
+	}
+	destinations, err := json.Marshal(summaries)
+	if err != nil {
+		return nil, err
+	}
+
+	q := sqlc.New(s.baseStore.db)
+
+	row, err := q.CreateManifest(context.Background(), sqlc.CreateManifestParams{
+		RunID:        manifest.RunID,
+		JobName:      manifest.JobName,
+		Sequence:     manifest.Sequence,
+		PreviousHash: manifest.PreviousHash,
+		Hash:         manifest.Hash,
+		Files:        manifest.Files,
+		Bytes:        manifest.Bytes,
+		Destinations: string(destinations),
+		Content:      compressed.Bytes(),
+		CreatedAt:    manifest.CreatedAt,
+	})
+	if err != nil {
+		return nil, errors.MapSQLError(err)
+	}
+
+	return mapSQLcToManifest(&row)
+}
+
+// GetManifest returns the manifest of selector.RunID, or the latest manifest of
+// selector.JobName.
+func (s *manifestsStore) GetManifest(selector *domain.ManifestSelector) (*domain.Manifest, error) {
+	q := sqlc.New(s.baseStore.db)
+
+	var row sqlc.Manifest
+	var err error
+	if selector.RunID != "" {
+		row, err = q.GetManifest(context.Background(), selector.RunID)
+	} else {
+		row, err = q.GetLatestManifest(context.Background(), selector.JobName)
+	}
+	if err != nil {
+		return nil, errors.MapSQLError(err)
+	}
+
+	return mapSQLcToManifest(&row)
+}
+
+func (s *manifestsStore) ListManifests(selector *domain.ManifestsSelector) ([]*domain.Manifest, error) {
+	q := sqlc.New(s.baseStore.db)
+
+	if !selector.Entries {
+		rows, err := q.ListManifestSummaries(context.Background(), selector.JobName)
+		if err != nil {
+			return nil, errors.MapSQLError(err)
+		}
+
+		result := make([]*domain.Manifest, len(rows))
+		for i, row := range rows {
+			result[i] = &domain.Manifest{
+				RunID:        row.RunID,
+				JobName:      row.JobName,
+				Sequence:     row.Sequence,
+				CreatedAt:    row.CreatedAt,
+				PreviousHash: row.PreviousHash,
+				Files:        row.Files,
+				Bytes:        row.Bytes,
+				Hash:         row.Hash,
+			}
+			if err := json.Unmarshal([]byte(row.Destinations), &result[i].Destinations); err != nil {
+				return nil, err
+			}
+		}
+
+		return result, nil
+	}
+
+	rows, err := q.ListManifests(context.Background(), selector.JobName)
+	if err != nil {
+		return nil, errors.MapSQLError(err)
+	}
+
+	result := make([]*domain.Manifest, len(rows))
+	for i := range rows {
+		if result[i], err = mapSQLcToManifest(&rows[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+// mapSQLcToManifest decodes the manifest stored in row. Its hash is the one of the row: a
+// content altered in the database no longer matches it.
+func mapSQLcToManifest(row *sqlc.Manifest) (*domain.Manifest, error) {
+	zr, err := gzip.NewReader(bytes.NewReader(row.Content))
+	if err != nil {
+		return nil, err
+	}
+	content, err := io.ReadAll(zr)
+	if err != nil {
+		return nil, err
+	}
+
+	manifest := &domain.Manifest{}
+	if err := json.Unmarshal(content, manifest); err != nil {
+		return nil, err
+	}
+	manifest.Hash = row.Hash
+
+	return manifest, nil
+}
@@ -0,0 +1,111 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/eva01/backup-guardian/domain"
+	"github.com/eva01/backup-guardian/internal/errors"
+	"github.com/eva01/backup-guardian/store/sqlc"
+)
+
+type remotesStore struct {
+	baseStore *Store
+}
+
+var _ domain.RemotesReadWriter = (*remotesStore)(nil)
+
+func (s *remotesStore) UpsertRemote(remote *domain.Remote) (*domain.Remote, error) {
+	if err := remote.Validate(); err != nil {
+		return nil, err
+	}
+
+	q := sqlc.New(s.baseStore.db)
+
+	params := sqlc.UpsertRemoteParams{
+		Name:      remote.Name,
+		Type:      remote.Type,
+		CreatedAt: remote.CreatedAt,
+		UpdatedAt: remote.UpdatedAt,
+	}
+	var err error
+	if params.Parameters, err = marshalOptions(remote.Parameters); err != nil {
+		return nil, err
+	}
+	if params.SecretFiles, err = marshalOptions(remote.SecretFiles); err != nil {
+		return nil, err
+	}
+	if params.SecretEnv, err = marshalOptions(remote.SecretEnv); err != nil {
+		return nil, err
+	}
+
+	row, err := q.UpsertRemote(context.Background(), params)
+	if err != nil {
+		return nil, errors.MapSQLError(err)
+	}
+
+	return mapSQLcToRemote(&row), nil
+}
+
+func (s *remotesStore) DeleteRemote(selector *domain.RemoteSelector) error {
+	q := sqlc.New(s.baseStore.db)
+
+	return errors.MapSQLError(q.DeleteRemote(context.Background(), selector.Name))
+}
+
+func (s *remotesStore) GetRemote(selector *domain.RemoteSelector) (*domain.Remote, error) {
+	q := sqlc.New(s.baseStore.db)
+
+	row, err := q.GetRemote(context.Background(), selector.Name)
+	if err != nil {
+		return nil, errors.MapSQLError(err)
+	}
+
+	return mapSQLcToRemote(&row), nil
+}
+
+func (s *remotesStore) ListRemotes() ([]*domain.Remote, error) {
+	q := sqlc.New(s.baseStore.db)
+
+	rows, err := q.ListRemotes(context.Background())
+	if err != nil {
+		return nil, errors.MapSQLError(err)
+	}
+
+	result := make([]*domain.Remote, len(rows))
+	for i := range rows {
+		result[i] = mapSQLcToRemote(&rows[i])
+	}
+
+	return result, nil
+}
+
+// marshalOptions encodes backend options as a JSON object, {} when there is none.
+func marshalOptions(options map[string]string) (string, error) {
+	if len(options) == 0 {
+		return "{}", nil
+	}
+
+	data, err := json.Marshal(options)
+	if err != nil {
+		return "", err
+	}
+
+	return string(data), nil
+}
+
+func mapSQLcToRemote(row *sqlc.Remote) *domain.Remote {
+	remote := &domain.Remote{
+		Name:      row.Name,
+		Type:      row.Type,
+		Origin:    domain.RemoteOriginDatabase,
+		CreatedAt: row.CreatedAt,
+		UpdatedAt: row.UpdatedAt,
+	}
+
+	_ = json.Unmarshal([]byte(row.Parameters), &remote.Parameters)
+	_ = json.Unmarshal([]byte(row.SecretFiles), &remote.SecretFiles)
+	_ = json.Unmarshal([]byte(row.SecretEnv), &remote.SecretEnv)
+
+	return remote
+}
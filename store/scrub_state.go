@@ -0,0 +1,94 @@
+package store
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"io"
+	"time"
+
+	"github.com/eva01/backup-guardian/domain"
+	"github.com/eva01/backup-guardian/internal/errors"
+	"github.com/eva01/backup-guardian/store/sqlc"
+)
+
+type scrubStatesStore struct {
+	baseStore *Store
+}
+
+var _ domain.ScrubStatesReadWriter = (*scrubStatesStore)(nil)
+
+// UpsertScrubState stores state, its checked files as gzip-compressed JSON: a rotation of a
+// large destination lists many paths.
+func (s *scrubStatesStore) UpsertScrubState(state *domain.ScrubState) (*domain.ScrubState, error) {
+	content, err := json.Marshal(state.Checked)
+	if err != nil {
+		return nil, err
+	}
+	var compressed bytes.Buffer
+	zw := gzip.NewWriter(&compressed)
+	if _, err := zw.Write(content); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+
+	q := sqlc.New(s.baseStore.db)
+
+	row, err := q.UpsertScrubState(context.Background(), sqlc.UpsertScrubStateParams{
+		JobName:           state.JobName,
+		Destination:       state.Destination,
+		Rotation:          state.Rotation,
+		RotationStartedAt: state.RotationStartedAt,
+		Checked:           compressed.Bytes(),
+		UpdatedAt:         time.Now().UTC(),
+	})
+	if err != nil {
+		return nil, errors.MapSQLError(err)
+	}
+
+	return mapSQLcToScrubState(&row)
+}
+
+func (s *scrubStatesStore) GetScrubState(selector *domain.ScrubStateSelector) (*domain.ScrubState, error) {
+	q := sqlc.New(s.baseStore.db)
+
+	row, err := q.GetScrubState(context.Background(), sqlc.GetScrubStateParams{
+		JobName:     selector.JobName,
+		Destination: selector.Destination,
+	})
+	if err != nil {
+		return nil, errors.MapSQLError(err)
+	}
+
+	return mapSQLcToScrubState(&row)
+}
+
+func mapSQLcToScrubState(row *sqlc.ScrubState) (*domain.ScrubState, error) {
+	zr, err := gzip.NewReader(bytes.NewReader(row.Checked))
+	if err != nil {
+		return nil, err
+	}
+	content, err := io.ReadAll(zr)
+	if err != nil {
+		return nil, err
+	}
+
+	state := &domain.ScrubState{
+		JobName:           row.JobName,
+		Destination:       row.Destination,
+		Rotation:          row.Rotation,
+		RotationStartedAt: row.RotationStartedAt,
+		UpdatedAt:         row.UpdatedAt,
+	}
+	if err := json.Unmarshal(content, &state.Checked); err != nil {
+		return nil, err
+	}
+	if state.Checked == nil {
+		state.Checked = map[string]time.Time{}
+	}
+
+	return state, nil
+}
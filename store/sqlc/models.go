@@ -9,6 +9,73 @@ import (
 	"time"
 )
 
+type JobAlert struct {
+	JobName  string         `json:"job_name"`
+	Kind     string         `json:"kind"`
+	Message  sql.NullString `json:"message"`
+	RaisedAt time.Time      `json:"raised_at"`
+}
+
+type JobAnomaly struct {
+	JobName    string         `json:"job_name"`
+	RunID      string         `json:"run_id"`
+	Action     string         `json:"action"`
+	Reasons    sql.NullString `json:"reasons"`
+	DetectedAt time.Time      `json:"detected_at"`
+}
+
+type JobPause struct {
+	Scope    string         `json:"scope"`
+	Reason   sql.NullString `json:"reason"`
+	PausedAt time.Time      `json:"paused_at"`
+	ResumeAt sql.NullTime   `json:"resume_at"`
+}
+
+type JobSchedule struct {
+	JobName   string       `json:"job_name"`
+	LastRunAt sql.NullTime `json:"last_run_at"`
+	NextDueAt time.Time    `json:"next_due_at"`
+	UpdatedAt time.Time    `json:"updated_at"`
+}
+
+type JobTrigger struct {
+	JobName     string         `json:"job_name"`
+	RequestedAt time.Time      `json:"requested_at"`
+	PipelineID  sql.NullString `json:"pipeline_id"`
+}
+
+type Manifest struct {
+	RunID        string    `json:"run_id"`
+	JobName      string    `json:"job_name"`
+	Sequence     int64     `json:"sequence"`
+	PreviousHash string    `json:"previous_hash"`
+	Hash         string    `json:"hash"`
+	Files        int64     `json:"files"`
+	Bytes        int64     `json:"bytes"`
+	Destinations string    `json:"destinations"`
+	Content      []byte    `json:"content"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+type Remote struct {
+	Name        string    `json:"name"`
+	Type        string    `json:"type"`
+	Parameters  string    `json:"parameters"`
+	SecretFiles string    `json:"secret_files"`
+	SecretEnv   string    `json:"secret_env"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+type ScrubState struct {
+	JobName           string    `json:"job_name"`
+	Destination       string    `json:"destination"`
+	Rotation          int64     `json:"rotation"`
+	RotationStartedAt time.Time `json:"rotation_started_at"`
+	Checked           []byte    `json:"checked"`
+	UpdatedAt         time.Time `json:"updated_at"`
+}
+
 type SyncRun struct {
 	ID               string         `json:"id"`
 	JobName          string         `json:"job_name"`
@@ -19,4 +86,27 @@ type SyncRun struct {
 	FilesTransferred sql.NullInt64  `json:"files_transferred"`
 	BytesTransferred sql.NullInt64  `json:"bytes_transferred"`
 	CreatedAt        time.Time      `json:"created_at"`
+	UploadLimit      sql.NullInt64  `json:"upload_limit"`
+	DownloadLimit    sql.NullInt64  `json:"download_limit"`
+	FilesTotal       sql.NullInt64  `json:"files_total"`
+	BytesTotal       sql.NullInt64  `json:"bytes_total"`
+	HookResults      sql.NullString `json:"hook_results"`
+	Filters          sql.NullString `json:"filters"`
+	Mode             string         `json:"mode"`
+	Destinations     sql.NullString `json:"destinations"`
+	PipelineID       sql.NullString `json:"pipeline_id"`
+	Kind             string         `json:"kind"`
+	Plan             sql.NullString `json:"plan"`
+	DestinationUsage sql.NullString `json:"destination_usage"`
+	ChangeStats      sql.NullString `json:"change_stats"`
+	Anomaly          sql.NullString `json:"anomaly"`
+	Scrub            sql.NullString `json:"scrub"`
+}
+
+type SyncRunLog struct {
+	RunID     string    `json:"run_id"`
+	Content   []byte    `json:"content"`
+	Size      int64     `json:"size"`
+	Truncated bool      `json:"truncated"`
+	CreatedAt time.Time `json:"created_at"`
 }
@@ -9,10 +9,44 @@ import (
 )
 
 type Querier interface {
+	CreateJobAnomaly(ctx context.Context, arg CreateJobAnomalyParams) (JobAnomaly, error)
+	CreateManifest(ctx context.Context, arg CreateManifestParams) (Manifest, error)
 	CreateSyncRun(ctx context.Context, arg CreateSyncRunParams) (SyncRun, error)
+	CreateSyncRunLog(ctx context.Context, arg CreateSyncRunLogParams) (SyncRunLog, error)
+	DeleteJobAlert(ctx context.Context, arg DeleteJobAlertParams) error
+	DeleteJobAnomaly(ctx context.Context, jobName string) error
+	DeleteJobPause(ctx context.Context, scope string) error
+	DeleteJobTrigger(ctx context.Context, jobName string) error
+	DeleteRemote(ctx context.Context, name string) error
+	GetJobAlert(ctx context.Context, arg GetJobAlertParams) (JobAlert, error)
+	GetJobAnomaly(ctx context.Context, jobName string) (JobAnomaly, error)
+	GetJobPause(ctx context.Context, scope string) (JobPause, error)
+	GetJobSchedule(ctx context.Context, jobName string) (JobSchedule, error)
+	GetJobTrigger(ctx context.Context, jobName string) (JobTrigger, error)
+	GetLatestManifest(ctx context.Context, jobName string) (Manifest, error)
+	GetManifest(ctx context.Context, runID string) (Manifest, error)
+	GetRemote(ctx context.Context, name string) (Remote, error)
+	GetScrubState(ctx context.Context, arg GetScrubStateParams) (ScrubState, error)
 	GetSyncRun(ctx context.Context, id string) (SyncRun, error)
+	GetSyncRunLog(ctx context.Context, runID string) (SyncRunLog, error)
+	ListJobAlerts(ctx context.Context) ([]JobAlert, error)
+	ListJobAnomalies(ctx context.Context) ([]JobAnomaly, error)
+	ListJobPauses(ctx context.Context) ([]JobPause, error)
+	ListJobSchedules(ctx context.Context) ([]JobSchedule, error)
+	ListJobTriggers(ctx context.Context) ([]JobTrigger, error)
+	ListManifestSummaries(ctx context.Context, jobName string) ([]ListManifestSummariesRow, error)
+	ListManifests(ctx context.Context, jobName string) ([]Manifest, error)
+	ListRemotes(ctx context.Context) ([]Remote, error)
 	ListSyncRuns(ctx context.Context, arg ListSyncRunsParams) ([]SyncRun, error)
+	ListSyncRunsByJob(ctx context.Context, arg ListSyncRunsByJobParams) ([]SyncRun, error)
+	ListSyncRunsByJobAndStatus(ctx context.Context, arg ListSyncRunsByJobAndStatusParams) ([]SyncRun, error)
 	UpdateSyncRun(ctx context.Context, arg UpdateSyncRunParams) error
+	UpsertJobAlert(ctx context.Context, arg UpsertJobAlertParams) (JobAlert, error)
+	UpsertJobPause(ctx context.Context, arg UpsertJobPauseParams) (JobPause, error)
+	UpsertJobSchedule(ctx context.Context, arg UpsertJobScheduleParams) (JobSchedule, error)
+	UpsertJobTrigger(ctx context.Context, arg UpsertJobTriggerParams) (JobTrigger, error)
+	UpsertRemote(ctx context.Context, arg UpsertRemoteParams) (Remote, error)
+	UpsertScrubState(ctx context.Context, arg UpsertScrubStateParams) (ScrubState, error)
 }
 
 var _ Querier = (*Queries)(nil)
@@ -11,19 +11,29 @@ import (
 )
 
 const createSyncRun = `-- name: CreateSyncRun :one
-INSERT INTO sync_runs (id, job_name, status, started_at)
-VALUES (?, ?, 'running', ?)
-RETURNING id, job_name, status, started_at, finished_at, error_message, files_transferred, bytes_transferred, created_at
+INSERT INTO sync_runs (id, job_name, mode, pipeline_id, kind, status, started_at)
+VALUES (?, ?, ?, ?, ?, 'running', ?)
+RETURNING id, job_name, status, started_at, finished_at, error_message, files_transferred, bytes_transferred, created_at, upload_limit, download_limit, files_total, bytes_total, hook_results, filters, mode, destinations, pipeline_id, kind, plan, destination_usage, change_stats, anomaly, scrub
 `
 
 type CreateSyncRunParams struct {
-	ID        string       `json:"id"`
-	JobName   string       `json:"job_name"`
-	StartedAt sql.NullTime `json:"started_at"`
+	ID         string         `json:"id"`
+	JobName    string         `json:"job_name"`
+	Mode       string         `json:"mode"`
+	PipelineID sql.NullString `json:"pipeline_id"`
+	Kind       string         `json:"kind"`
+	StartedAt  sql.NullTime   `json:"started_at"`
 }
 
 func (q *Queries) CreateSyncRun(ctx context.Context, arg CreateSyncRunParams) (SyncRun, error) {
-	row := q.db.QueryRowContext(ctx, createSyncRun, arg.ID, arg.JobName, arg.StartedAt)
+	row := q.db.QueryRowContext(ctx, createSyncRun,
+		arg.ID,
+		arg.JobName,
+		arg.Mode,
+		arg.PipelineID,
+		arg.Kind,
+		arg.StartedAt,
+	)
 	var i SyncRun
 	err := row.Scan(
 		&i.ID,
@@ -35,12 +45,27 @@ func (q *Queries) CreateSyncRun(ctx context.Context, arg CreateSyncRunParams) (S
 		&i.FilesTransferred,
 		&i.BytesTransferred,
 		&i.CreatedAt,
+		&i.UploadLimit,
+		&i.DownloadLimit,
+		&i.FilesTotal,
+		&i.BytesTotal,
+		&i.HookResults,
+		&i.Filters,
+		&i.Mode,
+		&i.Destinations,
+		&i.PipelineID,
+		&i.Kind,
+		&i.Plan,
+		&i.DestinationUsage,
+		&i.ChangeStats,
+		&i.Anomaly,
+		&i.Scrub,
 	)
 	return i, err
 }
 
 const getSyncRun = `-- name: GetSyncRun :one
-SELECT id, job_name, status, started_at, finished_at, error_message, files_transferred, bytes_transferred, created_at FROM sync_runs
+SELECT id, job_name, status, started_at, finished_at, error_message, files_transferred, bytes_transferred, created_at, upload_limit, download_limit, files_total, bytes_total, hook_results, filters, mode, destinations, pipeline_id, kind, plan, destination_usage, change_stats, anomaly, scrub FROM sync_runs
 WHERE id = ?
 `
 
@@ -57,23 +82,227 @@ func (q *Queries) GetSyncRun(ctx context.Context, id string) (SyncRun, error) {
 		&i.FilesTransferred,
 		&i.BytesTransferred,
 		&i.CreatedAt,
+		&i.UploadLimit,
+		&i.DownloadLimit,
+		&i.FilesTotal,
+		&i.BytesTotal,
+		&i.HookResults,
+		&i.Filters,
+		&i.Mode,
+		&i.Destinations,
+		&i.PipelineID,
+		&i.Kind,
+		&i.Plan,
+		&i.DestinationUsage,
+		&i.ChangeStats,
+		&i.Anomaly,
+		&i.Scrub,
 	)
 	return i, err
 }
 
 const listSyncRuns = `-- name: ListSyncRuns :many
-SELECT id, job_name, status, started_at, finished_at, error_message, files_transferred, bytes_transferred, created_at FROM sync_runs
+SELECT id, job_name, status, started_at, finished_at, error_message, files_transferred, bytes_transferred, created_at, upload_limit, download_limit, files_total, bytes_total, hook_results, filters, mode, destinations, pipeline_id, kind, plan, destination_usage, change_stats, anomaly, scrub FROM sync_runs
+WHERE (kind <> 'dry-run' OR CAST(? AS BOOLEAN))
 ORDER BY created_at DESC
 LIMIT ? OFFSET ?
 `
 
 type ListSyncRunsParams struct {
-	Limit  int64 `json:"limit"`
-	Offset int64 `json:"offset"`
+	DryRuns bool  `json:"dry_runs"`
+	Limit   int64 `json:"limit"`
+	Offset  int64 `json:"offset"`
 }
 
 func (q *Queries) ListSyncRuns(ctx context.Context, arg ListSyncRunsParams) ([]SyncRun, error) {
-	rows, err := q.db.QueryContext(ctx, listSyncRuns, arg.Limit, arg.Offset)
+	rows, err := q.db.QueryContext(ctx, listSyncRuns, arg.DryRuns, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []SyncRun{}
+	for rows.Next() {
+		var i SyncRun
+		if err := rows.Scan(
+			&i.ID,
+			&i.JobName,
+			&i.Status,
+			&i.StartedAt,
+			&i.FinishedAt,
+			&i.ErrorMessage,
+			&i.FilesTransferred,
+			&i.BytesTransferred,
+			&i.CreatedAt,
+			&i.UploadLimit,
+			&i.DownloadLimit,
+			&i.FilesTotal,
+			&i.BytesTotal,
+			&i.HookResults,
+			&i.Filters,
+			&i.Mode,
+			&i.Destinations,
+			&i.PipelineID,
+			&i.Kind,
+			&i.Plan,
+			&i.DestinationUsage,
+			&i.ChangeStats,
+			&i.Anomaly,
+			&i.Scrub,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listSyncRunsByJob = `-- name: ListSyncRunsByJob :many
+SELECT id, job_name, status, started_at, finished_at, error_message, files_transferred, bytes_transferred, created_at, upload_limit, download_limit, files_total, bytes_total, hook_results, filters, mode, destinations, pipeline_id, kind, plan, destination_usage, change_stats, anomaly, scrub FROM sync_runs
+WHERE job_name = ? AND (kind <> 'dry-run' OR CAST(? AS BOOLEAN))
+ORDER BY CASE WHEN CAST(? AS BOOLEAN) THEN created_at END, created_at DESC
+LIMIT ? OFFSET ?
+`
+
+type ListSyncRunsByJobParams struct {
+	JobName string `json:"job_name"`
+	DryRuns bool   `json:"dry_runs"`
+	Oldest  bool   `json:"oldest"`
+	Limit   int64  `json:"limit"`
+	Offset  int64  `json:"offset"`
+}
+
+func (q *Queries) ListSyncRunsByJob(ctx context.Context, arg ListSyncRunsByJobParams) ([]SyncRun, error) {
+	rows, err := q.db.QueryContext(ctx, listSyncRunsByJob, arg.JobName, arg.DryRuns, arg.Oldest, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []SyncRun{}
+	for rows.Next() {
+		var i SyncRun
+		if err := rows.Scan(
+			&i.ID,
+			&i.JobName,
+			&i.Status,
+			&i.StartedAt,
+			&i.FinishedAt,
+			&i.ErrorMessage,
+			&i.FilesTransferred,
+			&i.BytesTransferred,
+			&i.CreatedAt,
+			&i.UploadLimit,
+			&i.DownloadLimit,
+			&i.FilesTotal,
+			&i.BytesTotal,
+			&i.HookResults,
+			&i.Filters,
+			&i.Mode,
+			&i.Destinations,
+			&i.PipelineID,
+			&i.Kind,
+			&i.Plan,
+			&i.DestinationUsage,
+			&i.ChangeStats,
+			&i.Anomaly,
+			&i.Scrub,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listSyncRunsByJobAndStatus = `-- name: ListSyncRunsByJobAndStatus :many
+SELECT id, job_name, status, started_at, finished_at, error_message, files_transferred, bytes_transferred, created_at, upload_limit, download_limit, files_total, bytes_total, hook_results, filters, mode, destinations, pipeline_id, kind, plan, destination_usage, change_stats, anomaly, scrub FROM sync_runs
+WHERE job_name = ? AND status = ? AND (kind <> 'dry-run' OR CAST(? AS BOOLEAN))
+ORDER BY created_at DESC
+LIMIT ? OFFSET ?
+`
+
+type ListSyncRunsByJobAndStatusParams struct {
+	JobName string `json:"job_name"`
+	Status  string `json:"status"`
+	DryRuns bool   `json:"dry_runs"`
+	Limit   int64  `json:"limit"`
+	Offset  int64  `json:"offset"`
+}
+
+func (q *Queries) ListSyncRunsByJobAndStatus(ctx context.Context, arg ListSyncRunsByJobAndStatusParams) ([]SyncRun, error) {
+	rows, err := q.db.QueryContext(ctx, listSyncRunsByJobAndStatus, arg.JobName, arg.Status, arg.DryRuns, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []SyncRun{}
+	for rows.Next() {
+		var i SyncRun
+		if err := rows.Scan(
+			&i.ID,
+			&i.JobName,
+			&i.Status,
+			&i.StartedAt,
+			&i.FinishedAt,
+			&i.ErrorMessage,
+			&i.FilesTransferred,
+			&i.BytesTransferred,
+			&i.CreatedAt,
+			&i.UploadLimit,
+			&i.DownloadLimit,
+			&i.FilesTotal,
+			&i.BytesTotal,
+			&i.HookResults,
+			&i.Filters,
+			&i.Mode,
+			&i.Destinations,
+			&i.PipelineID,
+			&i.Kind,
+			&i.Plan,
+			&i.DestinationUsage,
+			&i.ChangeStats,
+			&i.Anomaly,
+			&i.Scrub,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listSyncRunsByPipeline = `-- name: ListSyncRunsByPipeline :many
+SELECT id, job_name, status, started_at, finished_at, error_message, files_transferred, bytes_transferred, created_at, upload_limit, download_limit, files_total, bytes_total, hook_results, filters, mode, destinations, pipeline_id, kind, plan, destination_usage, change_stats, anomaly, scrub FROM sync_runs
+WHERE pipeline_id = ?
+ORDER BY created_at
+LIMIT ? OFFSET ?
+`
+
+type ListSyncRunsByPipelineParams struct {
+	PipelineID sql.NullString `json:"pipeline_id"`
+	Limit      int64          `json:"limit"`
+	Offset     int64          `json:"offset"`
+}
+
+func (q *Queries) ListSyncRunsByPipeline(ctx context.Context, arg ListSyncRunsByPipelineParams) ([]SyncRun, error) {
+	rows, err := q.db.QueryContext(ctx, listSyncRunsByPipeline, arg.PipelineID, arg.Limit, arg.Offset)
 	if err != nil {
 		return nil, err
 	}
@@ -91,6 +320,21 @@ func (q *Queries) ListSyncRuns(ctx context.Context, arg ListSyncRunsParams) ([]S
 			&i.FilesTransferred,
 			&i.BytesTransferred,
 			&i.CreatedAt,
+			&i.UploadLimit,
+			&i.DownloadLimit,
+			&i.FilesTotal,
+			&i.BytesTotal,
+			&i.HookResults,
+			&i.Filters,
+			&i.Mode,
+			&i.Destinations,
+			&i.PipelineID,
+			&i.Kind,
+			&i.Plan,
+			&i.DestinationUsage,
+			&i.ChangeStats,
+			&i.Anomaly,
+			&i.Scrub,
 		); err != nil {
 			return nil, err
 		}
@@ -111,7 +355,19 @@ SET status = ?,
     finished_at = ?,
     error_message = ?,
     files_transferred = ?,
-    bytes_transferred = ?
+    bytes_transferred = ?,
+    upload_limit = ?,
+    download_limit = ?,
+    files_total = ?,
+    bytes_total = ?,
+    hook_results = ?,
+    filters = ?,
+    destinations = ?,
+    plan = ?,
+    destination_usage = ?,
+    change_stats = ?,
+    anomaly = ?,
+    scrub = ?
 WHERE id = ?
 `
 
@@ -121,6 +377,18 @@ type UpdateSyncRunParams struct {
 	ErrorMessage     sql.NullString `json:"error_message"`
 	FilesTransferred sql.NullInt64  `json:"files_transferred"`
 	BytesTransferred sql.NullInt64  `json:"bytes_transferred"`
+	UploadLimit      sql.NullInt64  `json:"upload_limit"`
+	DownloadLimit    sql.NullInt64  `json:"download_limit"`
+	FilesTotal       sql.NullInt64  `json:"files_total"`
+	BytesTotal       sql.NullInt64  `json:"bytes_total"`
+	HookResults      sql.NullString `json:"hook_results"`
+	Filters          sql.NullString `json:"filters"`
+	Destinations     sql.NullString `json:"destinations"`
+	Plan             sql.NullString `json:"plan"`
+	DestinationUsage sql.NullString `json:"destination_usage"`
+	ChangeStats      sql.NullString `json:"change_stats"`
+	Anomaly          sql.NullString `json:"anomaly"`
+	Scrub            sql.NullString `json:"scrub"`
 	ID               string         `json:"id"`
 }
 
@@ -131,6 +399,18 @@ func (q *Queries) UpdateSyncRun(ctx context.Context, arg UpdateSyncRunParams) er
 		arg.ErrorMessage,
 		arg.FilesTransferred,
 		arg.BytesTransferred,
+		arg.UploadLimit,
+		arg.DownloadLimit,
+		arg.FilesTotal,
+		arg.BytesTotal,
+		arg.HookResults,
+		arg.Filters,
+		arg.Destinations,
+		arg.Plan,
+		arg.DestinationUsage,
+		arg.ChangeStats,
+		arg.Anomaly,
+		arg.Scrub,
 		arg.ID,
 	)
 	return err
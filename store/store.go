@@ -9,7 +9,16 @@ import (
 
 // Store provides access to persistence layers.
 type Store struct {
-	SyncRuns domain.SyncRunsReadWriter
+	SyncRuns     domain.SyncRunsReadWriter
+	JobPauses    domain.JobPausesReadWriter
+	JobSchedules domain.JobSchedulesReadWriter
+	JobAlerts    domain.JobAlertsReadWriter
+	JobTriggers  domain.JobTriggersReadWriter
+	JobAnomalies domain.JobAnomaliesReadWriter
+	SyncRunLogs  domain.SyncRunLogsReadWriter
+	Remotes      domain.RemotesReadWriter
+	Manifests    domain.ManifestsReadWriter
+	ScrubStates  domain.ScrubStatesReadWriter
 
 	db *sql.DB
 }
@@ -22,6 +31,15 @@ func New(options ...Option) *Store {
 	s := &Store{}
 
 	s.SyncRuns = &syncRunsStore{baseStore: s}
+	s.JobPauses = &jobPausesStore{baseStore: s}
+	s.JobSchedules = &jobSchedulesStore{baseStore: s}
+	s.JobAlerts = &jobAlertsStore{baseStore: s}
+	s.JobTriggers = &jobTriggersStore{baseStore: s}
+	s.JobAnomalies = &jobAnomaliesStore{baseStore: s}
+	s.SyncRunLogs = &syncRunLogsStore{baseStore: s}
+	s.Remotes = &remotesStore{baseStore: s}
+	s.Manifests = &manifestsStore{baseStore: s}
+	s.ScrubStates = &scrubStatesStore{baseStore: s}
 
 	for _, opt := range options {
 		if err := opt(s); err != nil {
@@ -3,6 +3,7 @@ package store
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 
 	"github.com/eva01/backup-guardian/domain"
 	"github.com/eva01/backup-guardian/internal/errors"
@@ -23,10 +24,17 @@ func (s *syncRunsStore) CreateSyncRun(run *domain.SyncRun) (*domain.SyncRun, err
 	q := sqlc.New(s.baseStore.db)
 
 	startedAt := sql.NullTime{Time: run.StartedAt, Valid: !run.StartedAt.IsZero()}
+	kind := run.Kind
+	if kind == "" {
+		kind = domain.KindSync
+	}
 	row, err := q.CreateSyncRun(context.Background(), sqlc.CreateSyncRunParams{
-		ID:        run.ID,
-		JobName:   run.JobName,
-		StartedAt: startedAt,
+		ID:         run.ID,
+		JobName:    run.JobName,
+		Mode:       run.Mode,
+		PipelineID: sql.NullString{String: run.PipelineID, Valid: run.PipelineID != ""},
+		Kind:       kind,
+		StartedAt:  startedAt,
 	})
 	if err != nil {
 		return nil, errors.MapSQLError(err)
@@ -49,6 +57,78 @@ func (s *syncRunsStore) UpdateSyncRun(run *domain.SyncRun) error {
 	}
 	filesTransferred := sql.NullInt64{Int64: run.FilesTransferred, Valid: true}
 	bytesTransferred := sql.NullInt64{Int64: run.BytesTransferred, Valid: true}
+	uploadLimit := sql.NullInt64{Int64: run.UploadLimit, Valid: run.UploadLimit > 0}
+	downloadLimit := sql.NullInt64{Int64: run.DownloadLimit, Valid: run.DownloadLimit > 0}
+	filesTotal := sql.NullInt64{Int64: run.FilesTotal, Valid: run.FilesTotal > 0}
+	bytesTotal := sql.NullInt64{Int64: run.BytesTotal, Valid: run.BytesTotal > 0}
+	var hookResults sql.NullString
+	if len(run.Hooks) > 0 {
+		data, err := json.Marshal(run.Hooks)
+		if err != nil {
+			return err
+		}
+		hookResults = sql.NullString{String: string(data), Valid: true}
+	}
+	var filters sql.NullString
+	if run.Filters != nil {
+		data, err := json.Marshal(run.Filters)
+		if err != nil {
+			return err
+		}
+		filters = sql.NullString{String: string(data), Valid: true}
+	}
+	var destinations sql.NullString
+	if len(run.Destinations) > 0 {
+		data, err := json.Marshal(run.Destinations)
+		if err != nil {
+			return err
+		}
+		destinations = sql.NullString{String: string(data), Valid: true}
+	}
+	var plan sql.NullString
+	if run.Plan != nil {
+		data, err := json.Marshal(run.Plan)
+		if err != nil {
+			return err
+		}
+		plan = sql.NullString{String: string(data), Valid: true}
+	}
+
+	var usage sql.NullString
+	if len(run.Usage) > 0 {
+		data, err := json.Marshal(run.Usage)
+		if err != nil {
+			return err
+		}
+		usage = sql.NullString{String: string(data), Valid: true}
+	}
+
+	var changes sql.NullString
+	if run.Changes != nil {
+		data, err := json.Marshal(run.Changes)
+		if err != nil {
+			return err
+		}
+		changes = sql.NullString{String: string(data), Valid: true}
+	}
+
+	var anomaly sql.NullString
+	if run.Anomaly != nil {
+		data, err := json.Marshal(run.Anomaly)
+		if err != nil {
+			return err
+		}
+		anomaly = sql.NullString{String: string(data), Valid: true}
+	}
+
+	var scrub sql.NullString
+	if run.Scrub != nil {
+		data, err := json.Marshal(run.Scrub)
+		if err != nil {
+			return err
+		}
+		scrub = sql.NullString{String: string(data), Valid: true}
+	}
 
 	err := q.UpdateSyncRun(context.Background(), sqlc.UpdateSyncRunParams{
 		Status:           run.Status,
@@ -56,6 +136,18 @@ func (s *syncRunsStore) UpdateSyncRun(run *domain.SyncRun) error {
 		ErrorMessage:     errMsg,
 		FilesTransferred: filesTransferred,
 		BytesTransferred: bytesTransferred,
+		UploadLimit:      uploadLimit,
+		DownloadLimit:    downloadLimit,
+		FilesTotal:       filesTotal,
+		BytesTotal:       bytesTotal,
+		HookResults:      hookResults,
+		Filters:          filters,
+		Destinations:     destinations,
+		Plan:             plan,
+		DestinationUsage: usage,
+		ChangeStats:      changes,
+		Anomaly:          anomaly,
+		Scrub:            scrub,
 		ID:               run.ID,
 	})
 
@@ -82,10 +174,38 @@ func (s *syncRunsStore) ListSyncRuns(selector *domain.SyncRunsSelector) ([]*doma
 	}
 	offset := int64(selector.Offset)
 
-	rows, err := q.ListSyncRuns(context.Background(), sqlc.ListSyncRunsParams{
-		Limit:  limit,
-		Offset: offset,
-	})
+	var rows []sqlc.SyncRun
+	var err error
+	switch {
+	case selector.PipelineID != "":
+		rows, err = q.ListSyncRunsByPipeline(context.Background(), sqlc.ListSyncRunsByPipelineParams{
+			PipelineID: sql.NullString{String: selector.PipelineID, Valid: true},
+			Limit:      limit,
+			Offset:     offset,
+		})
+	case selector.JobName != "" && selector.Status != "":
+		rows, err = q.ListSyncRunsByJobAndStatus(context.Background(), sqlc.ListSyncRunsByJobAndStatusParams{
+			JobName: selector.JobName,
+			Status:  selector.Status,
+			DryRuns: selector.DryRuns,
+			Limit:   limit,
+			Offset:  offset,
+		})
+	case selector.JobName != "":
+		rows, err = q.ListSyncRunsByJob(context.Background(), sqlc.ListSyncRunsByJobParams{
+			JobName: selector.JobName,
+			DryRuns: selector.DryRuns,
+			Oldest:  selector.Oldest,
+			Limit:   limit,
+			Offset:  offset,
+		})
+	default:
+		rows, err = q.ListSyncRuns(context.Background(), sqlc.ListSyncRunsParams{
+			DryRuns: selector.DryRuns,
+			Limit:   limit,
+			Offset:  offset,
+		})
+	}
 	if err != nil {
 		return nil, errors.MapSQLError(err)
 	}
@@ -103,6 +223,8 @@ func mapSQLcToSyncRun(row *sqlc.SyncRun) *domain.SyncRun {
 		ID:        row.ID,
 		JobName:   row.JobName,
 		Status:    row.Status,
+		Mode:      row.Mode,
+		Kind:      row.Kind,
 		CreatedAt: row.CreatedAt,
 	}
 
@@ -121,6 +243,61 @@ func mapSQLcToSyncRun(row *sqlc.SyncRun) *domain.SyncRun {
 	if row.BytesTransferred.Valid {
 		run.BytesTransferred = row.BytesTransferred.Int64
 	}
+	if row.UploadLimit.Valid {
+		run.UploadLimit = row.UploadLimit.Int64
+	}
+	if row.DownloadLimit.Valid {
+		run.DownloadLimit = row.DownloadLimit.Int64
+	}
+	if row.FilesTotal.Valid {
+		run.FilesTotal = row.FilesTotal.Int64
+	}
+	if row.BytesTotal.Valid {
+		run.BytesTotal = row.BytesTotal.Int64
+	}
+	if row.HookResults.Valid {
+		// Results that cannot be decoded are dropped rather than failing reads of the run.
+		_ = json.Unmarshal([]byte(row.HookResults.String), &run.Hooks)
+	}
+	if row.Filters.Valid {
+		filters := &domain.Filters{}
+		if err := json.Unmarshal([]byte(row.Filters.String), filters); err == nil {
+			run.Filters = filters
+		}
+	}
+	if row.PipelineID.Valid {
+		run.PipelineID = row.PipelineID.String
+	}
+	if row.Destinations.Valid {
+		_ = json.Unmarshal([]byte(row.Destinations.String), &run.Destinations)
+	}
+	if row.Plan.Valid {
+		plan := &domain.SyncPlan{}
+		if err := json.Unmarshal([]byte(row.Plan.String), plan); err == nil {
+			run.Plan = plan
+		}
+	}
+	if row.DestinationUsage.Valid {
+		_ = json.Unmarshal([]byte(row.DestinationUsage.String), &run.Usage)
+	}
+	if row.ChangeStats.Valid {
+		changes := &domain.ChangeStats{}
+		if err := json.Unmarshal([]byte(row.ChangeStats.String), changes); err == nil {
+			run.Changes = changes
+		}
+	}
+	if row.Anomaly.Valid {
+		anomaly := &domain.Anomaly{}
+		if err := json.Unmarshal([]byte(row.Anomaly.String), anomaly); err == nil {
+			run.Anomaly = anomaly
+		}
+	}
+	if row.Scrub.Valid {
+		scrub := &domain.ScrubResult{}
+		if err := json.Unmarshal([]byte(row.Scrub.String), scrub); err == nil {
+			run.Scrub = scrub
+		}
+	}
 
 	return run
 }
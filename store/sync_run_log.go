@@ -0,0 +1,78 @@
+package store
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+
+	"github.com/eva01/backup-guardian/domain"
+	"github.com/eva01/backup-guardian/internal/errors"
+	"github.com/eva01/backup-guardian/store/sqlc"
+)
+
+type syncRunLogsStore struct {
+	baseStore *Store
+}
+
+var _ domain.SyncRunLogsReadWriter = (*syncRunLogsStore)(nil)
+
+// CreateSyncRunLog stores log, gzip-compressed.
+func (s *syncRunLogsStore) CreateSyncRunLog(log *domain.SyncRunLog) (*domain.SyncRunLog, error) {
+	if err := log.Validate(); err != nil {
+		return nil, err
+	}
+
+	var compressed bytes.Buffer
+	zw := gzip.NewWriter(&compressed)
+	if _, err := zw.Write(log.Content); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+
+	q := sqlc.New(s.baseStore.db)
+
+	row, err := q.CreateSyncRunLog(context.Background(), sqlc.CreateSyncRunLogParams{
+		RunID:     log.RunID,
+		Content:   compressed.Bytes(),
+		Size:      int64(len(log.Content)),
+		Truncated: log.Truncated,
+	})
+	if err != nil {
+		return nil, errors.MapSQLError(err)
+	}
+
+	return &domain.SyncRunLog{
+		RunID:     row.RunID,
+		Content:   log.Content,
+		Truncated: row.Truncated,
+		CreatedAt: row.CreatedAt,
+	}, nil
+}
+
+func (s *syncRunLogsStore) GetSyncRunLog(selector *domain.SyncRunLogSelector) (*domain.SyncRunLog, error) {
+	q := sqlc.New(s.baseStore.db)
+
+	row, err := q.GetSyncRunLog(context.Background(), selector.RunID)
+	if err != nil {
+		return nil, errors.MapSQLError(err)
+	}
+
+	zr, err := gzip.NewReader(bytes.NewReader(row.Content))
+	if err != nil {
+		return nil, err
+	}
+	content, err := io.ReadAll(zr)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.SyncRunLog{
+		RunID:     row.RunID,
+		Content:   content,
+		Truncated: row.Truncated,
+		CreatedAt: row.CreatedAt,
+	}, nil
+}
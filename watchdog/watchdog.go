@@ -0,0 +1,211 @@
+// Package watchdog raises alerts about jobs that stopped succeeding. It runs next to the job
+// loop, so that it notices jobs that stopped running altogether.
+package watchdog
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/eva01/backup-guardian/domain"
+	"github.com/eva01/backup-guardian/notify"
+	"github.com/eva01/backup-guardian/service"
+)
+
+const defaultInterval = time.Minute
+
+// StatusLister lists job statuses. Implemented by service.Service.
+type StatusLister interface {
+	JobStatuses() ([]*service.JobStatus, error)
+}
+
+// Watchdog periodically checks the RPO of every job, and notifies when a job starts or
+// stops violating it.
+type Watchdog struct {
+	statuses StatusLister
+	alerts   domain.JobAlertsReadWriter
+	notifier notify.Notifier
+	interval time.Duration
+	logger   *slog.Logger
+	now      func() time.Time
+}
+
+// Option configures the watchdog.
+type Option func(*Watchdog)
+
+// New creates a new watchdog.
+func New(options ...Option) *Watchdog {
+	w := &Watchdog{
+		interval: defaultInterval,
+		logger:   slog.Default(),
+		now:      time.Now,
+	}
+
+	for _, opt := range options {
+		opt(w)
+	}
+
+	return w
+}
+
+// WithStatuses sets the job status lister.
+func WithStatuses(statuses StatusLister) Option {
+	return func(w *Watchdog) { w.statuses = statuses }
+}
+
+// WithJobAlerts sets the job alerts store.
+func WithJobAlerts(alerts domain.JobAlertsReadWriter) Option {
+	return func(w *Watchdog) { w.alerts = alerts }
+}
+
+// WithNotifier sets the notifier.
+func WithNotifier(notifier notify.Notifier) Option {
+	return func(w *Watchdog) { w.notifier = notifier }
+}
+
+// WithInterval sets the interval between checks.
+func WithInterval(interval time.Duration) Option {
+	return func(w *Watchdog) { w.interval = interval }
+}
+
+// WithLogger sets the logger.
+func WithLogger(logger *slog.Logger) Option {
+	return func(w *Watchdog) { w.logger = logger }
+}
+
+// WithClock sets the function used to read the current time.
+func WithClock(now func() time.Time) Option {
+	return func(w *Watchdog) { w.now = now }
+}
+
+// Run checks jobs until ctx is cancelled.
+func (w *Watchdog) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := w.Check(ctx); err != nil {
+			w.logger.Error("Watchdog check failed", slog.Any("error", err))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Check evaluates the RPO of every job once. An alert is raised and notified when a job
+// starts violating its RPO, and cleared and notified when it recovers. The alerts of jobs
+// removed from the configuration are dropped. Jobs whose alert cannot be updated are skipped,
+// and reported together in the returned error.
+func (w *Watchdog) Check(ctx context.Context) error {
+	statuses, err := w.statuses.JobStatuses()
+	if err != nil {
+		return err
+	}
+
+	alerts, err := w.alerts.ListJobAlerts()
+	if err != nil {
+		return err
+	}
+	raised := map[string]*domain.JobAlert{}
+	for _, alert := range alerts {
+		if alert.Kind == domain.AlertKindRPO {
+			raised[alert.JobName] = alert
+		}
+	}
+
+	// A job whose alert cannot be saved does not keep the others from being checked.
+	var errs []error
+	for _, status := range statuses {
+		job := status.Job.Name
+		alert := raised[job]
+		delete(raised, job)
+
+		var err error
+		switch {
+		case status.Stale() && alert == nil:
+			err = w.raise(ctx, job, status.RPO)
+		case !status.Stale() && alert != nil:
+			err = w.clear(ctx, job, status.RPO)
+		}
+		if err != nil {
+			w.logger.Error("Failed to update RPO alert", slog.String("job", job), slog.Any("error", err))
+			errs = append(errs, fmt.Errorf("job %s: %w", job, err))
+		}
+	}
+
+	for job := range raised {
+		if err := w.alerts.DeleteJobAlert(&domain.JobAlertSelector{JobName: job, Kind: domain.AlertKindRPO}); err != nil {
+			w.logger.Error("Failed to drop RPO alert of removed job", slog.String("job", job), slog.Any("error", err))
+			errs = append(errs, fmt.Errorf("job %s: %w", job, err))
+			continue
+		}
+		w.logger.Info("RPO alert of removed job dropped", slog.String("job", job))
+	}
+
+	return errors.Join(errs...)
+}
+
+func (w *Watchdog) raise(ctx context.Context, job string, rpo *domain.RPOStatus) error {
+	now := w.now()
+	_, err := w.alerts.UpsertJobAlert(&domain.JobAlert{
+		JobName:  job,
+		Kind:     domain.AlertKindRPO,
+		Message:  rpo.Message(),
+		RaisedAt: now,
+	})
+	if err != nil {
+		return err
+	}
+
+	w.notify(ctx, &notify.Notification{
+		Event:    notify.EventRPOViolated,
+		Severity: notify.SeverityCritical,
+		JobName:  job,
+		Title:    fmt.Sprintf("Job %s has no successful run within its RPO", job),
+		Message:  rpo.Message(),
+		Time:     now,
+	})
+
+	return nil
+}
+
+// clear clears the RPO alert of job. rpo is nil when the job no longer has an RPO.
+func (w *Watchdog) clear(ctx context.Context, job string, rpo *domain.RPOStatus) error {
+	err := w.alerts.DeleteJobAlert(&domain.JobAlertSelector{JobName: job, Kind: domain.AlertKindRPO})
+	if err != nil {
+		return err
+	}
+
+	message := "The job no longer has an RPO"
+	if rpo != nil {
+		message = rpo.Message()
+	}
+
+	w.notify(ctx, &notify.Notification{
+		Event:    notify.EventRPORecovered,
+		Severity: notify.SeverityInfo,
+		JobName:  job,
+		Title:    fmt.Sprintf("Job %s is within its RPO again", job),
+		Message:  message,
+		Time:     w.now(),
+	})
+
+	return nil
+}
+
+func (w *Watchdog) notify(ctx context.Context, n *notify.Notification) {
+	if w.notifier == nil {
+		return
+	}
+
+	if err := w.notifier.Notify(ctx, n); err != nil {
+		w.logger.Error("Failed to send notification", slog.String("event", n.Event),
+			slog.String("job", n.JobName), slog.Any("error", err))
+	}
+}
@@ -0,0 +1,133 @@
+package watchdog_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/eva01/backup-guardian/domain"
+	domainmocks "github.com/eva01/backup-guardian/domain/mocks"
+	"github.com/eva01/backup-guardian/notify"
+	"github.com/eva01/backup-guardian/service"
+	"github.com/eva01/backup-guardian/watchdog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type statusList []*service.JobStatus
+
+func (s statusList) JobStatuses() ([]*service.JobStatus, error) { return s, nil }
+
+type recorder []*notify.Notification
+
+func (r *recorder) Notify(ctx context.Context, n *notify.Notification) error {
+	*r = append(*r, n)
+	return nil
+}
+
+func TestWatchdog_Check(t *testing.T) {
+	now := time.Date(2026, 1, 2, 12, 0, 0, 0, time.UTC)
+	stale := &service.JobStatus{
+		Job: &domain.SyncJob{Name: "stale", RPO: 26 * time.Hour},
+		RPO: domain.CheckRPO(26*time.Hour, now.Add(-30*time.Hour), now, now),
+	}
+	recovered := &service.JobStatus{
+		Job: &domain.SyncJob{Name: "recovered", RPO: 26 * time.Hour},
+		RPO: domain.CheckRPO(26*time.Hour, now.Add(-time.Hour), now, now),
+	}
+	stillStale := &service.JobStatus{
+		Job: &domain.SyncJob{Name: "still-stale", RPO: time.Hour},
+		RPO: domain.CheckRPO(time.Hour, now.Add(-2*time.Hour), now, now),
+	}
+	noRPO := &service.JobStatus{Job: &domain.SyncJob{Name: "no-rpo"}}
+
+	alertsMock := domainmocks.NewJobAlertsReadWriter(t)
+	alertsMock.On("ListJobAlerts").Return([]*domain.JobAlert{
+		{JobName: "recovered", Kind: domain.AlertKindRPO},
+		{JobName: "still-stale", Kind: domain.AlertKindRPO},
+		{JobName: "removed", Kind: domain.AlertKindRPO},
+	}, nil).Once()
+	alertsMock.On("UpsertJobAlert", mock.MatchedBy(func(a *domain.JobAlert) bool {
+		return a.JobName == "stale" && a.Kind == domain.AlertKindRPO && a.RaisedAt.Equal(now)
+	})).Return(nil, nil).Once()
+	alertsMock.On("DeleteJobAlert", &domain.JobAlertSelector{JobName: "recovered", Kind: domain.AlertKindRPO}).Return(nil).Once()
+	// The alert of a job removed by a reload is dropped, without notifying.
+	alertsMock.On("DeleteJobAlert", &domain.JobAlertSelector{JobName: "removed", Kind: domain.AlertKindRPO}).Return(nil).Once()
+
+	var notifications recorder
+	w := watchdog.New(
+		watchdog.WithStatuses(statusList{stale, recovered, stillStale, noRPO}),
+		watchdog.WithJobAlerts(alertsMock),
+		watchdog.WithNotifier(&notifications),
+		watchdog.WithClock(func() time.Time { return now }),
+	)
+
+	require.NoError(t, w.Check(context.Background()))
+	require.Len(t, notifications, 2)
+	assert.Equal(t, notify.EventRPOViolated, notifications[0].Event)
+	assert.Equal(t, notify.SeverityCritical, notifications[0].Severity)
+	assert.Equal(t, "stale", notifications[0].JobName)
+	assert.Equal(t, notify.EventRPORecovered, notifications[1].Event)
+	assert.Equal(t, "recovered", notifications[1].JobName)
+}
+
+func TestWatchdog_Check_AlertFails(t *testing.T) {
+	now := time.Date(2026, 1, 2, 12, 0, 0, 0, time.UTC)
+	status := func(name string) *service.JobStatus {
+		return &service.JobStatus{
+			Job: &domain.SyncJob{Name: name, RPO: time.Hour},
+			RPO: domain.CheckRPO(time.Hour, now.Add(-2*time.Hour), now, now),
+		}
+	}
+
+	alertsMock := domainmocks.NewJobAlertsReadWriter(t)
+	alertsMock.On("ListJobAlerts").Return([]*domain.JobAlert{{JobName: "removed", Kind: domain.AlertKindRPO}}, nil).Once()
+	alertsMock.On("UpsertJobAlert", mock.MatchedBy(func(a *domain.JobAlert) bool { return a.JobName == "first" })).
+		Return(nil, errors.New("database is locked")).Once()
+	alertsMock.On("UpsertJobAlert", mock.MatchedBy(func(a *domain.JobAlert) bool { return a.JobName == "second" })).
+		Return(nil, nil).Once()
+	alertsMock.On("DeleteJobAlert", &domain.JobAlertSelector{JobName: "removed", Kind: domain.AlertKindRPO}).Return(nil).Once()
+
+	var notifications recorder
+	w := watchdog.New(
+		watchdog.WithStatuses(statusList{status("first"), status("second")}),
+		watchdog.WithJobAlerts(alertsMock),
+		watchdog.WithNotifier(&notifications),
+		watchdog.WithClock(func() time.Time { return now }),
+	)
+
+	// The failure of the first job neither stops the second from being raised, nor the alert
+	// of the removed job from being dropped.
+	err := w.Check(context.Background())
+	assert.ErrorContains(t, err, "job first: database is locked")
+	require.Len(t, notifications, 1)
+	assert.Equal(t, "second", notifications[0].JobName)
+}
+
+func TestWatchdog_Run(t *testing.T) {
+	alertsMock := domainmocks.NewJobAlertsReadWriter(t)
+	checked := make(chan struct{}, 10)
+	alertsMock.On("ListJobAlerts").Run(func(args mock.Arguments) {
+		checked <- struct{}{}
+	}).Return([]*domain.JobAlert{}, nil)
+
+	w := watchdog.New(
+		watchdog.WithStatuses(statusList{}),
+		watchdog.WithJobAlerts(alertsMock),
+		watchdog.WithInterval(time.Millisecond),
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		w.Run(ctx)
+	}()
+
+	<-checked
+	<-checked
+	cancel()
+	<-done
+}
@@ -0,0 +1,405 @@
+// backup-guardian dashboard. Talks to the JSON API served next to it; routes are kept in the
+// URL fragment: #/, #/jobs/{job}, #/runs/{id} and #/pipelines/{id}.
+"use strict";
+
+const view = document.getElementById("view");
+
+async function api(method, path, body) {
+  const init = { method, headers: {} };
+  if (body !== undefined) {
+    init.headers["Content-Type"] = "application/json";
+    init.body = JSON.stringify(body);
+  }
+
+  const resp = await fetch(path, init);
+  if (resp.status === 204) {
+    return null;
+  }
+
+  const data = await resp.json().catch(() => null);
+  if (!resp.ok) {
+    throw new Error((data && data.message) || resp.statusText);
+  }
+
+  return data;
+}
+
+function el(tag, attrs, ...children) {
+  const node = document.createElement(tag);
+  for (const [key, value] of Object.entries(attrs || {})) {
+    if (key.startsWith("on")) {
+      node.addEventListener(key.slice(2), value);
+    } else if (value !== undefined && value !== null && value !== false) {
+      node.setAttribute(key, value);
+    }
+  }
+  for (const child of children.flat(Infinity)) {
+    if (child !== undefined && child !== null) {
+      node.append(child instanceof Node ? child : String(child));
+    }
+  }
+
+  return node;
+}
+
+function toast(message) {
+  const node = document.getElementById("toast");
+  node.textContent = message;
+  node.hidden = false;
+  clearTimeout(toast.timer);
+  toast.timer = setTimeout(() => { node.hidden = true; }, 4000);
+}
+
+function formatTime(value) {
+  return value ? new Date(value).toLocaleString() : "-";
+}
+
+function formatBytes(n) {
+  const units = ["B", "KiB", "MiB", "GiB", "TiB"];
+  let i = 0;
+  while (n >= 1024 && i < units.length - 1) {
+    n /= 1024;
+    i++;
+  }
+
+  return (i === 0 ? n : n.toFixed(1)) + " " + units[i];
+}
+
+function duration(run) {
+  if (!run.started_at) {
+    return null;
+  }
+  const end = run.finished_at ? new Date(run.finished_at) : new Date();
+
+  return (end - new Date(run.started_at)) / 1000;
+}
+
+function formatDuration(seconds) {
+  if (seconds === null) {
+    return "-";
+  }
+  seconds = Math.round(seconds);
+  const h = Math.floor(seconds / 3600);
+  const m = Math.floor((seconds % 3600) / 60);
+  const s = seconds % 60;
+
+  return h ? `${h}h${String(m).padStart(2, "0")}m` : m ? `${m}m${String(s).padStart(2, "0")}s` : `${s}s`;
+}
+
+function statusBadge(status) {
+  return el("span", { class: `status status-${status}` }, status);
+}
+
+function formatProgress(progress) {
+  let text = `running ${progress.percent.toFixed(1)}%, ${formatBytes(progress.bytes_done)} of ${formatBytes(progress.bytes_total)}`;
+  if (progress.speed > 0) {
+    text += ` at ${formatBytes(progress.speed)}/s`;
+  }
+  if (progress.eta_seconds) {
+    text += `, ETA ${formatDuration(progress.eta_seconds)}`;
+  }
+
+  return text;
+}
+
+function jobState(job) {
+  if (job.progress) {
+    return el("span", { class: "status-running" }, formatProgress(job.progress));
+  }
+  if (job.paused) {
+    return el("span", { class: "paused", title: (job.pause && job.pause.reason) || "" }, "paused");
+  }
+  if (job.rpo && job.rpo.violated) {
+    return el("span", { class: "stale" }, "stale");
+  }
+  if (job.triggered_at) {
+    return el("span", { class: "muted" }, "triggered");
+  }
+
+  return "ok";
+}
+
+function action(label, method, path, body, done) {
+  return el("button", {
+    onclick: async (event) => {
+      event.target.disabled = true;
+      try {
+        await api(method, path, body);
+        toast(`${label}: done`);
+        done();
+      } catch (err) {
+        toast(`${label}: ${err.message}`);
+        event.target.disabled = false;
+      }
+    },
+  }, label);
+}
+
+function jobActions(job, refresh) {
+  const path = `/api/jobs/${encodeURIComponent(job.name)}`;
+
+  return [
+    action("Run now", "POST", `${path}/trigger`, undefined, refresh),
+    " ",
+    job.pause && job.pause.scope === job.name
+      ? action("Resume", "POST", `${path}/resume`, undefined, refresh)
+      : action("Pause", "POST", `${path}/pause`, { reason: "paused from dashboard" }, refresh),
+  ];
+}
+
+async function renderJobs() {
+  const jobs = await api("GET", "/api/jobs");
+  const runnerPaused = jobs.some((job) => job.pause && job.pause.scope === "*");
+
+  document.getElementById("runner-state").replaceChildren(
+    runnerPaused
+      ? action("Resume runner", "POST", "/api/resume", undefined, route)
+      : action("Pause runner", "POST", "/api/pause", { reason: "paused from dashboard" }, route),
+  );
+
+  view.replaceChildren(
+    el("h1", {}, "Jobs"),
+    el("table", {},
+      el("thead", {}, el("tr", {},
+        ["Job", "State", "Last run", "Last status", "Duration", "Transferred", "Next due", ""].map((h) => el("th", {}, h)))),
+      el("tbody", {}, jobs.map((job) => el("tr", {},
+        el("td", {}, el("a", { href: `#/jobs/${encodeURIComponent(job.name)}` }, job.name)),
+        el("td", {}, jobState(job)),
+        el("td", {}, job.last_run ? el("a", { href: `#/runs/${job.last_run.id}` }, formatTime(job.last_run.started_at)) : "-"),
+        el("td", {}, job.last_run ? statusBadge(job.last_run.status) : "-"),
+        el("td", { class: "num" }, job.last_run ? formatDuration(duration(job.last_run)) : "-"),
+        el("td", { class: "num" }, job.last_run ? formatBytes(job.last_run.bytes_transferred) : "-"),
+        el("td", {}, formatTime(job.next_due_at)),
+        el("td", {}, jobActions(job, route)),
+      ))),
+    ),
+  );
+}
+
+function timeline(runs) {
+  const max = Math.max(1, ...runs.map((run) => duration(run) || 0));
+
+  return el("div", { class: "timeline" }, runs.slice().reverse().map((run) => el("a", {
+    href: `#/runs/${run.id}`,
+    class: `status-${run.status}`,
+    title: `${formatTime(run.started_at)}: ${run.status}, ${formatDuration(duration(run))}, ${formatBytes(run.bytes_transferred)}`,
+    style: `height: ${Math.max(8, Math.round(100 * (duration(run) || 0) / max))}%`,
+  })));
+}
+
+// dryRunAction returns a button running a dry run of job and showing its run page.
+function dryRunAction(job) {
+  return el("button", {
+    onclick: async (event) => {
+      event.target.disabled = true;
+      toast("Dry run: running...");
+      try {
+        const run = await api("POST", `/api/jobs/${encodeURIComponent(job.name)}/dry-run`);
+        location.hash = `#/runs/${run.id}`;
+      } catch (err) {
+        toast(`Dry run: ${err.message}`);
+        event.target.disabled = false;
+      }
+    },
+  }, "Dry run");
+}
+
+async function renderJob(name) {
+  const path = `/api/jobs/${encodeURIComponent(name)}`;
+  const [jobs, runs] = await Promise.all([api("GET", "/api/jobs"), api("GET", `${path}/runs?limit=100`)]);
+  const job = jobs.find((j) => j.name === name);
+  if (!job) {
+    throw new Error(`Unknown job ${name}`);
+  }
+
+  view.replaceChildren(
+    el("h1", {}, job.name, " ", jobActions(job, route), job.mode !== "bisync" && [" ", dryRunAction(job)]),
+    el("dl", {},
+      el("dt", {}, "Source"), el("dd", {}, job.source),
+      job.destinations
+        ? [el("dt", {}, "Destinations"), el("dd", {}, `${job.destinations.join(", ")} (${job.require} must succeed)`)]
+        : [el("dt", {}, "Destination"), el("dd", {}, job.destination)],
+      el("dt", {}, "Mode"), el("dd", {}, job.mode),
+      job.encrypted && [el("dt", {}, "Encryption"), el("dd", {}, "rclone crypt")],
+      job.after && [el("dt", {}, "After"), el("dd", {}, formatDependencies(job.after))],
+      el("dt", {}, "State"), el("dd", {}, jobState(job)),
+      el("dt", {}, "Next due"), el("dd", {}, formatTime(job.next_due_at)),
+      job.rpo && [el("dt", {}, "RPO"), el("dd", {}, `${job.rpo.rpo}, last success ${formatTime(job.rpo.last_success_at)}`)],
+    ),
+    el("h2", {}, "Recent runs"),
+    // Dry runs change nothing: they are listed but left out of the timeline.
+    timeline(runs.filter((run) => run.kind !== "dry-run")),
+    el("table", {},
+      el("thead", {}, el("tr", {},
+        ["Started", "Status", "Duration", "Files", "Transferred", "Error"].map((h) => el("th", {}, h)))),
+      el("tbody", {}, runs.map((run) => el("tr", {},
+        el("td", {}, el("a", { href: `#/runs/${run.id}` }, formatTime(run.started_at))),
+        el("td", {}, statusBadge(run.status), run.kind === "dry-run" && el("span", { class: "muted" }, " dry run")),
+        el("td", { class: "num" }, formatDuration(duration(run))),
+        el("td", { class: "num" }, run.files_transferred),
+        el("td", { class: "num" }, formatBytes(run.bytes_transferred)),
+        el("td", { class: "muted" }, run.error_message || ""),
+      ))),
+    ),
+  );
+}
+
+// formatDependencies returns the dependencies of a job, e.g. "backup (success), prune (completion)".
+function formatDependencies(after) {
+  return after.map((dep) => `${dep.job} (${dep.on})`).join(", ");
+}
+
+// formatFilters returns the filters of a run, one per line.
+function formatFilters(filters) {
+  const lines = [
+    ...(filters.include || []).map((rule) => `include ${rule}`),
+    ...(filters.exclude || []).map((rule) => `exclude ${rule}`),
+    ...(filters.rules || []).map((rule) => `rule ${rule}`),
+    ...(filters.filter_from || []).map((path) => `rules from ${path}`),
+    ...(filters.exclude_if_present || []).map((name) => `exclude directories containing ${name}`),
+  ];
+  if (filters.min_size) lines.push(`min size ${formatBytes(filters.min_size)}`);
+  if (filters.max_size) lines.push(`max size ${formatBytes(filters.max_size)}`);
+  if (filters.max_age) lines.push(`max age ${filters.max_age}`);
+
+  return lines.join("\n");
+}
+
+// logLevel is the minimum level of the run log records shown, kept across refreshes.
+let logLevel = "info";
+
+async function renderRun(id) {
+  const run = await api("GET", `/api/runs/${encodeURIComponent(id)}`);
+  const logView = el("pre", {}, "Loading...");
+  const levelSelect = el("select", { onchange: () => { logLevel = levelSelect.value; showLog(); } },
+    ["debug", "info", "warn", "error"].map((level) => el("option", { value: level, selected: level === logLevel }, level)));
+
+  async function showLog() {
+    const resp = await fetch(`/api/runs/${encodeURIComponent(id)}/log?level=${levelSelect.value}`);
+    if (resp.status === 404) {
+      logView.replaceWith(el("p", { class: "muted" }, "No log recorded for this run."));
+      return;
+    }
+    const text = await resp.text();
+    logView.textContent = resp.ok ? text || "No records at this level." : text;
+  }
+
+  view.replaceChildren(
+    el("h1", {}, el("a", { href: `#/jobs/${encodeURIComponent(run.job_name)}` }, run.job_name), " / ", run.id,
+      run.kind === "dry-run" && el("span", { class: "muted" }, " (dry run)")),
+    el("dl", {},
+      el("dt", {}, "Status"), el("dd", {}, statusBadge(run.status)),
+      el("dt", {}, "Mode"), el("dd", {}, run.mode),
+      el("dt", {}, "Started"), el("dd", {}, formatTime(run.started_at)),
+      el("dt", {}, "Finished"), el("dd", {}, formatTime(run.finished_at)),
+      el("dt", {}, "Duration"), el("dd", {}, formatDuration(duration(run))),
+      el("dt", {}, "Files"), el("dd", {}, run.files_total ? `${run.files_transferred} of ${run.files_total}` : run.files_transferred),
+      el("dt", {}, "Transferred"),
+      el("dd", {}, run.bytes_total ? `${formatBytes(run.bytes_transferred)} of ${formatBytes(run.bytes_total)}` : formatBytes(run.bytes_transferred)),
+      run.upload_limit && [el("dt", {}, "Upload limit"), el("dd", {}, `${formatBytes(run.upload_limit)}/s`)],
+      run.download_limit && [el("dt", {}, "Download limit"), el("dd", {}, `${formatBytes(run.download_limit)}/s`)],
+      run.pipeline_id && [el("dt", {}, "Pipeline"),
+        el("dd", {}, el("a", { href: `#/pipelines/${encodeURIComponent(run.pipeline_id)}` }, run.pipeline_id))],
+    ),
+    run.error_message && [el("h2", {}, "Error"), el("pre", {}, run.error_message)],
+    run.destinations && [
+      el("h2", {}, "Destinations"),
+      el("table", {},
+        el("thead", {}, el("tr", {},
+          ["Destination", "Status", "Duration", "Files", "Transferred", "Error"].map((h) => el("th", {}, h)))),
+        el("tbody", {}, run.destinations.map((dest) => el("tr", {},
+          el("td", {}, dest.destination),
+          el("td", {}, statusBadge(dest.status)),
+          el("td", { class: "num" }, formatDuration(dest.duration_seconds)),
+          el("td", { class: "num" }, dest.files_transferred),
+          el("td", { class: "num" }, formatBytes(dest.bytes_transferred)),
+          el("td", { class: "muted" }, dest.error || ""),
+        ))),
+      ),
+    ],
+    run.plan && [
+      el("h2", {}, "Planned changes"),
+      el("p", {}, `${run.plan.copies} to copy (${formatBytes(run.plan.copy_bytes)}), `,
+        `${run.plan.updates} to update (${formatBytes(run.plan.update_bytes)}), `,
+        `${run.plan.deletes} to delete (${formatBytes(run.plan.delete_bytes)})`,
+        run.plan.truncated && el("span", { class: "muted" }, `, only the first ${run.plan.changes.length} listed`)),
+      run.plan.changes.length > 0 && el("table", {},
+        el("thead", {}, el("tr", {}, ["Action", "Size", "Path"].map((h) => el("th", {}, h)))),
+        el("tbody", {}, run.plan.changes.map((change) => el("tr", {},
+          el("td", { class: `change-${change.action}` }, change.action),
+          el("td", { class: "num" }, change.size >= 0 ? formatBytes(change.size) : "-"),
+          el("td", {}, change.destination ? `${change.destination}: ${change.path}` : change.path),
+        ))),
+      ),
+    ],
+    run.filters && [el("h2", {}, "Filters"), el("pre", {}, formatFilters(run.filters))],
+    run.hooks && [
+      el("h2", {}, "Hooks"),
+      run.hooks.map((hook) => [
+        el("h3", {}, `${hook.event}: ${hook.name} `, statusBadge(hook.error ? "failed" : "success"), " ",
+          el("span", { class: "muted" }, formatDuration(hook.duration_seconds))),
+        hook.error && el("p", { class: "stale" }, hook.error),
+        hook.output && el("pre", {}, hook.output),
+      ]),
+    ],
+    el("h2", {}, "Log ", levelSelect),
+    logView,
+  );
+
+  await showLog();
+}
+
+async function renderPipeline(id) {
+  const pipeline = await api("GET", `/api/pipelines/${encodeURIComponent(id)}`);
+
+  view.replaceChildren(
+    el("h1", {}, "Pipeline ", pipeline.id),
+    el("table", {},
+      el("thead", {}, el("tr", {},
+        ["Job", "After", "State", "Started", "Duration", "Transferred", "Error"].map((h) => el("th", {}, h)))),
+      el("tbody", {}, pipeline.steps.map((step) => el("tr", {},
+        el("td", {}, el("a", { href: `#/jobs/${encodeURIComponent(step.job)}` }, step.job)),
+        el("td", { class: "muted" }, step.after ? formatDependencies(step.after) : ""),
+        el("td", {}, statusBadge(step.state)),
+        el("td", {}, step.run ? el("a", { href: `#/runs/${step.run.id}` }, formatTime(step.run.started_at)) : "-"),
+        el("td", { class: "num" }, step.run ? formatDuration(duration(step.run)) : "-"),
+        el("td", { class: "num" }, step.run ? formatBytes(step.run.bytes_transferred) : "-"),
+        el("td", { class: "muted" }, (step.run && step.run.error_message) || ""),
+      ))),
+    ),
+  );
+}
+
+async function route() {
+  const [, kind, id] = location.hash.replace(/^#/, "").split("/").map(decodeURIComponent);
+  try {
+    if (kind === "jobs" && id) {
+      await renderJob(id);
+    } else if (kind === "runs" && id) {
+      await renderRun(id);
+    } else if (kind === "pipelines" && id) {
+      await renderPipeline(id);
+    } else {
+      await renderJobs();
+    }
+  } catch (err) {
+    view.replaceChildren(el("p", { class: "stale" }, err.message));
+  }
+}
+
+// Refresh on progress events, at most every 2 seconds, so that running syncs update live.
+let pending = null;
+if (window.EventSource) {
+  const refresh = () => {
+    if (!pending && !document.hidden) {
+      pending = setTimeout(() => { pending = null; route(); }, 2000);
+    }
+  };
+  const stream = new EventSource("/api/progress/stream");
+  stream.addEventListener("progress", refresh);
+  stream.addEventListener("done", refresh);
+}
+
+window.addEventListener("hashchange", route);
+setInterval(() => { if (!document.hidden) route(); }, 15000);
+route();
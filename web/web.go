@@ -0,0 +1,19 @@
+// Package web embeds the static files of the web dashboard.
+package web
+
+import (
+	"embed"
+	"io/fs"
+	"net/http"
+)
+
+//go:embed static
+var static embed.FS
+
+// FS holds the dashboard files, index.html at its root.
+var FS, _ = fs.Sub(static, "static")
+
+// Handler returns the handler serving the dashboard files.
+func Handler() http.Handler {
+	return http.FileServerFS(FS)
+}